@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+)
+
+const ErrCodeUnauthorized = "unauthorized"
+
+// --- Semua endpoint /admin butuh header Authorization: Bearer <ADMIN_TOKEN> ---
+// Kalau token tidak dikonfigurasi, endpoint admin tidak didaftarkan sama sekali.
+func requireAdminToken(token string) gin.HandlerFunc {
+	expected := []byte(token)
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), expected) != 1 {
+			handlers.AbortWithError(c, http.StatusUnauthorized, handlers.APIError{
+				Code:    ErrCodeUnauthorized,
+				Message: "missing or invalid admin token",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+func registerAdminRoutes(g *gin.RouterGroup, deps app) {
+	reloader := deps.reloader
+	g.POST("/config/reload", func(c *gin.Context) {
+		result, err := reloader.Reload()
+		if err != nil {
+			handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest, errors.New("reload failed, keeping previous config: "+err.Error()))
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	})
+	registerFlagRoutes(g.Group("/flags"), deps.flags)
+	registerProviderRoutes(g.Group("/providers"), deps.killSwitch)
+	registerCacheRoutes(g.Group("/cache"), deps.cache, reloader.Current)
+	registerAPIKeyRoutes(g.Group("/api-keys"), deps.apiKeys)
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/cache"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+const (
+	ErrCodeInvalidAPIKey = "invalid_api_key"
+	ErrCodeQuotaExceeded = "quota_exceeded"
+)
+
+// --- Mode pemeriksaan X-API-Key ---
+const (
+	// Key tidak diperiksa, hanya dipakai sebagai namespace data tersimpan
+	APIKeysOff = "off"
+	// Key yang dikirim harus terdaftar & kena kuota; tanpa key tetap dilayani
+	APIKeysOptional = "optional"
+	// Semua request API wajib membawa key terdaftar
+	APIKeysRequired = "required"
+)
+
+// --- Header kuota di setiap response untuk request ber-key ---
+const (
+	quotaLimitHeader     = "X-RateLimit-Limit"
+	quotaRemainingHeader = "X-RateLimit-Remaining"
+	quotaResetHeader     = "X-RateLimit-Reset"
+)
+
+// Hasil lookup key di-cache per instance; pencabutan di instance lain
+// berlaku paling lambat setelah ini
+const apiKeyLookupTTL = time.Minute
+
+// Batas entri cache lookup; key acak yang tidak terdaftar juga di-cache,
+// jadi tanpa batas client bisa menggelembungkan memori
+const maxAPIKeyLookupEntries = 10000
+
+// --- Konfigurasi API key untuk developer pihak ketiga, bagian "api_keys" ---
+type APIKeysConfig struct {
+	// off (default), optional atau required
+	Mode string `yaml:"mode"`
+	// Request per hari (UTC) untuk key tanpa kuota sendiri; 0 = tanpa batas
+	DefaultDailyQuota int64 `yaml:"default_daily_quota"`
+}
+
+func defaultAPIKeysConfig() APIKeysConfig {
+	return APIKeysConfig{Mode: APIKeysOff, DefaultDailyQuota: 1000}
+}
+
+func (c APIKeysConfig) validate() error {
+	switch c.Mode {
+	case APIKeysOff, APIKeysOptional, APIKeysRequired:
+	default:
+		return fmt.Errorf("api_keys.mode must be one of off, optional or required, got %q", c.Mode)
+	}
+	if c.DefaultDailyQuota < 0 {
+		return errors.New("api_keys.default_daily_quota must not be negative")
+	}
+	return nil
+}
+
+// --- Penghitung request per key per hari ---
+// Di memori per instance, atau di Redis (dibagi semua instance) kalau
+// cache.backend redis.
+type quotaCounter interface {
+	// Tambah satu dan kembalikan jumlah request key ini di hari day
+	Incr(ctx context.Context, key string, day time.Time) (int64, error)
+}
+
+type memoryQuotaCounter struct {
+	mu     sync.Mutex
+	counts map[string]dailyCount
+}
+
+type dailyCount struct {
+	day   time.Time
+	count int64
+}
+
+func newMemoryQuotaCounter() *memoryQuotaCounter {
+	return &memoryQuotaCounter{counts: map[string]dailyCount{}}
+}
+
+func (m *memoryQuotaCounter) Incr(_ context.Context, key string, day time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.counts[key]
+	if !c.day.Equal(day) {
+		c = dailyCount{day: day}
+	}
+	c.count++
+	m.counts[key] = c
+	return c.count, nil
+}
+
+// Key: <prefix>:quota:<hash key>:<tanggal>, kedaluwarsa 48 jam setelah request pertama hari itu
+type redisQuotaCounter struct {
+	client *redis.Client
+	prefix string
+}
+
+func (r *redisQuotaCounter) Incr(ctx context.Context, key string, day time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, cache.RedisTimeout)
+	defer cancel()
+	name := r.prefix + key + ":" + day.Format(time.DateOnly)
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, name)
+	pipe.ExpireNX(ctx, name, 48*time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// --- Kuota sesuai backend cache yang aktif ---
+func newQuotaCounter(cfg CacheConfig, upstream cache.Store) quotaCounter {
+	if store, ok := upstream.(*cache.Redis); ok {
+		return &redisQuotaCounter{client: store.Client(), prefix: cfg.Redis.KeyPrefix + ":quota:"}
+	}
+	return newMemoryQuotaCounter()
+}
+
+// --- Lookup key lewat store dengan cache singkat, supaya database tidak ditanya tiap request ---
+type apiKeyLookup struct {
+	store services.APIKeyStore
+
+	mu      sync.Mutex
+	entries map[string]apiKeyLookupEntry
+}
+
+type apiKeyLookupEntry struct {
+	key     model.APIKey
+	found   bool
+	expires time.Time
+}
+
+func newAPIKeyLookup(store services.APIKeyStore) *apiKeyLookup {
+	return &apiKeyLookup{store: store, entries: map[string]apiKeyLookupEntry{}}
+}
+
+func (l *apiKeyLookup) get(ctx context.Context, hash string) (model.APIKey, bool, error) {
+	now := time.Now()
+	l.mu.Lock()
+	entry, ok := l.entries[hash]
+	l.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.key, entry.found, nil
+	}
+
+	key, err := l.store.Lookup(ctx, hash)
+	found := err == nil
+	if err != nil && !errors.Is(err, services.ErrAPIKeyNotFound) {
+		return model.APIKey{}, false, err
+	}
+	l.mu.Lock()
+	if len(l.entries) >= maxAPIKeyLookupEntries {
+		l.removeExpiredLocked(now)
+	}
+	// Masih penuh: semuanya masih berlaku, mulai dari kosong lagi; paling
+	// mahal satu lookup ulang per key
+	if len(l.entries) >= maxAPIKeyLookupEntries {
+		clear(l.entries)
+	}
+	l.entries[hash] = apiKeyLookupEntry{key: key, found: found, expires: now.Add(apiKeyLookupTTL)}
+	l.mu.Unlock()
+	return key, found, nil
+}
+
+func (l *apiKeyLookup) removeExpiredLocked(now time.Time) {
+	for hash, entry := range l.entries {
+		if !now.Before(entry.expires) {
+			delete(l.entries, hash)
+		}
+	}
+}
+
+// --- Buang entri kedaluwarsa secara berkala, seperti bucket rate limit ---
+func (l *apiKeyLookup) StartJanitor(lc *lifecycle, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.OnShutdown("api key lookup janitor", func(context.Context) error {
+		cancel()
+		return nil
+	})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.mu.Lock()
+				l.removeExpiredLocked(time.Now())
+				l.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Dipanggil setelah key dibuat/dicabut di instance ini
+func (l *apiKeyLookup) forget() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.entries)
+}
+
+// --- Middleware: periksa X-API-Key dan hitung kuota hariannya ---
+// Mode & kuota default dibaca per request supaya ikut hot reload.
+func requireAPIKeyQuota(config func() *Config, keys *apiKeyLookup, counter quotaCounter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config().APIKeys
+		if cfg.Mode == APIKeysOff {
+			c.Next()
+			return
+		}
+		raw := c.GetHeader(handlers.APIKeyHeader)
+		if raw == "" {
+			if cfg.Mode == APIKeysRequired {
+				handlers.AbortWithError(c, http.StatusUnauthorized, handlers.APIError{
+					Code:    handlers.ErrCodeAPIKeyRequired,
+					Message: handlers.APIKeyHeader + " header is required",
+				})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		hash := handlers.HashAPIKey(raw)
+		key, found, err := keys.get(ctx, hash)
+		if err != nil {
+			handlers.AbortWithError(c, http.StatusServiceUnavailable, handlers.APIError{
+				Code:      handlers.ErrCodeInternal,
+				Message:   "api key could not be checked, try again shortly",
+				Retryable: true,
+			})
+			return
+		}
+		if !found || key.RevokedAt != nil {
+			handlers.AbortWithError(c, http.StatusUnauthorized, handlers.APIError{
+				Code:    ErrCodeInvalidAPIKey,
+				Message: "unknown or revoked API key",
+			})
+			return
+		}
+
+		quota := key.DailyQuota
+		if quota == 0 {
+			quota = cfg.DefaultDailyQuota
+		}
+		if quota <= 0 {
+			c.Next()
+			return
+		}
+		now := time.Now().UTC()
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		reset := day.AddDate(0, 0, 1)
+		used, err := counter.Incr(ctx, hash, day)
+		if err != nil {
+			// Penghitung yang gagal tidak boleh mematikan API; request tetap dilayani
+			slog.WarnContext(ctx, "api key quota not counted", "key_id", key.ID, "error", err)
+			c.Next()
+			return
+		}
+		c.Header(quotaLimitHeader, strconv.FormatInt(quota, 10))
+		c.Header(quotaRemainingHeader, strconv.FormatInt(max(quota-used, 0), 10))
+		c.Header(quotaResetHeader, strconv.FormatInt(reset.Unix(), 10))
+		if used > quota {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			handlers.AbortWithError(c, http.StatusTooManyRequests, handlers.APIError{
+				Code:      ErrCodeQuotaExceeded,
+				Message:   fmt.Sprintf("daily quota of %d requests used up, resets at %s", quota, reset.Format(time.RFC3339)),
+				Retryable: true,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// --- Admin: POST/GET /admin/api-keys, DELETE /admin/api-keys/:id ---
+func registerAPIKeyRoutes(g *gin.RouterGroup, keys *apiKeyLookup) {
+	g.POST("", func(c *gin.Context) {
+		var body struct {
+			Name       string `json:"name"`
+			DailyQuota int64  `json:"daily_quota"`
+		}
+		if !handlers.BindJSON(c, &body) {
+			return
+		}
+		body.Name = strings.TrimSpace(body.Name)
+		if body.Name == "" || len(body.Name) > 100 {
+			handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest, errors.New("name must be 1 to 100 characters, e.g. the developer or app"))
+			return
+		}
+		if body.DailyQuota < 0 {
+			handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest, errors.New("daily_quota must not be negative; 0 uses api_keys.default_daily_quota"))
+			return
+		}
+		raw, err := newAPIKey()
+		if err != nil {
+			handlers.AbortWithError(c, http.StatusInternalServerError, handlers.APIError{Code: handlers.ErrCodeInternal, Message: err.Error()})
+			return
+		}
+		key := model.APIKey{
+			ID:         raw.id,
+			Name:       body.Name,
+			Prefix:     raw.secret[:len(apiKeyPrefix)+4],
+			DailyQuota: body.DailyQuota,
+			CreatedAt:  time.Now().UTC(),
+		}
+		if err := keys.store.Create(c.Request.Context(), key, handlers.HashAPIKey(raw.secret)); err != nil {
+			handlers.AbortWithServiceError(c, err)
+			return
+		}
+		keys.forget()
+		// Satu-satunya kesempatan melihat key aslinya
+		c.JSON(http.StatusCreated, gin.H{"key": raw.secret, "api_key": key})
+	})
+
+	g.GET("", func(c *gin.Context) {
+		list, err := keys.store.List(c.Request.Context())
+		if err != nil {
+			handlers.AbortWithServiceError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"api_keys": list})
+	})
+
+	g.DELETE("/:id", func(c *gin.Context) {
+		key, err := keys.store.Revoke(c.Request.Context(), c.Param("id"), time.Now().UTC())
+		if err != nil {
+			handlers.AbortWithServiceError(c, err)
+			return
+		}
+		keys.forget()
+		c.JSON(http.StatusOK, key)
+	})
+}
+
+// Awalan key supaya mudah dikenali (misal oleh secret scanner)
+const apiKeyPrefix = "tk_"
+
+type issuedKey struct {
+	id, secret string
+}
+
+// --- Key baru: 32 byte acak (base64url) plus ID 8 byte untuk admin ---
+func newAPIKey() (issuedKey, error) {
+	secret := make([]byte, 32)
+	id := make([]byte, 8)
+	if _, err := rand.Read(secret); err != nil {
+		return issuedKey{}, fmt.Errorf("generate api key: %w", err)
+	}
+	if _, err := rand.Read(id); err != nil {
+		return issuedKey{}, fmt.Errorf("generate api key: %w", err)
+	}
+	return issuedKey{id: hex.EncodeToString(id), secret: apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)}, nil
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/cache"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+const ErrCodeCacheUnavailable = "cache_unavailable"
+
+// --- Backend cache: memory per instance, atau Redis yang dibagi antar instance ---
+const (
+	CacheBackendMemory = "memory"
+	CacheBackendRedis  = "redis"
+)
+
+// --- Konfigurasi cache; TTL per jenis data upstream tetap di services.CacheTTLs ---
+type CacheConfig struct {
+	Backend string      `yaml:"backend"`
+	Redis   RedisConfig `yaml:"redis"`
+
+	// Umur response gabungan per lokasi; 0 = tidak di-cache
+	ConditionsTTL time.Duration `yaml:"conditions_ttl"`
+}
+
+type RedisConfig struct {
+	// Misal redis://:password@localhost:6379/0
+	URL string `yaml:"url"`
+	// Awalan key, supaya beberapa environment bisa berbagi satu Redis
+	KeyPrefix string `yaml:"key_prefix"`
+}
+
+func defaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Backend:       CacheBackendMemory,
+		Redis:         RedisConfig{KeyPrefix: "titikkondisi"},
+		ConditionsTTL: 10 * time.Minute,
+	}
+}
+
+func (c CacheConfig) validate() error {
+	switch c.Backend {
+	case CacheBackendMemory:
+	case CacheBackendRedis:
+		if c.Redis.URL == "" {
+			return fmt.Errorf("cache.redis.url is required for the redis backend")
+		}
+		if _, err := redis.ParseURL(c.Redis.URL); err != nil {
+			return fmt.Errorf("cache.redis.url is invalid: %v", err)
+		}
+	default:
+		return fmt.Errorf("cache.backend must be memory or redis, got %q", c.Backend)
+	}
+	if c.ConditionsTTL < 0 {
+		return fmt.Errorf("cache.conditions_ttl must not be negative")
+	}
+	return nil
+}
+
+// TTL yang berlaku untuk satu jenis data, termasuk yang bisa diatur lewat config
+func (c CacheConfig) ttl(dataType string) time.Duration {
+	if dataType == services.CacheConditions {
+		return c.ConditionsTTL
+	}
+	return services.CacheTTLs[dataType]
+}
+
+// Semua jenis data yang bisa ada di cache, terurut
+func cacheTypes() []string {
+	return slices.Sorted(maps.Keys(services.CacheTTLs))
+}
+
+// --- Pilih backend sesuai config; memory dibersihkan berkala sampai shutdown ---
+func newCacheStore(cfg CacheConfig, lc *lifecycle, config func() *Config) (cache.Store, error) {
+	if cfg.Backend == CacheBackendRedis {
+		return cache.NewRedis(cache.RedisOptions{
+			URL:       cfg.Redis.URL,
+			KeyPrefix: cfg.Redis.KeyPrefix,
+			TTL:       func(dataType string) time.Duration { return config().Cache.ttl(dataType) },
+			Observe:   cacheLookups.record,
+		}, lc)
+	}
+	store := cache.NewMemory(cacheLookups.record)
+	store.StartJanitor(lc, time.Minute)
+	return store, nil
+}
+
+// --- Hit/miss per jenis data di instance ini, juga diekspor ke Prometheus ---
+var (
+	cacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "cache_lookups_total",
+		Help:      "Cache lookups by data type and result (hit, miss).",
+	}, []string{"type", "result"})
+
+	cacheLookups = &cacheCounters{hits: map[string]int64{}, misses: map[string]int64{}}
+)
+
+type cacheCounters struct {
+	mu     sync.Mutex
+	hits   map[string]int64
+	misses map[string]int64
+}
+
+func (c *cacheCounters) record(dataType string, hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hit {
+		c.hits[dataType]++
+		cacheLookupsTotal.WithLabelValues(dataType, "hit").Inc()
+	} else {
+		c.misses[dataType]++
+		cacheLookupsTotal.WithLabelValues(dataType, "miss").Inc()
+	}
+}
+
+func (c *cacheCounters) get(dataType string) (hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits[dataType], c.misses[dataType]
+}
+
+// --- Statistik untuk /admin/cache ---
+type CacheTypeStats struct {
+	Entries       int     `json:"entries"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	HitRate       float64 `json:"hit_rate"`
+	OldestAgeSecs float64 `json:"oldest_age_seconds"`
+	TTLSecs       float64 `json:"ttl_seconds"`
+}
+
+type CacheEntryInfo struct {
+	Type          string  `json:"type"`
+	Location      string  `json:"location"`
+	AgeSecs       float64 `json:"age_seconds"`
+	ExpiresInSecs float64 `json:"expires_in_seconds"`
+	Hits          int64   `json:"hits"`
+	StoredAt      string  `json:"stored_at"`
+}
+
+type CacheStats struct {
+	Entries int                       `json:"entries"`
+	HitRate float64                   `json:"hit_rate"`
+	Types   map[string]CacheTypeStats `json:"types"`
+	Items   []CacheEntryInfo          `json:"items,omitempty"`
+}
+
+func cacheStats(ctx context.Context, store cache.Store, cfg CacheConfig, withItems bool, dataType string) (CacheStats, error) {
+	entries, err := store.Items(ctx)
+	if err != nil {
+		return CacheStats{}, err
+	}
+	now := time.Now()
+
+	stats := CacheStats{Types: map[string]CacheTypeStats{}}
+	for _, name := range cacheTypes() {
+		stats.Types[name] = CacheTypeStats{TTLSecs: cfg.ttl(name).Seconds()}
+	}
+	for _, entry := range entries {
+		t := stats.Types[entry.DataType]
+		t.Entries++
+		t.OldestAgeSecs = max(t.OldestAgeSecs, model.Round1(now.Sub(entry.StoredAt).Seconds()))
+		stats.Types[entry.DataType] = t
+		stats.Entries++
+
+		if withItems && (dataType == "" || entry.DataType == dataType) {
+			stats.Items = append(stats.Items, CacheEntryInfo{
+				Type:          entry.DataType,
+				Location:      entry.Location,
+				AgeSecs:       model.Round1(now.Sub(entry.StoredAt).Seconds()),
+				ExpiresInSecs: model.Round1(entry.Expires.Sub(now).Seconds()),
+				Hits:          entry.Hits,
+				StoredAt:      entry.StoredAt.UTC().Format(time.RFC3339),
+			})
+		}
+	}
+
+	var totalHits, totalLookups int64
+	for name, t := range stats.Types {
+		t.Hits, t.Misses = cacheLookups.get(name)
+		if lookups := t.Hits + t.Misses; lookups > 0 {
+			t.HitRate = model.Round2(float64(t.Hits) / float64(lookups))
+			totalHits += t.Hits
+			totalLookups += lookups
+		}
+		stats.Types[name] = t
+	}
+	if totalLookups > 0 {
+		stats.HitRate = model.Round2(float64(totalHits) / float64(totalLookups))
+	}
+	sort.Slice(stats.Items, func(i, j int) bool {
+		if stats.Items[i].Type != stats.Items[j].Type {
+			return stats.Items[i].Type < stats.Items[j].Type
+		}
+		return stats.Items[i].Location < stats.Items[j].Location
+	})
+	return stats, nil
+}
+
+// --- Admin: GET /admin/cache (statistik), DELETE /admin/cache (invalidasi) ---
+// Filter opsional: ?type=<jenis data, lihat services.CacheTTLs> dan ?lat=..&lon=..
+// Berguna kalau upstream sempat mengirim data yang salah.
+func registerCacheRoutes(g *gin.RouterGroup, store cache.Store, config func() *Config) {
+	g.GET("", func(c *gin.Context) {
+		dataType, ok := cacheTypeFilter(c)
+		if !ok {
+			return
+		}
+		withItems := c.Query("items") == "true" || dataType != ""
+		stats, err := cacheStats(c.Request.Context(), store, config().Cache, withItems, dataType)
+		if err != nil {
+			abortCacheUnavailable(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, stats)
+	})
+
+	g.DELETE("", func(c *gin.Context) {
+		dataType, ok := cacheTypeFilter(c)
+		if !ok {
+			return
+		}
+		location := ""
+		if c.Query("lat") != "" || c.Query("lon") != "" {
+			lat, lon, err := handlers.ParseCoordinates(c.Query("lat"), c.Query("lon"))
+			if err != nil {
+				handlers.AbortBadRequest(c, handlers.ErrCodeInvalidLocation, err)
+				return
+			}
+			location = services.CacheLocation(lat, lon)
+		}
+		removed, err := store.Invalidate(c.Request.Context(), dataType, location)
+		if err != nil {
+			abortCacheUnavailable(c, err)
+			return
+		}
+		slog.InfoContext(c.Request.Context(), "cache invalidated",
+			"type", dataType, "location", location, "removed", removed)
+		c.JSON(http.StatusOK, gin.H{"removed": removed})
+	})
+}
+
+func cacheTypeFilter(c *gin.Context) (string, bool) {
+	dataType := c.Query("type")
+	if types := cacheTypes(); dataType != "" && !slices.Contains(types, dataType) {
+		handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest,
+			fmt.Errorf("unknown cache type %q (use one of %s)", dataType, strings.Join(types, ", ")))
+		return "", false
+	}
+	return dataType, true
+}
+
+func abortCacheUnavailable(c *gin.Context, err error) {
+	slog.ErrorContext(c.Request.Context(), "cache backend unavailable", "error", err)
+	handlers.AbortWithError(c, http.StatusServiceUnavailable, handlers.APIError{
+		Code:      ErrCodeCacheUnavailable,
+		Message:   "cache backend is unavailable",
+		Retryable: true,
+	})
+}
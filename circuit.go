@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+var upstreamCircuitOpen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: metricsNamespace,
+	Name:      "upstream_circuit_open",
+	Help:      "1 while the circuit breaker of an upstream provider is open or half-open.",
+}, []string{"provider"})
+
+// --- Perubahan state circuit breaker client provider: gauge + log ---
+func observeCircuit(provider, from, to string) {
+	open := 0.0
+	if to != providers.CircuitClosed {
+		open = 1
+	}
+	upstreamCircuitOpen.WithLabelValues(provider).Set(open)
+
+	switch to {
+	case providers.CircuitOpen:
+		slog.Warn("upstream circuit opened", "provider", provider, "from", from)
+	case providers.CircuitHalfOpen:
+		slog.Info("probing upstream after circuit cooldown", "provider", provider)
+	default:
+		slog.Info("upstream circuit closed", "provider", provider)
+	}
+}
@@ -0,0 +1,461 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/notify"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Konfigurasi aplikasi: default < file YAML (opsional) < environment variable ---
+type Config struct {
+	Server        ServerConfig        `yaml:"server"`
+	Upstream      UpstreamConfig      `yaml:"upstream"`
+	Providers     providers.Config    `yaml:"providers"`
+	Logging       LoggingConfig       `yaml:"logging"`
+	Sentry        SentryConfig        `yaml:"sentry"`
+	Admin         AdminConfig         `yaml:"admin"`
+	CORS          CORSConfig          `yaml:"cors"`
+	Limits        LimitsConfig        `yaml:"limits"`
+	Cache         CacheConfig         `yaml:"cache"`
+	Scoring       indices.Scoring     `yaml:"scoring"`
+	Database      DatabaseConfig      `yaml:"database"`
+	APIKeys       APIKeysConfig       `yaml:"api_keys"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Subscriptions SubscriptionsConfig `yaml:"subscriptions"`
+	Email         notify.SMTPConfig   `yaml:"email"`
+	Push          notify.FCMConfig    `yaml:"push"`
+
+	// Zona waktu fallback kalau zona lokasi tidak diketahui
+	DefaultTimezone string `yaml:"default_timezone"`
+
+	// Feature flag: on/off atau rollout bertahap per persentase client
+	Features map[string]FeatureFlag `yaml:"features"`
+}
+
+type ServerConfig struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	// Jeda antara /readyz mulai 503 dan listener ditutup, supaya load
+	// balancer sempat berhenti mengirim traffic; 0 = langsung ditutup
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	TLS        TLSConfig     `yaml:"tls"`
+}
+
+type UpstreamConfig struct {
+	Timeout          time.Duration `yaml:"timeout"`
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout"`
+	ReadinessTTL     time.Duration `yaml:"readiness_ttl"`
+	// Retry Open-Meteo & sunrise-sunset saat gagal sementara
+	Retry providers.RetryPolicy `yaml:"retry"`
+	// Circuit breaker per provider saat gagal berturut-turut
+	Circuit providers.CircuitPolicy `yaml:"circuit"`
+	// Bagian opsional yang gagal: "partial" (meta.errors) atau "fail_fast"
+	FanoutPolicy string `yaml:"fanout_policy"`
+	// Timeout per task fanout di services; 0 = cukup REQUEST_TIMEOUT
+	TaskTimeout time.Duration `yaml:"task_timeout"`
+}
+
+type LoggingConfig struct {
+	Level string `yaml:"level"`
+}
+
+type AdminConfig struct {
+	Token string `yaml:"token"`
+}
+
+type SentryConfig struct {
+	DSN         string `yaml:"dsn"`
+	Environment string `yaml:"environment"`
+	Release     string `yaml:"release"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 10 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			DrainDelay:        5 * time.Second,
+		},
+		Upstream: UpstreamConfig{
+			Timeout:          10 * time.Second,
+			ReadinessTimeout: 3 * time.Second,
+			ReadinessTTL:     15 * time.Second,
+			Retry:            providers.DefaultRetryPolicy(),
+			Circuit:          providers.DefaultCircuitPolicy(),
+			FanoutPolicy:     services.FanoutPartial,
+		},
+		Providers:       providers.DefaultConfig(),
+		Logging:         LoggingConfig{Level: "info"},
+		CORS:            defaultCORSConfig(),
+		Limits:          defaultLimitsConfig(),
+		Cache:           defaultCacheConfig(),
+		Scoring:         indices.DefaultScoring(),
+		APIKeys:         defaultAPIKeysConfig(),
+		RateLimit:       defaultRateLimitConfig(),
+		Subscriptions:   defaultSubscriptionsConfig(),
+		Email:           notify.DefaultSMTPConfig(),
+		Push:            notify.DefaultFCMConfig(),
+		DefaultTimezone: "Asia/Jakarta",
+		Features: map[string]FeatureFlag{
+			FeatureGraphQL: {Enabled: true, Percentage: 100},
+			FeatureDocs:    {Enabled: true, Percentage: 100},
+			FeatureMetrics: {Enabled: true, Percentage: 100},
+		},
+	}
+}
+
+// --- Load konfigurasi dari file (kalau ada) lalu timpa dengan env ---
+func loadConfig(path string) (*Config, error) {
+	cfg := defaultConfig()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func applyEnv(cfg *Config) error {
+	envString("ADDR", &cfg.Server.Addr)
+	if port := os.Getenv("PORT"); port != "" {
+		cfg.Server.Addr = ":" + port
+	}
+	envString("PROVIDER_MODE", &cfg.Providers.Mode)
+	envString("PROVIDER_FIXTURES_DIR", &cfg.Providers.FixturesDir)
+	envString("OPEN_METEO_BASE_URL", &cfg.Providers.OpenMeteo.BaseURL)
+	envString("OPEN_METEO_API_KEY", &cfg.Providers.OpenMeteo.APIKey)
+	envString("AIR_QUALITY_BASE_URL", &cfg.Providers.AirQuality.BaseURL)
+	envString("AIR_QUALITY_API_KEY", &cfg.Providers.AirQuality.APIKey)
+	envString("SUNRISE_SUNSET_BASE_URL", &cfg.Providers.SunriseSunset.BaseURL)
+	envString("GEOCODING_BASE_URL", &cfg.Providers.Geocoding.BaseURL)
+	envString("REVERSE_GEOCODING_BASE_URL", &cfg.Providers.ReverseGeocoding.BaseURL)
+	envString("MARINE_BASE_URL", &cfg.Providers.Marine.BaseURL)
+	envString("ARCHIVE_BASE_URL", &cfg.Providers.Archive.BaseURL)
+	envString("ARCHIVE_API_KEY", &cfg.Providers.Archive.APIKey)
+	envString("SPACE_WEATHER_BASE_URL", &cfg.Providers.SpaceWeather.BaseURL)
+	envString("BMKG_NOWCAST_BASE_URL", &cfg.Providers.BMKGNowcast.BaseURL)
+	envString("BMKG_BASE_URL", &cfg.Providers.BMKG.BaseURL)
+	envString("BMKG_QUAKES_BASE_URL", &cfg.Providers.BMKGQuakes.BaseURL)
+	envString("USGS_BASE_URL", &cfg.Providers.USGS.BaseURL)
+	envString("MAGMA_BASE_URL", &cfg.Providers.MAGMA.BaseURL)
+	envString("LIGHT_POLLUTION_BASE_URL", &cfg.Providers.LightPollution.BaseURL)
+	envString("LIGHT_POLLUTION_API_KEY", &cfg.Providers.LightPollution.APIKey)
+	envString("DEFAULT_TIMEZONE", &cfg.DefaultTimezone)
+	envString("CACHE_BACKEND", &cfg.Cache.Backend)
+	envString("REDIS_URL", &cfg.Cache.Redis.URL)
+	envString("REDIS_KEY_PREFIX", &cfg.Cache.Redis.KeyPrefix)
+	envString("DATABASE_URL", &cfg.Database.URL)
+	envString("API_KEYS_MODE", &cfg.APIKeys.Mode)
+	envString("SMTP_HOST", &cfg.Email.Host)
+	envString("SMTP_USERNAME", &cfg.Email.Username)
+	envString("SMTP_PASSWORD", &cfg.Email.Password)
+	envString("EMAIL_FROM", &cfg.Email.From)
+	envString("FCM_PROJECT_ID", &cfg.Push.ProjectID)
+	envString("FCM_CREDENTIALS_FILE", &cfg.Push.CredentialsFile)
+	envString("FCM_BASE_URL", &cfg.Push.BaseURL)
+	envString("LOG_LEVEL", &cfg.Logging.Level)
+	envString("UPSTREAM_FANOUT_POLICY", &cfg.Upstream.FanoutPolicy)
+	envString("SENTRY_DSN", &cfg.Sentry.DSN)
+	envString("SENTRY_ENVIRONMENT", &cfg.Sentry.Environment)
+	envString("SENTRY_RELEASE", &cfg.Sentry.Release)
+	envString("ADMIN_TOKEN", &cfg.Admin.Token)
+	envString("TLS_CERT_FILE", &cfg.Server.TLS.CertFile)
+	envString("TLS_KEY_FILE", &cfg.Server.TLS.KeyFile)
+	envString("TLS_REDIRECT_ADDR", &cfg.Server.TLS.RedirectAddr)
+	envString("TLS_AUTOCERT_EMAIL", &cfg.Server.TLS.Autocert.Email)
+	envString("TLS_AUTOCERT_CACHE_DIR", &cfg.Server.TLS.Autocert.CacheDir)
+	envList("TLS_AUTOCERT_DOMAINS", &cfg.Server.TLS.Autocert.Domains)
+	envList("TRUSTED_PROXIES", &cfg.RateLimit.TrustedProxies)
+	envList("CORS_ALLOWED_ORIGINS", &cfg.CORS.AllowedOrigins)
+	envList("CORS_ALLOWED_METHODS", &cfg.CORS.AllowedMethods)
+	envList("CORS_ALLOWED_HEADERS", &cfg.CORS.AllowedHeaders)
+	envList("CORS_EXPOSED_HEADERS", &cfg.CORS.ExposedHeaders)
+	for name, target := range map[string]*int64{
+		"MAX_BODY_BYTES":               &cfg.Limits.MaxBodyBytes,
+		"MAX_IN_FLIGHT":                &cfg.Limits.MaxInFlight,
+		"MAX_BATCH_POINTS":             &cfg.Limits.MaxBatchPoints,
+		"BATCH_CONCURRENCY":            &cfg.Limits.BatchConcurrency,
+		"API_KEYS_DEFAULT_DAILY_QUOTA": &cfg.APIKeys.DefaultDailyQuota,
+		"RATE_LIMIT_PER_MINUTE":        &cfg.RateLimit.RequestsPerMinute,
+		"RATE_LIMIT_BURST":             &cfg.RateLimit.Burst,
+		"SMTP_PORT":                    &cfg.Email.Port,
+	} {
+		if err := envInt64(name, target); err != nil {
+			return err
+		}
+	}
+	if err := envBool("CORS_ALLOW_CREDENTIALS", &cfg.CORS.AllowCredentials); err != nil {
+		return err
+	}
+	if err := envBool("WEBHOOK_ALLOW_PRIVATE", &cfg.Subscriptions.AllowPrivateWebhooks); err != nil {
+		return err
+	}
+
+	durations := map[string]*time.Duration{
+		"READ_HEADER_TIMEOUT":          &cfg.Server.ReadHeaderTimeout,
+		"SHUTDOWN_TIMEOUT":             &cfg.Server.ShutdownTimeout,
+		"DRAIN_DELAY":                  &cfg.Server.DrainDelay,
+		"UPSTREAM_TIMEOUT":             &cfg.Upstream.Timeout,
+		"UPSTREAM_READINESS_TIMEOUT":   &cfg.Upstream.ReadinessTimeout,
+		"UPSTREAM_READINESS_CACHE_TTL": &cfg.Upstream.ReadinessTTL,
+		"CORS_MAX_AGE":                 &cfg.CORS.MaxAge,
+		"REQUEST_TIMEOUT":              &cfg.Limits.Timeouts.Default,
+		"BATCH_REQUEST_TIMEOUT":        &cfg.Limits.Timeouts.Batch,
+		"CONDITIONS_CACHE_TTL":         &cfg.Cache.ConditionsTTL,
+		"UPSTREAM_RETRY_BACKOFF":       &cfg.Upstream.Retry.Backoff,
+		"UPSTREAM_RETRY_MAX_BACKOFF":   &cfg.Upstream.Retry.MaxBackoff,
+		"UPSTREAM_CIRCUIT_COOLDOWN":    &cfg.Upstream.Circuit.Cooldown,
+		"UPSTREAM_TASK_TIMEOUT":        &cfg.Upstream.TaskTimeout,
+		"SUBSCRIPTIONS_INTERVAL":       &cfg.Subscriptions.Interval,
+		"WEBHOOK_TIMEOUT":              &cfg.Subscriptions.WebhookTimeout,
+		"SMTP_TIMEOUT":                 &cfg.Email.Timeout,
+		"FCM_TIMEOUT":                  &cfg.Push.Timeout,
+	}
+	for name, target := range durations {
+		if err := envDuration(name, target); err != nil {
+			return err
+		}
+	}
+
+	attempts := int64(cfg.Upstream.Retry.Attempts)
+	if err := envInt64("UPSTREAM_RETRY_ATTEMPTS", &attempts); err != nil {
+		return err
+	}
+	cfg.Upstream.Retry.Attempts = int(attempts)
+	if err := envFloat("UPSTREAM_RETRY_JITTER", &cfg.Upstream.Retry.Jitter); err != nil {
+		return err
+	}
+	failures := int64(cfg.Upstream.Circuit.Failures)
+	if err := envInt64("UPSTREAM_CIRCUIT_FAILURES", &failures); err != nil {
+		return err
+	}
+	cfg.Upstream.Circuit.Failures = int(failures)
+
+	// FEATURE_GRAPHQL=false, FEATURE_NEW_FORMULA=25%, dst.
+	if cfg.Features == nil {
+		cfg.Features = map[string]FeatureFlag{}
+	}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		feature, ok := strings.CutPrefix(name, "FEATURE_")
+		if !ok || feature == "" {
+			continue
+		}
+		flag, err := parseFeatureFlag(value)
+		if err != nil {
+			return fmt.Errorf("%s %v", name, err)
+		}
+		cfg.Features[strings.ToLower(feature)] = flag
+	}
+	return nil
+}
+
+func envString(name string, target *string) {
+	if v, ok := os.LookupEnv(name); ok {
+		*target = v
+	}
+}
+
+// --- Daftar dipisah koma, spasi di sekitar item dibuang ---
+func envList(name string, target *[]string) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*target = items
+}
+
+func envInt64(name string, target *int64) error {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%s must be an integer, got %q", name, v)
+	}
+	*target = n
+	return nil
+}
+
+func envFloat(name string, target *float64) error {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("%s must be a number, got %q", name, v)
+	}
+	*target = f
+	return nil
+}
+
+func envBool(name string, target *bool) error {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%s must be a boolean, got %q", name, v)
+	}
+	*target = b
+	return nil
+}
+
+func envDuration(name string, target *time.Duration) error {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration like 10s, got %q", name, v)
+	}
+	*target = d
+	return nil
+}
+
+func (c *Config) validate() error {
+	if c.Server.Addr == "" {
+		return fmt.Errorf("server.addr is required")
+	}
+	if c.Server.DrainDelay < 0 {
+		return fmt.Errorf("server.drain_delay must not be negative, got %s", c.Server.DrainDelay)
+	}
+	if err := c.Server.TLS.validate(); err != nil {
+		return err
+	}
+	if err := c.CORS.validate(); err != nil {
+		return err
+	}
+	if err := c.Limits.validate(); err != nil {
+		return err
+	}
+	if err := c.Cache.validate(); err != nil {
+		return err
+	}
+	if err := c.Database.validate(); err != nil {
+		return err
+	}
+	if err := c.APIKeys.validate(); err != nil {
+		return err
+	}
+	if err := c.RateLimit.validate(); err != nil {
+		return err
+	}
+	if err := c.Subscriptions.validate(); err != nil {
+		return err
+	}
+	if err := c.Email.Validate(); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if err := c.Push.Validate(); err != nil {
+		return fmt.Errorf("push: %w", err)
+	}
+	if err := c.Upstream.Retry.Validate(); err != nil {
+		return fmt.Errorf("upstream.retry: %w", err)
+	}
+	if err := c.Upstream.Circuit.Validate(); err != nil {
+		return fmt.Errorf("upstream.circuit: %w", err)
+	}
+	switch c.Upstream.FanoutPolicy {
+	case services.FanoutPartial, services.FanoutFailFast:
+	default:
+		return fmt.Errorf("upstream.fanout_policy must be %q or %q, got %q", services.FanoutPartial, services.FanoutFailFast, c.Upstream.FanoutPolicy)
+	}
+	if c.Upstream.TaskTimeout < 0 {
+		return fmt.Errorf("upstream.task_timeout must be >= 0, got %s", c.Upstream.TaskTimeout)
+	}
+	if err := c.Scoring.Validate(); err != nil {
+		return fmt.Errorf("scoring: %w", err)
+	}
+	if _, err := time.LoadLocation(c.DefaultTimezone); err != nil {
+		return fmt.Errorf("default_timezone %q is invalid: %w", c.DefaultTimezone, err)
+	}
+	if _, err := parseLogLevel(c.Logging.Level); err != nil {
+		return err
+	}
+	switch c.Providers.Mode {
+	case providers.ModeLive, providers.ModeMock:
+	case providers.ModeRecord, providers.ModeReplay:
+		if c.Providers.FixturesDir == "" {
+			return fmt.Errorf("providers.fixtures_dir is required in %s mode", c.Providers.Mode)
+		}
+	default:
+		return fmt.Errorf("providers.mode must be one of live, mock, record or replay, got %q", c.Providers.Mode)
+	}
+	for name, p := range map[string]providers.Endpoint{
+		"open_meteo":        c.Providers.OpenMeteo,
+		"air_quality":       c.Providers.AirQuality,
+		"sunrise_sunset":    c.Providers.SunriseSunset,
+		"geocoding":         c.Providers.Geocoding,
+		"reverse_geocoding": c.Providers.ReverseGeocoding,
+		"marine":            c.Providers.Marine,
+		"archive":           c.Providers.Archive,
+		"space_weather":     c.Providers.SpaceWeather,
+		"bmkg_nowcast":      c.Providers.BMKGNowcast,
+		"bmkg":              c.Providers.BMKG,
+		"bmkg_quakes":       c.Providers.BMKGQuakes,
+		"usgs":              c.Providers.USGS,
+		"magma":             c.Providers.MAGMA,
+		"light_pollution":   c.Providers.LightPollution,
+	} {
+		if !strings.HasPrefix(p.BaseURL, "http://") && !strings.HasPrefix(p.BaseURL, "https://") {
+			return fmt.Errorf("providers.%s.base_url must be an http(s) URL, got %q", name, p.BaseURL)
+		}
+	}
+	for i, area := range c.Providers.BMKGAreas {
+		if area.Code == "" {
+			return fmt.Errorf("providers.bmkg_areas[%d].code is required", i)
+		}
+		if area.Latitude < -90 || area.Latitude > 90 || area.Longitude < -180 || area.Longitude > 180 {
+			return fmt.Errorf("providers.bmkg_areas[%d] (%s) has invalid coordinates", i, area.Code)
+		}
+	}
+	for name, flag := range c.Features {
+		if err := flag.validate(); err != nil {
+			return fmt.Errorf("features.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func parseLogLevel(raw string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(raw)); err != nil {
+		return 0, fmt.Errorf("logging.level %q is invalid (use debug, info, warn or error)", raw)
+	}
+	return level, nil
+}
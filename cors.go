@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+)
+
+// --- CORS supaya web app bisa memanggil API langsung dari browser ---
+// AllowedOrigins kosong = CORS mati (tidak ada header yang ditambahkan).
+// Origin boleh persis ("https://app.titikkondisi.id"), wildcard subdomain
+// ("https://*.titikkondisi.id") atau "*" untuk semua.
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+func defaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowedHeaders: []string{"Content-Type", "Authorization", requestIDHeader, clientIDHeader, idempotencyHeader, handlers.APIKeyHeader},
+		ExposedHeaders: []string{requestIDHeader, "Deprecation", "Sunset", "Link", idempotencyReplayedHeader, quotaLimitHeader, quotaRemainingHeader, quotaResetHeader},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+func (c CORSConfig) validate() error {
+	if c.AllowCredentials {
+		for _, origin := range c.AllowedOrigins {
+			if origin == "*" {
+				return errors.New(`cors: allow_credentials cannot be combined with allowed_origins "*"`)
+			}
+		}
+	}
+	return nil
+}
+
+func (c CORSConfig) originAllowed(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(allowed, "*."); ok {
+			rest, found := strings.CutPrefix(strings.ToLower(origin), strings.ToLower(prefix))
+			if found && strings.HasSuffix(rest, "."+strings.ToLower(suffix)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// --- Middleware: dibaca dari config aktif per request supaya ikut hot reload ---
+func corsMiddleware(config func() *Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config().CORS
+		origin := c.GetHeader("Origin")
+		if origin == "" || len(cfg.AllowedOrigins) == 0 {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		if !cfg.originAllowed(origin) {
+			// Bukan error: browser sendiri yang akan memblokir response
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		if len(cfg.AllowedOrigins) == 1 && cfg.AllowedOrigins[0] == "*" && !cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		// --- Preflight: jawab langsung tanpa masuk ke handler ---
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+			if len(cfg.AllowedHeaders) > 0 {
+				h.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+			}
+			if cfg.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if len(cfg.ExposedHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+		}
+		c.Next()
+	}
+}
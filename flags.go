@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+)
+
+// --- Nama feature flag yang dikenal ---
+const (
+	FeatureGraphQL = "graphql"
+	FeatureDocs    = "docs"
+	FeatureMetrics = "metrics"
+	FeatureStatus  = "status"
+)
+
+// --- Satu feature flag: on/off plus persentase rollout bertahap ---
+// Di YAML bisa ditulis singkat (graphql: true) atau lengkap
+// (new_formula: {enabled: true, percentage: 10}).
+type FeatureFlag struct {
+	Enabled    bool `yaml:"enabled" json:"enabled"`
+	Percentage int  `yaml:"percentage" json:"percentage"`
+}
+
+func (f *FeatureFlag) UnmarshalYAML(unmarshal func(any) error) error {
+	var enabled bool
+	if err := unmarshal(&enabled); err == nil {
+		*f = FeatureFlag{Enabled: enabled, Percentage: 100}
+		return nil
+	}
+	type plain FeatureFlag
+	flag := plain{Enabled: true, Percentage: 100}
+	if err := unmarshal(&flag); err != nil {
+		return err
+	}
+	*f = FeatureFlag(flag)
+	return nil
+}
+
+// --- Parse nilai FEATURE_<NAME>: true/false atau persentase seperti 25% ---
+func parseFeatureFlag(raw string) (FeatureFlag, error) {
+	if pct, ok := strings.CutSuffix(strings.TrimSpace(raw), "%"); ok {
+		n, err := strconv.Atoi(pct)
+		if err != nil {
+			return FeatureFlag{}, fmt.Errorf("invalid percentage %q", raw)
+		}
+		return FeatureFlag{Enabled: true, Percentage: n}, nil
+	}
+	enabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		return FeatureFlag{}, fmt.Errorf("must be a boolean or a percentage like 25%%, got %q", raw)
+	}
+	return FeatureFlag{Enabled: enabled, Percentage: 100}, nil
+}
+
+func (f FeatureFlag) validate() error {
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return fmt.Errorf("percentage must be between 0 and 100, got %d", f.Percentage)
+	}
+	return nil
+}
+
+// --- Aktif untuk subject tertentu? Bucket stabil per (flag, subject) ---
+// Subject yang sama selalu dapat hasil yang sama, jadi client tidak
+// bolak-balik antara perilaku lama dan baru selama rollout.
+func (f FeatureFlag) activeFor(name, subject string) bool {
+	if !f.Enabled || f.Percentage <= 0 {
+		return false
+	}
+	if f.Percentage >= 100 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + subject))
+	return int(h.Sum32()%100) < f.Percentage
+}
+
+// --- Override runtime dari /admin/flags; menang atas config dan tahan reload ---
+type flagStore struct {
+	config func() *Config
+
+	mu        sync.RWMutex
+	overrides map[string]FeatureFlag
+}
+
+func newFlagStore(config func() *Config) *flagStore {
+	return &flagStore{config: config, overrides: map[string]FeatureFlag{}}
+}
+
+// Flag yang tidak disebut di config maupun override dianggap aktif penuh.
+func (s *flagStore) lookup(cfg *Config, name string) (FeatureFlag, string) {
+	s.mu.RLock()
+	override, ok := s.overrides[name]
+	s.mu.RUnlock()
+	if ok {
+		return override, "override"
+	}
+	if flag, ok := cfg.Features[name]; ok {
+		return flag, "config"
+	}
+	return FeatureFlag{Enabled: true, Percentage: 100}, "default"
+}
+
+func (s *flagStore) Enabled(name, subject string) bool {
+	flag, _ := s.lookup(s.config(), name)
+	return flag.activeFor(name, subject)
+}
+
+func (s *flagStore) Set(name string, flag FeatureFlag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[name] = flag
+}
+
+func (s *flagStore) Clear(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.overrides[name]
+	delete(s.overrides, name)
+	return ok
+}
+
+type FlagStatus struct {
+	Name string `json:"name"`
+	FeatureFlag
+	Source string `json:"source"` // default, config atau override
+}
+
+func (s *flagStore) List() []FlagStatus {
+	cfg := s.config()
+	names := map[string]bool{FeatureGraphQL: true, FeatureDocs: true, FeatureMetrics: true, FeatureStatus: true}
+	for name := range cfg.Features {
+		names[name] = true
+	}
+	s.mu.RLock()
+	for name := range s.overrides {
+		names[name] = true
+	}
+	s.mu.RUnlock()
+
+	list := make([]FlagStatus, 0, len(names))
+	for name := range names {
+		flag, source := s.lookup(cfg, name)
+		list = append(list, FlagStatus{Name: name, FeatureFlag: flag, Source: source})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// --- Subject rollout: header X-Client-ID kalau ada, selain itu IP client ---
+const clientIDHeader = "X-Client-ID"
+
+func rolloutSubject(c *gin.Context) string {
+	if id := c.GetHeader(clientIDHeader); id != "" {
+		return id
+	}
+	return c.ClientIP()
+}
+
+// --- Gate route dengan flag; dicek per request supaya ikut hot reload ---
+func (s *flagStore) Require(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.Enabled(name, rolloutSubject(c)) {
+			handlers.NoRoute(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// --- Admin: lihat dan ubah flag tanpa deploy ---
+func registerFlagRoutes(g *gin.RouterGroup, flags *flagStore) {
+	g.GET("", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"flags": flags.List()})
+	})
+	g.PUT("/:name", func(c *gin.Context) {
+		flag := FeatureFlag{Enabled: true, Percentage: 100}
+		if err := c.ShouldBindJSON(&flag); err != nil {
+			handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest, errors.New("body must be {\"enabled\": bool, \"percentage\": 0-100}"))
+			return
+		}
+		if err := flag.validate(); err != nil {
+			handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest, err)
+			return
+		}
+		name := strings.ToLower(c.Param("name"))
+		flags.Set(name, flag)
+		c.JSON(http.StatusOK, FlagStatus{Name: name, FeatureFlag: flag, Source: "override"})
+	})
+	g.DELETE("/:name", func(c *gin.Context) {
+		name := strings.ToLower(c.Param("name"))
+		if !flags.Clear(name) {
+			handlers.AbortWithError(c, http.StatusNotFound, handlers.APIError{
+				Code: handlers.ErrCodeNotFound, Message: "no runtime override for flag " + name,
+			})
+			return
+		}
+		flag, source := flags.lookup(flags.config(), name)
+		c.JSON(http.StatusOK, FlagStatus{Name: name, FeatureFlag: flag, Source: source})
+	})
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+	HealthDraining = "draining"
+)
+
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status    string             `json:"status"`
+	CheckedAt time.Time          `json:"checked_at"`
+	Checks    []DependencyStatus `json:"checks,omitempty"`
+}
+
+// --- Satu dependency yang dicek /readyz ---
+// Critical=true artinya instance ini tidak bisa melayani tanpa dependency
+// tersebut (cache, DB) sehingga /readyz balas 503. Upstream publik tidak
+// critical: kalau Open-Meteo down, semua instance sama-sama kena, jadi cukup
+// dilaporkan "degraded".
+type readinessCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+type readinessChecker struct {
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu     sync.Mutex
+	checks []readinessCheck
+	last   HealthResponse
+}
+
+func newReadinessChecker(timeout, ttl time.Duration) *readinessChecker {
+	return &readinessChecker{timeout: timeout, ttl: ttl}
+}
+
+func (r *readinessChecker) Register(check readinessCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+	r.last = HealthResponse{}
+}
+
+// --- Jalankan semua check paralel; hasil di-cache sebentar supaya probe tidak membanjiri upstream ---
+func (r *readinessChecker) Status(ctx context.Context) HealthResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.last.CheckedAt.IsZero() && time.Since(r.last.CheckedAt) < r.ttl {
+		return r.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	results := make([]DependencyStatus, len(r.checks))
+	var wg sync.WaitGroup
+	for i, check := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := check.Check(ctx)
+			results[i] = DependencyStatus{
+				Name:      check.Name,
+				Status:    HealthOK,
+				Critical:  check.Critical,
+				LatencyMS: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				results[i].Status = HealthDown
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	overall := HealthOK
+	for _, res := range results {
+		if res.Status == HealthOK {
+			continue
+		}
+		if res.Critical {
+			overall = HealthDown
+			break
+		}
+		overall = HealthDegraded
+	}
+
+	r.last = HealthResponse{Status: overall, CheckedAt: time.Now().UTC(), Checks: results}
+	return r.last
+}
+
+// --- /healthz: proses hidup dan bisa menjawab request ---
+func healthzHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: HealthOK, CheckedAt: time.Now().UTC()})
+}
+
+// --- /readyz: status per dependency, 503 kalau ada dependency critical yang down atau sedang shutdown ---
+func readyzHandler(checker *readinessChecker, lc *lifecycle) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if lc.Draining() {
+			c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: HealthDraining, CheckedAt: time.Now().UTC()})
+			return
+		}
+		status := checker.Status(c.Request.Context())
+		code := http.StatusOK
+		if status.Status == HealthDown {
+			code = http.StatusServiceUnavailable
+		}
+		c.JSON(code, status)
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/cache"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+)
+
+// --- Idempotency-Key untuk POST yang membuat resource (saat ini POST /subscriptions) ---
+// Retry dari jaringan mobile yang putus-nyambung dengan key yang sama dijawab
+// dengan response pertama tanpa menjalankan handler lagi. Key yang sama
+// dengan body berbeda ditolak 422; key yang masih diproses ditolak 409.
+const (
+	idempotencyHeader         = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyTTL            = 24 * time.Hour
+	idempotencyMaxKeyLength   = 255
+
+	ErrCodeIdempotencyConflict = "idempotency_conflict"
+	ErrCodeIdempotencyMismatch = "idempotency_key_reused"
+)
+
+type idempotencyEntry struct {
+	Fingerprint [32]byte `json:"fingerprint"`
+	Done        bool     `json:"done"`
+	Status      int      `json:"status,omitempty"`
+	ContentType string   `json:"content_type,omitempty"`
+	Body        []byte   `json:"body,omitempty"`
+}
+
+// --- Penyimpan Idempotency-Key ---
+// Di memori per instance, atau di Redis (dibagi semua instance) kalau
+// cache.backend redis, supaya retry yang mendarat di instance lain tetap
+// dijawab dengan response pertama.
+type idempotencyStore interface {
+	// Ambil slot untuk key; fresh false berarti entry lama dikembalikan
+	Begin(ctx context.Context, key string, fingerprint [32]byte) (entry idempotencyEntry, fresh bool, err error)
+	// Simpan response untuk key; 5xx melepas key supaya client bisa retry
+	Finish(ctx context.Context, key string, entry idempotencyEntry) error
+}
+
+type memoryIdempotencyEntry struct {
+	idempotencyEntry
+	expires time.Time
+}
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{entries: map[string]memoryIdempotencyEntry{}}
+}
+
+func (s *memoryIdempotencyStore) Begin(_ context.Context, key string, fingerprint [32]byte) (idempotencyEntry, bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expires) {
+		return entry.idempotencyEntry, false, nil
+	}
+	entry := idempotencyEntry{Fingerprint: fingerprint}
+	s.entries[key] = memoryIdempotencyEntry{idempotencyEntry: entry, expires: now.Add(idempotencyTTL)}
+	return entry, true, nil
+}
+
+func (s *memoryIdempotencyStore) Finish(_ context.Context, key string, entry idempotencyEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if entry.Status >= http.StatusInternalServerError {
+		delete(s.entries, key)
+		return nil
+	}
+	stored.idempotencyEntry = entry
+	s.entries[key] = stored
+	return nil
+}
+
+func (s *memoryIdempotencyStore) removeExpired() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, entry := range s.entries {
+		if now.After(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// --- Bersihkan key kadaluarsa secara berkala sampai shutdown ---
+func (s *memoryIdempotencyStore) StartJanitor(lc *lifecycle, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.OnShutdown("idempotency janitor", func(context.Context) error {
+		cancel()
+		return nil
+	})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.removeExpired()
+			}
+		}
+	}()
+}
+
+// Key: <prefix>:idempotency:<scope>, entry JSON dengan TTL Redis 24 jam
+// sejak request pertama; slot diambil dengan SET NX supaya hanya satu
+// instance yang menjalankan handler
+type redisIdempotencyStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func (r *redisIdempotencyStore) Begin(ctx context.Context, key string, fingerprint [32]byte) (idempotencyEntry, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, cache.RedisTimeout)
+	defer cancel()
+	entry := idempotencyEntry{Fingerprint: fingerprint}
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return idempotencyEntry{}, false, err
+	}
+	// Entry lama bisa kedaluwarsa di antara SET NX dan GET; coba sekali lagi
+	for range 2 {
+		created, err := r.client.SetNX(ctx, r.prefix+key, value, idempotencyTTL).Result()
+		if err != nil {
+			return idempotencyEntry{}, false, err
+		}
+		if created {
+			return entry, true, nil
+		}
+		raw, err := r.client.Get(ctx, r.prefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return idempotencyEntry{}, false, err
+		}
+		var existing idempotencyEntry
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return idempotencyEntry{}, false, fmt.Errorf("decode idempotency entry: %w", err)
+		}
+		return existing, false, nil
+	}
+	return idempotencyEntry{}, false, errors.New("idempotency key kept expiring")
+}
+
+func (r *redisIdempotencyStore) Finish(ctx context.Context, key string, entry idempotencyEntry) error {
+	// Tetap disimpan/dilepas walau request-nya sudah dibatalkan
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cache.RedisTimeout)
+	defer cancel()
+	if entry.Status >= http.StatusInternalServerError {
+		return r.client.Del(ctx, r.prefix+key).Err()
+	}
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return r.client.SetArgs(ctx, r.prefix+key, value, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+}
+
+// --- Penyimpan key sesuai backend cache yang aktif ---
+func newIdempotencyStore(cfg CacheConfig, upstream cache.Store, lc *lifecycle) idempotencyStore {
+	if store, ok := upstream.(*cache.Redis); ok {
+		return &redisIdempotencyStore{client: store.Client(), prefix: cfg.Redis.KeyPrefix + ":idempotency:"}
+	}
+	memory := newMemoryIdempotencyStore()
+	memory.StartJanitor(lc, 10*time.Minute)
+	return memory
+}
+
+// --- Simpan salinan body response selagi ditulis ke client ---
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// --- Middleware untuk route dengan Idempotent: true; tanpa header, request diproses biasa ---
+func requireIdempotency(store idempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > idempotencyMaxKeyLength {
+			handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest,
+				errors.New("Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				handlers.AbortWithError(c, http.StatusRequestEntityTooLarge, handlers.APIError{
+					Code:    handlers.ErrCodeBodyTooLarge,
+					Message: "request body exceeds " + strconv.FormatInt(tooLarge.Limit, 10) + " bytes",
+				})
+				return
+			}
+			handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Per API key juga, supaya response milik key lain (misal secret
+		// webhook) tidak pernah dijawab ulang ke pemilik lain
+		scope := c.Request.Method + " " + c.FullPath() + " " + key
+		if owner := c.GetHeader(handlers.APIKeyHeader); owner != "" {
+			scope += " " + handlers.HashAPIKey(owner)
+		}
+		fingerprint := sha256.Sum256(append([]byte(c.Request.URL.RawQuery+"\n"), body...))
+		entry, fresh, err := store.Begin(c.Request.Context(), scope, fingerprint)
+		if err != nil {
+			// Store yang gagal tidak boleh mematikan endpoint; request diproses biasa
+			slog.WarnContext(c.Request.Context(), "idempotency key not checked", "error", err)
+			c.Next()
+			return
+		}
+		if !fresh {
+			switch {
+			case entry.Fingerprint != fingerprint:
+				handlers.AbortWithError(c, http.StatusUnprocessableEntity, handlers.APIError{
+					Code:    ErrCodeIdempotencyMismatch,
+					Message: "Idempotency-Key was already used with a different request",
+				})
+			case !entry.Done:
+				handlers.AbortWithError(c, http.StatusConflict, handlers.APIError{
+					Code:      ErrCodeIdempotencyConflict,
+					Message:   "a request with this Idempotency-Key is still being processed",
+					Retryable: true,
+				})
+			default:
+				c.Header(idempotencyReplayedHeader, "true")
+				c.Data(entry.Status, entry.ContentType, entry.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		completed := false
+		defer func() {
+			// Handler panic: lepaskan key supaya retry tidak tertahan 409
+			if !completed {
+				entry.Status = http.StatusInternalServerError
+				finishIdempotency(c, store, scope, entry)
+			}
+		}()
+		c.Next()
+		entry.Done, entry.Status, entry.ContentType, entry.Body = true, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()
+		finishIdempotency(c, store, scope, entry)
+		completed = true
+	}
+}
+
+func finishIdempotency(c *gin.Context, store idempotencyStore, scope string, entry idempotencyEntry) {
+	if err := store.Finish(c.Request.Context(), scope, entry); err != nil {
+		slog.WarnContext(c.Request.Context(), "idempotency response not stored", "status", entry.Status, "error", err)
+	}
+}
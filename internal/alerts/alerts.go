@@ -0,0 +1,118 @@
+// Package alerts menyusun peringatan cuaca untuk satu lokasi dari peringatan
+// resmi (BMKG) dan kondisi berbahaya di prakiraan Open-Meteo.
+package alerts
+
+import (
+	"slices"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Prakiraan yang diperiksa untuk peringatan turunan
+const forecastWindow = 24 * time.Hour
+
+// Hembusan angin (km/j) untuk peringatan angin kencang
+const (
+	strongGusts = 60.0
+	severeGusts = 90.0
+)
+
+// Tekanan turun 3 hPa atau lebih dalam 3 jam: sistem badai mendekat
+const pressureDropAlert = -3.0
+
+// --- Peringatan untuk satu titik, urut dari yang mulai paling awal ---
+// Peringatan resmi yang wilayahnya memuat titik ini ditambah peringatan dari
+// prakiraan per jam dan tendensi tekanan; yang sudah lewat dibuang.
+func ForLocation(lat, lon float64, official []model.AreaAlert, weather model.WeatherData, forecast model.HourlyForecast, now time.Time) []model.Alert {
+	result := []model.Alert{}
+	for _, alert := range official {
+		if alert.End.After(now) && covers(alert.Polygons, lat, lon) {
+			result = append(result, alert.Alert)
+		}
+	}
+	result = append(result, fromForecast(forecast, now)...)
+	if alert, ok := pressureDrop(weather, now); ok {
+		result = append(result, alert)
+	}
+	slices.SortStableFunc(result, func(a, b model.Alert) int { return a.Start.Compare(b.Start) })
+	return result
+}
+
+// --- Peringatan dari jam-jam prakiraan yang berbahaya dalam 24 jam ke depan ---
+// Jam berurutan dengan jenis & tingkat yang sama digabung jadi satu jendela.
+func fromForecast(forecast model.HourlyForecast, now time.Time) []model.Alert {
+	current := now.Truncate(time.Hour)
+	end := current.Add(forecastWindow)
+	var result []model.Alert
+	open := map[string]int{} // jenis -> indeks peringatan yang masih bersambung
+	for _, hour := range forecast {
+		if hour.Time.Before(current) || !hour.Time.Before(end) {
+			continue
+		}
+		for _, alert := range hourAlerts(hour) {
+			if i, ok := open[alert.Type]; ok && result[i].End.Equal(hour.Time) && result[i].Severity == alert.Severity {
+				result[i].End = alert.End
+				continue
+			}
+			open[alert.Type] = len(result)
+			result = append(result, alert)
+		}
+	}
+	return result
+}
+
+func hourAlerts(hour model.ForecastHour) []model.Alert {
+	alert := func(alertType, severity, title string) model.Alert {
+		return model.Alert{Type: alertType, Severity: severity, Title: title, Source: "open-meteo",
+			Start: hour.Time, End: hour.Time.Add(time.Hour)}
+	}
+	var result []model.Alert
+	switch hour.WeatherCode {
+	case 95:
+		result = append(result, alert(model.AlertThunderstorm, model.SeverityModerate, "Potensi hujan disertai petir"))
+	case 96, 99:
+		result = append(result, alert(model.AlertThunderstorm, model.SeveritySevere, "Potensi badai petir disertai hujan es"))
+	case 65, 82:
+		result = append(result, alert(model.AlertHeavyRain, model.SeverityModerate, "Potensi hujan lebat"))
+	}
+	switch {
+	case hour.WindGusts >= severeGusts:
+		result = append(result, alert(model.AlertStrongWind, model.SeveritySevere, "Potensi angin sangat kencang"))
+	case hour.WindGusts >= strongGusts:
+		result = append(result, alert(model.AlertStrongWind, model.SeverityModerate, "Potensi angin kencang"))
+	}
+	return result
+}
+
+// --- Tekanan turun tajam: tanda awal cuaca memburuk, berlaku 3 jam ke depan ---
+func pressureDrop(weather model.WeatherData, now time.Time) (model.Alert, bool) {
+	if weather.PressureChange == nil || *weather.PressureChange > pressureDropAlert {
+		return model.Alert{}, false
+	}
+	return model.Alert{
+		Type:     model.AlertPressureDrop,
+		Severity: model.SeverityMinor,
+		Title:    "Tekanan udara turun tajam, cuaca bisa memburuk",
+		Source:   "open-meteo",
+		Start:    now,
+		End:      now.Add(3 * time.Hour),
+	}, true
+}
+
+// Titik di dalam salah satu poligon (ray casting; poligon kecil, bumi dianggap datar)
+func covers(polygons [][][2]float64, lat, lon float64) bool {
+	for _, polygon := range polygons {
+		inside := false
+		for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+			a, b := polygon[i], polygon[j]
+			if (a[0] > lat) != (b[0] > lat) && lon < (b[1]-a[1])*(lat-a[0])/(b[0]-a[0])+a[1] {
+				inside = !inside
+			}
+		}
+		if inside {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,269 @@
+package alerts
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/astro"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Kondisi langganan notifikasi (POST /subscriptions) ---
+// Perbandingan "<metrik> <op> <angka>" (indeks aktivitas atau field cuaca
+// seperti di indeks kustom, satuan metric) atau event: "rain starting",
+// "<jenis> alert" / "any alert", dan event astronomi "supermoon",
+// "stargazing night", "meteor shower" / "<nama> meteor shower" serta
+// "eclipse" / "solar eclipse" / "lunar eclipse".
+type Condition struct {
+	Metric    string
+	Op        string
+	Threshold float64
+	// Kosong untuk perbandingan; salah satu konstanta Event*
+	Event string
+	// Jenis peringatan untuk EventAlert; kosong = jenis apa saja
+	AlertType string
+	// Nama hujan meteor untuk EventMeteorShower; kosong = yang mana saja
+	Shower string
+	// astro.EclipseSolar / astro.EclipseLunar untuk EventEclipse; kosong = keduanya
+	Eclipse string
+}
+
+const (
+	EventRainStarting    = "rain starting"
+	EventAlert           = "alert"
+	EventSupermoon       = "supermoon"
+	EventStargazingNight = "stargazing night"
+	EventMeteorShower    = "meteor shower"
+	EventEclipse         = "eclipse"
+)
+
+// Malam mengamati bintang: bulan hampir gelap dan indeksnya setinggi ini
+const (
+	newMoonIllumination = 0.05
+	stargazingNightMin  = 8
+)
+
+// Hujan meteor berlaku sejak sekitar sehari sebelum malam puncaknya sampai
+// setengah hari sesudah puncak
+const (
+	meteorShowerLead  = 36 * time.Hour
+	meteorShowerAfter = 12 * time.Hour
+)
+
+// Gerhana diberitahukan sehari sebelum puncaknya terlihat dari lokasi,
+// dan masih berlaku sampai sejam sesudahnya
+const (
+	eclipseLead  = 24 * time.Hour
+	eclipseAfter = time.Hour
+	// Puncak lokal paling jauh sekian dari puncak global
+	eclipseLocalOffset = 4 * time.Hour
+)
+
+// Belum hujan, tapi peluang hujan di jam-jam berikutnya setinggi ini
+const (
+	rainStartProbability = 60
+	rainStartWindow      = 2 * time.Hour
+)
+
+// Peringatan yang mulai dalam jangka ini sudah dianggap berlaku
+const alertLeadTime = 3 * time.Hour
+
+var indexMetrics = map[string]func(model.CalculatedIndices) float64{
+	"hiking_index":      func(i model.CalculatedIndices) float64 { return i.HikingIndex },
+	"running_index":     func(i model.CalculatedIndices) float64 { return i.RunningIndex },
+	"cycling_index":     func(i model.CalculatedIndices) float64 { return i.CyclingIndex },
+	"stargazing_index":  func(i model.CalculatedIndices) float64 { return i.StargazingIndex },
+	"drone_index":       func(i model.CalculatedIndices) float64 { return i.DroneIndex },
+	"photography_index": func(i model.CalculatedIndices) float64 { return i.PhotographyIndex },
+}
+
+var alertTypes = []string{model.AlertThunderstorm, model.AlertHeavyRain, model.AlertStrongWind, model.AlertPressureDrop}
+
+var comparison = regexp.MustCompile(`^([a-z0-9_]+)\s*(>=|<=|>|<)\s*(-?[0-9]+(?:\.[0-9]+)?)$`)
+
+// --- Metrik yang bisa dibandingkan, terurut untuk pesan error & dokumentasi ---
+func ConditionMetrics() []string {
+	metrics := indices.CustomFields()
+	for name := range indexMetrics {
+		metrics = append(metrics, name)
+	}
+	slices.Sort(metrics)
+	return metrics
+}
+
+func ParseCondition(expr string) (Condition, error) {
+	expr = strings.ToLower(strings.Join(strings.Fields(expr), " "))
+	if expr == EventRainStarting {
+		return Condition{Event: EventRainStarting}, nil
+	}
+	switch expr {
+	case EventSupermoon, EventStargazingNight, EventMeteorShower, EventEclipse:
+		return Condition{Event: expr}, nil
+	case astro.EclipseSolar + " " + EventEclipse:
+		return Condition{Event: EventEclipse, Eclipse: astro.EclipseSolar}, nil
+	case astro.EclipseLunar + " " + EventEclipse:
+		return Condition{Event: EventEclipse, Eclipse: astro.EclipseLunar}, nil
+	}
+	if name, ok := strings.CutSuffix(expr, " "+EventMeteorShower); ok {
+		if !slices.ContainsFunc(astro.MeteorShowers, func(s astro.MeteorShower) bool { return s.Name == name }) {
+			return Condition{}, fmt.Errorf("meteor shower must be one of %v, got %q", meteorShowerNames(), name)
+		}
+		return Condition{Event: EventMeteorShower, Shower: name}, nil
+	}
+	if alertType, ok := strings.CutSuffix(expr, " "+EventAlert); ok {
+		if alertType == "any" {
+			return Condition{Event: EventAlert}, nil
+		}
+		if !slices.Contains(alertTypes, alertType) {
+			return Condition{}, fmt.Errorf("alert type must be any or one of %v, got %q", alertTypes, alertType)
+		}
+		return Condition{Event: EventAlert, AlertType: alertType}, nil
+	}
+	m := comparison.FindStringSubmatch(expr)
+	if m == nil {
+		return Condition{}, fmt.Errorf(`condition must be "<metric> <op> <number>" (e.g. "hiking_index >= 8"), "rain starting", "<type> alert", "supermoon", "stargazing night", "meteor shower" or "eclipse", got %q`, expr)
+	}
+	_, isIndex := indexMetrics[m[1]]
+	if _, isField := indices.CustomFieldValue(m[1], model.WeatherData{}); !isIndex && !isField {
+		return Condition{}, fmt.Errorf("condition metric %q is not supported (use one of %v)", m[1], ConditionMetrics())
+	}
+	threshold, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return Condition{}, fmt.Errorf("condition threshold %q is not a number", m[3])
+	}
+	return Condition{Metric: m[1], Op: m[2], Threshold: threshold}, nil
+}
+
+func meteorShowerNames() []string {
+	names := make([]string, len(astro.MeteorShowers))
+	for i, shower := range astro.MeteorShowers {
+		names[i] = shower.Name
+	}
+	return names
+}
+
+// --- Bentuk baku, yang disimpan & dikirim di webhook ---
+func (c Condition) String() string {
+	switch c.Event {
+	case EventRainStarting, EventSupermoon, EventStargazingNight:
+		return c.Event
+	case EventMeteorShower:
+		if c.Shower == "" {
+			return EventMeteorShower
+		}
+		return c.Shower + " " + EventMeteorShower
+	case EventEclipse:
+		if c.Eclipse == "" {
+			return EventEclipse
+		}
+		return c.Eclipse + " " + EventEclipse
+	case EventAlert:
+		if c.AlertType == "" {
+			return "any " + EventAlert
+		}
+		return c.AlertType + " " + EventAlert
+	}
+	return c.Metric + " " + c.Op + " " + strconv.FormatFloat(c.Threshold, 'f', -1, 64)
+}
+
+// --- Event astronomi: hasilnya bergantung pada titik pengamatan, bukan cuaca ---
+func (c Condition) Astronomical() bool {
+	switch c.Event {
+	case EventSupermoon, EventStargazingNight, EventMeteorShower, EventEclipse:
+		return true
+	}
+	return false
+}
+
+// --- Apakah kondisi terpenuhi sekarang di lat/lon, beserta nilai yang dinilai ---
+// Untuk "rain starting" nilainya peluang hujan tertinggi (%), untuk
+// peringatan jumlah peringatan yang cocok, untuk supermoon jarak bulan (km),
+// untuk malam bintang indeks stargazing, untuk hujan meteor ZHR-nya dan
+// untuk gerhana magnitudonya di lokasi.
+func (c Condition) Evaluate(data model.ConsolidatedResponse, forecast model.HourlyForecast, lat, lon float64, now time.Time) (bool, float64) {
+	switch c.Event {
+	case EventRainStarting:
+		return rainStarting(data.Weather, forecast, now)
+	case EventSupermoon:
+		return data.Moon.Supermoon, data.Moon.Distance
+	case EventStargazingNight:
+		stargazing := data.Indices.StargazingIndex
+		return data.Moon.Illumination <= newMoonIllumination && stargazing >= stargazingNightMin, stargazing
+	case EventMeteorShower:
+		return c.meteorShower(lat, now)
+	case EventEclipse:
+		return c.eclipse(lat, lon, now)
+	case EventAlert:
+		count := 0
+		for _, alert := range data.Alerts {
+			if (c.AlertType == "" || alert.Type == c.AlertType) && alert.End.After(now) && alert.Start.Before(now.Add(alertLeadTime)) {
+				count++
+			}
+		}
+		return count > 0, float64(count)
+	}
+	value, ok := indices.CustomFieldValue(c.Metric, data.Weather)
+	if get, isIndex := indexMetrics[c.Metric]; isIndex {
+		value, ok = get(data.Indices), true
+	}
+	if !ok {
+		return false, 0
+	}
+	matched, _ := indices.CompareCustom(c.Op, value, c.Threshold)
+	return matched, value
+}
+
+// Puncak sudah dekat (atau baru lewat) dan radiant-nya terlihat dari lintang
+// lokasi; kalau beberapa cocok, ZHR tertinggi yang dipakai
+func (c Condition) meteorShower(lat float64, now time.Time) (bool, float64) {
+	best := 0
+	for _, shower := range astro.MeteorShowers {
+		if c.Shower != "" && shower.Name != c.Shower {
+			continue
+		}
+		untilPeak := shower.Peak(now).Sub(now)
+		if untilPeak > meteorShowerLead || untilPeak < -meteorShowerAfter || !shower.VisibleFrom(lat) {
+			continue
+		}
+		best = max(best, shower.ZHR)
+	}
+	return best > 0, float64(best)
+}
+
+// Gerhana pertama yang puncak lokalnya sudah dekat (atau baru lewat);
+// gerhana yang tidak terlihat dari lokasi dilewati
+func (c Condition) eclipse(lat, lon float64, now time.Time) (bool, float64) {
+	for _, eclipse := range astro.Eclipses {
+		if c.Eclipse != "" && eclipse.Kind != c.Eclipse {
+			continue
+		}
+		if eclipse.Greatest.Before(now.Add(-eclipseAfter-eclipseLocalOffset)) || eclipse.Greatest.After(now.Add(eclipseLead+eclipseLocalOffset)) {
+			continue
+		}
+		peak, magnitude, visible := eclipse.Visibility(lat, lon)
+		untilPeak := peak.Sub(now)
+		if visible && untilPeak <= eclipseLead && untilPeak >= -eclipseAfter {
+			return true, magnitude
+		}
+	}
+	return false, 0
+}
+
+// Sedang hujan tidak dihitung sebagai "mulai hujan"
+func rainStarting(weather model.WeatherData, forecast model.HourlyForecast, now time.Time) (bool, float64) {
+	if weather.Precipitation > 0 {
+		return false, 0
+	}
+	highest := 0
+	for _, hour := range forecast {
+		if hour.Time.After(now) && !hour.Time.After(now.Add(rainStartWindow)) {
+			highest = max(highest, hour.PrecipitationProbability)
+		}
+	}
+	return highest >= rainStartProbability, float64(highest)
+}
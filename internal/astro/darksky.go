@@ -0,0 +1,122 @@
+package astro
+
+import (
+	"math"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Pusat galaksi (Sagittarius A*), koordinat ekuator J2000, derajat
+const (
+	galacticCoreRA  = 266.4168
+	galacticCoreDec = -29.0078
+)
+
+// Inti Bima Sakti baru layak difoto kalau cukup tinggi di atas horizon;
+// di bawah ini cahayanya habis diredam atmosfer dan polusi cahaya kota.
+const galacticCoreMinAltitude = 10.0
+
+// Bulan yang lebih tipis dari ini tidak mengganggu walau sedang di atas horizon
+const darkMoonIllumination = 0.1
+
+// Jarak antar sampel saat menyisir satu malam
+const darkSkyStep = 10 * time.Minute
+
+// --- Jendela Bima Sakti malam ini: inti galaksi terlihat di langit gelap ---
+// Syaratnya matahari di bawah -18° (malam astronomis), inti galaksi minimal
+// 10° di atas horizon, dan bulan di bawah horizon atau hampir gelap. Malam
+// dimulai jam 12 siang lokal, jadi sebelum siang yang dinilai malam yang
+// sedang berjalan. Kalau bulan memotong malam, yang dipilih jendela
+// terpanjang; nil kalau tidak ada sama sekali (misalnya musim inti galaksi
+// hanya muncul siang hari).
+func MilkyWayWindow(now time.Time, lat, lon float64) *model.TimeWindow {
+	y, m, d := now.Date()
+	start := time.Date(y, m, d, 12, 0, 0, 0, now.Location())
+	if now.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	end := start.Add(24 * time.Hour)
+	illumination := MoonPhase(start.Add(12 * time.Hour)).Illumination
+
+	var best, current *model.TimeWindow
+	var bestLength, currentLength time.Duration
+	for t := start; t.Before(end); t = t.Add(darkSkyStep) {
+		if !milkyWayVisible(t, lat, lon, illumination) {
+			current = nil
+			continue
+		}
+		if current == nil {
+			current, currentLength = &model.TimeWindow{Start: t.Format("15:04")}, 0
+		}
+		currentLength += darkSkyStep
+		current.End = t.Add(darkSkyStep).Format("15:04")
+		if currentLength > bestLength {
+			best, bestLength = current, currentLength
+		}
+	}
+	return best
+}
+
+func milkyWayVisible(t time.Time, lat, lon, illumination float64) bool {
+	if SunPosition(t, lat, lon).Elevation > astronomicalTwilightAltitude {
+		return false
+	}
+	if altitude(t, lat, lon, galacticCoreRA, galacticCoreDec) < galacticCoreMinAltitude {
+		return false
+	}
+	if illumination <= darkMoonIllumination {
+		return true
+	}
+	ra, dec := moonEquatorial(julianCenturies(t))
+	return altitude(t, lat, lon, ra, dec) < 0
+}
+
+// --- Ketinggian (derajat) benda langit dengan RA/Dec tertentu saat t ---
+func altitude(t time.Time, lat, lon, ra, dec float64) float64 {
+	hourAngle := (siderealTime(t) + lon - ra) * deg
+	phi, delta := lat*deg, dec*deg
+	return math.Asin(math.Sin(phi)*math.Sin(delta)+math.Cos(phi)*math.Cos(delta)*math.Cos(hourAngle)) / deg
+}
+
+// Waktu sideris rata-rata Greenwich, derajat (Meeus 12.4)
+func siderealTime(t time.Time) float64 {
+	T := julianCenturies(t)
+	return normalizeDegrees(280.46061837 + 360.98564736629*T*36525 + 0.000387933*T*T)
+}
+
+// Suku periodik lintang ekliptika bulan (Σb, 1e-6 derajat), suku terbesar
+// tabel 47.B Meeus
+var moonLatitudeTerms = []struct {
+	D, M, Mp, F float64
+	b           float64
+}{
+	{0, 0, 0, 1, 5128122},
+	{0, 0, 1, 1, 280602},
+	{0, 0, 1, -1, 277693},
+	{2, 0, 0, -1, 173237},
+	{2, 0, -1, 1, 55413},
+	{2, 0, -1, -1, 46271},
+	{2, 0, 0, 1, 32573},
+	{0, 0, 2, 1, 17198},
+}
+
+// --- RA & deklinasi (derajat) bulan, geosentris ---
+// Paralaks bulan (~1°) diabaikan; cukup untuk tahu bulan di atas horizon atau tidak.
+func moonEquatorial(T float64) (float64, float64) {
+	lon, _ := moonPosition(T)
+	D := (297.8501921 + 445267.1114034*T) * deg
+	M := (357.5291092 + 35999.0502909*T) * deg
+	Mp := (134.9633964 + 477198.8675055*T) * deg
+	F := (93.2720950 + 483202.0175233*T) * deg
+	var sumB float64
+	for _, term := range moonLatitudeTerms {
+		sumB += term.b * math.Sin(term.D*D+term.M*M+term.Mp*Mp+term.F*F)
+	}
+
+	lambda, beta := lon*deg, sumB/1e6*deg
+	epsilon := (23.4392911 - 0.0130042*T) * deg
+	ra := math.Atan2(math.Sin(lambda)*math.Cos(epsilon)-math.Tan(beta)*math.Sin(epsilon), math.Cos(lambda))
+	dec := math.Asin(math.Sin(beta)*math.Cos(epsilon) + math.Cos(beta)*math.Sin(epsilon)*math.Sin(lambda))
+	return normalizeDegrees(ra / deg), dec / deg
+}
@@ -0,0 +1,154 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// --- Gerhana matahari & bulan 2024-2030 (NASA Five Millennium Canon) ---
+// Yang disimpan hanya waktu puncak global; dari mana gerhana terlihat
+// dihitung sendiri dari posisi matahari & bulan. Gerhana bulan penumbra
+// tidak dimasukkan karena hampir tidak kelihatan dengan mata.
+// Tabel perlu diperpanjang sebelum 2031.
+type Eclipse struct {
+	// EclipseSolar atau EclipseLunar
+	Kind string
+	// Jenis di puncak global: total, annular atau partial
+	Type string
+	// Puncak global, UTC
+	Greatest time.Time
+	// Magnitudo di puncak global; untuk gerhana bulan magnitudo umbra
+	Magnitude float64
+}
+
+const (
+	EclipseSolar = "solar"
+	EclipseLunar = "lunar"
+
+	EclipseTotal   = "total"
+	EclipseAnnular = "annular"
+	EclipsePartial = "partial"
+)
+
+var Eclipses = []Eclipse{
+	{EclipseSolar, EclipseTotal, eclipseAt("2024-04-08T18:17"), 1.057},
+	{EclipseLunar, EclipsePartial, eclipseAt("2024-09-18T02:44"), 0.085},
+	{EclipseSolar, EclipseAnnular, eclipseAt("2024-10-02T18:45"), 0.933},
+	{EclipseLunar, EclipseTotal, eclipseAt("2025-03-14T06:59"), 1.178},
+	{EclipseSolar, EclipsePartial, eclipseAt("2025-03-29T10:47"), 0.938},
+	{EclipseLunar, EclipseTotal, eclipseAt("2025-09-07T18:12"), 1.362},
+	{EclipseSolar, EclipsePartial, eclipseAt("2025-09-21T19:41"), 0.855},
+	{EclipseSolar, EclipseAnnular, eclipseAt("2026-02-17T12:12"), 0.963},
+	{EclipseLunar, EclipseTotal, eclipseAt("2026-03-03T11:34"), 1.151},
+	{EclipseSolar, EclipseTotal, eclipseAt("2026-08-12T17:46"), 1.039},
+	{EclipseLunar, EclipsePartial, eclipseAt("2026-08-28T04:13"), 0.930},
+	{EclipseSolar, EclipseAnnular, eclipseAt("2027-02-06T16:00"), 0.928},
+	{EclipseSolar, EclipseTotal, eclipseAt("2027-08-02T10:07"), 1.079},
+	{EclipseLunar, EclipsePartial, eclipseAt("2028-01-12T04:13"), 0.066},
+	{EclipseSolar, EclipseAnnular, eclipseAt("2028-01-26T15:08"), 0.921},
+	{EclipseLunar, EclipsePartial, eclipseAt("2028-07-06T18:20"), 0.389},
+	{EclipseSolar, EclipseTotal, eclipseAt("2028-07-22T02:56"), 1.056},
+	{EclipseLunar, EclipseTotal, eclipseAt("2028-12-31T16:52"), 1.246},
+	{EclipseSolar, EclipsePartial, eclipseAt("2029-01-14T17:13"), 0.871},
+	{EclipseSolar, EclipsePartial, eclipseAt("2029-06-12T04:06"), 0.458},
+	{EclipseLunar, EclipseTotal, eclipseAt("2029-06-26T03:22"), 1.844},
+	{EclipseSolar, EclipsePartial, eclipseAt("2029-07-11T15:37"), 0.230},
+	{EclipseSolar, EclipsePartial, eclipseAt("2029-12-05T15:03"), 0.891},
+	{EclipseLunar, EclipseTotal, eclipseAt("2029-12-20T22:42"), 1.117},
+	{EclipseSolar, EclipseAnnular, eclipseAt("2030-06-01T06:29"), 0.944},
+	{EclipseLunar, EclipsePartial, eclipseAt("2030-06-15T18:33"), 0.502},
+	{EclipseSolar, EclipseTotal, eclipseAt("2030-11-25T06:51"), 1.047},
+}
+
+func eclipseAt(value string) time.Time {
+	t, err := time.Parse("2006-01-02T15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+const (
+	// Bayangan bulan butuh sekitar 3 jam untuk melintasi sisi siang Bumi
+	// sebelum maupun sesudah puncak global
+	solarEclipseSpan = 3*time.Hour + 30*time.Minute
+	solarEclipseStep = 2 * time.Minute
+
+	// Selisih TT - UTC; posisi bulan bergeser ~0,01° dalam sekian detik
+	deltaT = 69 * time.Second
+
+	earthRadiusKm = 6378.14
+	moonRadiusKm  = 1737.4
+	// Jari-jari sudut matahari (derajat) pada jarak rata-rata
+	sunSemiDiameter = 0.2666
+)
+
+// --- Gerhana dilihat dari lat/lon: saat puncak lokal dan magnitudonya ---
+// ok=false kalau gerhana tidak terlihat dari sana. Gerhana bulan terlihat
+// kalau bulan di atas horizon saat puncak, magnitudonya sama di mana pun.
+// Gerhana matahari dihitung topografis (paralaks bulan ~1°): magnitudo
+// adalah bagian diameter matahari yang tertutup selama matahari di atas
+// horizon; ≥ 1 berarti total.
+func (e Eclipse) Visibility(lat, lon float64) (time.Time, float64, bool) {
+	if e.Kind == EclipseLunar {
+		ra, dec := moonEquatorial(julianCenturies(e.Greatest.Add(deltaT)))
+		if altitude(e.Greatest, lat, lon, ra, dec) < 0 {
+			return time.Time{}, 0, false
+		}
+		return e.Greatest, e.Magnitude, true
+	}
+
+	var peak time.Time
+	best := 0.0
+	for t := e.Greatest.Add(-solarEclipseSpan); !t.After(e.Greatest.Add(solarEclipseSpan)); t = t.Add(solarEclipseStep) {
+		magnitude, sunUp := solarObscuration(t, lat, lon)
+		if sunUp && magnitude > best {
+			peak, best = t, magnitude
+		}
+	}
+	return peak, math.Round(best*1000) / 1000, best > 0
+}
+
+// Magnitudo gerhana matahari saat t dari lat/lon, dan apakah matahari terbit
+func solarObscuration(t time.Time, lat, lon float64) (float64, bool) {
+	T := julianCenturies(t.Add(deltaT))
+	sunRA, sunDec := sunEquatorial(T)
+	if altitude(t, lat, lon, sunRA, sunDec) < sunriseAltitude {
+		return 0, false
+	}
+	moonRA, moonDec := moonEquatorial(T)
+	_, distance := moonPosition(T)
+	moonRA, moonDec = topocentric(t, lat, lon, moonRA, moonDec, distance)
+
+	separation := angularSeparation(sunRA, sunDec, moonRA, moonDec)
+	moonSemiDiameter := math.Asin(moonRadiusKm/distance) / deg
+	return max(0, (moonSemiDiameter+sunSemiDiameter-separation)/(2*sunSemiDiameter)), true
+}
+
+// RA & deklinasi (derajat) matahari, dengan koreksi aberasi
+func sunEquatorial(T float64) (float64, float64) {
+	lambda := (sunLongitude(T) - 0.00569) * deg
+	epsilon := (23.4392911 - 0.0130042*T) * deg
+	ra := math.Atan2(math.Cos(epsilon)*math.Sin(lambda), math.Cos(lambda))
+	dec := math.Asin(math.Sin(epsilon) * math.Sin(lambda))
+	return normalizeDegrees(ra / deg), dec / deg
+}
+
+// Posisi geosentris ke topografis untuk benda sedekat bulan (Meeus bab 40,
+// Bumi dianggap bulat)
+func topocentric(t time.Time, lat, lon, ra, dec, distanceKm float64) (float64, float64) {
+	sinParallax := earthRadiusKm / distanceKm
+	hourAngle := (siderealTime(t) + lon - ra) * deg
+	phi, delta := lat*deg, dec*deg
+	denominator := math.Cos(delta) - math.Cos(phi)*sinParallax*math.Cos(hourAngle)
+	deltaRA := math.Atan2(-math.Cos(phi)*sinParallax*math.Sin(hourAngle), denominator)
+	topoDec := math.Atan2((math.Sin(delta)-math.Sin(phi)*sinParallax)*math.Cos(deltaRA), denominator)
+	return normalizeDegrees(ra + deltaRA/deg), topoDec / deg
+}
+
+// Jarak sudut (derajat) dua titik RA/Dec
+func angularSeparation(ra1, dec1, ra2, dec2 float64) float64 {
+	d1, d2 := dec1*deg, dec2*deg
+	cos := math.Sin(d1)*math.Sin(d2) + math.Cos(d1)*math.Cos(d2)*math.Cos((ra1-ra2)*deg)
+	return math.Acos(max(-1, min(1, cos))) / deg
+}
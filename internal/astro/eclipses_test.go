@@ -0,0 +1,65 @@
+package astro
+
+import (
+	"math"
+	"testing"
+)
+
+// Pembanding: peta lokal gerhana NASA/timeanddate. Magnitudo lokal boleh
+// meleset ±0,03 dan jam puncaknya ±4 menit (langkah pindai 2 menit); di
+// jalur totalitas yang dicek cukup "sekitar 1".
+func TestEclipseVisibility(t *testing.T) {
+	tests := []struct {
+		name      string
+		greatest  string
+		lat, lon  float64
+		visible   bool
+		peak      string
+		magnitude float64
+	}{
+		{name: "total solar in Dallas", greatest: "2024-04-08T18:17:00Z", lat: 32.78, lon: -96.80, visible: true, peak: "2024-04-08T18:42:00Z", magnitude: 1},
+		{name: "partial solar in New York", greatest: "2024-04-08T18:17:00Z", lat: 40.71, lon: -74.01, visible: true, peak: "2024-04-08T19:25:00Z", magnitude: 0.9},
+		{name: "solar at night in Jakarta", greatest: "2024-04-08T18:17:00Z", lat: -6.2, lon: 106.85, visible: false},
+		{name: "total solar in Luxor", greatest: "2027-08-02T10:07:00Z", lat: 25.69, lon: 32.64, visible: true, peak: "2027-08-02T10:06:00Z", magnitude: 1},
+		{name: "total lunar in New York", greatest: "2025-03-14T06:59:00Z", lat: 40.71, lon: -74.01, visible: true, peak: "2025-03-14T06:59:00Z", magnitude: 1.178},
+		{name: "lunar by day in Jakarta", greatest: "2025-03-14T06:59:00Z", lat: -6.2, lon: 106.85, visible: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eclipse := findEclipse(t, tt.greatest)
+			peak, magnitude, visible := eclipse.Visibility(tt.lat, tt.lon)
+			if visible != tt.visible {
+				t.Fatalf("visible = %v (magnitude %v), want %v", visible, magnitude, tt.visible)
+			}
+			if !tt.visible {
+				return
+			}
+			if diff := peak.Sub(mustTime(t, tt.peak)).Abs().Minutes(); diff > 4 {
+				t.Errorf("peak = %v, want about %v", peak, tt.peak)
+			}
+			if math.Abs(magnitude-tt.magnitude) > 0.03 {
+				t.Errorf("magnitude = %v, want about %v", magnitude, tt.magnitude)
+			}
+		})
+	}
+}
+
+func TestEclipsesSorted(t *testing.T) {
+	for i := 1; i < len(Eclipses); i++ {
+		if !Eclipses[i].Greatest.After(Eclipses[i-1].Greatest) {
+			t.Errorf("eclipse %v listed after %v", Eclipses[i].Greatest, Eclipses[i-1].Greatest)
+		}
+	}
+}
+
+func findEclipse(t *testing.T, greatest string) Eclipse {
+	t.Helper()
+	at := mustTime(t, greatest)
+	for _, eclipse := range Eclipses {
+		if eclipse.Greatest.Equal(at) {
+			return eclipse
+		}
+	}
+	t.Fatalf("no eclipse at %s", greatest)
+	return Eclipse{}
+}
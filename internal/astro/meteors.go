@@ -0,0 +1,61 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// --- Hujan meteor tahunan utama (kalender IMO) ---
+// Tanggal puncak bergeser paling banyak sehari antar tahun, jadi cukup
+// disimpan bulan & tanggalnya (UTC).
+type MeteorShower struct {
+	// Nama dalam huruf kecil, dipakai juga di kondisi langganan
+	Name  string
+	Month time.Month
+	Day   int
+	// Zenithal hourly rate saat puncak, meteor per jam di langit ideal
+	ZHR int
+	// Deklinasi radiant, derajat
+	RadiantDec float64
+}
+
+var MeteorShowers = []MeteorShower{
+	{Name: "quadrantids", Month: time.January, Day: 4, ZHR: 110, RadiantDec: 49},
+	{Name: "lyrids", Month: time.April, Day: 22, ZHR: 18, RadiantDec: 33},
+	{Name: "eta aquariids", Month: time.May, Day: 6, ZHR: 50, RadiantDec: -1},
+	{Name: "delta aquariids", Month: time.July, Day: 30, ZHR: 25, RadiantDec: -16},
+	{Name: "perseids", Month: time.August, Day: 12, ZHR: 100, RadiantDec: 58},
+	{Name: "orionids", Month: time.October, Day: 21, ZHR: 20, RadiantDec: 16},
+	{Name: "leonids", Month: time.November, Day: 17, ZHR: 15, RadiantDec: 22},
+	{Name: "geminids", Month: time.December, Day: 14, ZHR: 150, RadiantDec: 33},
+	{Name: "ursids", Month: time.December, Day: 22, ZHR: 10, RadiantDec: 76},
+}
+
+// Radiant yang puncaknya tidak sampai setinggi ini hanya memberi segelintir meteor
+const meteorRadiantMinAltitude = 20.0
+
+// --- Puncak terdekat dari at (sebelum atau sesudah), dalam tahun yang sama atau sebelahnya ---
+func (s MeteorShower) Peak(at time.Time) time.Time {
+	at = at.UTC()
+	nearest := time.Time{}
+	for _, year := range []int{at.Year() - 1, at.Year(), at.Year() + 1} {
+		// Tengah hari UTC, supaya malam puncak di Asia & Amerika sama-sama dekat
+		peak := time.Date(year, s.Month, s.Day, 12, 0, 0, 0, time.UTC)
+		if nearest.IsZero() || absDuration(peak.Sub(at)) < absDuration(nearest.Sub(at)) {
+			nearest = peak
+		}
+	}
+	return nearest
+}
+
+// Radiant cukup tinggi saat transit di lintang lat
+func (s MeteorShower) VisibleFrom(lat float64) bool {
+	return 90-math.Abs(lat-s.RadiantDec) >= meteorRadiantMinAltitude
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,157 @@
+// Package astro menghitung data astronomi (bulan, matahari) tanpa memanggil
+// provider eksternal.
+package astro
+
+import (
+	"math"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Bulan sinodis rata-rata, hari
+const synodicMonth = 29.530588853
+
+// Jarak rata-rata Bumi-Matahari, km; cukup untuk sudut fase bulan
+const sunDistanceKm = 149597870.7
+
+// Purnama lebih dekat dari ini disebut supermoon (definisi yang umum dipakai)
+const supermoonDistanceKm = 360000.0
+
+// Perigee/apogee: jarak saat ini ekstrem dibanding 12 jam sebelum & sesudahnya,
+// jadi flag-nya aktif sekitar 6 jam di kedua sisi saat tepatnya
+const apsisWindow = 12 * time.Hour
+
+// --- Fase bulan dari posisi bulan & matahari (Meeus, Astronomical Algorithms) ---
+// Elongasi bulan terhadap matahari menentukan fase & umur, sudut fase
+// menentukan bagian piringan yang diterangi.
+func MoonPhase(at time.Time) model.MoonData {
+	T := julianCenturies(at)
+	moonLon, moonDist := moonPosition(T)
+	elongation := normalizeDegrees(moonLon - sunLongitude(T))
+
+	psi := elongation * deg
+	phaseAngle := math.Atan2(sunDistanceKm*math.Sin(psi), moonDist-sunDistanceKm*math.Cos(psi))
+	illumination := (1 + math.Cos(phaseAngle)) / 2
+
+	// 0 = bulan baru, 0,5 = purnama
+	phase := elongation / 360
+	name := phaseName(phase)
+	_, before := moonPosition(julianCenturies(at.Add(-apsisWindow)))
+	_, after := moonPosition(julianCenturies(at.Add(apsisWindow)))
+	perigee := moonDist < before && moonDist < after
+	return model.MoonData{
+		PhaseName:    name,
+		Illumination: math.Round(illumination*100) / 100,
+		Age:          model.Round1(phase * synodicMonth),
+		PhaseAngle:   model.Round1(math.Abs(phaseAngle) / deg),
+		Distance:     math.Round(moonDist),
+		Perigee:      perigee,
+		Apogee:       moonDist > before && moonDist > after,
+		Supermoon:    name == fullMoon && moonDist < supermoonDistanceKm,
+	}
+}
+
+const fullMoon = "Bulan Purnama"
+
+// Nama fase utama berlaku sekitar satu hari di sekitar saat tepatnya
+func phaseName(phase float64) string {
+	const day = 1 / synodicMonth
+	switch {
+	case phase < day || phase > 1-day:
+		return "Bulan Baru"
+	case phase < 0.25-day:
+		return "Sabit Awal"
+	case phase < 0.25+day:
+		return "Kuartal Pertama"
+	case phase < 0.5-day:
+		return "Cembung Awal"
+	case phase < 0.5+day:
+		return fullMoon
+	case phase < 0.75-day:
+		return "Cembung Akhir"
+	case phase < 0.75+day:
+		return "Kuartal Akhir"
+	default:
+		return "Sabit Akhir"
+	}
+}
+
+const deg = math.Pi / 180
+
+// Abad Julian sejak J2000.0
+func julianCenturies(t time.Time) float64 {
+	jd := float64(t.UTC().UnixNano())/86400e9 + 2440587.5
+	return (jd - 2451545.0) / 36525
+}
+
+func normalizeDegrees(d float64) float64 {
+	d = math.Mod(d, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
+
+// --- Bujur ekliptika matahari (Meeus bab 25, presisi rendah ~0,01°) ---
+func sunLongitude(T float64) float64 {
+	L0 := 280.46646 + 36000.76983*T + 0.0003032*T*T
+	M := (357.52911 + 35999.05029*T - 0.0001537*T*T) * deg
+	C := (1.914602-0.004817*T-0.000014*T*T)*math.Sin(M) +
+		(0.019993-0.000101*T)*math.Sin(2*M) +
+		0.000289*math.Sin(3*M)
+	return normalizeDegrees(L0 + C)
+}
+
+// Suku periodik bujur (Σl, 1e-6 derajat) dan jarak (Σr, meter) bulan:
+// kelipatan D, M, M', F. Hanya suku terbesar dari tabel 47.A Meeus; galatnya
+// sekitar 0,05° dan beberapa ratus km, jauh di bawah yang terlihat mata.
+var moonTerms = []struct {
+	D, M, Mp, F float64
+	l, r        float64
+}{
+	{0, 0, 1, 0, 6288774, -20905355},
+	{2, 0, -1, 0, 1274027, -3699111},
+	{2, 0, 0, 0, 658314, -2955968},
+	{0, 0, 2, 0, 213618, -569925},
+	{0, 1, 0, 0, -185116, 48888},
+	{0, 0, 0, 2, -114332, -3149},
+	{2, 0, -2, 0, 58793, 246158},
+	{2, -1, -1, 0, 57066, -152138},
+	{2, 0, 1, 0, 53322, -170733},
+	{2, -1, 0, 0, 45758, -204586},
+	{0, 1, -1, 0, -40923, -129620},
+	{1, 0, 0, 0, -34720, 108743},
+	{0, 1, 1, 0, -30383, 104755},
+	{2, 0, 0, -2, 15327, 10321},
+	{0, 0, 1, 2, -12528, 0},
+	{0, 0, 1, -2, 10980, 79661},
+	{4, 0, -1, 0, 10675, -34782},
+	{0, 0, 3, 0, 10034, -23210},
+	{4, 0, -2, 0, 8548, -21636},
+	{2, 1, -1, 0, -7888, 24208},
+	{2, 1, 0, 0, -6766, 30824},
+	{1, 0, -1, 0, -5163, -8379},
+	{1, 1, 0, 0, 4987, -16675},
+	{2, -1, 1, 0, 4036, -12831},
+}
+
+// --- Bujur ekliptika (derajat) dan jarak pusat ke pusat (km) bulan, Meeus bab 47 ---
+func moonPosition(T float64) (float64, float64) {
+	Lp := 218.3164477 + 481267.88123421*T
+	D := (297.8501921 + 445267.1114034*T) * deg
+	M := (357.5291092 + 35999.0502909*T) * deg
+	Mp := (134.9633964 + 477198.8675055*T) * deg
+	F := (93.2720950 + 483202.0175233*T) * deg
+	// Eksentrisitas orbit Bumi yang mengecil memperkecil suku yang memuat M
+	E := 1 - 0.002516*T - 0.0000074*T*T
+
+	var sumL, sumR float64
+	for _, term := range moonTerms {
+		arg := term.D*D + term.M*M + term.Mp*Mp + term.F*F
+		factor := math.Pow(E, math.Abs(term.M))
+		sumL += term.l * factor * math.Sin(arg)
+		sumR += term.r * factor * math.Cos(arg)
+	}
+	return normalizeDegrees(Lp + sumL/1e6), 385000.56 + sumR/1000
+}
@@ -0,0 +1,74 @@
+package astro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Waktu fase dari tabel fase bulan NASA/USNO; jarak dari ephemeris JPL.
+// Toleransinya mengikuti galat suku Meeus yang dipotong (beberapa ratus km).
+func TestMoonPhase(t *testing.T) {
+	tests := []struct {
+		name         string
+		at           string
+		illumination float64
+		distanceKm   float64
+		phaseName    string
+	}{
+		{name: "full moon", at: "2024-01-25T17:54:00Z", illumination: 1, distanceKm: 401000, phaseName: fullMoon},
+		{name: "new moon", at: "2024-01-11T11:57:00Z", illumination: 0, distanceKm: 365000, phaseName: "Bulan Baru"},
+		{name: "first quarter", at: "2024-01-18T03:52:00Z", illumination: 0.5, distanceKm: 374700, phaseName: "Kuartal Pertama"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moon := MoonPhase(mustTime(t, tt.at))
+			if math.Abs(moon.Illumination-tt.illumination) > 0.02 {
+				t.Errorf("illumination = %v, want about %v", moon.Illumination, tt.illumination)
+			}
+			if math.Abs(moon.Distance-tt.distanceKm) > 1000 {
+				t.Errorf("distance = %v km, want about %v km", moon.Distance, tt.distanceKm)
+			}
+			if moon.PhaseName != tt.phaseName {
+				t.Errorf("phase name = %q, want %q", moon.PhaseName, tt.phaseName)
+			}
+			if moon.Supermoon {
+				t.Error("supermoon = true, want false")
+			}
+		})
+	}
+}
+
+// Purnama 18 September 2024 02:34 UTC, perigee 13:22 UTC di 357.286 km
+func TestMoonPhaseSupermoon(t *testing.T) {
+	full := MoonPhase(mustTime(t, "2024-09-18T02:34:00Z"))
+	if !full.Supermoon {
+		t.Errorf("supermoon = false at the September 2024 full moon (distance %v km)", full.Distance)
+	}
+
+	perigee := MoonPhase(mustTime(t, "2024-09-18T13:22:00Z"))
+	if math.Abs(perigee.Distance-357300) > 500 {
+		t.Errorf("distance = %v km, want about 357300 km", perigee.Distance)
+	}
+	if !perigee.Perigee || perigee.Apogee {
+		t.Errorf("perigee, apogee = %v, %v; want true, false", perigee.Perigee, perigee.Apogee)
+	}
+	if !perigee.Supermoon {
+		t.Error("supermoon = false at perigee half a day after the full moon")
+	}
+
+	// Dua hari kemudian bulan sudah menjauh dan bukan purnama lagi
+	later := MoonPhase(mustTime(t, "2024-09-20T13:22:00Z"))
+	if later.Perigee || later.Supermoon {
+		t.Errorf("perigee, supermoon = %v, %v two days later; want false, false", later.Perigee, later.Supermoon)
+	}
+}
+
+func mustTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return at
+}
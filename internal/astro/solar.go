@@ -0,0 +1,78 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Ketinggian pusat matahari (derajat) yang menandai tiap fase cahaya.
+// Senja sipil/nautika/astronomi memakai definisi baku; blue hour dan golden
+// hour mengikuti batas yang umum dipakai fotografer.
+const (
+	civilTwilightAltitude        = -6.0
+	nauticalTwilightAltitude     = -12.0
+	astronomicalTwilightAltitude = -18.0
+	blueHourAltitude             = -4.0
+	goldenHourAltitude           = 6.0
+	// Tepi atas piringan menyentuh horizon: radius 16' plus refraksi 34'
+	sunriseAltitude = -0.833
+)
+
+// --- Deklinasi (derajat) & equation of time (menit) matahari, algoritma NOAA ---
+// Berbasis bujur ekliptika Meeus bab 25 plus koreksi nutasi & aberasi;
+// galatnya di bawah satu menit untuk tahun-tahun sekitar J2000.
+func solarCoordinates(T float64) (float64, float64) {
+	L0 := normalizeDegrees(280.46646+36000.76983*T+0.0003032*T*T) * deg
+	M := (357.52911 + 35999.05029*T - 0.0001537*T*T) * deg
+	e := 0.016708634 - 0.000042037*T - 0.0000001267*T*T
+
+	omega := (125.04 - 1934.136*T) * deg
+	lambda := (sunLongitude(T) - 0.00569 - 0.00478*math.Sin(omega)) * deg
+	epsilon0 := 23 + (26+(21.448-T*(46.815+T*(0.00059-T*0.001813)))/60)/60
+	epsilon := (epsilon0 + 0.00256*math.Cos(omega)) * deg
+
+	declination := math.Asin(math.Sin(epsilon) * math.Sin(lambda))
+
+	y := math.Pow(math.Tan(epsilon/2), 2)
+	eqTime := y*math.Sin(2*L0) - 2*e*math.Sin(M) +
+		4*e*y*math.Sin(M)*math.Cos(2*L0) -
+		0.5*y*y*math.Sin(4*L0) - 1.25*e*e*math.Sin(2*M)
+	return declination / deg, 4 * eqTime / deg
+}
+
+// --- Transit matahari (solar noon) pada tanggal date, UTC ---
+func solarNoon(date time.Time, lon float64) time.Time {
+	y, m, d := date.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	// Perkiraan pertama di 12:00 waktu matahari rata-rata, lalu dikoreksi sekali
+	t := midnight.Add(time.Duration((720 - 4*lon) * float64(time.Minute)))
+	_, eqTime := solarCoordinates(julianCenturies(t))
+	return midnight.Add(time.Duration((720 - 4*lon - eqTime) * float64(time.Minute)))
+}
+
+// --- Saat matahari melewati ketinggian altitude pada tanggal date (UTC) ---
+// rising true untuk pagi, false untuk sore. ok false kalau pada tanggal itu
+// matahari tidak pernah mencapai ketinggian tersebut (lintang tinggi).
+// Posisi matahari dihitung ulang di perkiraan waktunya supaya akurat.
+func sunCrossing(date time.Time, lat, lon, altitude float64, rising bool) (time.Time, bool) {
+	y, m, d := date.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	t := midnight.Add(12 * time.Hour)
+	for range 3 {
+		declination, eqTime := solarCoordinates(julianCenturies(t))
+		noon := 720 - 4*lon - eqTime
+
+		phi, delta := lat*deg, declination*deg
+		cosH := (math.Sin(altitude*deg) - math.Sin(phi)*math.Sin(delta)) / (math.Cos(phi) * math.Cos(delta))
+		if cosH < -1 || cosH > 1 {
+			return time.Time{}, false
+		}
+		offset := 4 * math.Acos(cosH) / deg
+		if rising {
+			offset = -offset
+		}
+		t = midnight.Add(time.Duration((noon + offset) * float64(time.Minute)))
+	}
+	return t, true
+}
@@ -0,0 +1,115 @@
+package astro
+
+import (
+	"math"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Kalau matahari tidak pernah mencapai batas ketinggiannya (lintang tinggi di
+// musim panas/dingin), golden hour dan blue hour jatuh kembali ke durasi
+// tetap dari jam terbit & terbenam.
+const (
+	goldenHourLength = time.Hour
+	blueHourLength   = 30 * time.Minute
+)
+
+// --- Jam matahari di zona lokal: terbit/terbenam dari provider, sisanya dihitung ---
+// Golden hour: matahari -4° sampai +6°; blue hour: -6° sampai -4°; senja
+// sipil/nautika/astronomi dari -6°, -12° dan -18° pagi sampai sore.
+func SunTimes(sunrise, sunset time.Time, lat, lon float64, loc *time.Location) model.SunData {
+	sunriseLocal := sunrise.In(loc)
+	sunsetLocal := sunset.In(loc)
+	date := sunriseLocal
+
+	crossing := func(altitude float64, rising bool, fallback time.Time) time.Time {
+		if t, ok := sunCrossing(date, lat, lon, altitude, rising); ok {
+			return t.In(loc)
+		}
+		return fallback
+	}
+	blueMorning := crossing(blueHourAltitude, true, sunriseLocal)
+	blueEvening := crossing(blueHourAltitude, false, sunsetLocal)
+	goldenHourEnd := crossing(goldenHourAltitude, true, sunriseLocal.Add(goldenHourLength))
+	goldenHourStart := crossing(goldenHourAltitude, false, sunsetLocal.Add(-goldenHourLength))
+	dawn := crossing(civilTwilightAltitude, true, blueMorning.Add(-blueHourLength))
+	dusk := crossing(civilTwilightAltitude, false, blueEvening.Add(blueHourLength))
+
+	return model.SunData{
+		Sunrise:    sunriseLocal.Format("15:04"),
+		Sunset:     sunsetLocal.Format("15:04"),
+		GoldenHour: goldenHourEnd.Format("15:04"),
+
+		GoldenHourMorning: window(blueMorning, goldenHourEnd),
+		GoldenHourEvening: window(goldenHourStart, blueEvening),
+		BlueHourMorning:   window(dawn, blueMorning),
+		BlueHourEvening:   window(blueEvening, dusk),
+
+		CivilTwilight:        window(dawn, dusk),
+		NauticalTwilight:     twilight(date, lat, lon, nauticalTwilightAltitude, loc),
+		AstronomicalTwilight: twilight(date, lat, lon, astronomicalTwilightAltitude, loc),
+
+		SolarNoon:        solarNoon(date, lon).In(loc).Format("15:04"),
+		DayLengthMinutes: int(sunset.Sub(sunrise).Round(time.Minute).Minutes()),
+	}
+}
+
+// --- Jam matahari dihitung sepenuhnya lokal, tanpa provider ---
+// Cadangan saat sunrise-sunset.org tidak bisa dihubungi; selisihnya dengan
+// provider di bawah satu menit. Di lintang tinggi saat matahari tidak terbit
+// atau tidak terbenam, jam terbit/terbenam dan jendela cahayanya kosong.
+func LocalSunTimes(date time.Time, lat, lon float64) model.SunData {
+	loc := date.Location()
+	sunrise, ok1 := sunCrossing(date, lat, lon, sunriseAltitude, true)
+	sunset, ok2 := sunCrossing(date, lat, lon, sunriseAltitude, false)
+	if ok1 && ok2 {
+		return SunTimes(sunrise, sunset, lat, lon, loc)
+	}
+
+	noon := solarNoon(date, lon)
+	dayLength := 0
+	if SunPosition(noon, lat, lon).Elevation > 0 {
+		// Matahari tengah malam: siang sepanjang hari
+		dayLength = 24 * 60
+	}
+	return model.SunData{
+		CivilTwilight:        twilight(date, lat, lon, civilTwilightAltitude, loc),
+		NauticalTwilight:     twilight(date, lat, lon, nauticalTwilightAltitude, loc),
+		AstronomicalTwilight: twilight(date, lat, lon, astronomicalTwilightAltitude, loc),
+		SolarNoon:            noon.In(loc).Format("15:04"),
+		DayLengthMinutes:     dayLength,
+	}
+}
+
+// --- Azimuth & elevasi matahari saat at (geometris, tanpa koreksi refraksi) ---
+func SunPosition(at time.Time, lat, lon float64) model.SunPosition {
+	declination, eqTime := solarCoordinates(julianCenturies(at))
+	utc := at.UTC()
+	minutes := float64(utc.Hour()*60+utc.Minute()) + float64(utc.Second())/60
+
+	// Sudut jam: 0 saat transit, negatif pagi, positif sore
+	hourAngle := ((minutes+eqTime+4*lon)/4 - 180) * deg
+	phi, delta := lat*deg, declination*deg
+	elevation := math.Asin(math.Sin(phi)*math.Sin(delta) + math.Cos(phi)*math.Cos(delta)*math.Cos(hourAngle))
+	azimuth := math.Atan2(math.Sin(hourAngle), math.Cos(hourAngle)*math.Sin(phi)-math.Tan(delta)*math.Cos(phi))
+
+	return model.SunPosition{
+		Azimuth:   model.Round1(normalizeDegrees(azimuth/deg + 180)),
+		Elevation: model.Round1(elevation / deg),
+	}
+}
+
+// Awal senja pagi & akhir senja sore; kosong kalau langit tidak pernah segelap itu
+func twilight(date time.Time, lat, lon, altitude float64, loc *time.Location) model.TimeWindow {
+	dawn, ok1 := sunCrossing(date, lat, lon, altitude, true)
+	dusk, ok2 := sunCrossing(date, lat, lon, altitude, false)
+	if !ok1 || !ok2 {
+		return model.TimeWindow{}
+	}
+	return window(dawn.In(loc), dusk.In(loc))
+}
+
+func window(start, end time.Time) model.TimeWindow {
+	return model.TimeWindow{Start: start.Format("15:04"), End: end.Format("15:04")}
+}
@@ -0,0 +1,87 @@
+package astro
+
+import (
+	"testing"
+	"time"
+)
+
+// Jakarta, 21 Juni 2024: terbit 06:02 dan terbenam 17:47 WIB (timeanddate.com)
+func TestLocalSunTimes(t *testing.T) {
+	jakarta := mustLocation(t, "Asia/Jakarta")
+	sun := LocalSunTimes(time.Date(2024, 6, 21, 0, 0, 0, 0, jakarta), -6.2088, 106.8456)
+
+	assertClock(t, "sunrise", sun.Sunrise, "06:02")
+	assertClock(t, "sunset", sun.Sunset, "17:47")
+	assertClock(t, "solar noon", sun.SolarNoon, "11:54")
+	if sun.DayLengthMinutes < 700 || sun.DayLengthMinutes > 710 {
+		t.Errorf("day length = %d min, want about 705", sun.DayLengthMinutes)
+	}
+
+	// Urutan pagi: senja astronomis, nautika, sipil, blue hour, golden hour
+	morning := []struct{ name, value string }{
+		{"astronomical dawn", sun.AstronomicalTwilight.Start},
+		{"nautical dawn", sun.NauticalTwilight.Start},
+		{"civil dawn", sun.CivilTwilight.Start},
+		{"blue hour end", sun.BlueHourMorning.End},
+		{"sunrise", sun.Sunrise},
+		{"golden hour end", sun.GoldenHourMorning.End},
+	}
+	for i := 1; i < len(morning); i++ {
+		if morning[i-1].value >= morning[i].value {
+			t.Errorf("%s (%s) is not before %s (%s)", morning[i-1].name, morning[i-1].value, morning[i].name, morning[i].value)
+		}
+	}
+}
+
+func TestLocalSunTimesPolar(t *testing.T) {
+	oslo := mustLocation(t, "Europe/Oslo")
+	const tromsoLat, tromsoLon = 69.6496, 18.9560
+
+	t.Run("midnight sun", func(t *testing.T) {
+		sun := LocalSunTimes(time.Date(2024, 6, 21, 0, 0, 0, 0, oslo), tromsoLat, tromsoLon)
+		if sun.Sunrise != "" || sun.Sunset != "" {
+			t.Errorf("sunrise, sunset = %q, %q; want empty", sun.Sunrise, sun.Sunset)
+		}
+		if sun.DayLengthMinutes != 24*60 {
+			t.Errorf("day length = %d min, want %d", sun.DayLengthMinutes, 24*60)
+		}
+		if sun.CivilTwilight.Start != "" || sun.AstronomicalTwilight.Start != "" {
+			t.Errorf("twilight = %+v, want empty when the sun never sets", sun.CivilTwilight)
+		}
+		assertClock(t, "solar noon", sun.SolarNoon, "12:46")
+	})
+
+	t.Run("polar night", func(t *testing.T) {
+		sun := LocalSunTimes(time.Date(2024, 12, 21, 0, 0, 0, 0, oslo), tromsoLat, tromsoLon)
+		if sun.Sunrise != "" || sun.DayLengthMinutes != 0 {
+			t.Errorf("sunrise, day length = %q, %d; want empty, 0", sun.Sunrise, sun.DayLengthMinutes)
+		}
+		// Matahari tetap naik sampai sekitar -3°, jadi senja sipil masih ada
+		if sun.CivilTwilight.Start == "" || sun.CivilTwilight.End == "" {
+			t.Errorf("civil twilight = %+v, want a window around noon", sun.CivilTwilight)
+		}
+	})
+}
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+// Jam "15:04" boleh meleset 2 menit dari acuan
+func assertClock(t *testing.T, name, got, want string) {
+	t.Helper()
+	g, err1 := time.Parse("15:04", got)
+	w, err2 := time.Parse("15:04", want)
+	if err1 != nil || err2 != nil {
+		t.Errorf("%s = %q, want about %s", name, got, want)
+		return
+	}
+	if diff := g.Sub(w); diff < -2*time.Minute || diff > 2*time.Minute {
+		t.Errorf("%s = %s, want %s ± 2 min", name, got, want)
+	}
+}
@@ -0,0 +1,44 @@
+// Package cache menyimpan hasil upstream per jenis data + lokasi, di memory
+// per instance atau di Redis yang dibagi antar instance. Jenis data, TTL dan
+// pembulatan lokasi ditentukan di package services.
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Penyimpanan cache plus operasi untuk /admin/cache ---
+type Store interface {
+	services.Cache
+	// Hapus entry berdasarkan jenis data dan/atau lokasi; kosong = semua
+	Invalidate(ctx context.Context, dataType, location string) (int, error)
+	// Entry yang belum kadaluarsa
+	Items(ctx context.Context) ([]Item, error)
+}
+
+type Item struct {
+	DataType string
+	Location string
+	StoredAt time.Time
+	Expires  time.Time
+	Hits     int64
+}
+
+// --- Dipanggil tiap lookup, untuk statistik hit/miss di main ---
+type ObserveFunc func(dataType string, hit bool)
+
+// --- Tempat mendaftarkan hook shutdown (janitor, koneksi Redis) ---
+type Lifecycle interface {
+	OnShutdown(name string, fn func(ctx context.Context) error)
+}
+
+// Entry lokasi yang sama bisa bersufiks: "<lokasi>@<sumber>" untuk response
+// gabungan per provider, "<lokasi>:<rentang>" untuk history & klimatologi
+func matchesLocation(key, location string) bool {
+	rest, ok := strings.CutPrefix(key, location)
+	return ok && (rest == "" || rest[0] == '@' || rest[0] == ':')
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// --- Cache in-memory per instance ---
+type entry struct {
+	Item
+	value []byte
+}
+
+type Memory struct {
+	observe ObserveFunc
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// observe boleh nil
+func NewMemory(observe ObserveFunc) *Memory {
+	if observe == nil {
+		observe = func(string, bool) {}
+	}
+	return &Memory{observe: observe, entries: map[string]*entry{}}
+}
+
+func (s *Memory) Get(_ context.Context, dataType, location string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[dataType+"|"+location]
+	if !ok || time.Now().After(e.Expires) {
+		s.observe(dataType, false)
+		return nil, false
+	}
+	e.Hits++
+	s.observe(dataType, true)
+	return e.value, true
+}
+
+func (s *Memory) Set(_ context.Context, dataType, location string, value []byte, ttl time.Duration) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[dataType+"|"+location] = &entry{
+		Item:  Item{DataType: dataType, Location: location, StoredAt: now, Expires: now.Add(ttl)},
+		value: value,
+	}
+}
+
+func (s *Memory) Invalidate(_ context.Context, dataType, location string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for key, e := range s.entries {
+		if (dataType == "" || e.DataType == dataType) && (location == "" || matchesLocation(e.Location, location)) {
+			delete(s.entries, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (s *Memory) Items(context.Context) ([]Item, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make([]Item, 0, len(s.entries))
+	for _, e := range s.entries {
+		if !now.After(e.Expires) {
+			items = append(items, e.Item)
+		}
+	}
+	return items, nil
+}
+
+func (s *Memory) removeExpired() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, e := range s.entries {
+		if now.After(e.Expires) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// --- Bersihkan entry kadaluarsa secara berkala sampai shutdown ---
+func (s *Memory) StartJanitor(lc Lifecycle, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.OnShutdown("cache janitor", func(context.Context) error {
+		cancel()
+		return nil
+	})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.removeExpired()
+			}
+		}
+	}()
+}
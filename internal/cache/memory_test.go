@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+func TestMemoryCacheInvalidateLocation(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemory(nil)
+	location := services.CacheLocation(-6.2, 106.8)
+	keys := []struct{ dataType, location string }{
+		{services.CacheConditions, location},
+		{services.CacheConditions, location + "@bmkg"},
+		{services.CacheHistory, location + ":2024-06-01:2024-06-07"},
+		{services.CacheClimatology, location + ":1994-2023"},
+		{services.CacheConditions, services.CacheLocation(-6.2, 106.81)},
+		// Lokasi lain yang kebetulan berawalan sama
+		{services.CacheConditions, location + "5"},
+	}
+	for _, k := range keys {
+		store.Set(ctx, k.dataType, k.location, []byte("{}"), time.Hour)
+	}
+
+	removed, err := store.Invalidate(ctx, "", location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 4 {
+		t.Errorf("removed = %d, want 4", removed)
+	}
+	for i, k := range keys {
+		_, ok := store.Get(ctx, k.dataType, k.location)
+		if want := i >= 4; ok != want {
+			t.Errorf("%s %s still cached = %v, want %v", k.dataType, k.location, ok, want)
+		}
+	}
+}
+
+func TestMemoryCacheInvalidateTypeAndLocation(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemory(nil)
+	location := services.CacheLocation(-6.2, 106.8)
+	store.Set(ctx, services.CacheConditions, location+"@open-meteo", []byte("{}"), time.Hour)
+	store.Set(ctx, services.CacheHistory, location+":2024-06-01:2024-06-07", []byte("{}"), time.Hour)
+
+	removed, err := store.Invalidate(ctx, services.CacheConditions, location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, _ := store.Items(ctx)
+	types := make([]string, len(items))
+	for i, item := range items {
+		types[i] = item.DataType
+	}
+	if removed != 1 || !slices.Equal(types, []string{services.CacheHistory}) {
+		t.Errorf("removed = %d, remaining types = %v; want 1, [%s]", removed, types, services.CacheHistory)
+	}
+}
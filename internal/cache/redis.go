@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// --- Cache di Redis, supaya semua instance berbagi hasil upstream ---
+// Key: <prefix>:cache:<jenis data>:<lokasi>, umur entry memakai TTL Redis.
+// Redis yang lambat atau mati cukup dianggap miss; request tetap dilayani
+// langsung dari upstream. Kuota & idempotency yang ikut memakai Redis ini
+// memakai batas waktu yang sama.
+const RedisTimeout = 500 * time.Millisecond
+
+type RedisOptions struct {
+	// Misal redis://:password@localhost:6379/0
+	URL string
+	// Awalan key, supaya beberapa environment bisa berbagi satu Redis
+	KeyPrefix string
+	// TTL yang berlaku per jenis data, untuk memperkirakan waktu simpan
+	TTL     func(dataType string) time.Duration
+	Observe ObserveFunc
+}
+
+type Redis struct {
+	client  *redis.Client
+	prefix  string
+	ttl     func(dataType string) time.Duration
+	observe ObserveFunc
+}
+
+// Koneksi ditutup saat shutdown
+func NewRedis(opts RedisOptions, lc Lifecycle) (*Redis, error) {
+	redisOpts, err := redis.ParseURL(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("cache.redis.url is invalid: %v", err)
+	}
+	client := redis.NewClient(redisOpts)
+	lc.OnShutdown("redis cache", func(context.Context) error {
+		return client.Close()
+	})
+	store := &Redis{client: client, prefix: opts.KeyPrefix + ":cache:", ttl: opts.TTL, observe: opts.Observe}
+	if store.observe == nil {
+		store.observe = func(string, bool) {}
+	}
+	return store, nil
+}
+
+func (s *Redis) key(dataType, location string) string {
+	return s.prefix + dataType + ":" + location
+}
+
+func (s *Redis) Get(ctx context.Context, dataType, location string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(ctx, RedisTimeout)
+	defer cancel()
+	value, err := s.client.Get(ctx, s.key(dataType, location)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.WarnContext(ctx, "redis cache get failed", "type", dataType, "error", err)
+		}
+		s.observe(dataType, false)
+		return nil, false
+	}
+	s.observe(dataType, true)
+	return value, true
+}
+
+func (s *Redis) Set(ctx context.Context, dataType, location string, value []byte, ttl time.Duration) {
+	// Tetap disimpan walau request-nya sudah selesai/dibatalkan
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), RedisTimeout)
+	defer cancel()
+	if err := s.client.Set(ctx, s.key(dataType, location), value, ttl).Err(); err != nil {
+		slog.WarnContext(ctx, "redis cache set failed", "type", dataType, "error", err)
+	}
+}
+
+// Lokasi juga mencakup key bersufiks "@<sumber>" dan ":<rentang>" (lihat matchesLocation)
+func (s *Redis) Invalidate(ctx context.Context, dataType, location string) (int, error) {
+	dataType = cmp.Or(dataType, "*")
+	patterns := []string{s.key(dataType, "*")}
+	if location != "" {
+		patterns = []string{s.key(dataType, location), s.key(dataType, location+"@*"), s.key(dataType, location+":*")}
+	}
+	removed := 0
+	var batch []string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := s.client.Unlink(ctx, batch...).Result()
+		removed += int(n)
+		batch = batch[:0]
+		return err
+	}
+	for _, pattern := range patterns {
+		iter := s.client.Scan(ctx, 0, pattern, 500).Iterator()
+		for iter.Next(ctx) {
+			if batch = append(batch, iter.Val()); len(batch) == 500 {
+				if err := flush(); err != nil {
+					return removed, err
+				}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return removed, err
+		}
+	}
+	return removed, flush()
+}
+
+// Redis tidak menyimpan waktu simpan dan jumlah hit per entry; waktu simpan
+// diperkirakan dari sisa TTL, hit per entry selalu 0.
+func (s *Redis) Items(ctx context.Context) ([]Item, error) {
+	var keys []string
+	iter := s.client.Scan(ctx, 0, s.prefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	pipe := s.client.Pipeline()
+	ttls := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		ttls[i] = pipe.PTTL(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	now := time.Now()
+	items := make([]Item, 0, len(keys))
+	for i, key := range keys {
+		remaining := ttls[i].Val()
+		dataType, location, ok := strings.Cut(strings.TrimPrefix(key, s.prefix), ":")
+		if !ok || remaining <= 0 {
+			continue
+		}
+		expires := now.Add(remaining)
+		items = append(items, Item{
+			DataType: dataType,
+			Location: location,
+			StoredAt: expires.Add(-s.ttl(dataType)),
+			Expires:  expires,
+		})
+	}
+	return items, nil
+}
+
+// Koneksi yang sama dipakai penghitung kuota & Idempotency-Key
+func (s *Redis) Client() *redis.Client {
+	return s.client
+}
+
+func (s *Redis) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
@@ -0,0 +1,19 @@
+// Package clock menyediakan sumber waktu yang bisa diganti, supaya fase bulan,
+// jam matahari dan indeks bisa dihitung (dan dites) untuk tanggal mana pun.
+package clock
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+// --- Jam sistem, dipakai di production ---
+type System struct{}
+
+func (System) Now() time.Time { return time.Now() }
+
+// --- Waktu tetap, untuk tes atau request dengan tanggal tertentu ---
+type Fixed time.Time
+
+func (f Fixed) Now() time.Time { return time.Time(f) }
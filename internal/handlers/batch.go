@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Satu titik dalam batch; gagal per titik tidak menggagalkan seluruh batch ---
+type BatchLocation struct {
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+	Name string  `json:"name,omitempty"`
+}
+
+type BatchResult struct {
+	// Posisi titik di parameter points / body (mulai 0)
+	Index    int           `json:"index"`
+	Location BatchLocation `json:"location"`
+	Data     any           `json:"data,omitempty"`
+	Error    *APIError     `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+	Meta    BatchMeta     `json:"meta"`
+}
+
+type BatchMeta struct {
+	Units     string `json:"units"`
+	Count     int    `json:"count"`
+	Succeeded int    `json:"succeeded"`
+}
+
+// --- Handler untuk GET /weather?points=lat,lon;lat,lon ---
+// Untuk client sederhana yang butuh beberapa titik tapi sulit mengirim body POST.
+func (h *Weather) ByPoints(c *gin.Context) {
+	points, err := parsePoints(rawQueryValue(c, "points"), h.settings().MaxBatchPoints)
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	opts, ok := parseResponseOptions(c, c.Query("units"))
+	if !ok {
+		return
+	}
+	h.respondBatch(c, points, opts)
+}
+
+// --- Satu titik di body POST /weather/batch; name dikembalikan apa adanya ---
+type BatchPointRequest struct {
+	locationInput
+	Name string `json:"name,omitempty"`
+}
+
+// --- Handler untuk POST /weather/batch dengan body [{lat, lon, name}, ...] ---
+// Untuk aplikasi mobile yang menampilkan banyak lokasi tersimpan sekaligus.
+func (h *Weather) ByBatch(c *gin.Context) {
+	var input []BatchPointRequest
+	if !BindJSON(c, &input) {
+		return
+	}
+	points, err := batchPoints(input, h.settings().MaxBatchPoints)
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	opts, ok := parseResponseOptions(c, c.Query("units"))
+	if !ok {
+		return
+	}
+	h.respondBatch(c, points, opts)
+}
+
+func batchPoints(input []BatchPointRequest, limit int) ([]BatchLocation, error) {
+	if len(input) == 0 {
+		return nil, fmt.Errorf("body must be a non-empty array of locations, e.g. [{\"lat\": -7.54, \"lon\": 110.44, \"name\": \"Merbabu\"}]")
+	}
+	if limit > 0 && len(input) > limit {
+		return nil, fmt.Errorf("too many points: %d (max %d)", len(input), limit)
+	}
+	points := make([]BatchLocation, len(input))
+	for i, in := range input {
+		lat, lon, err := in.resolve()
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i+1, err)
+		}
+		points[i] = BatchLocation{Lat: lat, Lon: lon, Name: in.Name}
+	}
+	return points, nil
+}
+
+// --- Nilai query mentah; url.Query membuang pasangan yang memuat ";" ---
+// padahal ";" justru pemisah titik di parameter points.
+func rawQueryValue(c *gin.Context, key string) string {
+	for _, pair := range strings.Split(c.Request.URL.RawQuery, "&") {
+		name, value, _ := strings.Cut(pair, "=")
+		if name != key {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			return unescaped
+		}
+		return value
+	}
+	return ""
+}
+
+func parsePoints(raw string, limit int) ([]BatchLocation, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, fmt.Errorf("points is required, e.g. points=-7.54,110.44;-8.41,116.45")
+	}
+	var points []BatchLocation
+	for i, pair := range strings.Split(raw, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		lat, lon, err := parseCoordinatePair(pair)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i+1, err)
+		}
+		points = append(points, BatchLocation{Lat: lat, Lon: lon})
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("points must contain at least one coordinate pair")
+	}
+	if limit > 0 && len(points) > limit {
+		return nil, fmt.Errorf("too many points: %d (max %d)", len(points), limit)
+	}
+	return points, nil
+}
+
+// --- Worker pool: paling banyak `workers` titik diproses bersamaan ---
+// Hasil dikirim ke channel begitu satu titik selesai (urutan selesai, bukan
+// urutan input); channel ditutup setelah semua titik selesai atau ctx batal.
+type pointResult struct {
+	index    int
+	response model.ConsolidatedResponse
+	err      error
+}
+
+func (h *Weather) conditionsStream(ctx context.Context, points []BatchLocation, workers int, source string) <-chan pointResult {
+	if workers <= 0 || workers > len(points) {
+		workers = len(points)
+	}
+	jobs := make(chan int)
+	results := make(chan pointResult)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				response, err := h.svc.Conditions(ctx, points[i].Lat, points[i].Lon, source)
+				results <- pointResult{index: i, response: response, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range points {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// --- Kumpulkan semua hasil, urutan sama dengan urutan input ---
+func (h *Weather) conditionsFor(ctx context.Context, points []BatchLocation, source string) ([]model.ConsolidatedResponse, []error) {
+	responses := make([]model.ConsolidatedResponse, len(points))
+	errs := make([]error, len(points))
+	done := make([]bool, len(points))
+	for r := range h.conditionsStream(ctx, points, h.settings().BatchConcurrency, source) {
+		responses[r.index], errs[r.index], done[r.index] = r.response, r.err, true
+	}
+	// Titik yang belum sempat diproses saat deadline lewat
+	for i := range points {
+		if !done[i] {
+			errs[i] = ctx.Err()
+		}
+	}
+	return responses, errs
+}
+
+// --- Satu hasil batch dalam bentuk response biasa (units, skala AQI & sparse fields) ---
+func batchResult(index int, p BatchLocation, response model.ConsolidatedResponse, err error, opts responseOptions, fields string) BatchResult {
+	result := BatchResult{Index: index, Location: p}
+	if err != nil {
+		_, apiErr := serviceError(err)
+		result.Error = &apiErr
+		return result
+	}
+	converted := opts.apply(response)
+	result.Data = converted
+	if fields != "" {
+		// fields sudah divalidasi sebelum batch dimulai
+		result.Data, _ = selectFields(converted, fields)
+	}
+	return result
+}
+
+func (h *Weather) respondBatch(c *gin.Context, points []BatchLocation, opts responseOptions) {
+	fields := c.Query("fields")
+	if fields != "" && !wantsJSONAPI(c) {
+		// Dicek dari tipe response, bukan nilai kosongnya; section omitempty
+		// seperti volcano tidak ada di JSON nilai kosong
+		if _, err := parseFields(reflect.TypeFor[model.ConsolidatedResponse](), fields); err != nil {
+			AbortBadRequest(c, ErrCodeInvalidFields, err)
+			return
+		}
+	}
+
+	switch {
+	case wantsNDJSON(c):
+		h.streamBatch(c, points, opts, fields)
+		return
+	case wantsJSONAPI(c):
+		responses, errs := h.conditionsFor(c.Request.Context(), points, opts.source)
+		h.respondBatchJSONAPI(c, points, opts, responses, errs)
+		return
+	}
+
+	responses, errs := h.conditionsFor(c.Request.Context(), points, opts.source)
+	batch := BatchResponse{Results: make([]BatchResult, len(points)), Meta: BatchMeta{Units: opts.units, Count: len(points)}}
+	for i, p := range points {
+		batch.Results[i] = batchResult(i, p, responses[i], errs[i], opts, fields)
+		if errs[i] == nil {
+			batch.Meta.Succeeded++
+		}
+	}
+	c.JSON(http.StatusOK, batch)
+}
+
+// --- NDJSON: satu BatchResult per baris, dikirim begitu titiknya selesai ---
+// Baris datang sesuai urutan selesai; pakai "index" untuk memetakan ke input.
+func (h *Weather) streamBatch(c *gin.Context, points []BatchLocation, opts responseOptions, fields string) {
+	c.Header("Content-Type", ndjsonMediaType)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	enc := json.NewEncoder(c.Writer)
+	done := make([]bool, len(points))
+	write := func(result BatchResult) {
+		// Gagal tulis berarti client sudah putus; worker berhenti lewat context request
+		if enc.Encode(result) == nil {
+			c.Writer.Flush()
+		}
+	}
+	for r := range h.conditionsStream(ctx, points, h.settings().BatchConcurrency, opts.source) {
+		done[r.index] = true
+		write(batchResult(r.index, points[r.index], r.response, r.err, opts, fields))
+	}
+	for i, p := range points {
+		if !done[i] {
+			write(batchResult(i, p, model.ConsolidatedResponse{}, ctx.Err(), opts, fields))
+		}
+	}
+}
+
+// Titik yang gagal dilaporkan di meta.failures; JSON:API melarang data dan errors sekaligus
+func (h *Weather) respondBatchJSONAPI(c *gin.Context, points []BatchLocation, opts responseOptions, responses []model.ConsolidatedResponse, errs []error) {
+	fields := c.QueryMap("fields")
+	data, included := []jsonAPIResource{}, []jsonAPIResource{}
+	failures := []BatchResult{}
+	for i, p := range points {
+		if errs[i] != nil {
+			_, apiErr := serviceError(errs[i])
+			failures = append(failures, BatchResult{Index: i, Location: p, Error: &apiErr})
+			continue
+		}
+		resources, err := conditionsResources(p.Lat, p.Lon, opts.apply(responses[i]), fields)
+		if err != nil {
+			AbortBadRequest(c, ErrCodeInvalidFields, err)
+			return
+		}
+		data = append(data, resources[0])
+		included = append(included, resources[1:]...)
+	}
+	respondJSONAPI(c, http.StatusOK, jsonAPIDocument{
+		JSONAPI:  jsonAPIVersion,
+		Data:     data,
+		Included: included,
+		Meta:     gin.H{"units": opts.units, "count": len(points), "failures": failures},
+	})
+}
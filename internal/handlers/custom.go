@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Header pemilik indeks kustom yang disimpan ---
+// Key disimpan sebagai hash, tidak pernah dalam bentuk asli.
+const APIKeyHeader = "X-API-Key"
+
+// --- Handler REST untuk indeks buatan pengguna ---
+type CustomIndices struct {
+	svc   *services.Weather
+	store services.CustomIndexStore
+}
+
+func NewCustomIndices(svc *services.Weather, store services.CustomIndexStore) *CustomIndices {
+	return &CustomIndices{svc: svc, store: store}
+}
+
+// --- Body POST /indices/custom: lokasi + aturan, atau lokasi + nama indeks tersimpan ---
+type CustomIndexRequest struct {
+	locationInput
+	indices.CustomIndex
+	// Simpan aturan dengan nama ini untuk API key di header X-API-Key
+	Save bool `json:"save,omitempty"`
+}
+
+type CustomIndexList struct {
+	Indices []indices.CustomIndex `json:"indices"`
+}
+
+// --- Handler untuk POST /indices/custom ---
+func (h *CustomIndices) Score(c *gin.Context) {
+	var input CustomIndexRequest
+	if !BindJSON(c, &input) {
+		return
+	}
+	lat, lon, err := input.resolve()
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+
+	index := input.CustomIndex
+	// Tanpa aturan: pakai indeks tersimpan dengan nama tersebut
+	if len(index.Rules) == 0 && index.Name != "" && !input.Save {
+		owner, ok := requireAPIKey(c, "saved custom indices")
+		if !ok {
+			return
+		}
+		if index, err = h.store.Get(c.Request.Context(), owner, index.Name); err != nil {
+			AbortWithServiceError(c, err)
+			return
+		}
+	} else if err := index.Validate(input.Save); err != nil {
+		AbortBadRequest(c, ErrCodeInvalidRequest, err)
+		return
+	}
+	var owner string
+	if input.Save {
+		var ok bool
+		if owner, ok = requireAPIKey(c, "saved custom indices"); !ok {
+			return
+		}
+	}
+
+	response, err := h.svc.CustomIndex(c.Request.Context(), lat, lon, index)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	if input.Save {
+		if err := h.store.Save(c.Request.Context(), owner, index); err != nil {
+			AbortWithServiceError(c, err)
+			return
+		}
+		response.Saved = true
+	}
+	c.JSON(statusFor(response.Meta), response)
+}
+
+// --- Handler untuk GET /indices/custom: indeks tersimpan milik API key ---
+func (h *CustomIndices) List(c *gin.Context) {
+	owner, ok := requireAPIKey(c, "saved custom indices")
+	if !ok {
+		return
+	}
+	list, err := h.store.List(c.Request.Context(), owner)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CustomIndexList{Indices: list})
+}
+
+// --- Handler untuk DELETE /indices/custom/:name ---
+func (h *CustomIndices) Delete(c *gin.Context) {
+	owner, ok := requireAPIKey(c, "saved custom indices")
+	if !ok {
+		return
+	}
+	if err := h.store.Delete(c.Request.Context(), owner, c.Param("name")); err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Pemilik = hash SHA-256 dari X-API-Key; ok false berarti 401 sudah dikirim.
+// what menyebut fitur yang butuh key di pesan error.
+func requireAPIKey(c *gin.Context, what string) (string, bool) {
+	key := c.GetHeader(APIKeyHeader)
+	if key == "" {
+		AbortWithError(c, http.StatusUnauthorized, APIError{
+			Code:    ErrCodeAPIKeyRequired,
+			Message: APIKeyHeader + " header is required for " + what,
+		})
+		return "", false
+	}
+	return HashAPIKey(key), true
+}
+
+// --- Hash SHA-256 (hex) dari API key; bentuk yang disimpan & dicari ---
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,172 @@
+// Package handlers berisi handler HTTP & GraphQL untuk data cuaca, parsing
+// lokasi, serta error envelope yang dipakai semua endpoint.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Kode error yang bisa dipakai client untuk branching ---
+const (
+	ErrCodeInvalidRequest     = "invalid_request"
+	ErrCodeInvalidLocation    = "invalid_location"
+	ErrCodeInvalidUnits       = "invalid_units"
+	ErrCodeInvalidAQIScale    = "invalid_aqi_scale"
+	ErrCodeInvalidProvider    = "invalid_provider"
+	ErrCodeInvalidFields      = "invalid_fields"
+	ErrCodeInvalidLang        = "invalid_lang"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeAPIKeyRequired     = "api_key_required"
+	ErrCodeCustomIndexLimit   = "custom_index_limit"
+	ErrCodeFavoriteLimit      = "favorite_limit"
+	ErrCodeSubscriptionLimit  = "subscription_limit"
+	ErrCodeChannelUnavailable = "channel_unavailable"
+	ErrCodeDeviceLimit        = "device_limit"
+	ErrCodeBodyTooLarge       = "body_too_large"
+	ErrCodeUpstreamError      = "upstream_error"
+	ErrCodeUpstreamTimeout    = "upstream_timeout"
+	ErrCodeUpstreamDisabled   = "upstream_disabled"
+	ErrCodeCircuitOpen        = "upstream_circuit_open"
+	ErrCodeRequestCanceled    = "request_canceled"
+	ErrCodeInternal           = "internal_error"
+)
+
+// Konvensi nginx untuk client yang menutup koneksi sebelum response dikirim;
+// client-nya tidak membaca apa pun, tapi access log & metrics jadi jelas.
+const statusClientClosedRequest = 499
+
+// Key gin.Context tempat APIError disimpan untuk access log & error reporter
+const ErrorKey = "api_error"
+
+// --- Skema error yang sama untuk semua handler ---
+type APIError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+	Provider  string `json:"provider,omitempty"`
+	// Untuk error validasi: input yang salah dan alasannya (lihat Reason*)
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// --- Tulis error envelope dan hentikan chain handler ---
+func AbortWithError(c *gin.Context, status int, apiErr APIError) {
+	c.Set(ErrorKey, apiErr)
+	if wantsJSONAPI(c) {
+		abortWithJSONAPIError(c, status, apiErr)
+		return
+	}
+	c.AbortWithStatusJSON(status, ErrorResponse{Error: apiErr})
+}
+
+func AbortBadRequest(c *gin.Context, code string, err error) {
+	AbortWithError(c, http.StatusBadRequest, badRequestError(code, err))
+}
+
+// Error validasi field ikut membawa field & reason-nya
+func badRequestError(code string, err error) APIError {
+	apiErr := APIError{Code: code, Message: err.Error()}
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		apiErr.Field, apiErr.Reason = fieldErr.Field, fieldErr.Reason
+	}
+	return apiErr
+}
+
+// --- Petakan error dari service ke status & kode yang sesuai ---
+func AbortWithServiceError(c *gin.Context, err error) {
+	status, apiErr := serviceError(err)
+	AbortWithError(c, status, apiErr)
+}
+
+func serviceError(err error) (int, APIError) {
+	var providerErr *providers.Error
+	if errors.As(err, &providerErr) && providerErr.Kind == providers.KindCanceled {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return http.StatusGatewayTimeout, APIError{Code: ErrCodeUpstreamTimeout, Message: "request deadline exceeded", Retryable: true}
+		}
+		return statusClientClosedRequest, APIError{Code: ErrCodeRequestCanceled, Message: "request canceled by client"}
+	}
+	if errors.As(err, &providerErr) {
+		status, code := http.StatusBadGateway, ErrCodeUpstreamError
+		switch providerErr.Kind {
+		case providers.KindTimeout:
+			status, code = http.StatusGatewayTimeout, ErrCodeUpstreamTimeout
+		case providers.KindDisabled:
+			status, code = http.StatusServiceUnavailable, ErrCodeUpstreamDisabled
+		case providers.KindCircuitOpen:
+			status, code = http.StatusServiceUnavailable, ErrCodeCircuitOpen
+		}
+		return status, APIError{
+			Code:    code,
+			Message: providerErr.Error(),
+			// Circuit yang terbuka akan dicoba lagi setelah cooldown
+			Retryable: providerErr.Retryable() || providerErr.Kind == providers.KindCircuitOpen,
+			Provider:  providerErr.Provider,
+		}
+	}
+	if errors.Is(err, services.ErrPlaceNotFound) || errors.Is(err, services.ErrNoBMKGArea) || errors.Is(err, services.ErrNoSeaData) ||
+		errors.Is(err, services.ErrCustomIndexNotFound) || errors.Is(err, services.ErrFavoriteNotFound) || errors.Is(err, services.ErrAPIKeyNotFound) ||
+		errors.Is(err, services.ErrSubscriptionNotFound) || errors.Is(err, services.ErrDeviceNotFound) {
+		return http.StatusNotFound, APIError{Code: ErrCodeNotFound, Message: err.Error()}
+	}
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		return http.StatusBadRequest, APIError{Code: ErrCodeInvalidRequest, Message: err.Error()}
+	}
+	if errors.Is(err, services.ErrCustomIndexLimit) {
+		return http.StatusUnprocessableEntity, APIError{Code: ErrCodeCustomIndexLimit, Message: err.Error()}
+	}
+	if errors.Is(err, services.ErrFavoriteLimit) {
+		return http.StatusUnprocessableEntity, APIError{Code: ErrCodeFavoriteLimit, Message: err.Error()}
+	}
+	if errors.Is(err, services.ErrSubscriptionLimit) {
+		return http.StatusUnprocessableEntity, APIError{Code: ErrCodeSubscriptionLimit, Message: err.Error()}
+	}
+	if errors.Is(err, services.ErrDeviceLimit) {
+		return http.StatusUnprocessableEntity, APIError{Code: ErrCodeDeviceLimit, Message: err.Error()}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, APIError{Code: ErrCodeUpstreamTimeout, Message: "request deadline exceeded", Retryable: true}
+	}
+	return http.StatusInternalServerError, APIError{Code: ErrCodeInternal, Message: err.Error()}
+}
+
+// --- 404 & 405 pakai envelope yang sama ---
+func NoRoute(c *gin.Context) {
+	AbortWithError(c, http.StatusNotFound, APIError{Code: ErrCodeNotFound, Message: "route not found"})
+}
+
+func NoMethod(c *gin.Context) {
+	AbortWithError(c, http.StatusMethodNotAllowed, APIError{Code: ErrCodeInvalidRequest, Message: "method not allowed"})
+}
+
+// --- Bind body JSON; body kebesaran dibalas 413, body rusak 400 ---
+func BindJSON(c *gin.Context, dst any) bool {
+	err := c.ShouldBindJSON(dst)
+	if err == nil {
+		return true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		AbortWithError(c, http.StatusRequestEntityTooLarge, APIError{
+			Code:    ErrCodeBodyTooLarge,
+			Message: "request body exceeds " + strconv.FormatInt(tooLarge.Limit, 10) + " bytes",
+		})
+		return false
+	}
+	AbortBadRequest(c, ErrCodeInvalidRequest, fmt.Errorf("invalid request body: %v", err))
+	return false
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// ID pengguna dari aplikasi client; server tidak mengelola akun
+var userIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+const maxFavoriteNameLength = 100
+
+// --- Handler REST untuk lokasi favorit per pengguna ---
+// Daftar disimpan di bawah API key (X-API-Key), jadi ID pengguna cukup unik
+// di dalam satu aplikasi.
+type Favorites struct {
+	weather *Weather
+	store   services.FavoriteStore
+}
+
+func NewFavorites(weather *Weather, store services.FavoriteStore) *Favorites {
+	return &Favorites{weather: weather, store: store}
+}
+
+// --- Body POST /users/:id/locations ---
+type FavoriteRequest struct {
+	locationInput
+	Name string `json:"name"`
+}
+
+type FavoriteList struct {
+	Locations []model.Favorite `json:"locations"`
+}
+
+// --- Handler untuk POST /users/:id/locations; nama yang sama menimpa lokasi lama ---
+func (h *Favorites) Save(c *gin.Context) {
+	owner, user, ok := favoriteOwner(c)
+	if !ok {
+		return
+	}
+	var input FavoriteRequest
+	if !BindJSON(c, &input) {
+		return
+	}
+	name := strings.TrimSpace(input.Name)
+	if err := validateFavoriteName(name); err != nil {
+		AbortBadRequest(c, ErrCodeInvalidRequest, err)
+		return
+	}
+	lat, lon, err := input.resolve()
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	saved, err := h.store.Save(c.Request.Context(), owner, user, model.Favorite{Name: name, Latitude: lat, Longitude: lon})
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, saved)
+}
+
+// --- Handler untuk GET /users/:id/locations ---
+func (h *Favorites) List(c *gin.Context) {
+	owner, user, ok := favoriteOwner(c)
+	if !ok {
+		return
+	}
+	list, err := h.store.List(c.Request.Context(), owner, user)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, FavoriteList{Locations: list})
+}
+
+// --- Handler untuk DELETE /users/:id/locations/:name ---
+func (h *Favorites) Delete(c *gin.Context) {
+	owner, user, ok := favoriteOwner(c)
+	if !ok {
+		return
+	}
+	if err := h.store.Delete(c.Request.Context(), owner, user, c.Param("name")); err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// --- Handler untuk GET /users/:id/dashboard: data gabungan semua favorit ---
+// Bentuknya sama dengan batch; nama favorit ada di location.name tiap hasil.
+func (h *Favorites) Dashboard(c *gin.Context) {
+	owner, user, ok := favoriteOwner(c)
+	if !ok {
+		return
+	}
+	opts, ok := parseResponseOptions(c, c.Query("units"))
+	if !ok {
+		return
+	}
+	list, err := h.store.List(c.Request.Context(), owner, user)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	points := make([]BatchLocation, len(list))
+	for i, favorite := range list {
+		points[i] = BatchLocation{Lat: favorite.Latitude, Lon: favorite.Longitude, Name: favorite.Name}
+	}
+	h.weather.respondBatch(c, points, opts)
+}
+
+// API key pemilik dan ID pengguna dari path; ok false berarti error sudah dikirim
+func favoriteOwner(c *gin.Context) (string, string, bool) {
+	user := c.Param("id")
+	if !userIDPattern.MatchString(user) {
+		AbortBadRequest(c, ErrCodeInvalidRequest, fmt.Errorf("user id must be 1 to 64 letters, digits, '.', '-' or '_', got %q", user))
+		return "", "", false
+	}
+	owner, ok := requireAPIKey(c, "saved locations")
+	return owner, user, ok
+}
+
+func validateFavoriteName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > maxFavoriteNameLength {
+		return fmt.Errorf("name must be 1 to %d characters", maxFavoriteNameLength)
+	}
+	if strings.ContainsFunc(name, unicode.IsControl) {
+		return fmt.Errorf("name must not contain control characters")
+	}
+	return nil
+}
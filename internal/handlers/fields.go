@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// --- Sparse fieldset: ?fields=weather.temperature,indices,sun.sunrise ---
+// Path memakai nama field JSON dipisah titik; blok "meta" selalu ikut supaya
+// client tetap tahu satuan yang dipakai. Path yang tidak dikenal tipe
+// response ditolak, sedangkan field yang dikenal tapi kosong di response ini
+// (misal volcano di luar Indonesia) cukup tidak ikut.
+func selectFields(v any, raw string) (map[string]any, error) {
+	paths, err := parseFields(reflect.TypeOf(v), raw)
+	if err != nil {
+		return nil, err
+	}
+	full, err := toMap(v)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]any{}
+	for _, path := range paths {
+		copyField(full, out, path)
+	}
+	if meta, ok := full["meta"]; ok {
+		out["meta"] = meta
+	}
+	return out, nil
+}
+
+// --- Pecah & cek daftar fields terhadap tag JSON tipe response ---
+func parseFields(t reflect.Type, raw string) ([][]string, error) {
+	var paths [][]string
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		parts := strings.Split(path, ".")
+		if !knownField(t, parts) {
+			return nil, fmt.Errorf("unknown field %q", path)
+		}
+		paths = append(paths, parts)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("fields must list at least one field")
+	}
+	return paths, nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Tipe dengan marshaler sendiri (misal time.Time) dan slice dianggap satu
+// nilai utuh; path tidak bisa masuk ke dalamnya
+func knownField(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		return true
+	}
+	for _, marshaler := range []reflect.Type{jsonMarshalerType, textMarshalerType} {
+		if t.Implements(marshaler) || reflect.PointerTo(t).Implements(marshaler) {
+			return false
+		}
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && knownField(t.Elem(), path[1:])
+	case reflect.Struct:
+		field, ok := jsonField(t, path[0])
+		return ok && knownField(field.Type, path[1:])
+	}
+	return false
+}
+
+// Field struct dengan nama JSON name, termasuk field dari struct embedded
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		tagName, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && tagName == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if found, ok := jsonField(embedded, name); ok {
+					return found, true
+				}
+				continue
+			}
+		}
+		if tagName == "" {
+			tagName = field.Name
+		}
+		if tagName == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// Field yang kosong di response ini dilewati
+func copyField(src, dst map[string]any, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+	child, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	sub, ok := dst[path[0]].(map[string]any)
+	if !ok {
+		sub = map[string]any{}
+		dst[path[0]] = sub
+	}
+	copyField(child, sub, path[1:])
+}
+
+// --- Struct -> map lewat JSON, supaya nama field sama persis dengan response ---
+func toMap(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
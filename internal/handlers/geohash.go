@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+)
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// --- Decode geohash ke titik tengah sel-nya ---
+func decodeGeohash(hash string) (float64, float64, error) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if hash == "" {
+		return 0, 0, fmt.Errorf("geohash is required")
+	}
+	if len(hash) > 12 {
+		return 0, 0, fmt.Errorf("geohash must be at most 12 characters, got %d", len(hash))
+	}
+
+	latMin, latMax := -90.0, 90.0
+	lonMin, lonMax := -180.0, 180.0
+	evenBit := true
+
+	for _, ch := range hash {
+		idx := strings.IndexRune(geohashAlphabet, ch)
+		if idx < 0 {
+			return 0, 0, fmt.Errorf("geohash contains invalid character %q", ch)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := idx&(1<<bit) != 0
+			if evenBit {
+				mid := (lonMin + lonMax) / 2
+				if set {
+					lonMin = mid
+				} else {
+					lonMax = mid
+				}
+			} else {
+				mid := (latMin + latMax) / 2
+				if set {
+					latMin = mid
+				} else {
+					latMax = mid
+				}
+			}
+			evenBit = !evenBit
+		}
+	}
+
+	return (latMin + latMax) / 2, (lonMin + lonMax) / 2, nil
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/i18n"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Request GraphQL standar (POST body atau query string untuk GET) ---
+type GraphQLRequest struct {
+	Query         string         `json:"query" form:"query"`
+	OperationName string         `json:"operationName,omitempty" form:"operationName"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// --- Tipe GraphQL diturunkan dari struct Go (field json snake_case -> camelCase) ---
+// Jadi field baru di WeatherData dkk otomatis muncul di graph tanpa kerja ulang.
+type graphQLTypeBuilder struct {
+	objects map[reflect.Type]*graphql.Object
+}
+
+func (b *graphQLTypeBuilder) outputType(t reflect.Type) graphql.Output {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return graphql.DateTime
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return graphql.Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return graphql.Int
+	case reflect.Float32, reflect.Float64:
+		return graphql.Float
+	case reflect.String:
+		return graphql.String
+	case reflect.Slice, reflect.Array:
+		if elem := b.outputType(t.Elem()); elem != nil {
+			return graphql.NewList(elem)
+		}
+	case reflect.Struct:
+		return b.object(t)
+	}
+	return nil
+}
+
+func (b *graphQLTypeBuilder) object(t reflect.Type) *graphql.Object {
+	if obj, ok := b.objects[t]; ok {
+		return obj
+	}
+	obj := graphql.NewObject(graphql.ObjectConfig{
+		Name: t.Name(),
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := graphql.Fields{}
+			b.collectFields(t, nil, fields)
+			return fields
+		}),
+	})
+	b.objects[t] = obj
+	return obj
+}
+
+func (b *graphQLTypeBuilder) collectFields(t reflect.Type, index []int, fields graphql.Fields) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			b.collectFields(field.Type, fieldIndex, fields)
+			continue
+		}
+		if !field.IsExported() || name == "" {
+			continue
+		}
+
+		out := b.outputType(field.Type)
+		if out == nil {
+			continue
+		}
+		fields[snakeToCamel(name)] = &graphql.Field{
+			Type:    out,
+			Resolve: fieldResolver(fieldIndex),
+		}
+	}
+}
+
+func fieldResolver(index []int) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		v := reflect.ValueOf(p.Source)
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, nil
+		}
+		return v.FieldByIndex(index).Interface(), nil
+	}
+}
+
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// --- Error GraphQL membawa kode yang sama dengan error envelope REST ---
+type graphQLError struct {
+	APIError
+}
+
+func (e graphQLError) Error() string { return e.Message }
+
+func (e graphQLError) Extensions() map[string]any {
+	ext := map[string]any{"code": e.Code, "retryable": e.Retryable}
+	if e.Provider != "" {
+		ext["provider"] = e.Provider
+	}
+	if e.Field != "" {
+		ext["field"], ext["reason"] = e.Field, e.Reason
+	}
+	return ext
+}
+
+func toGraphQLError(code string, err error) error {
+	var providerErr *providers.Error
+	if errors.As(err, &providerErr) {
+		_, apiErr := serviceError(err)
+		return graphQLError{apiErr}
+	}
+	return graphQLError{badRequestError(code, err)}
+}
+
+// --- Schema GraphQL: query conditions, dailyForecast & hourlyForecast ---
+// Prakiraan memakai satuan metrik seperti endpoint /forecast.
+func BuildGraphQLSchema(svc *services.Weather) (graphql.Schema, error) {
+	builder := &graphQLTypeBuilder{objects: map[reflect.Type]*graphql.Object{}}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"conditions": &graphql.Field{
+				Type:        builder.object(reflect.TypeOf(model.ConsolidatedResponse{})),
+				Description: "Weather, sun, moon and indices for a coordinate (decimal or DMS)",
+				Args: graphql.FieldConfigArgument{
+					"lat":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"lon":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"units":    &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: model.UnitsMetric},
+					"aqiScale": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: indices.AQIScaleEuropean},
+					"provider": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: services.SourceAuto},
+					"lang":     &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: i18n.Default},
+				},
+				Resolve: resolveConditions(svc),
+			},
+			"dailyForecast": &graphql.Field{
+				Type:        builder.object(reflect.TypeOf(model.DailyForecastResponse{})),
+				Description: "7-day forecast with a hiking index per day",
+				Args: graphql.FieldConfigArgument{
+					"lat":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"lon":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"lang": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: i18n.Default},
+				},
+				Resolve: resolveDailyForecast(svc),
+			},
+			"hourlyForecast": &graphql.Field{
+				Type:        builder.outputType(reflect.TypeOf(model.HourlyForecast{})),
+				Description: "Hourly forecast for the coming days",
+				Args: graphql.FieldConfigArgument{
+					"lat": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"lon": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolveHourlyForecast(svc),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func resolveConditions(svc *services.Weather) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		rawUnits, _ := p.Args["units"].(string)
+		rawScale, _ := p.Args["aqiScale"].(string)
+		rawSource, _ := p.Args["provider"].(string)
+		rawLang, _ := p.Args["lang"].(string)
+
+		lat, lon, err := graphQLCoordinates(p)
+		if err != nil {
+			return nil, err
+		}
+		units, err := model.ParseUnitSystem(rawUnits)
+		if err != nil {
+			return nil, toGraphQLError(ErrCodeInvalidUnits, err)
+		}
+		scale, err := indices.ParseAQIScale(rawScale)
+		if err != nil {
+			return nil, toGraphQLError(ErrCodeInvalidAQIScale, err)
+		}
+		source, err := services.ParseSource(rawSource)
+		if err != nil {
+			return nil, toGraphQLError(ErrCodeInvalidProvider, err)
+		}
+		lang, err := i18n.ParseLang(rawLang)
+		if err != nil {
+			return nil, toGraphQLError(ErrCodeInvalidLang, err)
+		}
+
+		response, err := svc.Conditions(p.Context, lat, lon, source)
+		if err != nil {
+			return nil, toGraphQLError(ErrCodeInternal, err)
+		}
+		return i18n.LocalizeConditions(model.ApplyUnitSystem(indices.ApplyAQIScale(response, scale), units), lang), nil
+	}
+}
+
+func resolveDailyForecast(svc *services.Weather) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		lat, lon, err := graphQLCoordinates(p)
+		if err != nil {
+			return nil, err
+		}
+		rawLang, _ := p.Args["lang"].(string)
+		lang, err := i18n.ParseLang(rawLang)
+		if err != nil {
+			return nil, toGraphQLError(ErrCodeInvalidLang, err)
+		}
+		response, err := svc.DailyForecast(p.Context, lat, lon)
+		if err != nil {
+			return nil, toGraphQLError(ErrCodeInternal, err)
+		}
+		return i18n.LocalizeDailyForecast(response, lang), nil
+	}
+}
+
+func resolveHourlyForecast(svc *services.Weather) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		lat, lon, err := graphQLCoordinates(p)
+		if err != nil {
+			return nil, err
+		}
+		forecast, err := svc.HourlyForecast(p.Context, lat, lon)
+		if err != nil {
+			return nil, toGraphQLError(ErrCodeInternal, err)
+		}
+		return forecast, nil
+	}
+}
+
+func graphQLCoordinates(p graphql.ResolveParams) (float64, float64, error) {
+	rawLat, _ := p.Args["lat"].(string)
+	rawLon, _ := p.Args["lon"].(string)
+	lat, lon, err := ParseCoordinates(rawLat, rawLon)
+	if err != nil {
+		return 0, 0, toGraphQLError(ErrCodeInvalidLocation, err)
+	}
+	return lat, lon, nil
+}
+
+// --- Handler /graphql (GET & POST) ---
+func GraphQL(schema graphql.Schema) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req GraphQLRequest
+		if c.Request.Method == http.MethodGet {
+			req.Query = c.Query("query")
+			req.OperationName = c.Query("operationName")
+			if raw := c.Query("variables"); raw != "" {
+				if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+					AbortBadRequest(c, ErrCodeInvalidRequest, errors.New("variables must be a JSON object"))
+					return
+				}
+			}
+		} else if !BindJSON(c, &req) {
+			return
+		}
+		if strings.TrimSpace(req.Query) == "" {
+			AbortBadRequest(c, ErrCodeInvalidRequest, errors.New("query is required"))
+			return
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        c.Request.Context(),
+		})
+		c.JSON(http.StatusOK, result)
+	}
+}
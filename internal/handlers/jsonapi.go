@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Format JSON:API (https://jsonapi.org), dipilih lewat Accept: application/vnd.api+json ---
+// Response gabungan dipecah jadi resource bertipe: "conditions" sebagai data
+// utama dengan relationship ke "locations", "weather" dan "indices" yang
+// dikirim di "included". Sparse fieldset memakai gaya JSON:API:
+// ?fields[weather]=temperature,aqi.
+const jsonAPIMediaType = "application/vnd.api+json"
+
+type jsonAPIIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type jsonAPIRelationship struct {
+	Data jsonAPIIdentifier `json:"data"`
+}
+
+type jsonAPIResource struct {
+	jsonAPIIdentifier
+	Attributes    map[string]any                 `json:"attributes,omitempty"`
+	Relationships map[string]jsonAPIRelationship `json:"relationships,omitempty"`
+}
+
+type jsonAPIDocument struct {
+	JSONAPI  map[string]string `json:"jsonapi"`
+	Data     any               `json:"data"`
+	Included []jsonAPIResource `json:"included,omitempty"`
+	Meta     any               `json:"meta,omitempty"`
+}
+
+type jsonAPIError struct {
+	Status string         `json:"status"`
+	Code   string         `json:"code"`
+	Title  string         `json:"title"`
+	Detail string         `json:"detail"`
+	Source map[string]any `json:"source,omitempty"`
+	Meta   map[string]any `json:"meta,omitempty"`
+}
+
+// Bahan satu resource sebelum atributnya diubah ke map
+type jsonAPISource struct {
+	resourceType  string
+	attributes    any
+	relationships map[string]jsonAPIRelationship
+}
+
+func wantsJSONAPI(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, jsonAPIMediaType) == jsonAPIMediaType
+}
+
+// NDJSON hanya untuk endpoint batch; lihat streamBatch
+const ndjsonMediaType = "application/x-ndjson"
+
+func wantsNDJSON(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, ndjsonMediaType) == ndjsonMediaType
+}
+
+// --- Susun dokumen JSON:API untuk satu titik ---
+func conditionsDocument(lat, lon float64, resp model.ConsolidatedResponse, fields map[string]string) (jsonAPIDocument, error) {
+	resources, err := conditionsResources(lat, lon, resp, fields)
+	if err != nil {
+		return jsonAPIDocument{}, err
+	}
+	return jsonAPIDocument{
+		JSONAPI:  jsonAPIVersion,
+		Data:     resources[0],
+		Included: resources[1:],
+		Meta:     resp.Meta,
+	}, nil
+}
+
+var jsonAPIVersion = map[string]string{"version": "1.1"}
+
+// Resource "conditions" selalu di urutan pertama, sisanya untuk "included"
+func conditionsResources(lat, lon float64, resp model.ConsolidatedResponse, fields map[string]string) ([]jsonAPIResource, error) {
+	id := strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lon, 'f', -1, 64)
+	ref := func(resourceType string) jsonAPIRelationship {
+		return jsonAPIRelationship{Data: jsonAPIIdentifier{Type: resourceType, ID: id}}
+	}
+	toLocation := map[string]jsonAPIRelationship{"location": ref("locations")}
+	var place model.Place
+	if resp.Location != nil {
+		place = *resp.Location
+	}
+
+	resources := []jsonAPISource{
+		{"conditions", struct {
+			Sun                   model.SunData         `json:"sun"`
+			Moon                  model.MoonData        `json:"moon"`
+			MilkyWayWindow        *model.TimeWindow     `json:"milky_way_window,omitempty"`
+			LightPollution        *model.LightPollution `json:"light_pollution,omitempty"`
+			Alerts                []model.Alert         `json:"alerts"`
+			RecentSeismicActivity *bool                 `json:"recent_seismic_activity,omitempty"`
+			Volcano               *model.Volcano        `json:"volcano,omitempty"`
+		}{resp.Sun, resp.Moon, resp.MilkyWayWindow, resp.LightPollution, resp.Alerts, resp.RecentSeismicActivity, resp.Volcano}, map[string]jsonAPIRelationship{
+			"location": ref("locations"), "weather": ref("weather"), "indices": ref("indices"),
+		}},
+		{"locations", struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+			Name      string  `json:"name,omitempty"`
+			Region    string  `json:"region,omitempty"`
+			Country   string  `json:"country,omitempty"`
+		}{lat, lon, place.Name, place.Region, place.Country}, nil},
+		{"weather", resp.Weather, toLocation},
+		{"indices", resp.Indices, toLocation},
+	}
+
+	for resourceType := range fields {
+		if !slices.ContainsFunc(resources, func(r jsonAPISource) bool { return r.resourceType == resourceType }) {
+			return nil, fmt.Errorf("unknown resource type %q in fields", resourceType)
+		}
+	}
+
+	out := make([]jsonAPIResource, 0, len(resources))
+	for _, r := range resources {
+		attributes, err := toMap(r.attributes)
+		if err != nil {
+			return nil, err
+		}
+		if raw, ok := fields[r.resourceType]; ok {
+			if attributes, err = filterAttributes(attributes, raw); err != nil {
+				return nil, fmt.Errorf("fields[%s]: %v", r.resourceType, err)
+			}
+		}
+		out = append(out, jsonAPIResource{
+			jsonAPIIdentifier: jsonAPIIdentifier{Type: r.resourceType, ID: id},
+			Attributes:        attributes,
+			Relationships:     r.relationships,
+		})
+	}
+	return out, nil
+}
+
+// Nilai kosong (fields[weather]=) berarti tanpa atribut, sesuai spesifikasi
+func filterAttributes(attributes map[string]any, raw string) (map[string]any, error) {
+	out := map[string]any{}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		value, ok := attributes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		out[name] = value
+	}
+	return out, nil
+}
+
+func respondJSONAPI(c *gin.Context, status int, doc any) {
+	c.Header("Content-Type", jsonAPIMediaType)
+	c.JSON(status, doc)
+}
+
+// --- Error envelope versi JSON:API: {"errors": [...]} ---
+func abortWithJSONAPIError(c *gin.Context, status int, apiErr APIError) {
+	e := jsonAPIError{
+		Status: strconv.Itoa(status),
+		Code:   apiErr.Code,
+		Title:  http.StatusText(status),
+		Detail: apiErr.Message,
+		Meta:   map[string]any{"retryable": apiErr.Retryable},
+	}
+	if apiErr.Provider != "" {
+		e.Meta["provider"] = apiErr.Provider
+	}
+	if apiErr.Field != "" {
+		e.Source = map[string]any{"parameter": apiErr.Field}
+		e.Meta["reason"] = apiErr.Reason
+	}
+	c.Header("Content-Type", jsonAPIMediaType)
+	c.AbortWithStatusJSON(status, gin.H{"errors": []jsonAPIError{e}})
+}
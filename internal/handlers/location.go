@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// --- Parse & validasi lat/lon sebelum dipakai ke upstream API ---
+// Format yang diterima per komponen:
+//   - desimal: "-7.54", "-7,54", "7.54 S", "S 7.54"
+//   - DMS: "7°32'S", "7°32'15\"S", "7 32 15 LS"
+//
+// Hemisfer bisa pakai N/S/E/W atau singkatan Indonesia LU/LS/BT/BB.
+func ParseCoordinates(rawLat, rawLon string) (float64, float64, error) {
+	lat, err := parseCoordinate(rawLat, "lat", 90)
+	if err != nil {
+		return 0, 0, err
+	}
+	lon, err := parseCoordinate(rawLon, "lon", 180)
+	if err != nil {
+		return 0, 0, err
+	}
+	return lat, lon, nil
+}
+
+// --- Alasan validasi yang bisa dibaca mesin, dikirim sebagai error.reason ---
+const (
+	ReasonRequired          = "required"
+	ReasonInvalidFormat     = "invalid_format"
+	ReasonOutOfRange        = "out_of_range"
+	ReasonInvalidHemisphere = "invalid_hemisphere"
+)
+
+// --- Error validasi satu field input (lat, lon, ...) ---
+// Error envelope mengisi field & reason dari sini, pesannya tetap untuk manusia.
+type FieldError struct {
+	Field  string
+	Reason string
+	Err    error
+}
+
+func (e *FieldError) Error() string { return e.Err.Error() }
+func (e *FieldError) Unwrap() error { return e.Err }
+
+func fieldError(field, reason, format string, args ...any) error {
+	return &FieldError{Field: field, Reason: reason, Err: fmt.Errorf(format, args...)}
+}
+
+func parseCoordinate(raw, field string, limit float64) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, fieldError(field, ReasonRequired, "%s is required", field)
+	}
+	value, err := parseAngle(raw, field)
+	if err != nil {
+		return 0, err
+	}
+	if value < -limit || value > limit {
+		return 0, fieldError(field, ReasonOutOfRange, "%s must be between %g and %g, got %g", field, -limit, limit, value)
+	}
+	return value, nil
+}
+
+var (
+	angleNormalizer = strings.NewReplacer(
+		"º", "°", "˚", "°",
+		"′", "'", "’", "'", "‘", "'",
+		"″", "\"", "”", "\"", "“", "\"", "''", "\"",
+	)
+	hemispherePattern = regexp.MustCompile(`^(LU|LS|BT|BB|[NSEW])\s*|\s*(LU|LS|BT|BB|[NSEW])$`)
+	numberPattern     = regexp.MustCompile(`[0-9]+(?:[.,][0-9]+)?`)
+	// Desimal biasa saja; ParseFloat juga menerima NaN, Inf, hex dan eksponen
+	decimalPattern   = regexp.MustCompile(`^(?:[0-9]+(?:[.,][0-9]*)?|[.,][0-9]+)$`)
+	dmsMarkerPattern = regexp.MustCompile(`[°'"\s]`)
+)
+
+// --- Parse satu komponen koordinat (desimal atau DMS) ke derajat desimal ---
+func parseAngle(raw, field string) (float64, error) {
+	s := strings.ToUpper(angleNormalizer.Replace(strings.TrimSpace(raw)))
+
+	hemisphere := ""
+	if m := hemispherePattern.FindStringSubmatch(s); m != nil {
+		hemisphere = m[1] + m[2]
+		s = strings.TrimSpace(hemispherePattern.ReplaceAllString(s, ""))
+	}
+
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = strings.TrimSpace(s[1:])
+	} else if strings.HasPrefix(s, "+") {
+		s = strings.TrimSpace(s[1:])
+	}
+
+	var value float64
+	if dmsMarkerPattern.MatchString(s) {
+		parts := numberPattern.FindAllString(s, -1)
+		leftover := strings.Trim(numberPattern.ReplaceAllString(s, ""), "°'\" ")
+		if len(parts) == 0 || len(parts) > 3 || leftover != "" {
+			return 0, fieldError(field, ReasonInvalidFormat, "%s is not a valid coordinate, got %q", field, raw)
+		}
+		components := make([]float64, len(parts))
+		for i, part := range parts {
+			v, err := strconv.ParseFloat(strings.Replace(part, ",", ".", 1), 64)
+			if err != nil {
+				return 0, fieldError(field, ReasonInvalidFormat, "%s is not a valid coordinate, got %q", field, raw)
+			}
+			if i > 0 && v >= 60 {
+				return 0, fieldError(field, ReasonOutOfRange, "%s minutes/seconds must be below 60, got %q", field, raw)
+			}
+			components[i] = v
+		}
+		value = components[0]
+		if len(components) > 1 {
+			value += components[1] / 60
+		}
+		if len(components) > 2 {
+			value += components[2] / 3600
+		}
+	} else {
+		if !decimalPattern.MatchString(s) {
+			return 0, fieldError(field, ReasonInvalidFormat, "%s must be a decimal number or DMS, got %q", field, raw)
+		}
+		v, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+		if err != nil {
+			return 0, fieldError(field, ReasonInvalidFormat, "%s must be a decimal number or DMS, got %q", field, raw)
+		}
+		value = v
+	}
+
+	if hemisphere != "" {
+		if negative {
+			return 0, fieldError(field, ReasonInvalidHemisphere, "%s has both a minus sign and hemisphere %s, got %q", field, hemisphere, raw)
+		}
+		if !hemisphereMatches(hemisphere, field) {
+			return 0, fieldError(field, ReasonInvalidHemisphere, "%s cannot use hemisphere %s, got %q", field, hemisphere, raw)
+		}
+		switch hemisphere {
+		case "S", "LS", "W", "BB":
+			negative = true
+		}
+	}
+
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+func hemisphereMatches(hemisphere, field string) bool {
+	switch hemisphere {
+	case "N", "S", "LU", "LS":
+		return field == "lat"
+	default:
+		return field == "lon"
+	}
+}
+
+var (
+	latLonSuffixPattern = regexp.MustCompile(`^(.+?(?:LU|LS|[NS]))[\s,]+(.+)$`)
+	latLonPrefixPattern = regexp.MustCompile(`^((?:LU|LS|[NS])[^EWB]+?)[\s,]+((?:BT|BB|[EW]).+)$`)
+)
+
+// --- Parse pasangan "lat lon" dalam satu string, misal "7°32'S 110°26'E" atau "-7.54, 110.44" ---
+func parseCoordinatePair(raw string) (float64, float64, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return 0, 0, fmt.Errorf("coordinates are required")
+	}
+	upper := strings.ToUpper(angleNormalizer.Replace(s))
+
+	var rawLat, rawLon string
+	switch {
+	case strings.Contains(s, ";"):
+		rawLat, rawLon, _ = strings.Cut(s, ";")
+	case latLonPrefixPattern.MatchString(upper):
+		m := latLonPrefixPattern.FindStringSubmatch(upper)
+		rawLat, rawLon = m[1], m[2]
+	case latLonSuffixPattern.MatchString(upper) && strings.ContainsAny(upper, "EWBT"):
+		m := latLonSuffixPattern.FindStringSubmatch(upper)
+		rawLat, rawLon = m[1], m[2]
+	case strings.Count(s, ", ") == 1:
+		rawLat, rawLon, _ = strings.Cut(s, ", ")
+	case strings.Count(s, ",") == 1:
+		rawLat, rawLon, _ = strings.Cut(s, ",")
+	default:
+		fields := strings.Fields(s)
+		if len(fields) != 2 {
+			return 0, 0, fmt.Errorf("coordinates must contain a latitude and a longitude, got %q", raw)
+		}
+		rawLat, rawLon = fields[0], fields[1]
+	}
+	return ParseCoordinates(rawLat, rawLon)
+}
+
+// --- Nilai koordinat di JSON body: boleh number (-7.54) atau string ("7°32'S") ---
+type coordinateValue string
+
+func (v *coordinateValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = coordinateValue(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("coordinate must be a number or string")
+	}
+	*v = coordinateValue(n.String())
+	return nil
+}
+
+// Skema OpenAPI: JSON-nya tidak bisa ditebak dari tipe string-nya sendiri
+func (coordinateValue) OpenAPISchema() map[string]any {
+	return map[string]any{
+		"oneOf": []any{
+			map[string]any{"type": "number"},
+			map[string]any{"type": "string"},
+		},
+		"description": "Decimal degrees as number or string, or DMS notation",
+	}
+}
+
+// --- Lokasi dari JSON body: lat/lon terpisah, satu string "coordinates", atau "plus_code" ---
+// Untuk plus code pendek, lat/lon atau coordinates dipakai sebagai titik referensi.
+type locationInput struct {
+	Lat         coordinateValue `json:"lat,omitempty"`
+	Lon         coordinateValue `json:"lon,omitempty"`
+	Coordinates string          `json:"coordinates,omitempty"`
+	PlusCode    string          `json:"plus_code,omitempty"`
+}
+
+func (in locationInput) resolve() (float64, float64, error) {
+	if strings.TrimSpace(in.PlusCode) != "" {
+		refLat, refLon, hasReference := 0.0, 0.0, false
+		if in.Coordinates != "" || in.Lat != "" || in.Lon != "" {
+			var err error
+			refLat, refLon, err = in.resolveCoordinates()
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid plus code reference: %v", err)
+			}
+			hasReference = true
+		}
+		return decodePlusCode(in.PlusCode, refLat, refLon, hasReference)
+	}
+	return in.resolveCoordinates()
+}
+
+func (in locationInput) resolveCoordinates() (float64, float64, error) {
+	if strings.TrimSpace(in.Coordinates) != "" {
+		return parseCoordinatePair(in.Coordinates)
+	}
+	return ParseCoordinates(string(in.Lat), string(in.Lon))
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// --- Open Location Code (Plus Code), didecode lokal tanpa API Google ---
+const (
+	plusCodeAlphabet     = "23456789CFGHJMPQRVWX"
+	plusCodeSeparator    = '+'
+	plusCodeSeparatorPos = 8
+	plusCodePairLength   = 10
+	plusCodeMaxLength    = 15
+	plusCodeGridRows     = 5
+	plusCodeGridCols     = 4
+)
+
+var plusCodePairResolutions = []float64{20, 1, 0.05, 0.0025, 0.000125}
+
+// --- Decode plus code ke titik tengah area-nya ---
+// Kode pendek (misal "6X+2P") butuh titik referensi di sekitarnya; kirim
+// hasReference=false kalau tidak ada.
+func decodePlusCode(code string, refLat, refLon float64, hasReference bool) (float64, float64, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	sep := strings.IndexRune(code, plusCodeSeparator)
+	if err := validatePlusCode(code, sep); err != nil {
+		return 0, 0, err
+	}
+
+	if sep == plusCodeSeparatorPos {
+		return decodeFullPlusCode(code)
+	}
+	if !hasReference {
+		return 0, 0, fmt.Errorf("plus code %q is a short code and needs a reference location", code)
+	}
+	return recoverShortPlusCode(code, sep, refLat, refLon)
+}
+
+func validatePlusCode(code string, sep int) error {
+	if code == "" {
+		return fmt.Errorf("plus code is required")
+	}
+	if sep < 0 || strings.Count(code, string(plusCodeSeparator)) != 1 {
+		return fmt.Errorf("plus code %q must contain exactly one '+'", code)
+	}
+	if sep > plusCodeSeparatorPos || sep%2 != 0 {
+		return fmt.Errorf("plus code %q has '+' in an invalid position", code)
+	}
+	if after := len(code) - sep - 1; after == 1 || (after == 0 && sep < plusCodeSeparatorPos) {
+		return fmt.Errorf("plus code %q must have at least two characters after '+'", code)
+	}
+	if len(strings.ReplaceAll(code, "0", "")) > plusCodeMaxLength+1 {
+		return fmt.Errorf("plus code %q is too long", code)
+	}
+
+	if pad := strings.IndexByte(code, '0'); pad >= 0 {
+		if sep < plusCodeSeparatorPos {
+			return fmt.Errorf("short plus code %q cannot contain padding", code)
+		}
+		padding := code[pad:sep]
+		if pad == 0 || pad%2 != 0 || strings.Trim(padding, "0") != "" || sep != len(code)-1 {
+			return fmt.Errorf("plus code %q has invalid padding", code)
+		}
+	}
+
+	for i, ch := range code {
+		if i == sep || ch == '0' {
+			continue
+		}
+		if !strings.ContainsRune(plusCodeAlphabet, ch) {
+			return fmt.Errorf("plus code %q contains invalid character %q", code, ch)
+		}
+	}
+
+	if sep == plusCodeSeparatorPos {
+		if strings.IndexByte(plusCodeAlphabet, code[0])*20 >= 180 {
+			return fmt.Errorf("plus code %q has an out-of-range latitude", code)
+		}
+		if strings.IndexByte(plusCodeAlphabet, code[1])*20 >= 360 {
+			return fmt.Errorf("plus code %q has an out-of-range longitude", code)
+		}
+	}
+	return nil
+}
+
+func decodeFullPlusCode(code string) (float64, float64, error) {
+	clean := strings.NewReplacer(string(plusCodeSeparator), "", "0", "").Replace(code)
+	if len(clean) > plusCodeMaxLength {
+		clean = clean[:plusCodeMaxLength]
+	}
+
+	lat, lon := -90.0, -180.0
+	latCell, lonCell := 0.0, 0.0
+	for i := 0; i < len(clean) && i < plusCodePairLength; i += 2 {
+		res := plusCodePairResolutions[i/2]
+		lat += float64(strings.IndexByte(plusCodeAlphabet, clean[i])) * res
+		lon += float64(strings.IndexByte(plusCodeAlphabet, clean[i+1])) * res
+		latCell, lonCell = res, res
+	}
+	for i := plusCodePairLength; i < len(clean); i++ {
+		idx := strings.IndexByte(plusCodeAlphabet, clean[i])
+		latCell /= plusCodeGridRows
+		lonCell /= plusCodeGridCols
+		lat += float64(idx/plusCodeGridCols) * latCell
+		lon += float64(idx%plusCodeGridCols) * lonCell
+	}
+
+	centerLat := math.Min(lat+latCell/2, 90)
+	centerLon := normalizeLongitude(lon + lonCell/2)
+	return centerLat, centerLon, nil
+}
+
+// --- Pulihkan kode pendek pakai prefix dari titik referensi (algoritma recoverNearest) ---
+func recoverShortPlusCode(code string, sep int, refLat, refLon float64) (float64, float64, error) {
+	paddingLength := plusCodeSeparatorPos - sep
+	resolution := math.Pow(20, float64(2-paddingLength/2))
+	half := resolution / 2
+
+	refLat = math.Max(-90, math.Min(90, refLat))
+	refLon = normalizeLongitude(refLon)
+	prefix := encodePlusCodePairs(refLat, refLon)[:paddingLength]
+
+	lat, lon, err := decodeFullPlusCode(prefix + code)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch {
+	case refLat+half < lat && lat-resolution >= -90:
+		lat -= resolution
+	case refLat-half > lat && lat+resolution <= 90:
+		lat += resolution
+	}
+	switch {
+	case refLon+half < lon:
+		lon -= resolution
+	case refLon-half > lon:
+		lon += resolution
+	}
+	return lat, normalizeLongitude(lon), nil
+}
+
+func encodePlusCodePairs(lat, lon float64) string {
+	lat += 90
+	lon += 180
+	if lat >= 180 {
+		lat = 180 - plusCodePairResolutions[len(plusCodePairResolutions)-1]
+	}
+
+	var b strings.Builder
+	for _, res := range plusCodePairResolutions {
+		latDigit := min(int(math.Floor(lat/res)), len(plusCodeAlphabet)-1)
+		lonDigit := min(int(math.Floor(lon/res)), len(plusCodeAlphabet)-1)
+		b.WriteByte(plusCodeAlphabet[latDigit])
+		b.WriteByte(plusCodeAlphabet[lonDigit])
+		lat -= float64(latDigit) * res
+		lon -= float64(lonDigit) * res
+	}
+	return b.String()
+}
+
+func normalizeLongitude(lon float64) float64 {
+	for lon < -180 {
+		lon += 360
+	}
+	for lon >= 180 {
+		lon -= 360
+	}
+	return lon
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/alerts"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/i18n"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+const maxWebhookURLLength = 2048
+
+// Token registrasi FCM: base64url plus ":" dan panjangnya sekitar 160-an;
+// batasnya longgar tapi tetap aman dipakai di path URL
+const maxDeviceTokenLength = 4096
+
+var deviceTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_:-]+$`)
+
+// --- Pengaturan langganan yang bisa berubah saat reload ---
+type SubscriptionSettings struct {
+	// SMTP dikonfigurasi; tanpa itu langganan email ditolak
+	EmailEnabled bool
+	// FCM dikonfigurasi; tanpa itu kanal push & pendaftaran perangkat ditolak
+	PushEnabled bool
+}
+
+// --- Handler REST untuk langganan notifikasi ---
+// Langganan disimpan di bawah API key (X-API-Key); scheduler di main yang
+// menilai kondisinya dan mengirim webhook, email atau push.
+type Subscriptions struct {
+	store    services.SubscriptionStore
+	settings func() SubscriptionSettings
+}
+
+func NewSubscriptions(store services.SubscriptionStore, settings func() SubscriptionSettings) *Subscriptions {
+	return &Subscriptions{store: store, settings: settings}
+}
+
+// --- Body POST /subscriptions ---
+type SubscriptionRequest struct {
+	locationInput
+	// Misal "hiking_index >= 8", "rain starting" atau "thunderstorm alert";
+	// boleh kosong kalau daily_summary_hour atau weekly_report diisi
+	Condition string `json:"condition"`
+	// webhook (default), email, atau push (hanya ke perangkat yang didaftarkan)
+	Channel    string `json:"channel"`
+	WebhookURL string `json:"webhook_url"`
+	Email      string `json:"email"`
+	// Bahasa email & teks di notifikasi: id (default) atau en
+	Lang string `json:"lang"`
+	// Jam lokal (0-23) untuk ringkasan harian; kosong = tanpa ringkasan
+	DailySummaryHour *int `json:"daily_summary_hour"`
+	// Laporan mingguan lokasi tersimpan satu pengguna; kosong = tanpa laporan
+	WeeklyReport *model.ReportSchedule `json:"weekly_report"`
+	// Pengguna aplikasi seperti di /users/:id; event astronomi juga dicek di
+	// lokasi tersimpannya. Kosong = user_id laporan mingguan, kalau ada
+	UserID string `json:"user_id"`
+}
+
+type SubscriptionList struct {
+	Subscriptions []model.Subscription `json:"subscriptions"`
+}
+
+// --- Body POST /subscriptions/:id/devices ---
+type DeviceRequest struct {
+	// Token registrasi FCM dari aplikasi
+	Token string `json:"token"`
+}
+
+type DeviceRegistration struct {
+	SubscriptionID string `json:"subscription_id"`
+	Token          string `json:"token"`
+}
+
+// --- Handler untuk POST /subscriptions ---
+func (h *Subscriptions) Create(c *gin.Context) {
+	owner, ok := requireAPIKey(c, "subscriptions")
+	if !ok {
+		return
+	}
+	var input SubscriptionRequest
+	if !BindJSON(c, &input) {
+		return
+	}
+	lat, lon, err := input.resolve()
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	sub := model.Subscription{Latitude: lat, Longitude: lon, DailySummaryHour: input.DailySummaryHour, WeeklyReport: input.WeeklyReport, CreatedAt: time.Now().UTC()}
+	if hour := input.DailySummaryHour; hour != nil && (*hour < 0 || *hour > 23) {
+		AbortBadRequest(c, ErrCodeInvalidRequest, fieldError("daily_summary_hour", ReasonInvalidFormat, "daily_summary_hour must be between 0 and 23, got %d", *hour))
+		return
+	}
+	if report := input.WeeklyReport; report != nil {
+		if err := validateReportSchedule(*report); err != nil {
+			AbortBadRequest(c, ErrCodeInvalidRequest, err)
+			return
+		}
+	}
+	if sub.UserID = input.UserID; sub.UserID == "" && input.WeeklyReport != nil {
+		sub.UserID = input.WeeklyReport.UserID
+	} else if sub.UserID != "" && !userIDPattern.MatchString(sub.UserID) {
+		AbortBadRequest(c, ErrCodeInvalidRequest, fieldError("user_id", ReasonInvalidFormat, "user_id must be 1 to 64 letters, digits, '.', '-' or '_', got %q", sub.UserID))
+		return
+	}
+	if strings.TrimSpace(input.Condition) == "" {
+		if input.DailySummaryHour == nil && input.WeeklyReport == nil {
+			AbortBadRequest(c, ErrCodeInvalidRequest, fieldError("condition", ReasonRequired, "condition is required unless daily_summary_hour or weekly_report is set"))
+			return
+		}
+	} else {
+		condition, err := alerts.ParseCondition(input.Condition)
+		if err != nil {
+			AbortBadRequest(c, ErrCodeInvalidRequest, &FieldError{Field: "condition", Reason: ReasonInvalidFormat, Err: err})
+			return
+		}
+		sub.Condition = condition.String()
+	}
+	if sub.Lang, err = i18n.ParseLang(input.Lang); err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLang, &FieldError{Field: "lang", Reason: ReasonInvalidFormat, Err: err})
+		return
+	}
+
+	switch sub.Channel = strings.ToLower(strings.TrimSpace(input.Channel)); sub.Channel {
+	case "", model.ChannelWebhook:
+		sub.Channel = model.ChannelWebhook
+		if sub.WebhookURL, err = validateWebhookURL(input.WebhookURL); err != nil {
+			AbortBadRequest(c, ErrCodeInvalidRequest, err)
+			return
+		}
+		secret := make([]byte, 24)
+		if _, err := rand.Read(secret); err != nil {
+			AbortWithServiceError(c, err)
+			return
+		}
+		sub.Secret = "whsec_" + hex.EncodeToString(secret)
+	case model.ChannelEmail:
+		if !h.settings().EmailEnabled {
+			AbortWithError(c, http.StatusUnprocessableEntity, APIError{Code: ErrCodeChannelUnavailable, Message: "email notifications are not configured on this server"})
+			return
+		}
+		if sub.Email, err = validateEmail(input.Email); err != nil {
+			AbortBadRequest(c, ErrCodeInvalidRequest, err)
+			return
+		}
+	case model.ChannelPush:
+		if !h.settings().PushEnabled {
+			abortPushUnavailable(c)
+			return
+		}
+	default:
+		AbortBadRequest(c, ErrCodeInvalidRequest, fieldError("channel", ReasonInvalidFormat, "channel must be %q, %q or %q, got %q", model.ChannelWebhook, model.ChannelEmail, model.ChannelPush, input.Channel))
+		return
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	sub.ID = hex.EncodeToString(id)
+	sub, err = h.store.Create(c.Request.Context(), owner, sub)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, sub)
+}
+
+// --- Handler untuk GET /subscriptions ---
+func (h *Subscriptions) List(c *gin.Context) {
+	owner, ok := requireAPIKey(c, "subscriptions")
+	if !ok {
+		return
+	}
+	list, err := h.store.List(c.Request.Context(), owner)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SubscriptionList{Subscriptions: list})
+}
+
+// --- Handler untuk DELETE /subscriptions/:id ---
+func (h *Subscriptions) Delete(c *gin.Context) {
+	owner, ok := requireAPIKey(c, "subscriptions")
+	if !ok {
+		return
+	}
+	if err := h.store.Delete(c.Request.Context(), owner, c.Param("id")); err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// --- Handler untuk POST /subscriptions/:id/devices ---
+// Perangkat menerima push untuk langganan ini, apa pun kanalnya.
+func (h *Subscriptions) AddDevice(c *gin.Context) {
+	owner, ok := requireAPIKey(c, "subscriptions")
+	if !ok {
+		return
+	}
+	if !h.settings().PushEnabled {
+		abortPushUnavailable(c)
+		return
+	}
+	var input DeviceRequest
+	if !BindJSON(c, &input) {
+		return
+	}
+	token := strings.TrimSpace(input.Token)
+	if token == "" {
+		AbortBadRequest(c, ErrCodeInvalidRequest, fieldError("token", ReasonRequired, "token is required"))
+		return
+	}
+	if len(token) > maxDeviceTokenLength || !deviceTokenPattern.MatchString(token) {
+		AbortBadRequest(c, ErrCodeInvalidRequest, fieldError("token", ReasonInvalidFormat, "token must be an FCM registration token"))
+		return
+	}
+	id := c.Param("id")
+	if err := h.store.AddDevice(c.Request.Context(), owner, id, token); err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, DeviceRegistration{SubscriptionID: id, Token: token})
+}
+
+// --- Handler untuk DELETE /subscriptions/:id/devices/:token ---
+func (h *Subscriptions) RemoveDevice(c *gin.Context) {
+	owner, ok := requireAPIKey(c, "subscriptions")
+	if !ok {
+		return
+	}
+	if err := h.store.RemoveDevice(c.Request.Context(), owner, c.Param("id"), c.Param("token")); err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func abortPushUnavailable(c *gin.Context) {
+	AbortWithError(c, http.StatusUnprocessableEntity, APIError{Code: ErrCodeChannelUnavailable, Message: "push notifications are not configured on this server"})
+}
+
+// Alamat tujuan (termasuk IP privat) dicek lagi saat webhook dikirim
+func validateWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fieldError("webhook_url", ReasonRequired, "webhook_url is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || len(raw) > maxWebhookURLLength {
+		return "", fieldError("webhook_url", ReasonInvalidFormat, "webhook_url must be an absolute http(s) URL of at most %d characters", maxWebhookURLLength)
+	}
+	if u.User != nil {
+		return "", fieldError("webhook_url", ReasonInvalidFormat, "webhook_url must not contain credentials; verify the signature header instead")
+	}
+	return u.String(), nil
+}
+
+// Lokasinya boleh belum disimpan; laporan dilewati selama pengguna belum
+// punya lokasi tersimpan
+func validateReportSchedule(report model.ReportSchedule) error {
+	if !userIDPattern.MatchString(report.UserID) {
+		return fieldError("weekly_report.user_id", ReasonInvalidFormat, "weekly_report.user_id must be 1 to 64 letters, digits, '.', '-' or '_', got %q", report.UserID)
+	}
+	if report.Weekday < 0 || report.Weekday > 6 {
+		return fieldError("weekly_report.weekday", ReasonInvalidFormat, "weekly_report.weekday must be between 0 (Sunday) and 6 (Saturday), got %d", report.Weekday)
+	}
+	if report.Hour < 0 || report.Hour > 23 {
+		return fieldError("weekly_report.hour", ReasonInvalidFormat, "weekly_report.hour must be between 0 and 23, got %d", report.Hour)
+	}
+	return nil
+}
+
+// Hanya alamatnya yang disimpan; nama tampilan dibuang
+func validateEmail(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", fieldError("email", ReasonRequired, "email is required for the email channel")
+	}
+	addr, err := mail.ParseAddress(raw)
+	if err != nil {
+		return "", fieldError("email", ReasonInvalidFormat, "email must be a valid address: %v", err)
+	}
+	return addr.Address, nil
+}
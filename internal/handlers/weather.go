@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/i18n"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Pengaturan handler yang bisa berubah saat reload, dibaca per request ---
+type WeatherSettings struct {
+	// Jumlah titik maksimum per request batch
+	MaxBatchPoints int
+	// Titik batch yang diproses bersamaan per request
+	BatchConcurrency int
+}
+
+// --- Handler REST untuk data cuaca gabungan ---
+type Weather struct {
+	svc      *services.Weather
+	settings func() WeatherSettings
+}
+
+func NewWeather(svc *services.Weather, settings func() WeatherSettings) *Weather {
+	return &Weather{svc: svc, settings: settings}
+}
+
+// --- Handler untuk GET (pakai URL params) ---
+func (h *Weather) ByCoordinates(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	h.respond(c, lat, lon, c.Query("units"))
+}
+
+// --- Handler untuk GET /activities/:lat/:lon ---
+func (h *Weather) Activities(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	response, err := h.svc.Activities(c.Request.Context(), lat, lon)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	response = i18n.LocalizeActivities(response, lang)
+	c.JSON(statusFor(response.Meta), response)
+}
+
+// --- Handler untuk GET /alerts/:lat/:lon ---
+func (h *Weather) Alerts(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	response, err := h.svc.Alerts(c.Request.Context(), lat, lon)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	response = i18n.LocalizeAlerts(response, lang)
+	c.JSON(statusFor(response.Meta), response)
+}
+
+// --- Handler untuk GET /quakes/:lat/:lon?radius_km=100 ---
+func (h *Weather) Quakes(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	radius := services.DefaultQuakeRadiusKm
+	if raw := c.Query("radius_km"); raw != "" {
+		radius, err = strconv.ParseFloat(raw, 64)
+		if err != nil || radius <= 0 || radius > services.MaxQuakeRadiusKm {
+			AbortBadRequest(c, ErrCodeInvalidRequest, fmt.Errorf("radius_km must be a number between 0 and %g, got %q", services.MaxQuakeRadiusKm, raw))
+			return
+		}
+	}
+	response, err := h.svc.Quakes(c.Request.Context(), lat, lon, radius)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(statusFor(response.Meta), response)
+}
+
+// --- Handler untuk GET /tides/:lat/:lon ---
+func (h *Weather) Tides(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	response, err := h.svc.Tides(c.Request.Context(), lat, lon)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// --- Handler untuk GET /forecast/daily/:lat/:lon ---
+func (h *Weather) DailyForecast(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	response, err := h.svc.DailyForecast(c.Request.Context(), lat, lon)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	response = i18n.LocalizeDailyForecast(response, lang)
+	c.JSON(statusFor(response.Meta), response)
+}
+
+// --- Handler untuk GET /history/:lat/:lon?start=YYYY-MM-DD&end=YYYY-MM-DD ---
+func (h *Weather) History(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	var dates [2]time.Time
+	for i, name := range []string{"start", "end"} {
+		dates[i], err = time.Parse(time.DateOnly, c.Query(name))
+		if err != nil {
+			AbortBadRequest(c, ErrCodeInvalidRequest, fmt.Errorf("%s must be a date in YYYY-MM-DD format, got %q", name, c.Query(name)))
+			return
+		}
+	}
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	response, err := h.svc.History(c.Request.Context(), lat, lon, dates[0], dates[1])
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	response = i18n.LocalizeHistory(response, lang)
+	c.JSON(http.StatusOK, response)
+}
+
+// --- Handler untuk GET /climatology/:lat/:lon?years=10 ---
+func (h *Weather) Climatology(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	years := services.DefaultClimatologyYears
+	if raw := c.Query("years"); raw != "" {
+		years, err = strconv.Atoi(raw)
+		if err != nil || years < 1 || years > services.MaxClimatologyYears {
+			AbortBadRequest(c, ErrCodeInvalidRequest, fmt.Errorf("years must be a whole number from 1 to %d, got %q", services.MaxClimatologyYears, raw))
+			return
+		}
+	}
+	response, err := h.svc.Climatology(c.Request.Context(), lat, lon, years)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// --- Handler untuk GET /sun-exposure/:lat/:lon?skin_type=1-6 ---
+func (h *Weather) SunExposure(c *gin.Context) {
+	lat, lon, err := ParseCoordinates(c.Param("lat"), c.Param("lon"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	skinType, err := strconv.Atoi(c.Query("skin_type"))
+	if err != nil || !indices.ValidSkinType(skinType) {
+		AbortBadRequest(c, ErrCodeInvalidRequest,
+			fmt.Errorf("skin_type must be a Fitzpatrick type from 1 to 6, got %q", c.Query("skin_type")))
+		return
+	}
+	lang, ok := parseLang(c)
+	if !ok {
+		return
+	}
+	response, err := h.svc.SunExposure(c.Request.Context(), lat, lon, skinType)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, i18n.LocalizeSunExposure(response, lang))
+}
+
+// --- Handler untuk GET pakai geohash ---
+func (h *Weather) ByGeohash(c *gin.Context) {
+	lat, lon, err := decodeGeohash(c.Param("hash"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	h.respond(c, lat, lon, c.Query("units"))
+}
+
+// --- Handler untuk GET pakai plus code, kode pendek butuh ?ref=lat,lon ---
+func (h *Weather) ByPlusCode(c *gin.Context) {
+	var refLat, refLon float64
+	hasReference := false
+	if ref := c.Query("ref"); ref != "" {
+		var err error
+		refLat, refLon, err = parseCoordinatePair(ref)
+		if err != nil {
+			AbortBadRequest(c, ErrCodeInvalidLocation, fmt.Errorf("invalid ref: %v", err))
+			return
+		}
+		hasReference = true
+	}
+
+	lat, lon, err := decodePlusCode(c.Param("code"), refLat, refLon, hasReference)
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+	h.respond(c, lat, lon, c.Query("units"))
+}
+
+// --- Body untuk POST /weather ---
+type WeatherRequest struct {
+	locationInput
+	Units string `json:"units,omitempty"`
+}
+
+// --- Handler untuk POST (pakai JSON body) ---
+func (h *Weather) ByJSON(c *gin.Context) {
+	var input WeatherRequest
+	if !BindJSON(c, &input) {
+		return
+	}
+
+	lat, lon, err := input.resolve()
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLocation, err)
+		return
+	}
+
+	// --- Query string menang atas body kalau dua-duanya diisi ---
+	rawUnits := input.Units
+	if q := c.Query("units"); q != "" {
+		rawUnits = q
+	}
+	h.respond(c, lat, lon, rawUnits)
+}
+
+// --- Handler untuk GET pakai nama tempat, misal /weather/by-name/Gunung%20Rinjani ---
+func (h *Weather) ByName(c *gin.Context) {
+	name := strings.TrimSpace(c.Param("place"))
+	if n := utf8.RuneCountInString(name); n < 2 || n > 100 {
+		AbortBadRequest(c, ErrCodeInvalidLocation, fmt.Errorf("place name must be 2 to 100 characters"))
+		return
+	}
+	place, err := h.svc.Geocode(c.Request.Context(), name)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	h.respondAt(c, place.Latitude, place.Longitude, &place, c.Query("units"))
+}
+
+// --- Ambil data lalu kirim response sesuai sistem satuan & skala AQI yang diminta ---
+func (h *Weather) respond(c *gin.Context, lat, lon float64, rawUnits string) {
+	h.respondAt(c, lat, lon, nil, rawUnits)
+}
+
+// --- Sistem satuan, skala AQI, sumber cuaca & bahasa yang diminta ---
+// Response selalu dihitung dalam metric, AQI Eropa dan Bahasa Indonesia,
+// lalu dikonversi.
+type responseOptions struct {
+	units    string
+	aqiScale string
+	source   string
+	lang     string
+}
+
+// ?aqi_scale=, ?provider= dan ?lang= hanya dari query string; ok false berarti error sudah dikirim
+func parseResponseOptions(c *gin.Context, rawUnits string) (responseOptions, bool) {
+	units, err := model.ParseUnitSystem(rawUnits)
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidUnits, err)
+		return responseOptions{}, false
+	}
+	scale, err := indices.ParseAQIScale(c.Query("aqi_scale"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidAQIScale, err)
+		return responseOptions{}, false
+	}
+	source, err := services.ParseSource(c.Query("provider"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidProvider, err)
+		return responseOptions{}, false
+	}
+	lang, ok := parseLang(c)
+	if !ok {
+		return responseOptions{}, false
+	}
+	return responseOptions{units: units, aqiScale: scale, source: source, lang: lang}, true
+}
+
+// Skala AQI diterapkan sebelum terjemahan, karena kategori ISPU juga diterjemahkan
+func (o responseOptions) apply(resp model.ConsolidatedResponse) model.ConsolidatedResponse {
+	return i18n.LocalizeConditions(model.ApplyUnitSystem(indices.ApplyAQIScale(resp, o.aqiScale), o.units), o.lang)
+}
+
+// --- Bahasa teks rekomendasi dari ?lang=; ok false berarti error sudah dikirim ---
+func parseLang(c *gin.Context) (string, bool) {
+	lang, err := i18n.ParseLang(c.Query("lang"))
+	if err != nil {
+		AbortBadRequest(c, ErrCodeInvalidLang, err)
+		return "", false
+	}
+	return lang, true
+}
+
+// place diisi kalau lokasi berasal dari geocoding
+func (h *Weather) respondAt(c *gin.Context, lat, lon float64, place *model.Place, rawUnits string) {
+	opts, ok := parseResponseOptions(c, rawUnits)
+	if !ok {
+		return
+	}
+
+	response, err := h.svc.Conditions(c.Request.Context(), lat, lon, opts.source)
+	if err != nil {
+		AbortWithServiceError(c, err)
+		return
+	}
+	if place != nil {
+		response.Location = place
+	}
+	converted := opts.apply(response)
+
+	if wantsJSONAPI(c) {
+		doc, err := conditionsDocument(lat, lon, converted, c.QueryMap("fields"))
+		if err != nil {
+			AbortBadRequest(c, ErrCodeInvalidFields, err)
+			return
+		}
+		respondJSONAPI(c, statusFor(converted.Meta), doc)
+		return
+	}
+	if raw := c.Query("fields"); raw != "" {
+		selected, err := selectFields(converted, raw)
+		if err != nil {
+			AbortBadRequest(c, ErrCodeInvalidFields, err)
+			return
+		}
+		c.JSON(statusFor(converted.Meta), selected)
+		return
+	}
+	c.JSON(statusFor(converted.Meta), converted)
+}
+
+// --- 206 kalau sebagian response kosong karena upstream gagal (meta.errors) ---
+// Data yang ada tetap valid; client cukup menandai bagian yang hilang.
+func statusFor(meta model.ResponseMeta) int {
+	if len(meta.Errors) > 0 {
+		return http.StatusPartialContent
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/clock"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// Cache yang tidak pernah menyimpan apa-apa; tiap request memanggil provider
+type noCache struct{}
+
+func (noCache) Get(context.Context, string, string) ([]byte, bool)         { return nil, false }
+func (noCache) Set(context.Context, string, string, []byte, time.Duration) {}
+
+// Router dengan provider mode mock; kill switch dipakai untuk menggagalkan provider
+func newWeatherRouter(t *testing.T) (*gin.Engine, *providers.KillSwitch) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := providers.DefaultConfig()
+	cfg.Mode = providers.ModeMock
+	kill := providers.NewKillSwitch()
+	client := providers.New(providers.Options{
+		Config:     func() providers.Config { return cfg },
+		Timeout:    func() time.Duration { return time.Second },
+		KillSwitch: kill,
+	})
+	now := clock.Fixed(time.Date(2024, 6, 21, 5, 0, 0, 0, time.UTC))
+	svc := services.NewWeather(client, noCache{}, now, func() services.Settings {
+		return services.Settings{DefaultTimezone: "Asia/Jakarta", FanoutPolicy: services.FanoutPartial}
+	}, nil)
+	h := handlers.NewWeather(svc, func() handlers.WeatherSettings {
+		return handlers.WeatherSettings{MaxBatchPoints: 10, BatchConcurrency: 2}
+	})
+
+	r := gin.New()
+	r.GET("/api/v1/weather/:lat/:lon", h.ByCoordinates)
+	return r, kill
+}
+
+func get(r http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestWeatherByCoordinates(t *testing.T) {
+	r, _ := newWeatherRouter(t)
+
+	w := get(r, "/api/v1/weather/-6.2/106.8")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	var resp model.ConsolidatedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Meta.Units != model.UnitsMetric || len(resp.Meta.Errors) != 0 {
+		t.Errorf("meta = %+v, want metric units and no errors", resp.Meta)
+	}
+	if resp.Sun.Sunrise == "" {
+		t.Error("sun.sunrise is empty")
+	}
+}
+
+func TestWeatherByCoordinatesInvalidLocation(t *testing.T) {
+	r, _ := newWeatherRouter(t)
+
+	for _, target := range []string{
+		"/api/v1/weather/abc/106.8",
+		"/api/v1/weather/91/106.8",
+		"/api/v1/weather/-6.2/181",
+	} {
+		w := get(r, target)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, w.Code)
+			continue
+		}
+		var resp handlers.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Error.Code != handlers.ErrCodeInvalidLocation {
+			t.Errorf("%s: error code = %q, want %q", target, resp.Error.Code, handlers.ErrCodeInvalidLocation)
+		}
+	}
+}
+
+// Provider jam matahari mati: response tetap dikirim dengan 206 dan meta.errors
+func TestWeatherByCoordinatesPartial(t *testing.T) {
+	r, kill := newWeatherRouter(t)
+	kill.Disable(providers.SunriseSunset, "test")
+
+	w := get(r, "/api/v1/weather/-6.2/106.8")
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("status = %d, want 206: %s", w.Code, w.Body)
+	}
+	var resp model.ConsolidatedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Meta.Errors) != 1 || resp.Meta.Errors[0].Section != "sun" {
+		t.Fatalf("meta.errors = %+v, want one entry for sun", resp.Meta.Errors)
+	}
+	if got := resp.Meta.Errors[0]; got.Provider != providers.SunriseSunset || got.Kind != providers.KindDisabled {
+		t.Errorf("meta.errors[0] = %+v, want %s disabled", got, providers.SunriseSunset)
+	}
+	// Jam matahari dihitung lokal sebagai gantinya
+	if resp.Sun.Sunrise == "" {
+		t.Error("sun.sunrise is empty, want the local fallback")
+	}
+}
@@ -0,0 +1,162 @@
+package i18n
+
+// --- Katalog Bahasa Inggris, kunci = teks asli Bahasa Indonesia ---
+// Teks baru di indices/astro/alerts perlu ditambahkan di sini; yang lupa
+// ditambahkan tetap tampil dalam Bahasa Indonesia.
+var english = map[string]string{
+	// Fase bulan (astro)
+	"Bulan Baru":      "New Moon",
+	"Sabit Awal":      "Waxing Crescent",
+	"Kuartal Pertama": "First Quarter",
+	"Cembung Awal":    "Waxing Gibbous",
+	"Bulan Purnama":   "Full Moon",
+	"Cembung Akhir":   "Waning Gibbous",
+	"Kuartal Akhir":   "Last Quarter",
+	"Sabit Akhir":     "Waning Crescent",
+
+	// Peringatan dari prakiraan (alerts); peringatan resmi BMKG tidak diterjemahkan
+	"Potensi hujan disertai petir":                   "Possible rain with lightning",
+	"Potensi badai petir disertai hujan es":          "Possible thunderstorm with hail",
+	"Potensi hujan lebat":                            "Possible heavy rain",
+	"Potensi angin sangat kencang":                   "Possible very strong wind",
+	"Potensi angin kencang":                          "Possible strong wind",
+	"Tekanan udara turun tajam, cuaca bisa memburuk": "Air pressure falling sharply, weather may worsen",
+
+	// Kategori ISPU & level alergi
+	"Baik":               "Good",
+	"Sedang":             "Moderate",
+	"Tidak Sehat":        "Unhealthy",
+	"Sangat Tidak Sehat": "Very unhealthy",
+	"Berbahaya":          "Hazardous",
+	"tidak ada":          "none",
+	"rendah":             "low",
+	"sedang":             "moderate",
+	"tinggi":             "high",
+	"sangat tinggi":      "very high",
+
+	// Mendaki
+	"Sangat baik untuk mendaki!":                                                              "Great conditions for hiking!",
+	"Cukup baik, tetapi perhatikan cuaca.":                                                    "Fairly good, but keep an eye on the weather.",
+	"Kurang disarankan, kondisi tidak ideal.":                                                 "Not recommended, conditions are not ideal.",
+	"Tidak disarankan untuk mendaki hari ini.":                                                "Hiking is not recommended today.",
+	"Waspada kabut di pagi hari, bawa senter dan alat navigasi.":                              "Watch for morning fog, bring a headlamp and navigation.",
+	"Gunung %s berstatus %s (Level %s): jangan mendekati kawah dan patuhi radius aman PVMBG.": "Mount %s is at status %s (Level %s): stay away from the crater and respect the PVMBG exclusion zone.",
+	"Gunung %s berstatus %s (Level %s): pendakian umumnya ditutup, jauhi zona bahaya.":        "Mount %s is at status %s (Level %s): climbs are usually closed, stay out of the danger zone.",
+	"Gunung %s berstatus %s (Level %s): jangan mendaki, ikuti arahan evakuasi dari petugas.":  "Mount %s is at status %s (Level %s): do not climb, follow evacuation orders from officials.",
+
+	// Alasan di index_breakdown
+	"Suhu terasa terlalu panas.":                                       "Feels too hot.",
+	"Humidex berbahaya, risiko heat stroke.":                           "Dangerous humidex, risk of heat stroke.",
+	"Udara lembap dan gerah.":                                          "Humid and muggy.",
+	"Suhu terasa terlalu dingin, apalagi di punggungan yang berangin.": "Feels too cold, especially on windy ridges.",
+	"Hujan membuat jalur licin.":                                       "Rain makes the trail slippery.",
+	"UV sangat tinggi, kulit cepat terbakar.":                          "Very high UV, skin burns quickly.",
+	"Kualitas udara buruk untuk aktivitas berat.":                      "Air quality is poor for strenuous activity.",
+	"Langit tertutup awan, pemandangan terbatas.":                      "Overcast sky, limited views.",
+	"Suhu maksimum terlalu panas.":                                     "Maximum temperature is too hot.",
+	"Suhu minimum terlalu dingin untuk berangkat dini hari.":           "Minimum temperature is too cold for a pre-dawn start.",
+	"Hujan dalam sehari membuat jalur licin.":                          "Rain during the day makes the trail slippery.",
+	"Hujan lebat, jalur licin dan rawan longsor.":                      "Heavy rain, slippery trail and landslide risk.",
+	"Status gunung api membatasi pendakian.":                           "Volcano alert level limits hiking.",
+
+	// Lari & bersepeda
+	"Kondisi ideal untuk lari.":                                                                  "Ideal conditions for running.",
+	"Cukup baik untuk lari, bawa air minum.":                                                     "Fairly good for running, bring water.",
+	"Kurang ideal, kurangi intensitas atau lari di dalam ruangan.":                               "Not ideal, lower the intensity or run indoors.",
+	"Tidak disarankan lari di luar sekarang.":                                                    "Running outdoors is not recommended right now.",
+	"Serbuk sari tinggi, penderita alergi sebaiknya minum obat dulu atau lari di dalam ruangan.": "High pollen, allergy sufferers should take their medication first or run indoors.",
+	"Kondisi ideal untuk bersepeda.":                                                             "Ideal conditions for cycling.",
+	"Cukup baik untuk bersepeda, perhatikan angin.":                                              "Fairly good for cycling, mind the wind.",
+	"Kurang ideal, jalan basah atau angin kencang.":                                              "Not ideal, wet roads or strong wind.",
+	"Tidak disarankan bersepeda sekarang.":                                                       "Cycling is not recommended right now.",
+
+	// Mengamati bintang
+	"Masih siang, tunggu sampai langit gelap setelah matahari terbenam.":                       "It is still daytime, wait until the sky is dark after sunset.",
+	"Langit cerah dan gelap, sangat baik untuk mengamati bintang.":                             "Clear, dark sky, excellent for stargazing.",
+	"Cukup baik, bintang terang dan planet masih terlihat.":                                    "Fairly good, bright stars and planets are still visible.",
+	"Kurang ideal, awan atau cahaya bulan mengganggu pengamatan.":                              "Not ideal, clouds or moonlight get in the way.",
+	"Tidak disarankan, langit tertutup atau terlalu terang.":                                   "Not recommended, the sky is overcast or too bright.",
+	"Polusi cahaya tinggi (Bortle %d), cari lokasi yang lebih gelap untuk melihat Bima Sakti.": "Heavy light pollution (Bortle %d), find a darker site to see the Milky Way.",
+
+	// Drone
+	"Kondisi aman untuk menerbangkan drone.":                "Safe conditions for flying a drone.",
+	"Cukup aman, terbang dengan hati-hati.":                 "Fairly safe, fly with care.",
+	"Berisiko, sebaiknya tunda penerbangan.":                "Risky, better postpone the flight.",
+	"Jangan terbangkan drone sekarang.":                     "Do not fly a drone right now.",
+	"Hembusan angin kencang, jaga ketinggian tetap rendah.": "Strong gusts, keep the altitude low.",
+	"Badai geomagnetik, GPS dan kompas bisa tidak akurat.":  "Geomagnetic storm, GPS and compass may be inaccurate.",
+
+	// Paralayang
+	"Sudah gelap, paralayang hanya bisa di siang hari.":                      "It is already dark, paragliding is only possible in daylight.",
+	"Kondisi bagus untuk paralayang.":                                        "Good conditions for paragliding.",
+	"Bisa terbang, pantau perubahan angin.":                                  "Flyable, watch for changes in the wind.",
+	"Kurang aman, hanya untuk pilot berpengalaman.":                          "Marginal, for experienced pilots only.",
+	"Tidak aman untuk paralayang.":                                           "Unsafe for paragliding.",
+	"Risiko awan badai (cumulonimbus), segera mendarat bila awan menjulang.": "Risk of storm clouds (cumulonimbus), land promptly if clouds tower up.",
+	"Angin di ketinggian jauh lebih kencang, waspada turbulensi.":            "Wind aloft is much stronger, beware of turbulence.",
+
+	// Fotografi
+	"Cahaya dan langit sangat bagus untuk memotret.":     "Light and sky are excellent for photography.",
+	"Cukup baik untuk memotret.":                         "Fairly good for photography.",
+	"Kurang ideal, cahaya atau langit kurang mendukung.": "Not ideal, the light or sky is not cooperating.",
+	"Tidak disarankan memotret pemandangan sekarang.":    "Landscape photography is not recommended right now.",
+	"Golden hour sedang berlangsung sampai %s.":          "Golden hour is on until %s.",
+	"Golden hour berikutnya %s-%s.":                      "Next golden hour %s-%s.",
+
+	// Cuci mobil
+	"Aman cuci mobil, 2 hari ke depan diperkirakan kering.": "Safe to wash the car, the next 2 days look dry.",
+	"Boleh cuci mobil, tapi ada peluang hujan.":             "You can wash the car, but there is a chance of rain.",
+	"Sebaiknya ditunda, kemungkinan hujan cukup tinggi.":    "Better wait, rain is fairly likely.",
+	"Jangan cuci mobil dulu, hujan hampir pasti turun.":     "Do not wash the car yet, rain is almost certain.",
+
+	// Memancing
+	"Waktu yang sangat baik untuk memancing, ikan sedang aktif.": "Excellent time for fishing, the fish are active.",
+	"Cukup baik untuk memancing.":                                "Fairly good for fishing.",
+	"Kurang ideal, hasil tangkapan mungkin sedikit.":             "Not ideal, the catch may be small.",
+	"Tidak disarankan memancing, angin atau hujan terlalu kuat.": "Fishing is not recommended, the wind or rain is too strong.",
+	"Pasang berikutnya pukul %s.":                                "Next high tide at %s.",
+
+	// Berkemah
+	"Prakiraan malam ini belum tersedia.":                              "Tonight's forecast is not available yet.",
+	"Malam yang baik untuk berkemah.":                                  "A good night for camping.",
+	"Cukup baik untuk berkemah dengan persiapan.":                      "Fairly good for camping with some preparation.",
+	"Kurang ideal, siapkan perlengkapan ekstra.":                       "Not ideal, bring extra gear.",
+	"Tidak disarankan berkemah malam ini.":                             "Camping is not recommended tonight.",
+	"Pasang flysheet dan bawa jas hujan.":                              "Put up the flysheet and bring a raincoat.",
+	"Bawa sleeping bag hangat dan jaket tebal.":                        "Bring a warm sleeping bag and a thick jacket.",
+	"Bawa sleeping bag dan jaket.":                                     "Bring a sleeping bag and a jacket.",
+	"Pasang pasak dan tali tenda dengan kuat, cari tempat terlindung.": "Stake and guy the tent firmly, find a sheltered spot.",
+
+	// Pantai & selancar
+	"Cuaca ideal untuk ke pantai!":                       "Ideal beach weather!",
+	"Cukup baik untuk ke pantai, perhatikan kondisi.":    "Fairly good for the beach, keep an eye on conditions.",
+	"Kurang ideal untuk ke pantai.":                      "Not ideal for the beach.",
+	"Tidak disarankan ke pantai hari ini.":               "The beach is not recommended today.",
+	"Suhu air laut sekitar %.0f°C.":                      "Sea water around %.0f°C.",
+	"Pakai sunscreen dan berteduh saat tengah hari.":     "Wear sunscreen and seek shade around midday.",
+	"Ombak setinggi %.1f m, hati-hati saat berenang.":    "Waves up to %.1f m, take care when swimming.",
+	"Ombak bisa tinggi, hati-hati saat berenang.":        "Waves may be high, take care when swimming.",
+	"Surut berikutnya pukul %s.":                         "Next low tide at %s.",
+	"Data gelombang tidak tersedia untuk lokasi ini.":    "Wave data is not available for this location.",
+	"Ombak bagus untuk berselancar!":                     "Great waves for surfing!",
+	"Cukup baik untuk berselancar.":                      "Fairly good for surfing.",
+	"Kurang ideal, ombak kecil atau berantakan.":         "Not ideal, the waves are small or messy.",
+	"Tidak disarankan berselancar saat ini.":             "Surfing is not recommended right now.",
+	"Ombak sekitar %.1f m dengan periode %.0f detik.":    "Waves around %.1f m with a %.0f second period.",
+	"Ombak besar, hanya untuk peselancar berpengalaman.": "Big waves, for experienced surfers only.",
+
+	// Acara luar ruangan
+	"Cuaca mendukung untuk acara di luar ruangan.":             "The weather suits an outdoor event.",
+	"Acara luar ruangan masih bisa, siapkan rencana cadangan.": "An outdoor event is still possible, have a backup plan.",
+	"Kurang ideal, pertimbangkan tempat beratap.":              "Not ideal, consider a covered venue.",
+	"Tidak disarankan mengadakan acara di luar ruangan.":       "Holding an outdoor event is not recommended.",
+	"Siapkan tenda atau payung.":                               "Have tents or umbrellas ready.",
+	"Sediakan peneduh dan air minum yang cukup.":               "Provide shade and plenty of drinking water.",
+	"Ikat dekorasi dan tenda dengan kuat.":                     "Tie down decorations and tents firmly.",
+
+	// Paparan matahari
+	"UV rendah, tabir surya belum wajib saat ini.":                         "UV is low, sunscreen is not needed yet.",
+	"Aman beraktivitas di luar, pakai tabir surya kalau lebih dari 1 jam.": "Safe to be outside, wear sunscreen if out for more than an hour.",
+	"Pakai tabir surya SPF 30+ dan topi, batasi paparan langsung.":         "Wear SPF 30+ sunscreen and a hat, limit direct exposure.",
+	"UV sangat tinggi, hindari matahari langsung dan cari tempat teduh.":   "UV is very high, avoid direct sun and seek shade.",
+}
@@ -0,0 +1,152 @@
+// Package i18n menerjemahkan teks untuk pengguna (rekomendasi, nama fase
+// bulan, judul peringatan) dari Bahasa Indonesia ke bahasa yang diminta.
+//
+// Teks tetap dibuat dalam Bahasa Indonesia di indices, astro dan alerts, dan
+// teks itu sekaligus menjadi kunci katalog (seperti msgid gettext). Response
+// baru diterjemahkan di handler, sama seperti konversi satuan, sehingga cache
+// tidak perlu dipisah per bahasa.
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- Bahasa yang didukung; default Indonesia ---
+const (
+	LangID = "id"
+	LangEN = "en"
+
+	Default = LangID
+)
+
+var catalogs = map[string]*catalog{
+	LangEN: newCatalog(english),
+}
+
+// --- Parse nilai ?lang=, default ke Indonesia kalau kosong ---
+// Tag wilayah diabaikan: "en-US" dan "en_GB" sama dengan "en".
+func ParseLang(raw string) (string, error) {
+	lang := strings.ToLower(strings.TrimSpace(raw))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	switch lang {
+	case "", LangID:
+		return LangID, nil
+	case LangEN:
+		return lang, nil
+	default:
+		return "", fmt.Errorf("unsupported lang %q (use %q or %q)", raw, LangID, LangEN)
+	}
+}
+
+// --- Terjemahan satu teks; teks yang tidak ada di katalog dikembalikan apa adanya ---
+// Rekomendasi sering gabungan beberapa kalimat (rekomendasi dasar plus
+// tambahan soal pasang, kabut, polusi cahaya), jadi kalau teks utuhnya tidak
+// dikenal, tiap kalimat diterjemahkan sendiri-sendiri.
+func T(lang, text string) string {
+	c, ok := catalogs[lang]
+	if !ok || text == "" {
+		return text
+	}
+	if translated, ok := c.translate(text); ok {
+		return translated
+	}
+	sentences := splitSentences(text)
+	if len(sentences) == 1 {
+		return text
+	}
+	for i, sentence := range sentences {
+		if translated, ok := c.translate(sentence); ok {
+			sentences[i] = translated
+		}
+	}
+	return strings.Join(sentences, " ")
+}
+
+// Kalimat berakhir di "." atau "!" yang diikuti spasi; angka desimal
+// ("1.5 m") tidak terpotong karena titiknya tidak diikuti spasi.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text)-1; i++ {
+		if (text[i] == '.' || text[i] == '!') && text[i+1] == ' ' {
+			sentences = append(sentences, text[start:i+1])
+			start = i + 2
+		}
+	}
+	return append(sentences, text[start:])
+}
+
+// --- Katalog satu bahasa ---
+// Kunci yang memuat verb fmt (%s, %d, %.1f) dicocokkan sebagai pola; nilai
+// yang tertangkap disisipkan ke terjemahan dengan urutan yang sama.
+type catalog struct {
+	exact    map[string]string
+	patterns []pattern
+}
+
+type pattern struct {
+	re     *regexp.Regexp
+	format string
+}
+
+var formatVerb = regexp.MustCompile(`%(\.\d+)?[sdf]`)
+
+func newCatalog(messages map[string]string) *catalog {
+	c := &catalog{exact: map[string]string{}}
+	for source, translated := range messages {
+		if !formatVerb.MatchString(source) {
+			c.exact[source] = translated
+			continue
+		}
+		c.patterns = append(c.patterns, pattern{
+			re:     regexp.MustCompile("^" + formatPattern(source) + "$"),
+			format: formatVerb.ReplaceAllString(translated, "%s"),
+		})
+	}
+	// Urutan tetap supaya hasil tidak bergantung pada urutan map
+	sort.Slice(c.patterns, func(i, j int) bool { return c.patterns[i].re.String() < c.patterns[j].re.String() })
+	return c
+}
+
+// Regex dari format fmt: teks literal di-escape, tiap verb jadi satu grup
+func formatPattern(format string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range formatVerb.FindAllStringIndex(format, -1) {
+		b.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		switch format[loc[1]-1] {
+		case 's':
+			b.WriteString(`(.+?)`)
+		case 'd':
+			b.WriteString(`(-?\d+)`)
+		default:
+			b.WriteString(`(-?\d+(?:\.\d+)?)`)
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(format[last:]))
+	return b.String()
+}
+
+func (c *catalog) translate(text string) (string, bool) {
+	if translated, ok := c.exact[text]; ok {
+		return translated, true
+	}
+	for _, p := range c.patterns {
+		match := p.re.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		args := make([]any, len(match)-1)
+		for i, value := range match[1:] {
+			args[i] = value
+		}
+		return fmt.Sprintf(p.format, args...), true
+	}
+	return "", false
+}
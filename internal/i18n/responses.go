@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"slices"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Terjemahkan teks untuk pengguna di tiap jenis response ---
+// Response selalu dihitung dalam Bahasa Indonesia lalu diterjemahkan, seperti
+// model.ApplyUnitSystem. Slice disalin dulu supaya data asal (mis. dari cache)
+// tidak ikut berubah.
+
+func LocalizeConditions(resp model.ConsolidatedResponse, lang string) model.ConsolidatedResponse {
+	resp.Meta.Lang = lang
+	if lang == LangID {
+		return resp
+	}
+	resp.Moon.PhaseName = T(lang, resp.Moon.PhaseName)
+	resp.Weather.AirQuality.Category = T(lang, resp.Weather.AirQuality.Category)
+	if pollen := resp.Weather.Pollen; pollen != nil {
+		translated := *pollen
+		translated.AllergyLevel = T(lang, pollen.AllergyLevel)
+		resp.Weather.Pollen = &translated
+	}
+
+	idx := &resp.Indices
+	idx.HikingRecommendation = T(lang, idx.HikingRecommendation)
+	idx.RunningRecommendation = T(lang, idx.RunningRecommendation)
+	idx.CyclingRecommendation = T(lang, idx.CyclingRecommendation)
+	idx.StargazingRecommendation = T(lang, idx.StargazingRecommendation)
+	idx.DroneRecommendation = T(lang, idx.DroneRecommendation)
+	idx.PhotographyRecommendation = T(lang, idx.PhotographyRecommendation)
+	idx.IndexBreakdown = localizeBreakdown(idx.IndexBreakdown, lang)
+	if air := idx.AirSports; air != nil {
+		translated := *air
+		translated.ParaglidingRecommendation = T(lang, air.ParaglidingRecommendation)
+		idx.AirSports = &translated
+	}
+
+	resp.Alerts = localizeAlerts(resp.Alerts, lang)
+	return resp
+}
+
+func LocalizeActivities(resp model.ActivitiesResponse, lang string) model.ActivitiesResponse {
+	resp.Meta.Lang = lang
+	if lang == LangID {
+		return resp
+	}
+	resp.Activities = slices.Clone(resp.Activities)
+	for i := range resp.Activities {
+		resp.Activities[i].Recommendation = T(lang, resp.Activities[i].Recommendation)
+	}
+	return resp
+}
+
+func LocalizeAlerts(resp model.AlertsResponse, lang string) model.AlertsResponse {
+	resp.Meta.Lang = lang
+	resp.Alerts = localizeAlerts(resp.Alerts, lang)
+	return resp
+}
+
+func LocalizeDailyForecast(resp model.DailyForecastResponse, lang string) model.DailyForecastResponse {
+	resp.Meta.Lang = lang
+	if lang == LangID {
+		return resp
+	}
+	resp.Days = slices.Clone(resp.Days)
+	for i := range resp.Days {
+		resp.Days[i].HikingRecommendation = T(lang, resp.Days[i].HikingRecommendation)
+		resp.Days[i].IndexBreakdown = localizeBreakdown(resp.Days[i].IndexBreakdown, lang)
+	}
+	return resp
+}
+
+func LocalizeHistory(resp model.HistoryResponse, lang string) model.HistoryResponse {
+	resp.Meta.Lang = lang
+	if lang == LangID {
+		return resp
+	}
+	resp.Days = slices.Clone(resp.Days)
+	for i := range resp.Days {
+		resp.Days[i].HikingRecommendation = T(lang, resp.Days[i].HikingRecommendation)
+		resp.Days[i].IndexBreakdown = localizeBreakdown(resp.Days[i].IndexBreakdown, lang)
+	}
+	return resp
+}
+
+func LocalizeSunExposure(resp model.SunExposureResponse, lang string) model.SunExposureResponse {
+	resp.Meta.Lang = lang
+	resp.Recommendation = T(lang, resp.Recommendation)
+	return resp
+}
+
+func localizeBreakdown(factors []model.IndexFactor, lang string) []model.IndexFactor {
+	factors = slices.Clone(factors)
+	for i := range factors {
+		factors[i].Reason = T(lang, factors[i].Reason)
+	}
+	return factors
+}
+
+// Judul peringatan dari prakiraan; teks peringatan resmi BMKG tidak ada di
+// katalog sehingga tetap dalam bahasa aslinya
+func localizeAlerts(alerts []model.Alert, lang string) []model.Alert {
+	if lang == LangID {
+		return alerts
+	}
+	alerts = slices.Clone(alerts)
+	for i := range alerts {
+		alerts[i].Title = T(lang, alerts[i].Title)
+		alerts[i].Description = T(lang, alerts[i].Description)
+	}
+	return alerts
+}
@@ -0,0 +1,45 @@
+package indices
+
+import (
+	"math"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Nama aktivitas di response /activities ---
+const (
+	ActivityHiking  = "hiking"
+	ActivityCarWash = "car_wash"
+	ActivityFishing = "fishing"
+	ActivityCamping = "camping"
+	ActivityBeach   = "beach"
+	ActivityRunning = "running"
+	ActivityCycling = "cycling"
+	ActivitySurfing = "surfing"
+	// Piknik, resepsi, dan acara kumpul di luar ruangan
+	ActivityOutdoorEvent = "outdoor_event"
+)
+
+// --- Semua indeks aktivitas untuk endpoint /activities ---
+// waterTemp dan sea opsional (nil di darat atau kalau data laut tidak tersedia).
+func Activities(weather model.WeatherData, forecast model.HourlyForecast, moon model.MoonData, waterTemp *float64, sea *model.SeaState, volcano *model.Volcano, scoring Scoring, now time.Time) []model.ActivityIndex {
+	current := Calculate(weather, scoring)
+	hiking, hikingRecommendation := VolcanoHiking(current.HikingIndex, current.HikingRecommendation, volcano)
+	return []model.ActivityIndex{
+		{Activity: ActivityHiking, Score: hiking, Recommendation: hikingRecommendation},
+		{Activity: ActivityRunning, Score: current.RunningIndex, Recommendation: current.RunningRecommendation},
+		{Activity: ActivityCycling, Score: current.CyclingIndex, Recommendation: current.CyclingRecommendation},
+		CarWash(forecast, now),
+		Fishing(weather, forecast, moon, sea, now),
+		Camping(forecast, now),
+		Beach(weather, waterTemp, sea),
+		Surfing(weather, sea),
+		OutdoorEvent(weather, forecast, now),
+	}
+}
+
+// --- Skor dibatasi 0-10 dengan satu desimal ---
+func clampScore(score float64) float64 {
+	return math.Round(max(0, min(10, score))*10) / 10
+}
@@ -0,0 +1,59 @@
+package indices
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+func TestClampScore(t *testing.T) {
+	tests := []struct {
+		in, want float64
+	}{
+		{-3, 0},
+		{0, 0},
+		{4.04, 4},
+		{7.25, 7.3},
+		{9.96, 10},
+		{10, 10},
+		{12.5, 10},
+	}
+	for _, tt := range tests {
+		if got := clampScore(tt.in); got != tt.want {
+			t.Errorf("clampScore(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestActivities(t *testing.T) {
+	now := time.Date(2024, 6, 21, 9, 0, 0, 0, time.UTC)
+	weather := model.WeatherData{Temperature: 24, Apparent: 25, Humidity: 60, CloudCover: 30, UVIndex: 4, WindSpeed: 8, Visibility: 20000}
+	forecast := model.HourlyForecast{{Time: now}, {Time: now.Add(time.Hour)}}
+
+	got := Activities(weather, forecast, model.MoonData{}, nil, nil, nil, DefaultScoring(), now)
+	want := []string{
+		ActivityHiking, ActivityRunning, ActivityCycling, ActivityCarWash, ActivityFishing,
+		ActivityCamping, ActivityBeach, ActivitySurfing, ActivityOutdoorEvent,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d activities, want %d", len(got), len(want))
+	}
+	for i, activity := range got {
+		if activity.Activity != want[i] {
+			t.Errorf("activity %d = %q, want %q", i, activity.Activity, want[i])
+		}
+		if activity.Score < 0 || activity.Score > 10 {
+			t.Errorf("%s score = %v, want within 0-10", activity.Activity, activity.Score)
+		}
+		if activity.Recommendation == "" {
+			t.Errorf("%s has no recommendation", activity.Activity)
+		}
+	}
+	// Tiga indeks pertama sama dengan perhitungan cuaca saat ini
+	current := Calculate(weather, DefaultScoring())
+	if got[0].Score != current.HikingIndex || got[1].Score != current.RunningIndex || got[2].Score != current.CyclingIndex {
+		t.Errorf("hiking, running, cycling = %v, %v, %v; want %v, %v, %v",
+			got[0].Score, got[1].Score, got[2].Score, current.HikingIndex, current.RunningIndex, current.CyclingIndex)
+	}
+}
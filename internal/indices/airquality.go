@@ -0,0 +1,189 @@
+package indices
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Skala AQI yang didukung lewat ?aqi_scale= ---
+const (
+	AQIScaleEuropean = "european"
+	AQIScaleUS       = "us"
+	AQIScaleISPU     = "ispu"
+)
+
+// --- Parse nilai ?aqi_scale=, default ke european kalau kosong ---
+func ParseAQIScale(raw string) (string, error) {
+	switch scale := strings.ToLower(strings.TrimSpace(raw)); scale {
+	case "":
+		return AQIScaleEuropean, nil
+	case AQIScaleEuropean, AQIScaleUS, AQIScaleISPU:
+		return scale, nil
+	default:
+		return "", fmt.Errorf("unsupported aqi_scale %q (use %q, %q or %q)", raw, AQIScaleEuropean, AQIScaleUS, AQIScaleISPU)
+	}
+}
+
+// Satu titik patah skala indeks: konsentrasi (µg/m³) dan nilai indeksnya.
+// Di antara dua titik, indeks diinterpolasi linear.
+type aqiBreakpoint struct {
+	Concentration, Index float64
+}
+
+// --- European AQI (EEA, seperti dipakai Open-Meteo) per polutan ---
+// CO tidak termasuk dalam indeks Eropa.
+var europeanAQI = map[string][]aqiBreakpoint{
+	"pm2_5": {{0, 0}, {10, 20}, {20, 40}, {25, 60}, {50, 80}, {75, 100}, {800, 120}},
+	"pm10":  {{0, 0}, {20, 20}, {40, 40}, {50, 60}, {100, 80}, {150, 100}, {1200, 120}},
+	"o3":    {{0, 0}, {50, 20}, {100, 40}, {130, 60}, {240, 80}, {380, 100}, {800, 120}},
+	"no2":   {{0, 0}, {40, 20}, {90, 40}, {120, 60}, {230, 80}, {340, 100}, {1000, 120}},
+	"so2":   {{0, 0}, {100, 20}, {200, 40}, {350, 60}, {500, 80}, {750, 100}, {1250, 120}},
+}
+
+// --- US AQI (EPA, ambang PM2.5 revisi 2024) ---
+// Ambang resmi dalam ppb/ppm sudah dikonversi ke µg/m³ pada 25 °C; O3, NO2
+// dan SO2 memakai ambang 1 jam, karena yang tersedia konsentrasi per jam.
+var usAQI = map[string][]aqiBreakpoint{
+	"pm2_5": {{0, 0}, {9, 50}, {35.4, 100}, {55.4, 150}, {125.4, 200}, {225.4, 300}, {325.4, 500}},
+	"pm10":  {{0, 0}, {54, 50}, {154, 100}, {254, 150}, {354, 200}, {424, 300}, {604, 500}},
+	"o3":    {{0, 0}, {106, 50}, {137, 100}, {167, 150}, {206, 200}, {392, 300}, {1184, 500}},
+	"no2":   {{0, 0}, {100, 50}, {188, 100}, {677, 150}, {1220, 200}, {2348, 300}, {3852, 500}},
+	"so2":   {{0, 0}, {92, 50}, {197, 100}, {485, 150}, {797, 200}, {1583, 300}, {2633, 500}},
+	"co":    {{0, 0}, {5038, 50}, {10763, 100}, {14198, 150}, {17633, 200}, {34808, 300}, {57708, 500}},
+}
+
+// --- ISPU (Permen LHK P.14/2020), skala yang dipakai berita lokal ---
+var ispu = map[string][]aqiBreakpoint{
+	"pm2_5": {{0, 0}, {15.5, 50}, {55.4, 100}, {150.4, 200}, {250.4, 300}, {500, 500}},
+	"pm10":  {{0, 0}, {50, 50}, {150, 100}, {350, 200}, {420, 300}, {500, 500}},
+	"o3":    {{0, 0}, {120, 50}, {235, 100}, {400, 200}, {800, 300}, {1000, 500}},
+	"no2":   {{0, 0}, {80, 50}, {200, 100}, {1130, 200}, {2260, 300}, {3000, 500}},
+	"so2":   {{0, 0}, {52, 50}, {180, 100}, {400, 200}, {800, 300}, {1200, 500}},
+	"co":    {{0, 0}, {4000, 50}, {8000, 100}, {15000, 200}, {30000, 300}, {45000, 500}},
+}
+
+var aqiScales = map[string]map[string][]aqiBreakpoint{
+	AQIScaleEuropean: europeanAQI,
+	AQIScaleUS:       usAQI,
+	AQIScaleISPU:     ispu,
+}
+
+// Kategori per skala: batas atas indeks dan namanya, dengan istilah resmi
+// masing-masing (ISPU dalam bahasa Indonesia)
+type aqiCategory struct {
+	Max  float64
+	Name string
+}
+
+var aqiCategories = map[string][]aqiCategory{
+	AQIScaleEuropean: {{20, "Good"}, {40, "Fair"}, {60, "Moderate"}, {80, "Poor"}, {100, "Very poor"}, {math.Inf(1), "Extremely poor"}},
+	AQIScaleUS: {{50, "Good"}, {100, "Moderate"}, {150, "Unhealthy for sensitive groups"}, {200, "Unhealthy"},
+		{300, "Very unhealthy"}, {math.Inf(1), "Hazardous"}},
+	AQIScaleISPU: {{50, "Baik"}, {100, "Sedang"}, {200, "Tidak Sehat"}, {300, "Sangat Tidak Sehat"}, {math.Inf(1), "Berbahaya"}},
+}
+
+func aqiCategoryName(scale string, aqi int) string {
+	for _, category := range aqiCategories[scale] {
+		if float64(aqi) <= category.Max {
+			return category.Name
+		}
+	}
+	return ""
+}
+
+// Urutan tetap supaya polutan dominan tidak berubah-ubah saat nilainya sama
+var pollutantOrder = []string{"pm2_5", "pm10", "o3", "no2", "so2", "co"}
+
+func pollutantValues(p model.Pollutants) map[string]float64 {
+	return map[string]float64{
+		"pm2_5": p.PM25, "pm10": p.PM10, "o3": p.O3,
+		"no2": p.NO2, "so2": p.SO2, "co": p.CO,
+	}
+}
+
+// --- Blok air_quality: AQI dari provider, polutan dominan dihitung di sini ---
+// Dominan = polutan dengan sub-indeks tertinggi; kosong kalau semua nol.
+func AirQuality(reading model.AirQualityHour) model.AirQuality {
+	_, dominant := scaleIndex(europeanAQI, reading.Pollutants)
+	return model.AirQuality{
+		AQI:               reading.AQI,
+		Scale:             AQIScaleEuropean,
+		Category:          aqiCategoryName(AQIScaleEuropean, reading.AQI),
+		DominantPollutant: dominant,
+		Pollutants:        reading.Pollutants,
+	}
+}
+
+// --- Hitung ulang air_quality dalam skala yang diminta dari konsentrasi polutan ---
+// Skala Eropa memakai AQI dari provider apa adanya; weather.aqi tetap Eropa
+// karena ambang indeks aktivitas mengacu ke sana.
+func ApplyAQIScale(resp model.ConsolidatedResponse, scale string) model.ConsolidatedResponse {
+	air := &resp.Weather.AirQuality
+	if scale == AQIScaleEuropean || scale == air.Scale {
+		return resp
+	}
+	index, dominant := scaleIndex(aqiScales[scale], air.Pollutants)
+	air.AQI = int(math.Round(index))
+	air.Scale, air.Category, air.DominantPollutant = scale, aqiCategoryName(scale, air.AQI), dominant
+	return resp
+}
+
+// --- Jam kualitas udara untuk jam sekarang; ok=false kalau tidak ada ---
+func CurrentAirQuality(hours []model.AirQualityHour, now time.Time) (model.AirQualityHour, bool) {
+	current := now.Truncate(time.Hour)
+	for _, hour := range hours {
+		if hour.Time.Equal(current) {
+			return hour, true
+		}
+	}
+	return model.AirQualityHour{}, false
+}
+
+// --- AQI per jam untuk aqiForecastHours jam setelah jam sekarang ---
+const aqiForecastHours = 24
+
+func AQIForecast(hours []model.AirQualityHour, now time.Time) []model.AQIHour {
+	current := now.Truncate(time.Hour)
+	end := current.Add(aqiForecastHours * time.Hour)
+	var forecast []model.AQIHour
+	for _, hour := range hours {
+		if hour.Time.After(current) && !hour.Time.After(end) {
+			forecast = append(forecast, model.AQIHour{Time: hour.Time, AQI: hour.AQI})
+		}
+	}
+	return forecast
+}
+
+// Indeks keseluruhan = sub-indeks tertinggi, beserta polutannya
+func scaleIndex(scale map[string][]aqiBreakpoint, p model.Pollutants) (float64, string) {
+	values := pollutantValues(p)
+	best, dominant := 0.0, ""
+	for _, name := range pollutantOrder {
+		points, ok := scale[name]
+		if !ok {
+			continue
+		}
+		if index := subIndex(points, values[name]); index > best {
+			best, dominant = index, name
+		}
+	}
+	return best, dominant
+}
+
+// Interpolasi linear antar titik patah; di atas titik terakhir dibatasi
+func subIndex(points []aqiBreakpoint, concentration float64) float64 {
+	if concentration <= points[0].Concentration {
+		return points[0].Index
+	}
+	for i := 1; i < len(points); i++ {
+		lo, hi := points[i-1], points[i]
+		if concentration <= hi.Concentration {
+			return lo.Index + (concentration-lo.Concentration)/(hi.Concentration-lo.Concentration)*(hi.Index-lo.Index)
+		}
+	}
+	return points[len(points)-1].Index
+}
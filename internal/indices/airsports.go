@@ -0,0 +1,109 @@
+package indices
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Jam prakiraan atmosfer untuk jam sekarang; ok=false kalau tidak ada ---
+func CurrentAloft(hours []model.AloftHour, now time.Time) (model.AloftHour, bool) {
+	current := now.Truncate(time.Hour)
+	for _, hour := range hours {
+		if hour.Time.Equal(current) {
+			return hour, true
+		}
+	}
+	return model.AloftHour{}, false
+}
+
+// --- Dasar awan cumulus (meter di atas permukaan) dari selisih suhu & titik embun ---
+// Rumus Espy: sekitar 125 m per derajat spread.
+func CloudBase(temperature, dewPoint float64) float64 {
+	return math.Round(max(0, temperature-dewPoint) * 125)
+}
+
+// --- Olahraga udara: paralayang dari angin permukaan & 850 hPa dan termal ---
+// Heuristik termal: dasar awan tinggi = termal kuat dan tinggi; CAPE sedang
+// membantu, CAPE besar berisiko cumulonimbus (overdevelopment). now di zona lokasi.
+func AirSports(weather model.WeatherData, aloft model.AloftHour, sun model.SunData, now time.Time) *model.AirSportsIndices {
+	result := &model.AirSportsIndices{
+		WindSurface: aloft.WindSurface,
+		Wind850hPa:  aloft.Wind850hPa,
+		CloudBase:   CloudBase(aloft.Temperature, aloft.DewPoint),
+	}
+
+	clock := sinceMidnight(now)
+	if clock < sunClock(sun.Sunrise, 6*time.Hour) || clock >= sunClock(sun.Sunset, 18*time.Hour) {
+		result.ParaglidingRecommendation = "Sudah gelap, paralayang hanya bisa di siang hari."
+		return result
+	}
+
+	score := 10.0
+	surface, upper := aloft.WindSurface.Speed, aloft.Wind850hPa.Speed
+
+	switch {
+	case surface > 30:
+		score -= 6
+	case surface > 25:
+		score -= 4
+	case surface > 20:
+		score -= 2
+	}
+	switch {
+	case upper > 35:
+		score -= 3
+	case upper > 25:
+		score -= 1
+	}
+	// Perbedaan angin besar antar ketinggian = turbulensi (wind shear)
+	if math.Abs(upper-surface) > 25 {
+		score -= 2
+	}
+
+	switch {
+	case aloft.CAPE > 1500:
+		score -= 4
+	case aloft.CAPE < 50:
+		// Termal lemah, hanya bisa meluncur turun
+		score -= 1
+	}
+	switch {
+	case result.CloudBase < 500:
+		score -= 2
+	case result.CloudBase < 1000:
+		score -= 1
+	}
+	if aloft.CloudCoverLow > 80 {
+		score -= 2
+	}
+	if weather.Precipitation > 0.1 {
+		score -= 5
+	}
+	result.ParaglidingIndex = clampScore(score)
+
+	switch s := result.ParaglidingIndex; {
+	case s >= 8:
+		result.ParaglidingRecommendation = "Kondisi bagus untuk paralayang."
+	case s >= 5:
+		result.ParaglidingRecommendation = "Bisa terbang, pantau perubahan angin."
+	case s >= 3:
+		result.ParaglidingRecommendation = "Kurang aman, hanya untuk pilot berpengalaman."
+	default:
+		result.ParaglidingRecommendation = "Tidak aman untuk paralayang."
+	}
+
+	var tips []string
+	if aloft.CAPE > 1500 {
+		tips = append(tips, "Risiko awan badai (cumulonimbus), segera mendarat bila awan menjulang.")
+	}
+	if math.Abs(upper-surface) > 25 {
+		tips = append(tips, "Angin di ketinggian jauh lebih kencang, waspada turbulensi.")
+	}
+	if len(tips) > 0 {
+		result.ParaglidingRecommendation += " " + strings.Join(tips, " ")
+	}
+	return result
+}
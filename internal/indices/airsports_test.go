@@ -0,0 +1,83 @@
+package indices
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+func TestCloudBase(t *testing.T) {
+	tests := []struct {
+		temperature, dewPoint, want float64
+	}{
+		{30, 20, 1250},
+		{25, 21.8, 400},
+		// Titik embun di atas suhu (data janggal) tidak memberi dasar awan negatif
+		{20, 25, 0},
+	}
+	for _, tt := range tests {
+		if got := CloudBase(tt.temperature, tt.dewPoint); got != tt.want {
+			t.Errorf("CloudBase(%v, %v) = %v, want %v", tt.temperature, tt.dewPoint, got, tt.want)
+		}
+	}
+}
+
+func TestAirSports(t *testing.T) {
+	sun := model.SunData{Sunrise: "06:00", Sunset: "18:00"}
+	noon := time.Date(2024, 6, 21, 11, 0, 0, 0, time.UTC)
+	good := model.AloftHour{
+		Temperature:   30,
+		DewPoint:      20,
+		WindSurface:   model.Wind{Speed: 10},
+		Wind850hPa:    model.Wind{Speed: 15},
+		CloudCoverLow: 20,
+		CAPE:          500,
+	}
+	with := func(change func(*model.AloftHour)) model.AloftHour {
+		a := good
+		change(&a)
+		return a
+	}
+
+	tests := []struct {
+		name           string
+		weather        model.WeatherData
+		aloft          model.AloftHour
+		now            time.Time
+		score          float64
+		recommendation string
+	}{
+		{"good thermals", model.WeatherData{}, good, noon, 10, "Kondisi bagus untuk paralayang."},
+		{"after sunset", model.WeatherData{}, good, noon.Add(9 * time.Hour), 0, "Sudah gelap"},
+		{"weak thermals", model.WeatherData{}, with(func(a *model.AloftHour) { a.CAPE = 30 }), noon, 9, "Kondisi bagus"},
+		{"overdevelopment", model.WeatherData{}, with(func(a *model.AloftHour) { a.CAPE = 2000 }), noon, 6, "Bisa terbang, pantau perubahan angin. Risiko awan badai"},
+		{"low cloud base", model.WeatherData{}, with(func(a *model.AloftHour) { a.Temperature, a.DewPoint = 25, 21.8 }), noon, 8, "Kondisi bagus"},
+		{
+			"wind shear",
+			model.WeatherData{},
+			with(func(a *model.AloftHour) { a.WindSurface.Speed, a.Wind850hPa.Speed = 5, 40 }),
+			noon, 5, "Bisa terbang, pantau perubahan angin. Angin di ketinggian",
+		},
+		{"strong surface wind", model.WeatherData{}, with(func(a *model.AloftHour) { a.WindSurface.Speed = 31 }), noon, 4, "Kurang aman"},
+		{"rain", model.WeatherData{Precipitation: 0.5}, good, noon, 5, "Bisa terbang"},
+		{
+			"clamped at 0",
+			model.WeatherData{Precipitation: 2},
+			with(func(a *model.AloftHour) { a.WindSurface.Speed, a.CAPE, a.CloudCoverLow = 35, 2000, 90 }),
+			noon, 0, "Tidak aman untuk paralayang.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AirSports(tt.weather, tt.aloft, sun, tt.now)
+			if got.ParaglidingIndex != tt.score || !strings.HasPrefix(got.ParaglidingRecommendation, tt.recommendation) {
+				t.Errorf("AirSports = %v %q, want %v %q...", got.ParaglidingIndex, got.ParaglidingRecommendation, tt.score, tt.recommendation)
+			}
+			if got.CloudBase != CloudBase(tt.aloft.Temperature, tt.aloft.DewPoint) {
+				t.Errorf("cloud base = %v, want %v", got.CloudBase, CloudBase(tt.aloft.Temperature, tt.aloft.DewPoint))
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package indices
+
+import "github.com/AntonTian/TitikKondisi-Backend/internal/model"
+
+// --- Level alergi serbuk sari ---
+const (
+	AllergyNone = iota
+	AllergyLow
+	AllergyModerate
+	AllergyHigh
+	AllergyVeryHigh
+)
+
+var allergyLevels = [...]string{"tidak ada", "rendah", "sedang", "tinggi", "sangat tinggi"}
+
+// Ambang butir/m³ untuk rendah, sedang, tinggi dan sangat tinggi. Rumput
+// memicu gejala di konsentrasi jauh lebih rendah daripada serbuk pohon.
+var pollenThresholds = map[string][4]float64{
+	"alder":   {1, 15, 90, 1500},
+	"birch":   {1, 15, 90, 1500},
+	"olive":   {1, 15, 90, 1500},
+	"grass":   {1, 5, 20, 200},
+	"mugwort": {1, 10, 50, 500},
+	"ragweed": {1, 10, 50, 500},
+}
+
+// --- Indeks alergi: level tertinggi di antara semua jenis serbuk sari ---
+// nil tetap nil (data serbuk sari tidak tersedia di lokasi itu).
+func Allergy(pollen *model.Pollen) *model.Pollen {
+	if pollen == nil {
+		return nil
+	}
+	result := *pollen
+	result.AllergyIndex, result.Dominant = AllergyNone, ""
+	concentrations := map[string]*float64{
+		"alder": pollen.Alder, "birch": pollen.Birch, "grass": pollen.Grass,
+		"mugwort": pollen.Mugwort, "olive": pollen.Olive, "ragweed": pollen.Ragweed,
+	}
+	for _, kind := range []string{"alder", "birch", "grass", "mugwort", "olive", "ragweed"} {
+		value := concentrations[kind]
+		if value == nil {
+			continue
+		}
+		level := AllergyNone
+		for i, threshold := range pollenThresholds[kind] {
+			if *value >= threshold {
+				level = i + 1
+			}
+		}
+		if level > result.AllergyIndex {
+			result.AllergyIndex, result.Dominant = level, kind
+		}
+	}
+	result.AllergyLevel = allergyLevels[result.AllergyIndex]
+	return &result
+}
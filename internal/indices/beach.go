@@ -0,0 +1,100 @@
+package indices
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Beach Index: suhu, UV, hujan, angin dan (kalau ada) suhu air laut ---
+// waterTemp dan sea nil kalau data laut tidak tersedia; indeks tetap dihitung tanpanya.
+func Beach(weather model.WeatherData, waterTemp *float64, sea *model.SeaState) model.ActivityIndex {
+	score := 10.0
+
+	switch t := weather.Temperature; {
+	case t < 24:
+		score -= 3
+	case t < 27:
+		score -= 1
+	case t > 34:
+		// Pasir panas dan risiko dehidrasi
+		score -= 2
+	}
+
+	switch {
+	case weather.UVIndex > 10:
+		score -= 2
+	case weather.UVIndex > 7:
+		score -= 1
+	}
+
+	switch {
+	case weather.Precipitation > 1:
+		score -= 4
+	case weather.Precipitation > 0.2:
+		score -= 1
+	}
+
+	// Angin kencang = ombak tinggi dan arus berbahaya
+	switch {
+	case weather.WindSpeed > 35:
+		score -= 4
+	case weather.WindSpeed > 25:
+		score -= 2
+	}
+
+	if sea != nil {
+		switch {
+		case sea.Waves.Height > 2.5:
+			score -= 3
+		case sea.Waves.Height > 1.5:
+			score -= 1
+		}
+	}
+
+	if waterTemp != nil {
+		switch {
+		case *waterTemp < 22:
+			score -= 2
+		case *waterTemp < 25:
+			score -= 1
+		}
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Cuaca ideal untuk ke pantai!"
+	case score >= 5:
+		recommendation = "Cukup baik untuk ke pantai, perhatikan kondisi."
+	case score >= 3:
+		recommendation = "Kurang ideal untuk ke pantai."
+	default:
+		recommendation = "Tidak disarankan ke pantai hari ini."
+	}
+
+	var tips []string
+	if waterTemp != nil {
+		tips = append(tips, fmt.Sprintf("Suhu air laut sekitar %.0f°C.", *waterTemp))
+	}
+	if weather.UVIndex > 7 {
+		tips = append(tips, "Pakai sunscreen dan berteduh saat tengah hari.")
+	}
+	switch {
+	case sea != nil && sea.Waves.Height > 1.5:
+		tips = append(tips, fmt.Sprintf("Ombak setinggi %.1f m, hati-hati saat berenang.", sea.Waves.Height))
+	case sea == nil && weather.WindSpeed > 25:
+		tips = append(tips, "Ombak bisa tinggi, hati-hati saat berenang.")
+	}
+	// Saat surut karang & kolam pasang surut terbuka
+	if sea != nil && sea.Tides.NextLow != nil {
+		tips = append(tips, fmt.Sprintf("Surut berikutnya pukul %s.", sea.Tides.NextLow.Time.Format("15:04")))
+	}
+	if len(tips) > 0 {
+		recommendation += " " + strings.Join(tips, " ")
+	}
+
+	return model.ActivityIndex{Activity: ActivityBeach, Score: score, Recommendation: recommendation}
+}
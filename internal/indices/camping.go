@@ -0,0 +1,115 @@
+package indices
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Malam berkemah dihitung dari jam 18 sampai jam 6 pagi waktu lokal
+const (
+	nightStartHour = 18
+	nightEndHour   = 6
+)
+
+// --- Camping Index: suhu minimum, peluang hujan dan angin malam ini ---
+// Yang dinilai jam-jam tidur di tenda, bukan kondisi saat ini.
+func Camping(forecast model.HourlyForecast, now time.Time) model.ActivityIndex {
+	start, end := tonight(forecast, now)
+
+	found := false
+	minTemp, maxRain, maxWind := 0.0, 0, 0.0
+	for _, hour := range forecast {
+		if hour.Time.Before(start) || !hour.Time.Before(end) {
+			continue
+		}
+		if !found || hour.Temperature < minTemp {
+			minTemp = hour.Temperature
+		}
+		found = true
+		maxRain = max(maxRain, hour.PrecipitationProbability)
+		maxWind = max(maxWind, hour.WindSpeed)
+	}
+	if !found {
+		return model.ActivityIndex{Activity: ActivityCamping, Recommendation: "Prakiraan malam ini belum tersedia."}
+	}
+
+	score := 10.0
+	switch {
+	case minTemp < 5:
+		score -= 4
+	case minTemp < 10:
+		score -= 2
+	case minTemp < 15:
+		score -= 1
+	case minTemp > 26:
+		// Terlalu gerah untuk tidur di tenda
+		score -= 1
+	}
+	switch {
+	case maxRain > 70:
+		score -= 4
+	case maxRain > 40:
+		score -= 2
+	case maxRain > 20:
+		score -= 1
+	}
+	switch {
+	case maxWind > 40:
+		score -= 4
+	case maxWind > 25:
+		score -= 2
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Malam yang baik untuk berkemah."
+	case score >= 5:
+		recommendation = "Cukup baik untuk berkemah dengan persiapan."
+	case score >= 3:
+		recommendation = "Kurang ideal, siapkan perlengkapan ekstra."
+	default:
+		recommendation = "Tidak disarankan berkemah malam ini."
+	}
+
+	// Saran perlengkapan sesuai kondisi terburuk malam ini
+	var gear []string
+	if maxRain > 20 {
+		gear = append(gear, "Pasang flysheet dan bawa jas hujan.")
+	}
+	if minTemp < 10 {
+		gear = append(gear, "Bawa sleeping bag hangat dan jaket tebal.")
+	} else if minTemp < 15 {
+		gear = append(gear, "Bawa sleeping bag dan jaket.")
+	}
+	if maxWind > 25 {
+		gear = append(gear, "Pasang pasak dan tali tenda dengan kuat, cari tempat terlindung.")
+	}
+	if len(gear) > 0 {
+		recommendation += " " + strings.Join(gear, " ")
+	}
+
+	return model.ActivityIndex{Activity: ActivityCamping, Score: score, Recommendation: recommendation}
+}
+
+// --- Rentang malam ini di zona waktu prakiraan ---
+// Sebelum jam 6 pagi, "malam ini" adalah sisa malam yang sedang berjalan.
+func tonight(forecast model.HourlyForecast, now time.Time) (time.Time, time.Time) {
+	loc := now.Location()
+	if len(forecast) > 0 {
+		loc = forecast[0].Time.Location()
+	}
+	local := now.In(loc)
+	y, m, d := local.Date()
+	if local.Hour() < nightEndHour {
+		return local.Truncate(time.Hour), time.Date(y, m, d, nightEndHour, 0, 0, 0, loc)
+	}
+	start := time.Date(y, m, d, nightStartHour, 0, 0, 0, loc)
+	if local.After(start) {
+		start = local.Truncate(time.Hour)
+	}
+	return start, time.Date(y, m, d+1, nightEndHour, 0, 0, 0, loc)
+}
@@ -0,0 +1,40 @@
+package indices
+
+import (
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Car Wash Index: layak cuci mobil kalau 48 jam ke depan kering ---
+// Yang menentukan peluang hujan tertinggi; hujan dalam 12 jam pertama
+// dihitung lebih berat karena mobil baru saja dicuci.
+func CarWash(forecast model.HourlyForecast, now time.Time) model.ActivityIndex {
+	worst := 0.0
+	for _, hour := range forecast {
+		if hour.Time.Before(now.Truncate(time.Hour)) || !hour.Time.Before(now.Add(48*time.Hour)) {
+			continue
+		}
+		chance := float64(hour.PrecipitationProbability)
+		if hour.Time.Before(now.Add(12 * time.Hour)) {
+			chance *= 1.2
+		}
+		worst = max(worst, chance)
+	}
+
+	score := clampScore(10 - worst/10)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Aman cuci mobil, 2 hari ke depan diperkirakan kering."
+	case score >= 5:
+		recommendation = "Boleh cuci mobil, tapi ada peluang hujan."
+	case score >= 3:
+		recommendation = "Sebaiknya ditunda, kemungkinan hujan cukup tinggi."
+	default:
+		recommendation = "Jangan cuci mobil dulu, hujan hampir pasti turun."
+	}
+
+	return model.ActivityIndex{Activity: ActivityCarWash, Score: score, Recommendation: recommendation}
+}
@@ -0,0 +1,130 @@
+package indices
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Indeks buatan pengguna (POST /indices/custom) ---
+// Sama seperti indeks bawaan: skor mulai dari 10 dan tiap aturan yang
+// terpenuhi mengurangi skor sebesar Weight. Nilai selalu dalam satuan metric.
+type CustomIndex struct {
+	// Wajib kalau disimpan; huruf, angka, "-" dan "_"
+	Name  string       `json:"name,omitempty"`
+	Rules []CustomRule `json:"rules"`
+}
+
+type CustomRule struct {
+	// Nama field cuaca, lihat CustomFields
+	Field string `json:"field"`
+	// >, >=, < atau <=
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+	// Poin yang dikurangi kalau aturan terpenuhi, 0-10
+	Weight float64 `json:"weight"`
+}
+
+const maxCustomRules = 20
+
+var customIndexName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,50}$`)
+
+// Field yang bisa dipakai aturan, sama dengan nama JSON di weather
+var customFields = map[string]func(model.WeatherData) float64{
+	"temperature":          func(w model.WeatherData) float64 { return w.Temperature },
+	"apparent_temperature": func(w model.WeatherData) float64 { return apparentTemperature(w) },
+	"humidity":             func(w model.WeatherData) float64 { return float64(w.Humidity) },
+	"dew_point":            func(w model.WeatherData) float64 { return w.DewPoint },
+	"humidex":              func(w model.WeatherData) float64 { return w.Humidex },
+	"precipitation":        func(w model.WeatherData) float64 { return w.Precipitation },
+	"cloud_cover":          func(w model.WeatherData) float64 { return float64(w.CloudCover) },
+	"uv_index":             func(w model.WeatherData) float64 { return w.UVIndex },
+	"aqi":                  func(w model.WeatherData) float64 { return float64(w.AQI) },
+	"wind_speed":           func(w model.WeatherData) float64 { return w.WindSpeed },
+	"wind_gusts":           func(w model.WeatherData) float64 { return w.WindGusts },
+	"pressure":             func(w model.WeatherData) float64 { return w.Pressure },
+	"visibility":           func(w model.WeatherData) float64 { return w.Visibility },
+	"elevation":            func(w model.WeatherData) float64 { return w.Elevation },
+}
+
+var customOps = map[string]func(value, threshold float64) bool{
+	">":  func(v, t float64) bool { return v > t },
+	">=": func(v, t float64) bool { return v >= t },
+	"<":  func(v, t float64) bool { return v < t },
+	"<=": func(v, t float64) bool { return v <= t },
+}
+
+// --- Nama field yang didukung, terurut untuk pesan error & dokumentasi ---
+func CustomFields() []string {
+	fields := make([]string, 0, len(customFields))
+	for name := range customFields {
+		fields = append(fields, name)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// --- Nilai satu field cuaca; ok false kalau field tidak didukung ---
+func CustomFieldValue(field string, weather model.WeatherData) (float64, bool) {
+	get, ok := customFields[field]
+	if !ok {
+		return 0, false
+	}
+	return get(weather), true
+}
+
+// --- Bandingkan nilai dengan ambang memakai operator aturan; ok false kalau operator tidak dikenal ---
+func CompareCustom(op string, value, threshold float64) (matched, ok bool) {
+	compare, ok := customOps[op]
+	if !ok {
+		return false, false
+	}
+	return compare(value, threshold), true
+}
+
+// requireName true untuk indeks yang akan disimpan
+func (ci CustomIndex) Validate(requireName bool) error {
+	if (requireName || ci.Name != "") && !customIndexName.MatchString(ci.Name) {
+		return fmt.Errorf("name must be 1 to 50 letters, digits, '-' or '_', got %q", ci.Name)
+	}
+	if len(ci.Rules) == 0 || len(ci.Rules) > maxCustomRules {
+		return fmt.Errorf("rules must have 1 to %d entries, got %d", maxCustomRules, len(ci.Rules))
+	}
+	for i, rule := range ci.Rules {
+		if _, ok := customFields[rule.Field]; !ok {
+			return fmt.Errorf("rules[%d].field %q is not supported (use one of %v)", i, rule.Field, CustomFields())
+		}
+		if _, ok := customOps[rule.Op]; !ok {
+			return fmt.Errorf("rules[%d].op must be >, >=, < or <=, got %q", i, rule.Op)
+		}
+		if rule.Weight < 0 || rule.Weight > 10 {
+			return fmt.Errorf("rules[%d].weight must be between 0 and 10, got %g", i, rule.Weight)
+		}
+	}
+	return nil
+}
+
+// --- Hitung skor; tiap aturan ikut dikembalikan dengan nilai aktual dan hasilnya ---
+// Aturan harus sudah lolos Validate.
+func (ci CustomIndex) Score(weather model.WeatherData) (float64, []model.CustomRuleResult) {
+	score := 10.0
+	results := make([]model.CustomRuleResult, len(ci.Rules))
+	for i, rule := range ci.Rules {
+		value := customFields[rule.Field](weather)
+		matched := customOps[rule.Op](value, rule.Threshold)
+		if matched {
+			score -= rule.Weight
+		}
+		results[i] = model.CustomRuleResult{
+			Field:     rule.Field,
+			Op:        rule.Op,
+			Threshold: rule.Threshold,
+			Weight:    rule.Weight,
+			Value:     value,
+			Matched:   matched,
+		}
+	}
+	return clampScore(score), results
+}
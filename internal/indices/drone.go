@@ -0,0 +1,78 @@
+package indices
+
+import (
+	"strings"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Drone Index: angin & hembusan, hujan, jarak pandang dan indeks Kp ---
+// Ambang angin mengikuti drone konsumer kelas ringan (tahan ~38 km/j).
+// kp nil kalau data cuaca antariksa tidak tersedia.
+func Drone(weather model.WeatherData, kp *float64) (float64, string) {
+	score := 10.0
+
+	switch {
+	case weather.WindSpeed > 38:
+		score -= 6
+	case weather.WindSpeed > 29:
+		score -= 3
+	case weather.WindSpeed > 20:
+		score -= 1
+	}
+	switch {
+	case weather.WindGusts > 45:
+		score -= 4
+	case weather.WindGusts > 35:
+		score -= 2
+	}
+
+	// Kebanyakan drone tidak tahan air
+	if weather.Precipitation > 0.1 {
+		score -= 5
+	}
+
+	// Terbang harus dalam jangkauan pandang (VLOS); 0 = data tidak ada
+	switch v := weather.Visibility; {
+	case v > 0 && v < 1000:
+		score -= 5
+	case v > 0 && v < 3000:
+		score -= 2
+	}
+
+	if kp != nil {
+		switch {
+		case *kp >= 7:
+			score -= 4
+		case *kp >= 5:
+			score -= 2
+		case *kp >= 4:
+			score -= 1
+		}
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Kondisi aman untuk menerbangkan drone."
+	case score >= 5:
+		recommendation = "Cukup aman, terbang dengan hati-hati."
+	case score >= 3:
+		recommendation = "Berisiko, sebaiknya tunda penerbangan."
+	default:
+		recommendation = "Jangan terbangkan drone sekarang."
+	}
+
+	var tips []string
+	if weather.WindGusts > 35 {
+		tips = append(tips, "Hembusan angin kencang, jaga ketinggian tetap rendah.")
+	}
+	if kp != nil && *kp >= 5 {
+		tips = append(tips, "Badai geomagnetik, GPS dan kompas bisa tidak akurat.")
+	}
+	if len(tips) > 0 {
+		recommendation += " " + strings.Join(tips, " ")
+	}
+	return score, recommendation
+}
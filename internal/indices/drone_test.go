@@ -0,0 +1,55 @@
+package indices
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+func TestDrone(t *testing.T) {
+	kp := func(v float64) *float64 { return &v }
+	calm := model.WeatherData{WindSpeed: 10, WindGusts: 15, Visibility: 20000}
+	with := func(change func(*model.WeatherData)) model.WeatherData {
+		w := calm
+		change(&w)
+		return w
+	}
+
+	tests := []struct {
+		name           string
+		weather        model.WeatherData
+		kp             *float64
+		score          float64
+		recommendation string
+	}{
+		{"calm", calm, nil, 10, "Kondisi aman untuk menerbangkan drone."},
+		{"wind at 20", with(func(w *model.WeatherData) { w.WindSpeed = 20 }), nil, 10, "Kondisi aman"},
+		{"wind above 20", with(func(w *model.WeatherData) { w.WindSpeed = 20.1 }), nil, 9, "Kondisi aman"},
+		{"wind above 29", with(func(w *model.WeatherData) { w.WindSpeed = 29.1 }), nil, 7, "Cukup aman, terbang dengan hati-hati."},
+		{"wind above 38", with(func(w *model.WeatherData) { w.WindSpeed = 38.1 }), nil, 4, "Berisiko, sebaiknya tunda penerbangan."},
+		{"gusty", with(func(w *model.WeatherData) { w.WindGusts = 35.1 }), nil, 8, "Kondisi aman untuk menerbangkan drone. Hembusan angin kencang"},
+		{"strong gusts", with(func(w *model.WeatherData) { w.WindGusts = 45.1 }), nil, 6, "Cukup aman"},
+		{"drizzle", with(func(w *model.WeatherData) { w.Precipitation = 0.2 }), nil, 5, "Cukup aman"},
+		{"fog", with(func(w *model.WeatherData) { w.Visibility = 999 }), nil, 5, "Cukup aman"},
+		{"haze", with(func(w *model.WeatherData) { w.Visibility = 2999 }), nil, 8, "Kondisi aman"},
+		// 0 berarti jarak pandang tidak diketahui, bukan nol
+		{"no visibility data", with(func(w *model.WeatherData) { w.Visibility = 0 }), nil, 10, "Kondisi aman"},
+		{"kp 4", calm, kp(4), 9, "Kondisi aman"},
+		{"kp 5", calm, kp(5), 8, "Kondisi aman untuk menerbangkan drone. Badai geomagnetik"},
+		{"kp 7", calm, kp(7), 6, "Cukup aman"},
+		{
+			"clamped at 0",
+			model.WeatherData{WindSpeed: 45, WindGusts: 60, Precipitation: 2, Visibility: 500},
+			kp(8), 0, "Jangan terbangkan drone sekarang.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, recommendation := Drone(tt.weather, tt.kp)
+			if score != tt.score || !strings.HasPrefix(recommendation, tt.recommendation) {
+				t.Errorf("Drone = %v %q, want %v %q...", score, recommendation, tt.score, tt.recommendation)
+			}
+		})
+	}
+}
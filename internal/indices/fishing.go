@@ -0,0 +1,88 @@
+package indices
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Fishing Index: tekanan, angin, fase bulan dan hujan ---
+// Ikan cenderung aktif makan saat tekanan turun perlahan menjelang perubahan
+// cuaca, dan saat bulan baru/purnama ketika pasang-surut paling kuat.
+// sea nil kalau data laut tidak tersedia; indeks tetap dihitung tanpanya.
+func Fishing(weather model.WeatherData, forecast model.HourlyForecast, moon model.MoonData, sea *model.SeaState, now time.Time) model.ActivityIndex {
+	score := 7.0
+
+	trend, ok := PressureTrend(forecast, now)
+	if weather.PressureChange != nil {
+		trend, ok = *weather.PressureChange, true
+	}
+	if ok {
+		switch {
+		case trend <= -3:
+			// Turun tajam: badai mendekat
+			score -= 2
+		case trend <= -0.5:
+			score += 2
+		case trend >= 3:
+			score -= 2
+		case trend >= 1:
+			score -= 1
+		}
+	}
+
+	switch {
+	case weather.WindSpeed > 30:
+		score -= 4
+	case weather.WindSpeed > 20:
+		score -= 2
+	case weather.WindSpeed >= 5 && weather.WindSpeed <= 15:
+		// Riak kecil membuat ikan kurang waspada
+		score += 0.5
+	}
+
+	// 1 saat bulan baru/purnama, 0 saat kuartal
+	spring := math.Abs(2*moon.Illumination - 1)
+	score += spring * 1.5
+	// Pasang perigee: bulan dekat sekaligus baru/purnama, arus paling kuat
+	if moon.Perigee && spring > 0.8 {
+		score += 0.5
+	}
+
+	// Air pasang membawa ikan ke pinggir; saat air diam menjelang titik balik sepi
+	if sea != nil {
+		switch {
+		case slackTide(sea.Tides, now):
+			score -= 0.5
+		case sea.Tides.State == TideRising:
+			score += 1
+		}
+	}
+
+	switch {
+	case weather.Precipitation > 5:
+		score -= 3
+	case weather.Precipitation > 1:
+		score -= 1
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Waktu yang sangat baik untuk memancing, ikan sedang aktif."
+	case score >= 5:
+		recommendation = "Cukup baik untuk memancing."
+	case score >= 3:
+		recommendation = "Kurang ideal, hasil tangkapan mungkin sedikit."
+	default:
+		recommendation = "Tidak disarankan memancing, angin atau hujan terlalu kuat."
+	}
+	if sea != nil && sea.Tides.NextHigh != nil {
+		recommendation += fmt.Sprintf(" Pasang berikutnya pukul %s.", sea.Tides.NextHigh.Time.Format("15:04"))
+	}
+
+	return model.ActivityIndex{Activity: ActivityFishing, Score: score, Recommendation: recommendation}
+}
@@ -0,0 +1,52 @@
+package indices
+
+import (
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Kabut radiasi terbentuk saat udara hampir jenuh (selisih suhu dan titik
+// embun kecil) dan angin lemah, paling sering menjelang sampai sesaat
+// setelah matahari terbit.
+const (
+	fogSpread        = 2.0  // °C
+	fogWind          = 10.0 // km/j
+	fogVisibility    = 1000 // m, batas kabut menurut WMO
+	fogBeforeSunrise = 3 * time.Hour
+	fogAfterSunrise  = 2 * time.Hour
+)
+
+// --- Risiko kabut di sekitar matahari terbit berikutnya ---
+// Jarak pandang di bawah 1 km berarti sudah berkabut sekarang. Selain itu
+// dicek jam-jam prakiraan di sekitar terbit; kalau sekarang sudah di jendela
+// itu, kondisi saat ini juga dihitung. now harus di zona waktu lokasi.
+func FogRisk(weather model.WeatherData, forecast model.HourlyForecast, sun model.SunData, now time.Time) bool {
+	if weather.Visibility > 0 && weather.Visibility < fogVisibility {
+		return true
+	}
+
+	y, m, d := now.Date()
+	sunrise := time.Date(y, m, d, 0, 0, 0, 0, now.Location()).Add(sunClock(sun.Sunrise, 6*time.Hour))
+	if now.After(sunrise.Add(fogAfterSunrise)) {
+		sunrise = sunrise.AddDate(0, 0, 1)
+	}
+	start, end := sunrise.Add(-fogBeforeSunrise), sunrise.Add(fogAfterSunrise)
+
+	if !now.Before(start) && foggy(weather.Temperature, weather.DewPoint, weather.WindSpeed) {
+		return true
+	}
+	for _, hour := range forecast {
+		if hour.Time.Before(start) || hour.Time.After(end) {
+			continue
+		}
+		if foggy(hour.Temperature, hour.DewPoint, hour.WindSpeed) {
+			return true
+		}
+	}
+	return false
+}
+
+func foggy(temperature, dewPoint, wind float64) bool {
+	return temperature-dewPoint <= fogSpread && wind <= fogWind
+}
@@ -0,0 +1,122 @@
+// Package indices menghitung indeks aktivitas (skor 0-10 + rekomendasi)
+// dari data cuaca yang sudah dikonsolidasi.
+package indices
+
+import (
+	"math"
+	"slices"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Calculate Hiking, Running & Cycling Index dari cuaca saat ini ---
+func Calculate(weather model.WeatherData, scoring Scoring) model.CalculatedIndices {
+	result := hikingIndex(weather, scoring.Hiking)
+	result.RunningIndex, result.RunningRecommendation = Running(weather)
+	result.CyclingIndex, result.CyclingRecommendation = Cycling(weather)
+	return result
+}
+
+func hikingIndex(weather model.WeatherData, scoring HikingScoring) model.CalculatedIndices {
+	apparent := apparentTemperature(weather)
+	factors := []model.IndexFactor{
+		heatFactor(weather, scoring.HotApparent),
+		// Angin di punggungan membuat dingin terasa lebih menggigit
+		indexFactor(model.FactorCold, apparent, scoring.ColdApparent.Threshold, scoring.ColdApparent.below(apparent),
+			"Suhu terasa terlalu dingin, apalagi di punggungan yang berangin."),
+		indexFactor(model.FactorPrecipitation, weather.Precipitation, scoring.Precipitation.Threshold, scoring.Precipitation.above(weather.Precipitation),
+			"Hujan membuat jalur licin."),
+		indexFactor(model.FactorUV, weather.UVIndex, scoring.UV.Threshold, scoring.UV.above(weather.UVIndex),
+			"UV sangat tinggi, kulit cepat terbakar."),
+		indexFactor(model.FactorAQI, float64(weather.AQI), scoring.AQI.Threshold, scoring.AQI.above(float64(weather.AQI)),
+			"Kualitas udara buruk untuk aktivitas berat."),
+		indexFactor(model.FactorCloudCover, float64(weather.CloudCover), scoring.CloudCover.Threshold, scoring.CloudCover.above(float64(weather.CloudCover)),
+			"Langit tertutup awan, pemandangan terbatas."),
+	}
+
+	result := hikingResult(10 - deducted(factors))
+	result.IndexBreakdown = factors
+	// Pendakian subuh ke puncak paling sering terganggu kabut
+	if weather.FogRisk {
+		result.HikingRecommendation += " Waspada kabut di pagi hari, bawa senter dan alat navigasi."
+	}
+	return result
+}
+
+// --- Hiking Index untuk satu hari prakiraan ---
+// Tanpa kelembapan & AQI harian; hujan dinilai dari total sehari. Panas dari
+// suhu maksimum, dingin dari suhu minimum: pendakian ke puncak biasanya
+// berangkat dini hari, saat suhu terendah.
+func HikingDay(day model.DailyForecast, scoring DailyHikingScoring) model.CalculatedIndices {
+	rain := indexFactor(model.FactorPrecipitation, day.PrecipitationSum, scoring.Rain.Threshold, scoring.Rain.above(day.PrecipitationSum),
+		"Hujan dalam sehari membuat jalur licin.")
+	if heavy := scoring.HeavyRain.above(day.PrecipitationSum); heavy > 0 {
+		rain = indexFactor(model.FactorPrecipitation, day.PrecipitationSum, scoring.HeavyRain.Threshold, heavy,
+			"Hujan lebat, jalur licin dan rawan longsor.")
+	}
+	factors := []model.IndexFactor{
+		indexFactor(model.FactorHeat, day.TemperatureMax, scoring.Hot.Threshold, scoring.Hot.above(day.TemperatureMax),
+			"Suhu maksimum terlalu panas."),
+		indexFactor(model.FactorCold, day.TemperatureMin, scoring.Cold.Threshold, scoring.Cold.below(day.TemperatureMin),
+			"Suhu minimum terlalu dingin untuk berangkat dini hari."),
+		rain,
+		indexFactor(model.FactorUV, day.UVIndexMax, scoring.UV.Threshold, scoring.UV.above(day.UVIndexMax),
+			"UV sangat tinggi, kulit cepat terbakar."),
+	}
+
+	result := hikingResult(10 - deducted(factors))
+	result.IndexBreakdown = factors
+	return result
+}
+
+// --- Hiking Index untuk satu hari lampau, dengan aturan harian yang sama ---
+// Reanalisis ERA5 tidak punya UV, jadi faktor UV tidak ikut dinilai.
+func HikingPastDay(day model.HistoryDay, scoring DailyHikingScoring) model.CalculatedIndices {
+	result := HikingDay(model.DailyForecast{TemperatureMin: day.TemperatureMin, TemperatureMax: day.TemperatureMax, PrecipitationSum: day.PrecipitationSum}, scoring)
+	result.IndexBreakdown = slices.DeleteFunc(result.IndexBreakdown, func(f model.IndexFactor) bool {
+		return f.Factor == model.FactorUV
+	})
+	return result
+}
+
+// --- Satu baris index_breakdown; alasan hanya diisi kalau poin dipotong ---
+func indexFactor(name string, value, threshold float64, points int, reason string) model.IndexFactor {
+	factor := model.IndexFactor{Factor: name, Value: model.Round1(value), Threshold: threshold, PointsDeducted: float64(points)}
+	if points > 0 {
+		factor.Reason = reason
+	}
+	return factor
+}
+
+func deducted(factors []model.IndexFactor) int {
+	total := 0
+	for _, f := range factors {
+		total += int(f.PointsDeducted)
+	}
+	return total
+}
+
+func hikingResult(score int) model.CalculatedIndices {
+	if score < 0 {
+		score = 0
+	} else if score > 10 {
+		score = 10
+	}
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Sangat baik untuk mendaki!"
+	case score >= 5:
+		recommendation = "Cukup baik, tetapi perhatikan cuaca."
+	case score >= 3:
+		recommendation = "Kurang disarankan, kondisi tidak ideal."
+	default:
+		recommendation = "Tidak disarankan untuk mendaki hari ini."
+	}
+
+	return model.CalculatedIndices{
+		HikingIndex:          math.Round(float64(score)*10) / 10,
+		HikingRecommendation: recommendation,
+	}
+}
@@ -0,0 +1,90 @@
+package indices
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Cuaca yang tidak memotong satu poin pun dengan DefaultScoring
+var idealHiking = model.WeatherData{Temperature: 24, Apparent: 25, UVIndex: 5, AQI: 20, CloudCover: 30}
+
+func TestHikingIndex(t *testing.T) {
+	tests := []struct {
+		name           string
+		change         func(*model.WeatherData)
+		score          float64
+		recommendation string
+	}{
+		{"ideal", func(w *model.WeatherData) {}, 10, "Sangat baik untuk mendaki!"},
+		{"apparent at hot threshold", func(w *model.WeatherData) { w.Apparent = 35 }, 10, "Sangat baik untuk mendaki!"},
+		{"apparent above hot threshold", func(w *model.WeatherData) { w.Apparent = 35.1 }, 7, "Cukup baik, tetapi perhatikan cuaca."},
+		{"apparent at cold threshold", func(w *model.WeatherData) { w.Apparent = 16 }, 10, "Sangat baik untuk mendaki!"},
+		{"apparent below cold threshold", func(w *model.WeatherData) { w.Apparent = 15.9 }, 8, "Sangat baik untuk mendaki!"},
+		{"rain at threshold", func(w *model.WeatherData) { w.Precipitation = 1 }, 10, "Sangat baik untuk mendaki!"},
+		{"rain above threshold", func(w *model.WeatherData) { w.Precipitation = 1.1 }, 6, "Cukup baik, tetapi perhatikan cuaca."},
+		{"dangerous humidex", func(w *model.WeatherData) { w.Comfort = ComfortBerbahaya }, 5, "Cukup baik, tetapi perhatikan cuaca."},
+		{"score 3", func(w *model.WeatherData) { w.Precipitation, w.UVIndex, w.CloudCover = 5, 10, 90 }, 3, "Kurang disarankan, kondisi tidak ideal."},
+		{"score 2", func(w *model.WeatherData) { w.Precipitation, w.AQI, w.CloudCover = 5, 150, 90 }, 2, "Tidak disarankan untuk mendaki hari ini."},
+		{
+			"clamped at 0",
+			func(w *model.WeatherData) {
+				w.Apparent, w.Precipitation, w.UVIndex, w.AQI, w.CloudCover = 40, 5, 10, 150, 90
+			},
+			0, "Tidak disarankan untuk mendaki hari ini.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weather := idealHiking
+			tt.change(&weather)
+			got := Calculate(weather, DefaultScoring())
+			if got.HikingIndex != tt.score || got.HikingRecommendation != tt.recommendation {
+				t.Errorf("hiking = %v %q, want %v %q", got.HikingIndex, got.HikingRecommendation, tt.score, tt.recommendation)
+			}
+		})
+	}
+}
+
+func TestHikingIndexBreakdown(t *testing.T) {
+	weather := idealHiking
+	weather.Precipitation = 3
+	weather.FogRisk = true
+	got := Calculate(weather, DefaultScoring())
+
+	if len(got.IndexBreakdown) != 6 {
+		t.Fatalf("got %d factors, want 6", len(got.IndexBreakdown))
+	}
+	for _, factor := range got.IndexBreakdown {
+		deducts := factor.Factor == model.FactorPrecipitation
+		if (factor.PointsDeducted > 0) != deducts || (factor.Reason != "") != deducts {
+			t.Errorf("factor %+v: only precipitation should deduct points and give a reason", factor)
+		}
+	}
+	if !strings.Contains(got.HikingRecommendation, "kabut") {
+		t.Errorf("recommendation = %q, want a fog warning", got.HikingRecommendation)
+	}
+}
+
+func TestHikingDay(t *testing.T) {
+	scoring := DefaultScoring().Hiking.Daily
+	tests := []struct {
+		name  string
+		day   model.DailyForecast
+		score float64
+	}{
+		{"mild dry day", model.DailyForecast{TemperatureMin: 20, TemperatureMax: 28, UVIndexMax: 6}, 10},
+		{"light rain", model.DailyForecast{TemperatureMin: 20, TemperatureMax: 28, PrecipitationSum: 5}, 8},
+		// Hujan lebat menggantikan penalti hujan biasa, bukan ditambah
+		{"heavy rain", model.DailyForecast{TemperatureMin: 20, TemperatureMax: 28, PrecipitationSum: 25}, 6},
+		{"hot and cold", model.DailyForecast{TemperatureMin: 5, TemperatureMax: 34}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HikingDay(tt.day, scoring); got.HikingIndex != tt.score {
+				t.Errorf("hiking = %v, want %v", got.HikingIndex, tt.score)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package indices
+
+import (
+	"math"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Kategori kenyamanan dari humidex ---
+const (
+	ComfortNyaman    = "nyaman"
+	ComfortGerah     = "gerah"
+	ComfortBerbahaya = "berbahaya"
+)
+
+// --- Humidex (Environment Canada): suhu yang terasa karena kelembapan ---
+// Tekanan uap dihitung dari RH dengan rumus Magnus.
+func Humidex(temperature float64, humidity int) float64 {
+	vapor := float64(humidity) / 100 * 6.112 * math.Exp(17.67*temperature/(temperature+243.5))
+	return model.Round1(temperature + 5.0/9.0*(vapor-10))
+}
+
+// --- Titik embun dari suhu & RH (Magnus), untuk sumber yang tidak mengirimnya ---
+func DewPoint(temperature float64, humidity int) float64 {
+	if humidity <= 0 {
+		return temperature
+	}
+	gamma := math.Log(float64(humidity)/100) + 17.62*temperature/(243.12+temperature)
+	return model.Round1(243.12 * gamma / (17.62 - gamma))
+}
+
+// Di bawah 30 tidak terasa gerah; mulai 40 aktivitas berat berisiko heat stroke.
+func Comfort(humidex float64) string {
+	switch {
+	case humidex >= 40:
+		return ComfortBerbahaya
+	case humidex >= 30:
+		return ComfortGerah
+	default:
+		return ComfortNyaman
+	}
+}
+
+// Suhu terasa; data lama di cache (atau provider yang tidak mengirimnya) jatuh ke suhu udara
+func apparentTemperature(weather model.WeatherData) float64 {
+	if weather.Apparent == 0 && weather.Temperature != 0 {
+		return weather.Temperature
+	}
+	return weather.Apparent
+}
+
+// --- Penalti panas untuk indeks aktivitas, disesuaikan kategori kenyamanan ---
+// Panas kering lebih bisa ditoleransi daripada panas lembap; kalau kategori
+// belum dihitung, kembali ke aturan suhu saja. "Panas" diukur dari suhu terasa
+// di atas hotApparent.
+func heatPenalty(weather model.WeatherData, hotApparent float64) int {
+	hot := apparentTemperature(weather) > hotApparent
+	switch weather.Comfort {
+	case ComfortBerbahaya:
+		return 5
+	case ComfortGerah:
+		if hot {
+			return 3
+		}
+		return 1
+	case ComfortNyaman:
+		if hot {
+			return 1
+		}
+		return 0
+	default:
+		if hot {
+			return 3
+		}
+		return 0
+	}
+}
+
+// --- Faktor panas untuk index_breakdown ---
+// Alasannya mengikuti sumber penalti: humidex atau suhu terasa.
+func heatFactor(weather model.WeatherData, hotApparent float64) model.IndexFactor {
+	points := heatPenalty(weather, hotApparent)
+	reason := "Suhu terasa terlalu panas."
+	switch {
+	case weather.Comfort == ComfortBerbahaya:
+		reason = "Humidex berbahaya, risiko heat stroke."
+	case weather.Comfort == ComfortGerah && apparentTemperature(weather) <= hotApparent:
+		reason = "Udara lembap dan gerah."
+	}
+	return indexFactor(model.FactorHeat, apparentTemperature(weather), hotApparent, points, reason)
+}
@@ -0,0 +1,34 @@
+package indices
+
+import (
+	"math"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Kecerahan langit alami (airglow, cahaya bintang), mcd/m²
+const naturalSkyBrightness = 0.171168
+
+// Batas bawah SQM (mag/arcsec²) tiap kelas Bortle 1-8; di bawah kelas 8 = 9
+var bortleSQM = []float64{21.99, 21.89, 21.69, 20.49, 19.50, 18.94, 18.38, 17.80}
+
+// --- Kelas Bortle dari kecerahan langit buatan (mcd/m², World Atlas 2015) ---
+func Bortle(artificial float64) model.LightPollution {
+	sqm := math.Log10((artificial+naturalSkyBrightness)/108e6) / -0.4
+	class := len(bortleSQM) + 1
+	for i, limit := range bortleSQM {
+		if sqm >= limit {
+			class = i + 1
+			break
+		}
+	}
+	return model.LightPollution{Bortle: class, SkyBrightness: model.Round2(sqm)}
+}
+
+// Mulai Bortle 7 inti Bima Sakti praktis tidak terlihat lagi
+const milkyWayMaxBortle = 6
+
+// --- Bima Sakti masih bisa terlihat dari langit ini? Tanpa data dianggap bisa ---
+func MilkyWayVisible(light *model.LightPollution) bool {
+	return light == nil || light.Bortle <= milkyWayMaxBortle
+}
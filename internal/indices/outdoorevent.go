@@ -0,0 +1,86 @@
+package indices
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Rentang prakiraan yang relevan untuk satu acara (piknik, resepsi, kumpul)
+const eventWindow = 6 * time.Hour
+
+// --- Outdoor Event Index: kenyamanan suhu, peluang hujan 6 jam ke depan, angin ---
+func OutdoorEvent(weather model.WeatherData, forecast model.HourlyForecast, now time.Time) model.ActivityIndex {
+	maxRain := 0
+	for _, hour := range forecast {
+		if hour.Time.Before(now.Truncate(time.Hour)) || !hour.Time.Before(now.Add(eventWindow)) {
+			continue
+		}
+		maxRain = max(maxRain, hour.PrecipitationProbability)
+	}
+
+	score := 10.0
+	switch {
+	case weather.Comfort == ComfortBerbahaya:
+		score -= 4
+	case feelsLike(weather) >= 35:
+		score -= 2
+	case weather.Comfort == ComfortGerah:
+		score -= 1
+	case weather.Temperature < 18:
+		score -= 2
+	}
+
+	switch {
+	case maxRain > 70:
+		score -= 5
+	case maxRain > 40:
+		score -= 3
+	case maxRain > 20:
+		score -= 1
+	}
+	if weather.Precipitation > 0.2 {
+		score -= 2
+	}
+
+	// Tenda, dekorasi dan makanan mudah terganggu angin
+	switch {
+	case weather.WindSpeed > 30:
+		score -= 3
+	case weather.WindSpeed > 20:
+		score -= 1
+	}
+	if weather.UVIndex > 8 {
+		score -= 1
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Cuaca mendukung untuk acara di luar ruangan."
+	case score >= 5:
+		recommendation = "Acara luar ruangan masih bisa, siapkan rencana cadangan."
+	case score >= 3:
+		recommendation = "Kurang ideal, pertimbangkan tempat beratap."
+	default:
+		recommendation = "Tidak disarankan mengadakan acara di luar ruangan."
+	}
+
+	var tips []string
+	if maxRain > 40 {
+		tips = append(tips, "Siapkan tenda atau payung.")
+	}
+	if weather.UVIndex > 8 || feelsLike(weather) >= 35 {
+		tips = append(tips, "Sediakan peneduh dan air minum yang cukup.")
+	}
+	if weather.WindSpeed > 20 {
+		tips = append(tips, "Ikat dekorasi dan tenda dengan kuat.")
+	}
+	if len(tips) > 0 {
+		recommendation += " " + strings.Join(tips, " ")
+	}
+
+	return model.ActivityIndex{Activity: ActivityOutdoorEvent, Score: score, Recommendation: recommendation}
+}
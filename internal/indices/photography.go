@@ -0,0 +1,100 @@
+package indices
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Di luar jendela, cahaya masih cukup bagus kalau jendelanya tinggal sebentar lagi
+const lightWindowMargin = time.Hour
+
+// --- Photography Index: kualitas cahaya (golden/blue hour), awan dan hujan ---
+// now harus di zona waktu lokasi, sama seperti jam di SunData.
+func Photography(weather model.WeatherData, sun model.SunData, now time.Time) (float64, string) {
+	clock := sinceMidnight(now)
+	sunrise, sunset := sunClock(sun.Sunrise, 6*time.Hour), sunClock(sun.Sunset, 18*time.Hour)
+	windows := []model.TimeWindow{sun.BlueHourMorning, sun.GoldenHourMorning, sun.GoldenHourEvening, sun.BlueHourEvening}
+
+	score := 10.0
+	switch distance := windowDistance(windows, clock); {
+	case distance == 0:
+		// Sedang golden/blue hour
+	case distance <= lightWindowMargin:
+		score -= 2
+	case clock > sunrise && clock < sunset:
+		// Siang terik: cahaya keras dan bayangan tajam
+		score -= 4
+	default:
+		score -= 6
+	}
+
+	// Awan sebagian memberi warna dan tekstur; langit polos atau mendung penuh kurang menarik
+	switch cloud := weather.CloudCover; {
+	case cloud > 85:
+		score -= 4
+	case cloud > 60:
+		score -= 2
+	case cloud < 20:
+		score -= 1
+	}
+
+	switch {
+	case weather.Precipitation > 1:
+		score -= 3
+	case weather.Precipitation > 0.2:
+		score -= 1
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Cahaya dan langit sangat bagus untuk memotret."
+	case score >= 5:
+		recommendation = "Cukup baik untuk memotret."
+	case score >= 3:
+		recommendation = "Kurang ideal, cahaya atau langit kurang mendukung."
+	default:
+		recommendation = "Tidak disarankan memotret pemandangan sekarang."
+	}
+	if next, ok := nextWindow(sun, clock); ok {
+		if clock >= sunClock(next.Start, 0) {
+			recommendation += fmt.Sprintf(" Golden hour sedang berlangsung sampai %s.", next.End)
+		} else {
+			recommendation += fmt.Sprintf(" Golden hour berikutnya %s-%s.", next.Start, next.End)
+		}
+	}
+	return score, recommendation
+}
+
+// Jarak dari jam sekarang ke jendela terdekat; 0 kalau sedang di dalamnya
+func windowDistance(windows []model.TimeWindow, clock time.Duration) time.Duration {
+	best := 24 * time.Hour
+	for _, w := range windows {
+		start, end := sunClock(w.Start, -1), sunClock(w.End, -1)
+		if start < 0 || end < 0 {
+			continue
+		}
+		switch {
+		case clock >= start && clock < end:
+			return 0
+		case clock < start:
+			best = min(best, start-clock)
+		default:
+			best = min(best, clock-end)
+		}
+	}
+	return best
+}
+
+// Golden hour yang sedang atau akan berlangsung hari ini; ok=false setelah golden hour sore selesai
+func nextWindow(sun model.SunData, clock time.Duration) (model.TimeWindow, bool) {
+	for _, w := range []model.TimeWindow{sun.GoldenHourMorning, sun.GoldenHourEvening} {
+		if end := sunClock(w.End, -1); end >= 0 && clock < end {
+			return w, true
+		}
+	}
+	return model.TimeWindow{}, false
+}
@@ -0,0 +1,47 @@
+package indices
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+func TestPhotography(t *testing.T) {
+	sun := model.SunData{
+		Sunrise:           "06:00",
+		Sunset:            "18:00",
+		BlueHourMorning:   model.TimeWindow{Start: "05:35", End: "05:50"},
+		GoldenHourMorning: model.TimeWindow{Start: "05:50", End: "06:45"},
+		GoldenHourEvening: model.TimeWindow{Start: "17:15", End: "18:10"},
+		BlueHourEvening:   model.TimeWindow{Start: "18:10", End: "18:25"},
+	}
+	at := func(hour, minute int) time.Time { return time.Date(2024, 6, 21, hour, minute, 0, 0, time.UTC) }
+	partlyCloudy := model.WeatherData{CloudCover: 40}
+
+	tests := []struct {
+		name           string
+		weather        model.WeatherData
+		now            time.Time
+		score          float64
+		recommendation string
+	}{
+		{"golden hour", partlyCloudy, at(17, 30), 10, "Cahaya dan langit sangat bagus untuk memotret. Golden hour sedang berlangsung sampai 18:10."},
+		{"blue hour", partlyCloudy, at(18, 15), 10, "Cahaya dan langit sangat bagus untuk memotret."},
+		{"before golden hour", partlyCloudy, at(16, 30), 8, "Cahaya dan langit sangat bagus untuk memotret. Golden hour berikutnya 17:15-18:10."},
+		{"midday", partlyCloudy, at(12, 0), 6, "Cukup baik untuk memotret. Golden hour berikutnya 17:15-18:10."},
+		{"late night", partlyCloudy, at(22, 0), 4, "Kurang ideal, cahaya atau langit kurang mendukung."},
+		{"before dawn", partlyCloudy, at(2, 0), 4, "Kurang ideal, cahaya atau langit kurang mendukung. Golden hour berikutnya 05:50-06:45."},
+		{"overcast golden hour", model.WeatherData{CloudCover: 90}, at(17, 30), 6, "Cukup baik untuk memotret. Golden hour sedang berlangsung sampai 18:10."},
+		{"cloudless golden hour", model.WeatherData{CloudCover: 10}, at(17, 30), 9, "Cahaya dan langit sangat bagus untuk memotret. Golden hour sedang berlangsung sampai 18:10."},
+		{"rain at midday", model.WeatherData{CloudCover: 70, Precipitation: 1.5}, at(12, 0), 1, "Tidak disarankan memotret pemandangan sekarang. Golden hour berikutnya 17:15-18:10."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, recommendation := Photography(tt.weather, sun, tt.now)
+			if score != tt.score || recommendation != tt.recommendation {
+				t.Errorf("Photography = %v %q, want %v %q", score, recommendation, tt.score, tt.recommendation)
+			}
+		})
+	}
+}
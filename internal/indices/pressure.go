@@ -0,0 +1,68 @@
+package indices
+
+import (
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Rentang tren tekanan; 3 jam adalah acuan standar tendensi barometrik
+const pressureTrendWindow = 3 * time.Hour
+
+// --- Tendensi barometrik dari perubahan 3 jam ---
+const (
+	PressureRising  = "rising"
+	PressureFalling = "falling"
+	PressureSteady  = "steady"
+)
+
+// Perubahan di bawah 1 hPa per 3 jam dianggap stabil
+func PressureTendency(change float64) string {
+	switch {
+	case change >= 1:
+		return PressureRising
+	case change <= -1:
+		return PressureFalling
+	default:
+		return PressureSteady
+	}
+}
+
+// --- Perubahan tekanan (hPa) selama 3 jam terakhir dari prakiraan per jam ---
+// ok=false kalau jam-jam itu tidak ada di prakiraan atau tekanannya kosong.
+func PressureTrend(forecast model.HourlyForecast, now time.Time) (float64, bool) {
+	var current, earlier *model.ForecastHour
+	end := now.Truncate(time.Hour)
+	for i := range forecast {
+		switch hour := &forecast[i]; {
+		case hour.Time.Equal(end):
+			current = hour
+		case hour.Time.Equal(end.Add(-pressureTrendWindow)):
+			earlier = hour
+		}
+	}
+	if current == nil || earlier == nil || current.PressureMSL == 0 || earlier.PressureMSL == 0 {
+		return 0, false
+	}
+	return model.Round1(current.PressureMSL - earlier.PressureMSL), true
+}
+
+// --- Perubahan tekanan 3 jam dari riwayat pembacaan yang disimpan per lokasi ---
+// Dipakai pembacaan tertua yang umurnya 2-4 jam, lalu diskalakan ke 3 jam.
+func PressureHistoryTrend(history []model.PressureReading, current float64, now time.Time) (float64, bool) {
+	var earlier *model.PressureReading
+	for i := range history {
+		age := now.Sub(history[i].At)
+		if age < 2*time.Hour || age > 4*time.Hour {
+			continue
+		}
+		if earlier == nil || history[i].At.Before(earlier.At) {
+			earlier = &history[i]
+		}
+	}
+	if earlier == nil || current == 0 || earlier.Pressure == 0 {
+		return 0, false
+	}
+	scale := float64(pressureTrendWindow) / float64(now.Sub(earlier.At))
+	return model.Round1((current - earlier.Pressure) * scale), true
+}
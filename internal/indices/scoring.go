@@ -0,0 +1,109 @@
+package indices
+
+import "fmt"
+
+// --- Ambang & penalti skor indeks mendaki, bagian "scoring" di config ---
+// Dibaca per perhitungan supaya bisa disetel ops lewat reload tanpa deploy
+// ulang. Indeks lain masih memakai konstanta di file masing-masing.
+type Scoring struct {
+	Hiking HikingScoring `yaml:"hiking"`
+}
+
+// Skor dikurangi Penalty kalau nilai melewati Threshold (di atas, atau di
+// bawah untuk ambang dingin)
+type Penalty struct {
+	Threshold float64 `yaml:"threshold"`
+	Penalty   int     `yaml:"penalty"`
+}
+
+// --- Hiking Index dari cuaca saat ini ---
+type HikingScoring struct {
+	// Suhu terasa (°C) yang dianggap panas; besar penaltinya dari kategori
+	// kenyamanan, lihat heatPenalty. Suhu terasa di tropis lembap biasanya
+	// beberapa derajat di atas suhu udara, jadi ambang default (35 / 16) berbeda
+	// dari ambang harian yang memakai suhu udara maksimum (33 / 18).
+	HotApparent float64 `yaml:"hot_apparent"`
+	// Suhu terasa (°C) di bawah ambang
+	ColdApparent Penalty `yaml:"cold_apparent"`
+	// Hujan (mm/jam), UV index, AQI Eropa dan tutupan awan (%) di atas ambang
+	Precipitation Penalty `yaml:"precipitation"`
+	UV            Penalty `yaml:"uv"`
+	AQI           Penalty `yaml:"aqi"`
+	CloudCover    Penalty `yaml:"cloud_cover"`
+
+	Daily DailyHikingScoring `yaml:"daily"`
+}
+
+// --- Hiking Index per hari prakiraan ---
+// Hujan dinilai dari total sehari: HeavyRain menggantikan Rain, bukan ditambah.
+// Hot dinilai dari suhu maksimum, Cold dari suhu minimum.
+type DailyHikingScoring struct {
+	Hot       Penalty `yaml:"hot"`
+	Cold      Penalty `yaml:"cold"`
+	Rain      Penalty `yaml:"rain"`
+	HeavyRain Penalty `yaml:"heavy_rain"`
+	UV        Penalty `yaml:"uv"`
+}
+
+func DefaultScoring() Scoring {
+	return Scoring{Hiking: HikingScoring{
+		HotApparent:   35,
+		ColdApparent:  Penalty{Threshold: 16, Penalty: 2},
+		Precipitation: Penalty{Threshold: 1, Penalty: 4},
+		UV:            Penalty{Threshold: 8, Penalty: 2},
+		AQI:           Penalty{Threshold: 100, Penalty: 3},
+		CloudCover:    Penalty{Threshold: 80, Penalty: 1},
+		Daily: DailyHikingScoring{
+			Hot:       Penalty{Threshold: 33, Penalty: 3},
+			Cold:      Penalty{Threshold: 8, Penalty: 2},
+			Rain:      Penalty{Threshold: 2, Penalty: 2},
+			HeavyRain: Penalty{Threshold: 10, Penalty: 4},
+			UV:        Penalty{Threshold: 8, Penalty: 2},
+		},
+	}}
+}
+
+func (s Scoring) Validate() error {
+	h := s.Hiking
+	penalties := map[string]Penalty{
+		"hiking.cold_apparent":    h.ColdApparent,
+		"hiking.precipitation":    h.Precipitation,
+		"hiking.uv":               h.UV,
+		"hiking.aqi":              h.AQI,
+		"hiking.cloud_cover":      h.CloudCover,
+		"hiking.daily.hot":        h.Daily.Hot,
+		"hiking.daily.cold":       h.Daily.Cold,
+		"hiking.daily.rain":       h.Daily.Rain,
+		"hiking.daily.heavy_rain": h.Daily.HeavyRain,
+		"hiking.daily.uv":         h.Daily.UV,
+	}
+	for name, p := range penalties {
+		if p.Penalty < 0 || p.Penalty > 10 {
+			return fmt.Errorf("%s.penalty must be between 0 and 10, got %d", name, p.Penalty)
+		}
+	}
+	if h.ColdApparent.Threshold >= h.HotApparent {
+		return fmt.Errorf("hiking.cold_apparent.threshold must be below hot_apparent, got %g and %g", h.ColdApparent.Threshold, h.HotApparent)
+	}
+	if h.Daily.Cold.Threshold >= h.Daily.Hot.Threshold {
+		return fmt.Errorf("hiking.daily.cold.threshold must be below hot.threshold, got %g and %g", h.Daily.Cold.Threshold, h.Daily.Hot.Threshold)
+	}
+	if h.Daily.Rain.Threshold >= h.Daily.HeavyRain.Threshold {
+		return fmt.Errorf("hiking.daily.rain.threshold must be below heavy_rain.threshold, got %g and %g", h.Daily.Rain.Threshold, h.Daily.HeavyRain.Threshold)
+	}
+	return nil
+}
+
+func (p Penalty) above(value float64) int {
+	if value > p.Threshold {
+		return p.Penalty
+	}
+	return 0
+}
+
+func (p Penalty) below(value float64) int {
+	if value < p.Threshold {
+		return p.Penalty
+	}
+	return 0
+}
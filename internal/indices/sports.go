@@ -0,0 +1,123 @@
+package indices
+
+import (
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Suhu terasa; kalau humidex belum dihitung pakai suhu udara saja
+func feelsLike(weather model.WeatherData) float64 {
+	if weather.Humidex != 0 {
+		return weather.Humidex
+	}
+	return weather.Temperature
+}
+
+// --- Running Index: pelari tahan dingin tapi rentan panas lembap ---
+func Running(weather model.WeatherData) (float64, string) {
+	score := 10.0
+
+	switch felt := feelsLike(weather); {
+	case felt >= 40:
+		score -= 5
+	case felt >= 35:
+		score -= 3
+	case felt >= 30:
+		score -= 1
+	case weather.Temperature < 0:
+		score -= 3
+	case weather.Temperature < 5:
+		score -= 1
+	}
+
+	switch {
+	case weather.Precipitation > 2:
+		score -= 3
+	case weather.Precipitation > 0.5:
+		score -= 1
+	}
+
+	// Napas berat saat lari: polusi lebih berpengaruh daripada saat jalan kaki
+	switch {
+	case weather.AQI > 80:
+		score -= 3
+	case weather.AQI > 60:
+		score -= 1
+	}
+
+	if weather.UVIndex > 8 {
+		score -= 1
+	}
+	if weather.WindSpeed > 40 {
+		score -= 2
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Kondisi ideal untuk lari."
+	case score >= 5:
+		recommendation = "Cukup baik untuk lari, bawa air minum."
+	case score >= 3:
+		recommendation = "Kurang ideal, kurangi intensitas atau lari di dalam ruangan."
+	default:
+		recommendation = "Tidak disarankan lari di luar sekarang."
+	}
+
+	// Serbuk sari tidak mengubah skor (tidak semua pelari alergi), cukup peringatan
+	if weather.Pollen != nil && weather.Pollen.AllergyIndex >= AllergyHigh {
+		recommendation += " Serbuk sari tinggi, penderita alergi sebaiknya minum obat dulu atau lari di dalam ruangan."
+	}
+	return score, recommendation
+}
+
+// --- Cycling Index: pesepeda paling terganggu angin dan jalan basah ---
+func Cycling(weather model.WeatherData) (float64, string) {
+	score := 10.0
+
+	switch {
+	case weather.WindSpeed > 35:
+		score -= 4
+	case weather.WindSpeed > 25:
+		score -= 2
+	case weather.WindSpeed > 15:
+		score -= 1
+	}
+
+	switch felt := feelsLike(weather); {
+	case felt >= 40:
+		score -= 4
+	case felt >= 35:
+		score -= 2
+	case weather.Temperature < 8:
+		// Angin laju membuat dingin terasa lebih menggigit
+		score -= 2
+	}
+
+	// Jalan basah licin dan jarak pengereman lebih panjang
+	switch {
+	case weather.Precipitation > 0.5:
+		score -= 3
+	case weather.Precipitation > 0.1:
+		score -= 1
+	}
+
+	if weather.AQI > 80 {
+		score -= 2
+	}
+	if weather.UVIndex > 8 {
+		score -= 1
+	}
+	score = clampScore(score)
+
+	switch {
+	case score >= 8:
+		return score, "Kondisi ideal untuk bersepeda."
+	case score >= 5:
+		return score, "Cukup baik untuk bersepeda, perhatikan angin."
+	case score >= 3:
+		return score, "Kurang ideal, jalan basah atau angin kencang."
+	default:
+		return score, "Tidak disarankan bersepeda sekarang."
+	}
+}
@@ -0,0 +1,77 @@
+package indices
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+func TestRunning(t *testing.T) {
+	tests := []struct {
+		name           string
+		weather        model.WeatherData
+		score          float64
+		recommendation string
+	}{
+		{"ideal", model.WeatherData{Temperature: 20}, 10, "Kondisi ideal untuk lari."},
+		{"just below warm", model.WeatherData{Temperature: 29.9}, 10, "Kondisi ideal untuk lari."},
+		{"warm", model.WeatherData{Temperature: 30}, 9, "Kondisi ideal untuk lari."},
+		// Humidex dipakai kalau ada, bukan suhu udara
+		{"humid heat", model.WeatherData{Temperature: 32, Humidex: 40}, 5, "Cukup baik untuk lari, bawa air minum."},
+		{"freezing", model.WeatherData{Temperature: -1}, 7, "Cukup baik untuk lari, bawa air minum."},
+		{"rain at 2 mm", model.WeatherData{Temperature: 20, Precipitation: 2}, 9, "Kondisi ideal untuk lari."},
+		{"polluted", model.WeatherData{Temperature: 20, AQI: 81, Precipitation: 3, WindSpeed: 41}, 2, "Tidak disarankan lari di luar sekarang."},
+		{
+			"clamped at 0",
+			model.WeatherData{Temperature: 33, Humidex: 42, Precipitation: 3, AQI: 90, UVIndex: 9, WindSpeed: 50},
+			0, "Tidak disarankan lari di luar sekarang.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, recommendation := Running(tt.weather)
+			if score != tt.score || recommendation != tt.recommendation {
+				t.Errorf("Running = %v %q, want %v %q", score, recommendation, tt.score, tt.recommendation)
+			}
+		})
+	}
+}
+
+// Serbuk sari hanya menambah peringatan, skor tetap
+func TestRunningPollen(t *testing.T) {
+	score, recommendation := Running(model.WeatherData{Temperature: 20, Pollen: &model.Pollen{AllergyIndex: AllergyHigh}})
+	if score != 10 || !strings.Contains(recommendation, "Serbuk sari tinggi") {
+		t.Errorf("Running = %v %q, want 10 with a pollen warning", score, recommendation)
+	}
+}
+
+func TestCycling(t *testing.T) {
+	tests := []struct {
+		name           string
+		weather        model.WeatherData
+		score          float64
+		recommendation string
+	}{
+		{"calm", model.WeatherData{Temperature: 22, WindSpeed: 15}, 10, "Kondisi ideal untuk bersepeda."},
+		{"breezy", model.WeatherData{Temperature: 22, WindSpeed: 15.1}, 9, "Kondisi ideal untuk bersepeda."},
+		{"windy", model.WeatherData{Temperature: 22, WindSpeed: 25.1}, 8, "Kondisi ideal untuk bersepeda."},
+		{"cold", model.WeatherData{Temperature: 7.9}, 8, "Kondisi ideal untuk bersepeda."},
+		{"drizzle at 0.1 mm", model.WeatherData{Temperature: 22, Precipitation: 0.1}, 10, "Kondisi ideal untuk bersepeda."},
+		{"score 5", model.WeatherData{Temperature: 22, WindSpeed: 40, Precipitation: 0.2}, 5, "Cukup baik untuk bersepeda, perhatikan angin."},
+		{"score 3", model.WeatherData{Temperature: 22, WindSpeed: 40, Precipitation: 1}, 3, "Kurang ideal, jalan basah atau angin kencang."},
+		{
+			"clamped at 0",
+			model.WeatherData{Temperature: 35, Humidex: 41, WindSpeed: 40, Precipitation: 1, AQI: 90, UVIndex: 9},
+			0, "Tidak disarankan bersepeda sekarang.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, recommendation := Cycling(tt.weather)
+			if score != tt.score || recommendation != tt.recommendation {
+				t.Errorf("Cycling = %v %q, want %v %q", score, recommendation, tt.score, tt.recommendation)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package indices
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Langit baru benar-benar gelap sekitar 1 jam setelah terbenam (dan sampai
+// 1 jam sebelum terbit); di antaranya masih senja.
+const darkAfterSunset = time.Hour
+
+// Pengurangan skor per kelas Bortle; langit pedesaan (1-3) tidak dikurangi
+var bortlePenalty = []float64{0, 0, 0, 1, 2, 3, 4, 5, 5}
+
+// --- Stargazing Index: awan, cahaya bulan, polusi cahaya dan seberapa gelap langit sekarang ---
+// now harus di zona waktu lokasi, sama seperti jam di SunData. light nil
+// kalau data polusi cahaya tidak tersedia.
+func Stargazing(weather model.WeatherData, moon model.MoonData, sun model.SunData, light *model.LightPollution, now time.Time) (float64, string) {
+	sunrise, sunset := sunClock(sun.Sunrise, 6*time.Hour), sunClock(sun.Sunset, 18*time.Hour)
+	clock := sinceMidnight(now)
+
+	// Siang hari: bintang tidak terlihat apa pun cuacanya
+	if clock >= sunrise && clock < sunset {
+		return 0, "Masih siang, tunggu sampai langit gelap setelah matahari terbenam."
+	}
+
+	score := 10.0
+	twilight := (clock >= sunset && clock < sunset+darkAfterSunset) ||
+		(clock >= sunrise-darkAfterSunset && clock < sunrise)
+	if twilight {
+		score -= 2
+	}
+
+	// Awan paling menentukan: langit tertutup penuh = hampir tidak ada bintang
+	score -= float64(weather.CloudCover) / 100 * 7
+	// Bulan purnama menenggelamkan bintang redup dan Bima Sakti; bulan yang
+	// lebih dekat juga lebih terang (kuadrat terbalik jarak)
+	score -= moon.Illumination * 3 * moonBrightness(moon)
+	if weather.Precipitation > 0.5 {
+		score -= 2
+	}
+	// Cahaya kota menenggelamkan bintang redup apa pun kondisi awan & bulan
+	if light != nil {
+		score -= bortlePenalty[light.Bortle-1]
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Langit cerah dan gelap, sangat baik untuk mengamati bintang."
+	case score >= 5:
+		recommendation = "Cukup baik, bintang terang dan planet masih terlihat."
+	case score >= 3:
+		recommendation = "Kurang ideal, awan atau cahaya bulan mengganggu pengamatan."
+	default:
+		recommendation = "Tidak disarankan, langit tertutup atau terlalu terang."
+	}
+	if light != nil && !MilkyWayVisible(light) {
+		recommendation += fmt.Sprintf(" Polusi cahaya tinggi (Bortle %d), cari lokasi yang lebih gelap untuk melihat Bima Sakti.", light.Bortle)
+	}
+	return score, recommendation
+}
+
+// Kecerahan bulan relatif terhadap jarak rata-rata: ~1,15 saat supermoon
+func moonBrightness(moon model.MoonData) float64 {
+	const meanDistanceKm = 384400.0
+	if moon.Distance <= 0 {
+		return 1
+	}
+	return math.Pow(meanDistanceKm/moon.Distance, 2)
+}
+
+// Jam lokal sebagai durasi sejak tengah malam, supaya bisa dibandingkan dengan sunClock
+func sinceMidnight(t time.Time) time.Duration {
+	y, m, d := t.Date()
+	return t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+}
+
+// Jam "15:04" dari SunData sebagai durasi sejak tengah malam
+func sunClock(hhmm string, fallback time.Duration) time.Duration {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return fallback
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
@@ -0,0 +1,57 @@
+package indices
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+func TestStargazing(t *testing.T) {
+	sun := model.SunData{Sunrise: "06:00", Sunset: "18:00"}
+	at := func(hour, minute int) time.Time { return time.Date(2024, 6, 21, hour, minute, 0, 0, time.UTC) }
+	newMoon := model.MoonData{Illumination: 0, Distance: 384400}
+
+	tests := []struct {
+		name           string
+		weather        model.WeatherData
+		moon           model.MoonData
+		light          *model.LightPollution
+		now            time.Time
+		score          float64
+		recommendation string
+	}{
+		{"daytime", model.WeatherData{}, newMoon, nil, at(12, 0), 0, "Masih siang"},
+		{"clear dark night", model.WeatherData{}, newMoon, nil, at(22, 0), 10, "Langit cerah dan gelap"},
+		{"evening twilight", model.WeatherData{}, newMoon, nil, at(18, 30), 8, "Langit cerah dan gelap"},
+		{"dark after twilight", model.WeatherData{}, newMoon, nil, at(19, 0), 10, "Langit cerah dan gelap"},
+		{"morning twilight", model.WeatherData{}, newMoon, nil, at(5, 30), 8, "Langit cerah dan gelap"},
+		{"overcast", model.WeatherData{CloudCover: 100}, newMoon, nil, at(22, 0), 3, "Kurang ideal"},
+		{"overcast and raining", model.WeatherData{CloudCover: 100, Precipitation: 1}, newMoon, nil, at(22, 0), 1, "Tidak disarankan"},
+		{"full moon", model.WeatherData{}, model.MoonData{Illumination: 1, Distance: 384400}, nil, at(22, 0), 7, "Cukup baik"},
+		// Bulan lebih dekat, lebih terang: 3 × (384400/357000)² ≈ 3,48
+		{"supermoon", model.WeatherData{}, model.MoonData{Illumination: 1, Distance: 357000}, nil, at(22, 0), 6.5, "Cukup baik"},
+		{"rural sky", model.WeatherData{}, newMoon, &model.LightPollution{Bortle: 2}, at(22, 0), 10, "Langit cerah dan gelap"},
+		{"city sky", model.WeatherData{}, newMoon, &model.LightPollution{Bortle: 9}, at(22, 0), 5, "Cukup baik"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, recommendation := Stargazing(tt.weather, tt.moon, sun, tt.light, tt.now)
+			if score != tt.score || !strings.HasPrefix(recommendation, tt.recommendation) {
+				t.Errorf("Stargazing = %v %q, want %v %q...", score, recommendation, tt.score, tt.recommendation)
+			}
+		})
+	}
+}
+
+func TestStargazingLightPollutionTip(t *testing.T) {
+	sun := model.SunData{Sunrise: "06:00", Sunset: "18:00"}
+	night := time.Date(2024, 6, 21, 22, 0, 0, 0, time.UTC)
+	for bortle := 1; bortle <= 9; bortle++ {
+		_, recommendation := Stargazing(model.WeatherData{}, model.MoonData{}, sun, &model.LightPollution{Bortle: bortle}, night)
+		if got, want := strings.Contains(recommendation, "Bima Sakti"), bortle > milkyWayMaxBortle; got != want {
+			t.Errorf("Bortle %d: Milky Way tip = %v, want %v", bortle, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package indices
+
+import (
+	"math"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Dosis eritema minimal (J/m²) per tipe kulit Fitzpatrick ---
+// UV index 1 setara 0,025 W/m² radiasi eritemal.
+var minimalErythemalDose = map[int]float64{1: 200, 2: 250, 3: 350, 4: 450, 5: 600, 6: 1000}
+
+const (
+	uvIndexWatts = 0.025
+	// Tabir surya dianjurkan mulai UV 3, dioles ulang tiap 2 jam
+	sunscreenUV       = 3
+	sunscreenInterval = 2 * time.Hour
+)
+
+func ValidSkinType(skinType int) bool {
+	_, ok := minimalErythemalDose[skinType]
+	return ok
+}
+
+// --- Waktu aman tanpa pelindung + jadwal oles ulang tabir surya untuk hari ini ---
+// Tanggal dan jam mengikuti zona lokasi dari prakiraan.
+func SunExposure(forecast model.HourlyForecast, skinType int, now time.Time) model.SunExposureResponse {
+	dose := minimalErythemalDose[skinType]
+	response := model.SunExposureResponse{
+		SkinType: skinType,
+		Date:     now.Format(time.DateOnly),
+		Hours:    []model.SunExposureHour{},
+		Reapply:  []string{},
+		Meta:     model.ResponseMeta{Units: model.UnitsMetric},
+	}
+	if len(forecast) == 0 {
+		response.Recommendation = sunExposureRecommendation(0, nil)
+		return response
+	}
+
+	now = now.In(forecast[0].Time.Location())
+	response.Date = now.Format(time.DateOnly)
+	currentHour := now.Truncate(time.Hour)
+
+	var today model.HourlyForecast
+	for _, hour := range forecast {
+		if hour.Time.Format(time.DateOnly) == response.Date {
+			today = append(today, hour)
+		}
+	}
+
+	// Akumulasi dosis dari sekarang sampai MED tercapai
+	received := 0.0
+	for _, hour := range today {
+		if hour.UVIndex > 0 {
+			response.Hours = append(response.Hours, model.SunExposureHour{
+				Time:        hour.Time.Format("15:04"),
+				UVIndex:     hour.UVIndex,
+				SafeMinutes: minutesToBurn(dose, hour.UVIndex),
+			})
+		}
+		if hour.Time.Equal(currentHour) {
+			response.UVIndex = hour.UVIndex
+		}
+		end := hour.Time.Add(time.Hour)
+		if !end.After(now) || response.SafeMinutes != nil {
+			continue
+		}
+		start := hour.Time
+		if start.Before(now) {
+			start = now
+		}
+		rate := hour.UVIndex * uvIndexWatts
+		if rate > 0 && received+rate*end.Sub(start).Seconds() >= dose {
+			burnAt := start.Add(time.Duration((dose - received) / rate * float64(time.Second)))
+			minutes := int(burnAt.Sub(now).Minutes())
+			response.SafeMinutes = &minutes
+		}
+		received += rate * end.Sub(start).Seconds()
+	}
+
+	// Oles pertama di jam pertama UV >= 3 (atau sekarang), lalu tiap 2 jam
+	var next time.Time
+	for _, hour := range today {
+		if hour.UVIndex < sunscreenUV || hour.Time.Add(time.Hour).Before(now) {
+			continue
+		}
+		if next.IsZero() {
+			next = hour.Time
+			if next.Before(now) {
+				next = now.Truncate(15 * time.Minute)
+			}
+		}
+		for !next.After(hour.Time) {
+			response.Reapply = append(response.Reapply, next.Format("15:04"))
+			next = next.Add(sunscreenInterval)
+		}
+	}
+
+	response.Recommendation = sunExposureRecommendation(response.UVIndex, response.SafeMinutes)
+	return response
+}
+
+func minutesToBurn(dose, uv float64) *int {
+	if uv < 1 {
+		return nil
+	}
+	minutes := int(math.Round(dose / (uv * uvIndexWatts) / 60))
+	return &minutes
+}
+
+func sunExposureRecommendation(uv float64, safeMinutes *int) string {
+	switch {
+	case uv < sunscreenUV:
+		return "UV rendah, tabir surya belum wajib saat ini."
+	case safeMinutes == nil || *safeMinutes >= 60:
+		return "Aman beraktivitas di luar, pakai tabir surya kalau lebih dari 1 jam."
+	case *safeMinutes >= 20:
+		return "Pakai tabir surya SPF 30+ dan topi, batasi paparan langsung."
+	default:
+		return "UV sangat tinggi, hindari matahari langsung dan cari tempat teduh."
+	}
+}
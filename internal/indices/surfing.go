@@ -0,0 +1,68 @@
+package indices
+
+import (
+	"fmt"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Surfing Index: tinggi & periode gelombang, angin dan hujan ---
+// sea nil kalau data laut tidak tersedia (titik di darat atau Marine gagal).
+func Surfing(weather model.WeatherData, sea *model.SeaState) model.ActivityIndex {
+	if sea == nil {
+		return model.ActivityIndex{Activity: ActivitySurfing, Recommendation: "Data gelombang tidak tersedia untuk lokasi ini."}
+	}
+	score := 10.0
+
+	switch h := sea.Waves.Height; {
+	case h < 0.5:
+		// Laut terlalu tenang
+		score -= 6
+	case h < 1:
+		score -= 3
+	case h > 4:
+		score -= 6
+	case h > 2.5:
+		// Hanya untuk peselancar berpengalaman
+		score -= 2
+	}
+
+	// Periode pendek = ombak angin yang berantakan, periode panjang = swell rapi
+	switch p := sea.Waves.Period; {
+	case p < 6:
+		score -= 3
+	case p < 9:
+		score -= 1
+	case p >= 12:
+		score += 0.5
+	}
+
+	switch {
+	case weather.WindSpeed > 30:
+		score -= 3
+	case weather.WindSpeed > 20:
+		score -= 1.5
+	}
+	if weather.Precipitation > 5 {
+		score -= 1
+	}
+	score = clampScore(score)
+
+	var recommendation string
+	switch {
+	case score >= 8:
+		recommendation = "Ombak bagus untuk berselancar!"
+	case score >= 5:
+		recommendation = "Cukup baik untuk berselancar."
+	case score >= 3:
+		recommendation = "Kurang ideal, ombak kecil atau berantakan."
+	default:
+		recommendation = "Tidak disarankan berselancar saat ini."
+	}
+	recommendation += fmt.Sprintf(" Ombak sekitar %.1f m dengan periode %.0f detik.", sea.Waves.Height, sea.Waves.Period)
+	if sea.Waves.Height > 2.5 {
+		recommendation += " Ombak besar, hanya untuk peselancar berpengalaman."
+	}
+
+	return model.ActivityIndex{Activity: ActivitySurfing, Score: score, Recommendation: recommendation}
+}
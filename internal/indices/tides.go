@@ -0,0 +1,106 @@
+package indices
+
+import (
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Jenis titik balik & arah pasang surut ---
+const (
+	TideHigh    = "high"
+	TideLow     = "low"
+	TideRising  = "rising"
+	TideFalling = "falling"
+)
+
+// Pasang & surut yang dilaporkan di extremes
+const tideWindow = 48 * time.Hour
+
+// --- Pasang surut dan gelombang saat ini dari deret muka air per jam ---
+// Titik balik dicari dari puncak/lembah lokal lalu dihaluskan dengan parabola
+// lewat tiga jam di sekitarnya, jadi waktunya tidak terkunci ke jam bulat.
+// ok false kalau deret tidak mencakup jam sekarang (misal titik di darat).
+func SeaState(hours []model.MarineHour, now time.Time) (model.SeaState, bool) {
+	current := -1
+	for i, hour := range hours {
+		if !hour.Time.After(now) {
+			current = i
+		}
+	}
+	if current < 0 || current == len(hours)-1 {
+		return model.SeaState{}, false
+	}
+
+	// Muka air sekarang: interpolasi linear antara dua jam
+	a, b := hours[current], hours[current+1]
+	frac := float64(now.Sub(a.Time)) / float64(b.Time.Sub(a.Time))
+	tides := model.Tides{Height: model.Round2(a.SeaLevel + (b.SeaLevel-a.SeaLevel)*frac), Extremes: []model.TideExtreme{}}
+
+	for _, extreme := range tideExtremes(hours) {
+		if !extreme.Time.After(now) || extreme.Time.After(now.Add(tideWindow)) {
+			continue
+		}
+		tides.Extremes = append(tides.Extremes, extreme)
+		switch {
+		case extreme.Type == TideHigh && tides.NextHigh == nil:
+			tides.NextHigh = &extreme
+		case extreme.Type == TideLow && tides.NextLow == nil:
+			tides.NextLow = &extreme
+		}
+	}
+	tides.State = TideFalling
+	if b.SeaLevel > a.SeaLevel {
+		tides.State = TideRising
+	}
+	if len(tides.Extremes) > 0 {
+		// Arah menuju titik balik berikutnya lebih tepat daripada selisih satu jam
+		if tides.Extremes[0].Type == TideHigh {
+			tides.State = TideRising
+		} else {
+			tides.State = TideFalling
+		}
+	}
+
+	return model.SeaState{
+		Tides: tides,
+		Waves: model.Waves{Height: a.WaveHeight, Period: a.WavePeriod, Direction: a.WaveDirection},
+	}, true
+}
+
+func tideExtremes(hours []model.MarineHour) []model.TideExtreme {
+	var extremes []model.TideExtreme
+	for i := 1; i < len(hours)-1; i++ {
+		// Hanya tiga jam berurutan; jam yang hilang membuat parabola menyesatkan
+		if hours[i].Time.Sub(hours[i-1].Time) != time.Hour || hours[i+1].Time.Sub(hours[i].Time) != time.Hour {
+			continue
+		}
+		a, b, c := hours[i-1].SeaLevel, hours[i].SeaLevel, hours[i+1].SeaLevel
+		var kind string
+		switch {
+		case b > a && b >= c:
+			kind = TideHigh
+		case b < a && b <= c:
+			kind = TideLow
+		default:
+			continue
+		}
+		// Puncak parabola lewat (-1, a), (0, b), (1, c)
+		offset, height := 0.0, b
+		if curve := a - 2*b + c; curve != 0 {
+			offset = (a - c) / (2 * curve)
+			height = b - (a-c)*offset/4
+		}
+		extremes = append(extremes, model.TideExtreme{
+			Type:   kind,
+			Time:   hours[i].Time.Add(time.Duration(offset * float64(time.Hour))).Truncate(time.Minute),
+			Height: model.Round2(height),
+		})
+	}
+	return extremes
+}
+
+// Air hampir diam (slack) menjelang titik balik berikutnya
+func slackTide(tides model.Tides, now time.Time) bool {
+	return len(tides.Extremes) > 0 && tides.Extremes[0].Time.Sub(now) <= 30*time.Minute
+}
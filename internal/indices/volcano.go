@@ -0,0 +1,72 @@
+package indices
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Tingkat aktivitas gunung api menurut PVMBG (MAGMA Indonesia) ---
+const (
+	VolcanoNormal  = 1
+	VolcanoWaspada = 2
+	VolcanoSiaga   = 3
+	VolcanoAwas    = 4
+)
+
+var volcanoStatuses = map[int]string{
+	VolcanoNormal:  "Normal",
+	VolcanoWaspada: "Waspada",
+	VolcanoSiaga:   "Siaga",
+	VolcanoAwas:    "Awas",
+}
+
+// PVMBG menulis tingkat dengan angka Romawi
+var volcanoLevels = map[int]string{
+	VolcanoNormal:  "I",
+	VolcanoWaspada: "II",
+	VolcanoSiaga:   "III",
+	VolcanoAwas:    "IV",
+}
+
+// Nama status untuk tingkat 1-4; kosong kalau tingkatnya tidak dikenal
+func VolcanoStatus(level int) string {
+	return volcanoStatuses[level]
+}
+
+// --- Batas atas Hiking Index selama gunung api berstatus waspada ke atas ---
+// Cuaca secerah apa pun tidak membuat pendakian aman saat Siaga atau Awas,
+// jadi skor dipotong dan rekomendasinya diganti peringatan status.
+func VolcanoHiking(score float64, recommendation string, volcano *model.Volcano) (float64, string) {
+	if volcano == nil || volcano.Level < VolcanoWaspada {
+		return score, recommendation
+	}
+	name := fmt.Sprintf("Gunung %s berstatus %s (Level %s)", volcano.Name, volcano.Status, volcanoLevels[volcano.Level])
+	switch volcano.Level {
+	case VolcanoWaspada:
+		if score > 5 {
+			score, recommendation = 5, hikingResult(5).HikingRecommendation
+		}
+		return score, recommendation + " " + name + ": jangan mendekati kawah dan patuhi radius aman PVMBG."
+	case VolcanoSiaga:
+		return min(score, 1), name + ": pendakian umumnya ditutup, jauhi zona bahaya."
+	default:
+		return 0, name + ": jangan mendaki, ikuti arahan evakuasi dari petugas."
+	}
+}
+
+// --- Tambahkan faktor gunung api ke breakdown kalau statusnya memotong skor ---
+// before & after = Hiking Index sebelum dan sesudah VolcanoHiking.
+func VolcanoFactor(breakdown []model.IndexFactor, before, after float64, volcano *model.Volcano) []model.IndexFactor {
+	if volcano == nil || after >= before {
+		return breakdown
+	}
+	return append(slices.Clip(breakdown), model.IndexFactor{
+		Factor:         model.FactorVolcano,
+		Value:          float64(volcano.Level),
+		Threshold:      VolcanoWaspada,
+		PointsDeducted: model.Round1(before - after),
+		Reason:         "Status gunung api membatasi pendakian.",
+	})
+}
@@ -0,0 +1,728 @@
+// Package model berisi tipe data response yang dipakai bersama oleh
+// providers, services, indices, astro dan handlers.
+package model
+
+import "time"
+
+// --- Struct untuk data cuaca, matahari, bulan, dan indeks ---
+type WeatherData struct {
+	// Sumber data cuaca saat ini: open-meteo atau bmkg
+	Source      string  `json:"source"`
+	Temperature float64 `json:"temperature"`
+	// Suhu terasa (Steadman, dari Open-Meteo): panas lembap & angin dingin
+	Apparent      float64 `json:"apparent_temperature"`
+	Humidity      int     `json:"humidity"`
+	DewPoint      float64 `json:"dew_point"`
+	Precipitation float64 `json:"precipitation"`
+	CloudCover    int     `json:"cloud_cover"`
+	UVIndex       float64 `json:"uv_index"`
+	// European AQI; sama dengan air_quality.aqi, dipertahankan untuk client lama
+	AQI        int        `json:"aqi"`
+	AirQuality AirQuality `json:"air_quality"`
+	// European AQI per jam untuk 24 jam ke depan
+	AQIForecast []AQIHour `json:"aqi_forecast,omitempty"`
+	// Serbuk sari dan indeks alergi; hanya ada di wilayah yang dicakup CAMS Eropa
+	Pollen *Pollen `json:"pollen,omitempty"`
+	// Angin & hembusan 10 m dalam km/j, arah asal angin dalam derajat
+	// (0 = utara), tekanan permukaan laut dalam hPa, jarak pandang dalam meter
+	WindSpeed     float64 `json:"wind_speed"`
+	WindGusts     float64 `json:"wind_gusts"`
+	WindDirection int     `json:"wind_direction"`
+	Pressure      float64 `json:"pressure"`
+	Visibility    float64 `json:"visibility"`
+	// Tekanan di permukaan (ketinggian lokasi), hPa
+	SurfacePressure float64 `json:"surface_pressure"`
+	// Ketinggian titik dari model elevasi Open-Meteo (90 m), meter
+	Elevation float64 `json:"elevation"`
+	// Perubahan tekanan permukaan laut 3 jam terakhir dan tendensinya
+	// (rising/falling/steady); kosong kalau riwayat belum cukup
+	PressureChange *float64 `json:"pressure_change_3h,omitempty"`
+	PressureTrend  string   `json:"pressure_trend,omitempty"`
+	// Kabut mungkin muncul sekitar matahari terbit berikutnya (atau sudah ada)
+	FogRisk bool `json:"fog_risk"`
+
+	// Humidex dan kategorinya: nyaman, gerah atau berbahaya
+	Humidex float64 `json:"humidex"`
+	Comfort string  `json:"comfort"`
+
+	// UV maksimum hari ini & besok dari prakiraan per jam
+	DailyUV []DailyUV `json:"daily_uv,omitempty"`
+}
+
+type DailyUV struct {
+	Date  string  `json:"date"`
+	UVMax float64 `json:"uv_max"`
+	// Jam lokal (HH:MM) saat UV di atas 8
+	HighUVHours []string `json:"high_uv_hours"`
+}
+
+type SunData struct {
+	Sunrise    string `json:"sunrise"`
+	Sunset     string `json:"sunset"`
+	GoldenHour string `json:"golden_hour_end"`
+
+	// Jendela cahaya untuk fotografi, jam lokal "15:04"
+	GoldenHourMorning TimeWindow `json:"golden_hour_morning"`
+	GoldenHourEvening TimeWindow `json:"golden_hour_evening"`
+	BlueHourMorning   TimeWindow `json:"blue_hour_morning"`
+	BlueHourEvening   TimeWindow `json:"blue_hour_evening"`
+
+	// Senja: start = awal fajar pagi, end = akhir senja sore. Kosong kalau
+	// matahari tidak turun sedalam itu (lintang tinggi di musim panas)
+	CivilTwilight        TimeWindow `json:"civil_twilight"`
+	NauticalTwilight     TimeWindow `json:"nautical_twilight"`
+	AstronomicalTwilight TimeWindow `json:"astronomical_twilight"`
+
+	// Transit matahari (jam lokal "15:04") dan lama siang dari terbit ke terbenam
+	SolarNoon        string `json:"solar_noon"`
+	DayLengthMinutes int    `json:"day_length_minutes"`
+	// Posisi matahari saat request
+	Position SunPosition `json:"position"`
+}
+
+// --- Polusi cahaya di lokasi, dari World Atlas 2015 ---
+type LightPollution struct {
+	// Skala Bortle: 1 langit gelap sempurna .. 9 pusat kota
+	Bortle int `json:"bortle"`
+	// Kecerahan langit di zenit, mag/arcsec² (seperti bacaan SQM); makin besar makin gelap
+	SkyBrightness float64 `json:"sky_brightness"`
+}
+
+type SunPosition struct {
+	// Derajat dari utara searah jarum jam (90 = timur, 270 = barat)
+	Azimuth float64 `json:"azimuth"`
+	// Derajat di atas horizon; negatif saat matahari di bawah horizon
+	Elevation float64 `json:"elevation"`
+}
+
+type TimeWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type MoonData struct {
+	PhaseName string `json:"phase_name"`
+	// Bagian piringan bulan yang diterangi, 0-1
+	Illumination float64 `json:"illumination"`
+	// Hari sejak bulan baru
+	Age float64 `json:"age"`
+	// Sudut Matahari-Bulan-Bumi, derajat: 0 = purnama, 180 = bulan baru
+	PhaseAngle float64 `json:"phase_angle"`
+	// Jarak pusat Bumi ke pusat Bulan, km (356.000-407.000)
+	Distance float64 `json:"distance"`
+	// Bulan sedang di titik terdekat/terjauh orbitnya (dalam ~6 jam)
+	Perigee bool `json:"perigee"`
+	Apogee  bool `json:"apogee"`
+	// Purnama lebih dekat dari 360.000 km: tampak lebih besar & terang, pasang lebih tinggi
+	Supermoon bool `json:"supermoon"`
+}
+
+type CalculatedIndices struct {
+	HikingIndex              float64 `json:"hiking_index"`
+	HikingRecommendation     string  `json:"hiking_recommendation"`
+	RunningIndex             float64 `json:"running_index"`
+	RunningRecommendation    string  `json:"running_recommendation"`
+	CyclingIndex             float64 `json:"cycling_index"`
+	CyclingRecommendation    string  `json:"cycling_recommendation"`
+	StargazingIndex          float64 `json:"stargazing_index"`
+	StargazingRecommendation string  `json:"stargazing_recommendation"`
+	DroneIndex               float64 `json:"drone_index"`
+	DroneRecommendation      string  `json:"drone_recommendation"`
+	// Hanya ada kalau data angin di ketinggian tersedia
+	AirSports                 *AirSportsIndices `json:"air_sports,omitempty"`
+	PhotographyIndex          float64           `json:"photography_index"`
+	PhotographyRecommendation string            `json:"photography_recommendation"`
+	// Faktor yang dinilai hiking_index, termasuk yang tidak memotong skor
+	IndexBreakdown []IndexFactor `json:"index_breakdown,omitempty"`
+}
+
+// --- Faktor yang dinilai indeks ---
+const (
+	FactorHeat          = "heat"
+	FactorCold          = "cold"
+	FactorPrecipitation = "precipitation"
+	FactorUV            = "uv"
+	FactorAQI           = "aqi"
+	FactorCloudCover    = "cloud_cover"
+	FactorVolcano       = "volcano"
+)
+
+// --- Satu faktor indeks: nilai saat ini, ambangnya, poin yang dipotong dan alasannya ---
+// Suhu dalam °C dan hujan dalam mm (ikut ?units=imperial di response gabungan);
+// untuk gunung api nilainya level status.
+type IndexFactor struct {
+	Factor         string  `json:"factor"`
+	Value          float64 `json:"value"`
+	Threshold      float64 `json:"threshold"`
+	PointsDeducted float64 `json:"points_deducted"`
+	// Hanya diisi kalau poin dipotong
+	Reason string `json:"reason,omitempty"`
+}
+
+type ResponseMeta struct {
+	Units string `json:"units"`
+	// Zona waktu IANA tempat jam-jam di response diformat
+	Timezone string `json:"timezone,omitempty"`
+	// Bahasa teks rekomendasi, fase bulan & judul peringatan (?lang=)
+	Lang string `json:"lang,omitempty"`
+	// Bagian yang tidak lengkap karena upstream gagal; response tetap dikirim
+	// dengan status 206
+	Errors []SectionError `json:"errors,omitempty"`
+}
+
+// --- Satu bagian response yang dikosongkan atau diganti cadangan ---
+type SectionError struct {
+	// Path field yang terdampak, misal "weather.aqi" atau "sun"
+	Section  string `json:"section"`
+	Provider string `json:"provider,omitempty"`
+	// network, timeout, status, decode atau disabled
+	Kind    string `json:"kind,omitempty"`
+	Message string `json:"message"`
+}
+
+// --- Tempat hasil geocoding ---
+type Place struct {
+	Name      string  `json:"name"`
+	Region    string  `json:"region,omitempty"`
+	Country   string  `json:"country,omitempty"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+type ConsolidatedResponse struct {
+	Location *Place      `json:"location,omitempty"`
+	Weather  WeatherData `json:"weather"`
+	Sun      SunData     `json:"sun"`
+	Moon     MoonData    `json:"moon"`
+	// Inti Bima Sakti di atas horizon saat malam astronomis tanpa cahaya bulan,
+	// jam lokal; kosong kalau malam ini tidak ada jendela seperti itu
+	MilkyWayWindow *TimeWindow `json:"milky_way_window,omitempty"`
+	// Kecerahan langit malam karena cahaya buatan; kosong kalau data tidak tersedia
+	LightPollution *LightPollution   `json:"light_pollution,omitempty"`
+	Indices        CalculatedIndices `json:"indices"`
+	// Peringatan cuaca yang berlaku di lokasi ini; kosong kalau tidak ada
+	Alerts []Alert `json:"alerts"`
+	// Ada gempa dalam 100 km selama 72 jam terakhir; hanya untuk titik pegunungan
+	RecentSeismicActivity *bool `json:"recent_seismic_activity,omitempty"`
+	// Gunung api terdekat (dalam 10 km) dan tingkat aktivitasnya; hanya Indonesia
+	Volcano *Volcano     `json:"volcano,omitempty"`
+	Meta    ResponseMeta `json:"meta"`
+}
+
+// --- Indeks olahraga udara dari angin permukaan & 850 hPa (~1500 m) ---
+type AirSportsIndices struct {
+	ParaglidingIndex          float64 `json:"paragliding_index"`
+	ParaglidingRecommendation string  `json:"paragliding_recommendation"`
+	WindSurface               Wind    `json:"wind_10m"`
+	Wind850hPa                Wind    `json:"wind_850hpa"`
+	// Perkiraan dasar awan cumulus di atas permukaan, meter
+	CloudBase float64 `json:"cloud_base"`
+}
+
+type Wind struct {
+	Speed     float64 `json:"speed"`
+	Direction int     `json:"direction"`
+}
+
+// --- Satu jam data atmosfer untuk olahraga udara ---
+type AloftHour struct {
+	Time          time.Time `json:"time"`
+	Temperature   float64   `json:"temperature"`
+	DewPoint      float64   `json:"dew_point"`
+	WindSurface   Wind      `json:"wind_10m"`
+	Wind850hPa    Wind      `json:"wind_850hpa"`
+	CloudCoverLow int       `json:"cloud_cover_low"`
+	CAPE          float64   `json:"cape"`
+}
+
+// --- Satu pembacaan tekanan permukaan laut, untuk riwayat tren per lokasi ---
+type PressureReading struct {
+	At       time.Time `json:"at"`
+	Pressure float64   `json:"pressure"`
+}
+
+// --- Jenis & tingkat peringatan cuaca; tingkat mengikuti CAP ---
+const (
+	AlertThunderstorm = "thunderstorm"
+	AlertHeavyRain    = "heavy_rain"
+	AlertStrongWind   = "strong_wind"
+	AlertPressureDrop = "pressure_drop"
+	AlertOther        = "other"
+
+	SeverityMinor    = "minor"
+	SeverityModerate = "moderate"
+	SeveritySevere   = "severe"
+	SeverityExtreme  = "extreme"
+)
+
+// --- Satu peringatan cuaca dengan jendela berlakunya ---
+type Alert struct {
+	Type        string `json:"type"`
+	Severity    string `json:"severity"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Sumber: bmkg (peringatan resmi) atau open-meteo (diturunkan dari prakiraan)
+	Source string    `json:"source"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// --- Peringatan beserta poligon wilayahnya (titik [lat, lon]) ---
+// Satu feed berlaku untuk banyak lokasi, jadi disaring per titik.
+type AreaAlert struct {
+	Alert
+	Polygons [][][2]float64 `json:"polygons"`
+}
+
+type AlertsResponse struct {
+	Alerts []Alert      `json:"alerts"`
+	Meta   ResponseMeta `json:"meta"`
+}
+
+// --- Serbuk sari (butir/m³) dari Open-Meteo Air Quality ---
+// Jenis yang tidak tersedia di lokasi itu bernilai null.
+type Pollen struct {
+	Alder   *float64 `json:"alder"`
+	Birch   *float64 `json:"birch"`
+	Grass   *float64 `json:"grass"`
+	Mugwort *float64 `json:"mugwort"`
+	Olive   *float64 `json:"olive"`
+	Ragweed *float64 `json:"ragweed"`
+
+	// 0 (tidak ada) sampai 4 (sangat tinggi), dari jenis dengan level tertinggi
+	AllergyIndex int    `json:"allergy_index"`
+	AllergyLevel string `json:"allergy_level"`
+	Dominant     string `json:"dominant,omitempty"`
+}
+
+// --- Konsentrasi polutan dekat permukaan, semuanya µg/m³ ---
+type Pollutants struct {
+	PM25 float64 `json:"pm2_5"`
+	PM10 float64 `json:"pm10"`
+	O3   float64 `json:"o3"`
+	NO2  float64 `json:"no2"`
+	SO2  float64 `json:"so2"`
+	CO   float64 `json:"co"`
+}
+
+// --- Blok kualitas udara: indeks, polutan penentunya dan konsentrasinya ---
+// Skala default Eropa; ?aqi_scale= bisa memilih US AQI atau ISPU.
+type AirQuality struct {
+	AQI               int        `json:"aqi"`
+	Scale             string     `json:"scale"`
+	Category          string     `json:"category"`
+	DominantPollutant string     `json:"dominant_pollutant,omitempty"`
+	Pollutants        Pollutants `json:"pollutants"`
+}
+
+type AQIHour struct {
+	Time time.Time `json:"time"`
+	AQI  int       `json:"aqi"`
+}
+
+// --- Satu langkah prakiraan BMKG (per 3 jam, waktu UTC) ---
+type BMKGForecastStep struct {
+	Time          time.Time `json:"time"`
+	Temperature   float64   `json:"temperature"`
+	Humidity      int       `json:"humidity"`
+	CloudCover    int       `json:"cloud_cover"`
+	Precipitation float64   `json:"precipitation"`
+	WeatherCode   int       `json:"weather_code"`
+	Description   string    `json:"description"`
+	WindSpeed     float64   `json:"wind_speed"`
+	WindDirection int       `json:"wind_direction"`
+	Visibility    float64   `json:"visibility"`
+}
+
+// --- Satu gempa bumi dari katalog USGS atau BMKG ---
+type Earthquake struct {
+	Time      time.Time `json:"time"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	// Kedalaman hiposenter, km
+	Depth     float64 `json:"depth"`
+	Magnitude float64 `json:"magnitude"`
+	Place     string  `json:"place"`
+	// Jarak episenter dari titik yang ditanyakan, km
+	Distance float64 `json:"distance"`
+	// Wilayah yang merasakan guncangan beserta skala MMI (hanya BMKG)
+	Felt   string `json:"felt,omitempty"`
+	Source string `json:"source"`
+}
+
+type QuakesResponse struct {
+	Quakes   []Earthquake `json:"quakes"`
+	RadiusKm float64      `json:"radius_km"`
+	Meta     ResponseMeta `json:"meta"`
+}
+
+// --- Status gunung api dari MAGMA Indonesia (PVMBG) ---
+type Volcano struct {
+	Name string `json:"name"`
+	// Kode gunung api MAGMA, misal MER untuk Merapi
+	Code string `json:"code"`
+	// 1 Normal, 2 Waspada, 3 Siaga, 4 Awas
+	Level     int     `json:"level"`
+	Status    string  `json:"status"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Jarak puncak dari titik yang ditanyakan, km
+	Distance float64 `json:"distance"`
+}
+
+// --- Satu jam dari Open-Meteo Marine ---
+type MarineHour struct {
+	Time time.Time `json:"time"`
+	// Muka air terhadap rata-rata permukaan laut (termasuk pasang surut), meter
+	SeaLevel float64 `json:"sea_level"`
+	// Tinggi gelombang signifikan (m), periode (detik) dan arah asalnya (derajat)
+	WaveHeight    float64 `json:"wave_height"`
+	WavePeriod    float64 `json:"wave_period"`
+	WaveDirection int     `json:"wave_direction"`
+}
+
+// --- Satu pasang tertinggi atau surut terendah ---
+type TideExtreme struct {
+	Type   string    `json:"type"` // high atau low
+	Time   time.Time `json:"time"`
+	Height float64   `json:"height"`
+}
+
+// --- Pasang surut di satu titik: muka air sekarang dan titik balik berikutnya ---
+type Tides struct {
+	// Muka air saat ini terhadap rata-rata permukaan laut, meter
+	Height float64 `json:"height"`
+	// rising (menuju pasang) atau falling (menuju surut)
+	State    string       `json:"state"`
+	NextHigh *TideExtreme `json:"next_high,omitempty"`
+	NextLow  *TideExtreme `json:"next_low,omitempty"`
+	// Semua pasang & surut dalam 48 jam ke depan, urut waktu
+	Extremes []TideExtreme `json:"extremes"`
+}
+
+type Waves struct {
+	Height    float64 `json:"height"`
+	Period    float64 `json:"period"`
+	Direction int     `json:"direction"`
+}
+
+// --- Kondisi laut saat ini untuk indeks memancing, selancar dan pantai ---
+type SeaState struct {
+	Tides Tides `json:"tides"`
+	Waves Waves `json:"waves"`
+}
+
+type TidesResponse struct {
+	SeaState
+	Meta ResponseMeta `json:"meta"`
+}
+
+// --- Satu jam dari Air Quality: AQI Eropa, polutan dan serbuk sari ---
+type AirQualityHour struct {
+	Time       time.Time  `json:"time"`
+	AQI        int        `json:"aqi"`
+	Pollutants Pollutants `json:"pollutants"`
+	Pollen     *Pollen    `json:"pollen,omitempty"`
+}
+
+// --- Prakiraan per jam dari provider, urut waktu ---
+type HourlyForecast []ForecastHour
+
+type ForecastHour struct {
+	Time                     time.Time `json:"time"`
+	Temperature              float64   `json:"temperature"`
+	PrecipitationProbability int       `json:"precipitation_probability"`
+	UVIndex                  float64   `json:"uv_index"`
+	PressureMSL              float64   `json:"pressure_msl"`
+	WindSpeed                float64   `json:"wind_speed"`
+	DewPoint                 float64   `json:"dew_point"`
+	// Kode cuaca WMO (95-99 = badai petir) dan hembusan angin 10 m (km/j)
+	WeatherCode int     `json:"weather_code"`
+	WindGusts   float64 `json:"wind_gusts"`
+}
+
+// --- Indeks satu aktivitas untuk endpoint /activities ---
+type ActivityIndex struct {
+	Activity       string  `json:"activity"`
+	Score          float64 `json:"score"`
+	Recommendation string  `json:"recommendation"`
+}
+
+type ActivitiesResponse struct {
+	Activities []ActivityIndex `json:"activities"`
+	Volcano    *Volcano        `json:"volcano,omitempty"`
+	Meta       ResponseMeta    `json:"meta"`
+}
+
+// --- Paparan matahari aman per tipe kulit (Fitzpatrick I-VI) ---
+type SunExposureHour struct {
+	Time    string  `json:"time"`
+	UVIndex float64 `json:"uv_index"`
+	// Menit sampai kulit terbakar kalau UV jam ini bertahan; null = UV terlalu rendah
+	SafeMinutes *int `json:"safe_minutes"`
+}
+
+type SunExposureResponse struct {
+	SkinType int     `json:"skin_type"`
+	Date     string  `json:"date"`
+	UVIndex  float64 `json:"uv_index"`
+	// Menit tanpa pelindung mulai sekarang mengikuti kurva UV; null = aman sampai malam
+	SafeMinutes    *int              `json:"safe_minutes"`
+	Hours          []SunExposureHour `json:"hours"`
+	Reapply        []string          `json:"sunscreen_reapply"`
+	Recommendation string            `json:"recommendation"`
+	Meta           ResponseMeta      `json:"meta"`
+}
+
+// --- Satu observasi yang tercatat: cuaca & indeks saat response gabungan dihitung ---
+type Observation struct {
+	// Sel lokasi yang sama dengan cache (2 desimal, ~1 km)
+	Location   string            `json:"location"`
+	Latitude   float64           `json:"latitude"`
+	Longitude  float64           `json:"longitude"`
+	ObservedAt time.Time         `json:"observed_at"`
+	Weather    WeatherData       `json:"weather"`
+	Indices    CalculatedIndices `json:"indices"`
+}
+
+// --- Skor indeks buatan pengguna beserta hasil tiap aturannya ---
+type CustomIndexResponse struct {
+	Name  string             `json:"name,omitempty"`
+	Score float64            `json:"score"`
+	Rules []CustomRuleResult `json:"rules"`
+	// Indeks ikut disimpan untuk API key ini
+	Saved bool         `json:"saved"`
+	Meta  ResponseMeta `json:"meta"`
+}
+
+type CustomRuleResult struct {
+	Field     string  `json:"field"`
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+	Weight    float64 `json:"weight"`
+	// Nilai field saat ini (metric) dan apakah aturan terpenuhi
+	Value   float64 `json:"value"`
+	Matched bool    `json:"matched"`
+}
+
+// --- API key untuk developer pihak ketiga; key aslinya tidak pernah disimpan ---
+type APIKey struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Awal key (misal tk_Ab3x) supaya bisa dikenali di daftar
+	Prefix string `json:"prefix"`
+	// Request per hari (UTC); 0 = memakai api_keys.default_daily_quota
+	DailyQuota int64      `json:"daily_quota"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// --- Lokasi favorit yang disimpan pengguna ---
+type Favorite struct {
+	Name      string    `json:"name"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	SavedAt   time.Time `json:"saved_at"`
+}
+
+// --- Kanal pengiriman notifikasi langganan ---
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+	// Hanya push ke perangkat yang didaftarkan
+	ChannelPush = "push"
+)
+
+// --- Jenis notifikasi langganan ---
+const (
+	EventConditionTriggered = "condition.triggered"
+	EventDailySummary       = "daily.summary"
+	EventWeeklyReport       = "weekly.report"
+)
+
+// --- Langganan notifikasi: kondisi yang mulai terpenuhi, ringkasan harian dan/atau laporan mingguan ---
+type Subscription struct {
+	ID        string  `json:"id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Bentuk baku kondisi, misal "hiking_index >= 8" atau "rain starting";
+	// kosong kalau hanya ringkasan harian atau laporan mingguan
+	Condition string `json:"condition,omitempty"`
+	// ChannelWebhook, ChannelEmail atau ChannelPush
+	Channel    string `json:"channel"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+	Email      string `json:"email,omitempty"`
+	// Kunci HMAC untuk header signature di tiap webhook
+	Secret string `json:"secret,omitempty"`
+	// Bahasa teks rekomendasi, peringatan & email
+	Lang string `json:"lang"`
+	// Jam lokal (0-23) pengiriman ringkasan harian; kosong = tidak ada
+	DailySummaryHour *int      `json:"daily_summary_hour,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	// Kondisi terpenuhi pada evaluasi terakhir; notifikasi hanya dikirim saat
+	// berubah dari false ke true
+	Triggered       bool       `json:"triggered"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	// Tanggal lokal ringkasan harian terakhir
+	LastSummaryDate string `json:"last_summary_date,omitempty"`
+	// Token FCM perangkat yang ikut menerima push, apa pun kanalnya
+	DeviceTokens []string `json:"device_tokens,omitempty"`
+	// Jadwal laporan mingguan lokasi tersimpan; kosong = tidak ada
+	WeeklyReport *ReportSchedule `json:"weekly_report,omitempty"`
+	// Tanggal lokal laporan mingguan terakhir
+	LastReportDate string `json:"last_report_date,omitempty"`
+	// Pengguna aplikasi pemilik langganan; kondisi astronomi juga dinilai di
+	// tiap lokasi tersimpannya
+	UserID string `json:"user_id,omitempty"`
+	// Hash API key pemilik; diisi store untuk scheduler, tidak ikut response
+	Owner string `json:"-"`
+}
+
+// --- Jadwal laporan mingguan untuk lokasi tersimpan satu pengguna aplikasi ---
+type ReportSchedule struct {
+	// ID pengguna seperti di /users/:id, di bawah API key yang sama
+	UserID string `json:"user_id"`
+	// Hari lokal di lokasi langganan, 0 = Minggu sampai 6 = Sabtu
+	Weekday int `json:"weekday"`
+	// Jam lokal (0-23)
+	Hour int `json:"hour"`
+}
+
+// --- Satu lokasi tersimpan di laporan mingguan ---
+type ReportLocation struct {
+	Name      string      `json:"name"`
+	Latitude  float64     `json:"latitude"`
+	Longitude float64     `json:"longitude"`
+	Weather   WeatherData `json:"weather"`
+	// Prakiraan mulai hari laporan, paling lama 7 hari
+	Days []DailyForecast `json:"days"`
+	// Hari dengan indeks mendaki tertinggi; kosong kalau prakiraan gagal diambil
+	BestDay *DailyForecast `json:"best_day,omitempty"`
+	// Cuaca yang tercatat 7 hari sebelum laporan; kosong kalau tidak ada riwayat
+	PastWeek []ObservedDay `json:"past_week,omitempty"`
+}
+
+// --- Ringkasan observasi tersimpan satu hari lokal ---
+// Urutan field sama dengan kolom query repository (RowToStructByPos).
+type ObservedDay struct {
+	Date           string  `json:"date"`
+	TemperatureMin float64 `json:"temperature_min"`
+	TemperatureMax float64 `json:"temperature_max"`
+	WindGustsMax   float64 `json:"wind_gusts_max"`
+	// Ada observasi yang mencatat hujan hari itu
+	Rained bool `json:"rained"`
+	// Jumlah observasi; sedikit berarti lokasi jarang diminta hari itu
+	Observations int `json:"observations"`
+	// Rata-rata hiking_index dari observasi
+	HikingIndex float64 `json:"hiking_index"`
+}
+
+// --- Isi notifikasi langganan (body webhook, data template email) ---
+type SubscriptionNotification struct {
+	Event          string  `json:"event"`
+	SubscriptionID string  `json:"subscription_id"`
+	Condition      string  `json:"condition,omitempty"`
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	Location       *Place  `json:"location,omitempty"`
+	// Waktu kondisi terpenuhi atau ringkasan dibuat
+	TriggeredAt time.Time `json:"triggered_at"`
+	// Zona waktu lokasi, untuk menampilkan jam lokal
+	Timezone string `json:"timezone"`
+	// Nilai metrik kondisi saat terpenuhi; untuk event, peluang hujan (%)
+	// atau jumlah peringatan. Kosong di ringkasan harian
+	Value   *float64          `json:"value,omitempty"`
+	Weather WeatherData       `json:"weather"`
+	Indices CalculatedIndices `json:"indices"`
+	Alerts  []Alert           `json:"alerts"`
+	// Prakiraan hari ini; hanya di ringkasan harian
+	Forecast *DailyForecast `json:"forecast,omitempty"`
+	// Lokasi tersimpan pengguna; hanya di laporan mingguan
+	Report []ReportLocation `json:"report,omitempty"`
+}
+
+// --- Prakiraan harian dengan indeks mendaki per hari ---
+type DailyForecast struct {
+	Date                 string  `json:"date"`
+	TemperatureMin       float64 `json:"temperature_min"`
+	TemperatureMax       float64 `json:"temperature_max"`
+	PrecipitationSum     float64 `json:"precipitation_sum"`
+	UVIndexMax           float64 `json:"uv_index_max"`
+	HikingIndex          float64 `json:"hiking_index"`
+	HikingRecommendation string  `json:"hiking_recommendation"`
+	// Faktor yang dinilai hiking_index hari itu
+	IndexBreakdown []IndexFactor `json:"index_breakdown,omitempty"`
+}
+
+type DailyForecastResponse struct {
+	Days    []DailyForecast `json:"days"`
+	Volcano *Volcano        `json:"volcano,omitempty"`
+	Meta    ResponseMeta    `json:"meta"`
+}
+
+// --- Cuaca satu hari di masa lalu (ERA5) dengan indeks mendaki yang dihitung ulang ---
+type HistoryDay struct {
+	Date               string  `json:"date"`
+	WeatherCode        int     `json:"weather_code"`
+	TemperatureMin     float64 `json:"temperature_min"`
+	TemperatureMax     float64 `json:"temperature_max"`
+	PrecipitationSum   float64 `json:"precipitation_sum"`
+	PrecipitationHours float64 `json:"precipitation_hours"`
+	// km/h
+	WindSpeedMax         float64       `json:"wind_speed_max"`
+	HikingIndex          float64       `json:"hiking_index"`
+	HikingRecommendation string        `json:"hiking_recommendation"`
+	IndexBreakdown       []IndexFactor `json:"index_breakdown,omitempty"`
+}
+
+// --- Ringkasan rentang /history untuk kilas balik perjalanan ---
+type HistorySummary struct {
+	Days int `json:"days"`
+	// Hari dengan hujan 1 mm atau lebih
+	RainDays           int     `json:"rain_days"`
+	PrecipitationTotal float64 `json:"precipitation_total"`
+	TemperatureMin     float64 `json:"temperature_min"`
+	TemperatureMax     float64 `json:"temperature_max"`
+	HikingIndexAverage float64 `json:"hiking_index_average"`
+	// Hari dengan hiking_index tertinggi (yang paling awal kalau sama)
+	BestDay string `json:"best_day,omitempty"`
+}
+
+// --- Musim per bulan (Schmidt-Ferguson, dari rata-rata hujan bulanan) ---
+const (
+	SeasonDry        = "dry"
+	SeasonTransition = "transition"
+	SeasonWet        = "wet"
+)
+
+// --- Rata-rata satu bulan kalender selama periode klimatologi ---
+type ClimatologyMonth struct {
+	Month int `json:"month"`
+	// Rata-rata suhu minimum & maksimum harian
+	TemperatureMin float64 `json:"temperature_min"`
+	TemperatureMax float64 `json:"temperature_max"`
+	// Rata-rata total hujan sebulan (mm) dan jumlah hari hujan (>= 1 mm)
+	Precipitation      float64 `json:"precipitation"`
+	RainDays           float64 `json:"rain_days"`
+	HikingIndexAverage float64 `json:"hiking_index_average"`
+	// dry, transition atau wet; kosong kalau bulan ini tidak ada datanya
+	Season string `json:"season,omitempty"`
+}
+
+type ClimatologyResponse struct {
+	StartYear int                `json:"start_year"`
+	EndYear   int                `json:"end_year"`
+	Months    []ClimatologyMonth `json:"months"`
+	// Bulan dengan hiking_index_average tertinggi, terbaik dulu
+	BestMonths []int        `json:"best_months"`
+	DryMonths  []int        `json:"dry_months"`
+	Meta       ResponseMeta `json:"meta"`
+}
+
+type HistoryResponse struct {
+	Start   string         `json:"start"`
+	End     string         `json:"end"`
+	Days    []HistoryDay   `json:"days"`
+	Summary HistorySummary `json:"summary"`
+	Meta    ResponseMeta   `json:"meta"`
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// --- Sistem satuan yang didukung lewat ?units= ---
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+)
+
+// --- Parse nilai ?units=, default ke metric kalau kosong ---
+func ParseUnitSystem(raw string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", UnitsMetric:
+		return UnitsMetric, nil
+	case UnitsImperial:
+		return UnitsImperial, nil
+	default:
+		return "", fmt.Errorf("unsupported units %q (use %q or %q)", raw, UnitsMetric, UnitsImperial)
+	}
+}
+
+// --- Konversi response (selalu dihitung dalam metric) ke sistem satuan yang diminta ---
+// Indeks dihitung sebelum konversi, jadi nilainya tidak berubah.
+func ApplyUnitSystem(resp ConsolidatedResponse, units string) ConsolidatedResponse {
+	resp.Meta.Units = units
+	if units != UnitsImperial {
+		return resp
+	}
+
+	resp.Weather.Temperature = Round1(celsiusToFahrenheit(resp.Weather.Temperature))
+	resp.Weather.Apparent = Round1(celsiusToFahrenheit(resp.Weather.Apparent))
+	resp.Weather.DewPoint = Round1(celsiusToFahrenheit(resp.Weather.DewPoint))
+	resp.Weather.Humidex = Round1(celsiusToFahrenheit(resp.Weather.Humidex))
+	resp.Weather.Precipitation = Round2(mmToInches(resp.Weather.Precipitation))
+	resp.Weather.WindSpeed = Round1(kmhToMph(resp.Weather.WindSpeed))
+	resp.Weather.WindGusts = Round1(kmhToMph(resp.Weather.WindGusts))
+	resp.Weather.Visibility = Round1(resp.Weather.Visibility / 1609.344)
+	resp.Weather.Elevation = math.Round(resp.Weather.Elevation * 3.28084)
+	if air := resp.Indices.AirSports; air != nil {
+		converted := *air
+		converted.WindSurface.Speed = Round1(kmhToMph(air.WindSurface.Speed))
+		converted.Wind850hPa.Speed = Round1(kmhToMph(air.Wind850hPa.Speed))
+		converted.CloudBase = math.Round(air.CloudBase * 3.28084)
+		resp.Indices.AirSports = &converted
+	}
+	if breakdown := resp.Indices.IndexBreakdown; breakdown != nil {
+		converted := slices.Clone(breakdown)
+		for i, f := range converted {
+			switch f.Factor {
+			case FactorHeat, FactorCold:
+				converted[i].Value = Round1(celsiusToFahrenheit(f.Value))
+				converted[i].Threshold = Round1(celsiusToFahrenheit(f.Threshold))
+			case FactorPrecipitation:
+				converted[i].Value = Round2(mmToInches(f.Value))
+				converted[i].Threshold = Round2(mmToInches(f.Threshold))
+			}
+		}
+		resp.Indices.IndexBreakdown = converted
+	}
+	resp.Weather.Pressure = Round2(hPaToInHg(resp.Weather.Pressure))
+	resp.Weather.SurfacePressure = Round2(hPaToInHg(resp.Weather.SurfacePressure))
+	if change := resp.Weather.PressureChange; change != nil {
+		converted := Round2(hPaToInHg(*change))
+		resp.Weather.PressureChange = &converted
+	}
+	return resp
+}
+
+func celsiusToFahrenheit(c float64) float64 { return c*9/5 + 32 }
+
+func mmToInches(mm float64) float64 { return mm / 25.4 }
+
+func kmhToMph(kmh float64) float64 { return kmh / 1.609344 }
+
+func hPaToInHg(hPa float64) float64 { return hPa / 33.8639 }
+
+// --- Pembulatan untuk angka yang ditampilkan ke client ---
+func Round1(v float64) float64 { return math.Round(v*10) / 10 }
+
+func Round2(v float64) float64 { return math.Round(v*100) / 100 }
@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+func TestComposeAlternative(t *testing.T) {
+	from := &mail.Address{Name: "TitikKondisi", Address: "noreply@titikkondisi.id"}
+	to := &mail.Address{Address: "user@example.com"}
+	data, err := compose(from, to, Email{Subject: "Laporan", Body: "Teks biasa\n", HTML: "<p>Versi HTML</p>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q (%v), want multipart/alternative", msg.Header.Get("Content-Type"), err)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	want := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", "Teks biasa\r\n"},
+		{"text/html; charset=utf-8", "<p>Versi HTML</p>"},
+	}
+	for _, w := range want {
+		part, err := reader.NextRawPart()
+		if err != nil {
+			t.Fatalf("part %s: %v", w.contentType, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != w.contentType {
+			t.Errorf("content type = %q, want %q", got, w.contentType)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != w.content {
+			t.Errorf("%s body = %q, want %q", w.contentType, body, w.content)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("extra part after html: %v", err)
+	}
+}
+
+func TestComposePlain(t *testing.T) {
+	from := &mail.Address{Address: "noreply@titikkondisi.id"}
+	to := &mail.Address{Address: "user@example.com"}
+	data, err := compose(from, to, Email{Subject: "Hujan", Body: "Mulai hujan\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msg.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("content type = %q, want text/plain", got)
+	}
+}
+
+func TestRenderHTMLWeeklyReport(t *testing.T) {
+	notification := model.SubscriptionNotification{
+		Event:          model.EventWeeklyReport,
+		SubscriptionID: "sub-1",
+		TriggeredAt:    time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC),
+		Timezone:       "Asia/Jakarta",
+		Report: []model.ReportLocation{{
+			Name: "Bukit <Bintang>",
+			Days: []model.DailyForecast{{Date: "2024-06-21", TemperatureMin: 22, TemperatureMax: 31, HikingIndex: 7.5}},
+			PastWeek: []model.ObservedDay{
+				{Date: "2024-06-14", TemperatureMin: 21.5, TemperatureMax: 30, WindGustsMax: 28, Rained: true, Observations: 12, HikingIndex: 6.2},
+			},
+		}},
+	}
+	html, err := RenderHTML(notification, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"from 2024-06-21", "Bukit &lt;Bintang&gt;", "Past week", "<td>2024-06-14</td>", "28 km/h", "sub-1"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("html does not contain %q", want)
+		}
+	}
+
+	_, body, err := Render(notification, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "Sepekan terakhir:\n- 2024-06-14: 21.5 sampai 30 °C, hembusan sampai 28 km/j, hujan, mendaki 6.2/10") {
+		t.Errorf("text body has no past week:\n%s", body)
+	}
+
+	notification.Event = model.EventDailySummary
+	if html, err := RenderHTML(notification, "en"); err != nil || html != "" {
+		t.Errorf("daily summary html = %q, %v; want none", html, err)
+	}
+}
@@ -0,0 +1,270 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Firebase Cloud Messaging (HTTP v1) untuk push ke aplikasi mobile ---
+// File service account kosong = kanal push mati.
+type FCMConfig struct {
+	// Kosong = project_id dari file service account
+	ProjectID string `yaml:"project_id"`
+	// JSON service account Firebase (Project settings > Service accounts)
+	CredentialsFile string `yaml:"credentials_file"`
+	// Hanya diganti untuk pengujian
+	BaseURL string `yaml:"base_url"`
+	// Batas waktu satu percobaan kirim, termasuk mengambil access token
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// Token perangkat sudah tidak berlaku (aplikasi dihapus, token diganti);
+// perangkatnya sebaiknya dilupakan, bukan dicoba lagi
+var ErrUnregistered = errors.New("fcm device token is no longer registered")
+
+func DefaultFCMConfig() FCMConfig {
+	return FCMConfig{BaseURL: "https://fcm.googleapis.com", Timeout: 10 * time.Second}
+}
+
+func (c FCMConfig) Enabled() bool { return c.CredentialsFile != "" }
+
+// File service account dibaca di sini supaya salah path atau isi ketahuan
+// saat start/reload, bukan saat notifikasi pertama
+func (c FCMConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	if u, err := url.Parse(c.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("base_url must be an absolute URL, got %q", c.BaseURL)
+	}
+	account, err := loadServiceAccount(c.CredentialsFile)
+	if err != nil {
+		return err
+	}
+	if c.ProjectID == "" && account.ProjectID == "" {
+		return errors.New("project_id is required when the credentials file has none")
+	}
+	return nil
+}
+
+// --- Isi satu push; Data ikut ke aplikasi sebagai pasangan string ---
+type Push struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+type serviceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+
+	key *rsa.PrivateKey
+}
+
+func loadServiceAccount(path string) (*serviceAccount, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read credentials_file: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("credentials_file is not a service account JSON: %w", err)
+	}
+	if account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("credentials_file has no client_email or token_uri")
+	}
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, errors.New("credentials_file has no PEM private_key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if err != nil || !ok {
+		return nil, errors.New("credentials_file private_key must be an RSA key")
+	}
+	account.key = key
+	return &account, nil
+}
+
+// --- Client FCM; access token OAuth disimpan sampai hampir kedaluwarsa ---
+type FCM struct {
+	client *http.Client
+
+	mu sync.Mutex
+	// Service account & token untuk file yang sedang dipakai; dibaca ulang
+	// kalau path-nya berubah saat reload
+	file        string
+	account     *serviceAccount
+	accessToken string
+	expires     time.Time
+}
+
+func NewFCM(client *http.Client) *FCM {
+	return &FCM{client: client}
+}
+
+// --- Kirim satu push ke satu token perangkat; batas waktunya dari ctx ---
+func (f *FCM) Send(ctx context.Context, cfg FCMConfig, device string, msg Push) error {
+	account, token, err := f.token(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	project := cfg.ProjectID
+	if project == "" {
+		project = account.ProjectID
+	}
+	type notification struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	body, err := json.Marshal(map[string]any{"message": map[string]any{
+		"token":        device,
+		"notification": notification{Title: msg.Title, Body: msg.Body},
+		"data":         msg.Data,
+		"android":      map[string]string{"priority": "high"},
+	}})
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimRight(cfg.BaseURL, "/") + "/v1/projects/" + url.PathEscape(project) + "/messages:send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm send: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	return fcmError(resp)
+}
+
+// 404 UNREGISTERED (dan 400 untuk token yang formatnya salah) berarti
+// perangkatnya tidak bisa dikirimi lagi; status lain boleh dicoba ulang
+func fcmError(resp *http.Response) error {
+	var payload struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&payload)
+	for _, detail := range payload.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return fmt.Errorf("%w: %s", ErrUnregistered, payload.Error.Message)
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrUnregistered, payload.Error.Message)
+	}
+	if resp.StatusCode == http.StatusBadRequest && strings.Contains(payload.Error.Message, "registration token") {
+		return fmt.Errorf("%w: %s", ErrUnregistered, payload.Error.Message)
+	}
+	return fmt.Errorf("fcm answered %s: %s", resp.Status, payload.Error.Message)
+}
+
+// --- Access token OAuth dari JWT service account (grant jwt-bearer) ---
+func (f *FCM) token(ctx context.Context, cfg FCMConfig) (*serviceAccount, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file != cfg.CredentialsFile || f.account == nil {
+		account, err := loadServiceAccount(cfg.CredentialsFile)
+		if err != nil {
+			return nil, "", err
+		}
+		f.file, f.account, f.accessToken = cfg.CredentialsFile, account, ""
+	}
+	// Diperbarui semenit sebelum kedaluwarsa supaya tidak habis di tengah jalan
+	if f.accessToken != "" && time.Until(f.expires) > time.Minute {
+		return f.account, f.accessToken, nil
+	}
+
+	assertion, err := signJWT(f.account, time.Now())
+	if err != nil {
+		return nil, "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fcm access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fcm access token: token endpoint answered %s", resp.Status)
+	}
+	var granted struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&granted); err != nil || granted.AccessToken == "" {
+		return nil, "", errors.New("fcm access token: token endpoint returned no access_token")
+	}
+	f.accessToken = granted.AccessToken
+	f.expires = time.Now().Add(time.Duration(granted.ExpiresIn) * time.Second)
+	return f.account, f.accessToken, nil
+}
+
+func signJWT(account *serviceAccount, now time.Time) (string, error) {
+	segment := func(v any) string {
+		raw, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	unsigned := segment(map[string]string{"alg": "RS256", "typ": "JWT", "kid": account.PrivateKeyID}) + "." +
+		segment(map[string]any{
+			"iss":   account.ClientEmail,
+			"scope": fcmScope,
+			"aud":   account.TokenURI,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		})
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, account.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign fcm assertion: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
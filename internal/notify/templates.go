@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/i18n"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Template pesan per bahasa: templates/<lang>/<event>.tmpl ---
+// Tiap file mendefinisikan "subject" & "body" untuk email serta "push_title"
+// & "push_body" untuk push; bagian bersama email (cuaca saat ini,
+// peringatan, penutup) ada di common.tmpl bahasa yang sama.
+// Event yang juga punya <event>.html.tmpl (template "html") dikirim sebagai
+// email teks + HTML; file itu diparse dengan html/template supaya nama
+// lokasi & teks rekomendasi di-escape.
+//
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	templateLangs  = []string{i18n.LangID, i18n.LangEN}
+	templateEvents = []string{model.EventConditionTriggered, model.EventDailySummary, model.EventWeeklyReport}
+)
+
+// Format jam lokal di email; zona ditulis supaya tidak ambigu
+const timeLayout = "2006-01-02 15:04 MST"
+
+// lang -> event -> template; diparse sekali saat start, error di sini bug
+var (
+	templates     = loadTemplates()
+	htmlTemplates = loadHTMLTemplates()
+)
+
+var templateFuncs = map[string]any{
+	"num": formatNumber,
+	// Diganti per email dengan zona lokasi, lihat render
+	"localTime": func(t time.Time) string { return t.Format(timeLayout) },
+}
+
+func loadTemplates() map[string]map[string]*template.Template {
+	loaded := map[string]map[string]*template.Template{}
+	for _, lang := range templateLangs {
+		loaded[lang] = map[string]*template.Template{}
+		for _, event := range templateEvents {
+			loaded[lang][event] = template.Must(template.New(event).Funcs(templateFuncs).ParseFS(templateFS,
+				"templates/"+lang+"/common.tmpl", "templates/"+lang+"/"+event+".tmpl"))
+		}
+	}
+	return loaded
+}
+
+// Hanya event yang punya file HTML; yang lain tetap email teks saja
+func loadHTMLTemplates() map[string]map[string]*htmltemplate.Template {
+	loaded := map[string]map[string]*htmltemplate.Template{}
+	for _, lang := range templateLangs {
+		loaded[lang] = map[string]*htmltemplate.Template{}
+		for _, event := range templateEvents {
+			name := "templates/" + lang + "/" + event + ".html.tmpl"
+			if _, err := fs.Stat(templateFS, name); err != nil {
+				continue
+			}
+			loaded[lang][event] = htmltemplate.Must(htmltemplate.New(event).Funcs(templateFuncs).ParseFS(templateFS, name))
+		}
+	}
+	return loaded
+}
+
+// --- Data yang tersedia di template: isi notifikasi plus teks siap tampil ---
+type templateData struct {
+	model.SubscriptionNotification
+	// Nama tempat, atau koordinat kalau nama tidak diketahui
+	Place string
+	// Waktu notifikasi & tanggalnya di zona lokasi
+	Time string
+	Date string
+}
+
+// --- Subjek & isi email untuk satu notifikasi ---
+// Bahasa yang tidak punya template jatuh ke Bahasa Indonesia.
+func Render(notification model.SubscriptionNotification, lang string) (subject, body string, err error) {
+	parts, err := render(notification, lang, "subject", "body")
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimLeft(parts[1], "\n"), nil
+}
+
+// --- Versi HTML email; kosong kalau event ini hanya punya versi teks ---
+func RenderHTML(notification model.SubscriptionNotification, lang string) (string, error) {
+	byEvent, ok := htmlTemplates[lang]
+	if !ok {
+		byEvent = htmlTemplates[i18n.Default]
+	}
+	tmpl, ok := byEvent[notification.Event]
+	if !ok {
+		return "", nil
+	}
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	data, loc := newTemplateData(notification)
+	tmpl.Funcs(htmltemplate.FuncMap{"localTime": func(t time.Time) string { return t.In(loc).Format(timeLayout) }})
+	var buf strings.Builder
+	if err := tmpl.ExecuteTemplate(&buf, "html", data); err != nil {
+		return "", fmt.Errorf("render %s html: %w", notification.Event, err)
+	}
+	return buf.String(), nil
+}
+
+// --- Judul & isi singkat push untuk satu notifikasi ---
+func RenderPush(notification model.SubscriptionNotification, lang string) (Push, error) {
+	parts, err := render(notification, lang, "push_title", "push_body")
+	if err != nil {
+		return Push{}, err
+	}
+	return Push{Title: strings.TrimSpace(parts[0]), Body: strings.TrimSpace(parts[1])}, nil
+}
+
+// Jalankan beberapa template bernama dari file event yang sama
+func render(notification model.SubscriptionNotification, lang string, names ...string) ([]string, error) {
+	byEvent, ok := templates[lang]
+	if !ok {
+		byEvent = templates[i18n.Default]
+	}
+	tmpl, ok := byEvent[notification.Event]
+	if !ok {
+		return nil, fmt.Errorf("no template for event %q", notification.Event)
+	}
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	data, loc := newTemplateData(notification)
+	tmpl.Funcs(template.FuncMap{"localTime": func(t time.Time) string { return t.In(loc).Format(timeLayout) }})
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		var buf strings.Builder
+		if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, fmt.Errorf("render %s %s: %w", notification.Event, name, err)
+		}
+		parts[i] = buf.String()
+	}
+	return parts, nil
+}
+
+// Isi template beserta zona lokasi; zona yang tidak dikenal jadi UTC
+func newTemplateData(notification model.SubscriptionNotification) (templateData, *time.Location) {
+	loc, err := time.LoadLocation(notification.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := notification.TriggeredAt.In(loc)
+	return templateData{
+		SubscriptionNotification: notification,
+		Place:                    placeName(notification),
+		Time:                     local.Format(timeLayout),
+		Date:                     local.Format(time.DateOnly),
+	}, loc
+}
+
+func placeName(n model.SubscriptionNotification) string {
+	if p := n.Location; p != nil && p.Name != "" {
+		if p.Region != "" && p.Region != p.Name {
+			return p.Name + ", " + p.Region
+		}
+		return p.Name
+	}
+	return fmt.Sprintf("%.4f, %.4f", n.Latitude, n.Longitude)
+}
+
+// Satu angka desimal cukup untuk email; nilai bulat tanpa ".0"
+func formatNumber(v any) string {
+	switch n := v.(type) {
+	case *float64:
+		if n == nil {
+			return ""
+		}
+		v = *n
+	case int:
+		return strconv.Itoa(n)
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	return strconv.FormatFloat(math.Round(f*10)/10, 'f', -1, 64)
+}
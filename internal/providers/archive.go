@@ -0,0 +1,54 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- API Call ke Open-Meteo Historical Weather: agregat harian dari reanalisis ERA5 ---
+// Tanggal lokal lokasi, start & end ikut dihitung. ERA5 baru tersedia sekitar
+// 5 hari kemudian; hari yang belum ada datanya (nilainya null) dilewati.
+func (c *Client) HistoricalDaily(ctx context.Context, lat, lon float64, start, end time.Time) ([]model.HistoryDay, error) {
+	endpoint := c.opts.Config().Archive
+	archiveURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/archive?latitude=%s&longitude=%s&start_date=%s&end_date=%s&daily=weather_code,temperature_2m_min,temperature_2m_max,precipitation_sum,precipitation_hours,wind_speed_10m_max&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon), start.Format(time.DateOnly), end.Format(time.DateOnly),
+	), endpoint)
+
+	var result struct {
+		Daily struct {
+			Time               []string   `json:"time"`
+			WeatherCode        []*float64 `json:"weather_code"`
+			TemperatureMin     []*float64 `json:"temperature_2m_min"`
+			TemperatureMax     []*float64 `json:"temperature_2m_max"`
+			PrecipitationSum   []*float64 `json:"precipitation_sum"`
+			PrecipitationHours []*float64 `json:"precipitation_hours"`
+			WindSpeedMax       []*float64 `json:"wind_speed_10m_max"`
+		} `json:"daily"`
+	}
+	if err := c.getJSON(ctx, Archive, archiveURL, &result); err != nil {
+		return nil, err
+	}
+
+	series := result.Daily
+	days := make([]model.HistoryDay, 0, len(series.Time))
+	for i, date := range series.Time {
+		tempMax := valueAt(series.TemperatureMax, i)
+		if tempMax == nil {
+			continue
+		}
+		days = append(days, model.HistoryDay{
+			Date:               date,
+			WeatherCode:        int(valueOrZero(valueAt(series.WeatherCode, i))),
+			TemperatureMin:     valueOrZero(valueAt(series.TemperatureMin, i)),
+			TemperatureMax:     *tempMax,
+			PrecipitationSum:   valueOrZero(valueAt(series.PrecipitationSum, i)),
+			PrecipitationHours: valueOrZero(valueAt(series.PrecipitationHours, i)),
+			WindSpeedMax:       valueOrZero(valueAt(series.WindSpeedMax, i)),
+		})
+	}
+	return days, nil
+}
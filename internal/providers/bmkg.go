@@ -0,0 +1,223 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Wilayah layanan BMKG: kotak kasar di sekitar kepulauan Indonesia ---
+func InIndonesia(lat, lon float64) bool {
+	return lat >= -11.5 && lat <= 6.5 && lon >= 94.5 && lon <= 141.5
+}
+
+// Titik lebih jauh dari ini dari wilayah BMKG mana pun tidak dilayani BMKG
+const bmkgAreaRadiusKm = 15.0
+
+// --- Wilayah BMKG terdekat dari config untuk titik di Indonesia ---
+func (c *Client) BMKGArea(lat, lon float64) (BMKGArea, bool) {
+	if !InIndonesia(lat, lon) {
+		return BMKGArea{}, false
+	}
+	var nearest BMKGArea
+	best := math.Inf(1)
+	for _, area := range c.opts.Config().BMKGAreas {
+		if d := DistanceKm(lat, lon, area.Latitude, area.Longitude); d < best {
+			nearest, best = area, d
+		}
+	}
+	return nearest, best <= bmkgAreaRadiusKm
+}
+
+// --- Jarak lingkaran besar (haversine) antara dua titik, km ---
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	rad := math.Pi / 180
+	dLat, dLon := (lat2-lat1)*rad, (lon2-lon1)*rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// --- API Call ke BMKG: prakiraan 3 harian per 3 jam untuk satu wilayah adm4 ---
+// Curah hujan BMKG adalah total 3 jam; di sini dibagi rata per jam supaya
+// sebanding dengan curah hujan Open-Meteo.
+func (c *Client) BMKGForecast(ctx context.Context, code string) ([]model.BMKGForecastStep, error) {
+	endpoint := c.opts.Config().BMKG
+	forecastURL := withAPIKey(fmt.Sprintf("%s/publik/prakiraan-cuaca?adm4=%s", endpoint.BaseURL, url.QueryEscape(code)), endpoint)
+
+	var result struct {
+		Data []struct {
+			Cuaca [][]struct {
+				UTCDatetime   string  `json:"utc_datetime"`
+				Temperature   float64 `json:"t"`
+				Humidity      int     `json:"hu"`
+				CloudCover    int     `json:"tcc"`
+				Precipitation float64 `json:"tp"`
+				WeatherCode   int     `json:"weather"`
+				Description   string  `json:"weather_desc"`
+				WindSpeed     float64 `json:"ws"`
+				WindDirection int     `json:"wd_deg"`
+				Visibility    float64 `json:"vs"`
+			} `json:"cuaca"`
+		} `json:"data"`
+	}
+	if err := c.getJSON(ctx, BMKG, forecastURL, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Data) == 0 {
+		return nil, newDecodeError(BMKG, fmt.Errorf("no forecast for area %q", code))
+	}
+
+	var steps []model.BMKGForecastStep
+	for _, day := range result.Data[0].Cuaca {
+		for _, raw := range day {
+			t, err := time.ParseInLocation(time.DateTime, raw.UTCDatetime, time.UTC)
+			if err != nil {
+				return nil, newDecodeError(BMKG, fmt.Errorf("invalid utc_datetime %q", raw.UTCDatetime))
+			}
+			steps = append(steps, model.BMKGForecastStep{
+				Time:          t,
+				Temperature:   raw.Temperature,
+				Humidity:      raw.Humidity,
+				CloudCover:    raw.CloudCover,
+				Precipitation: model.Round1(raw.Precipitation / 3),
+				WeatherCode:   raw.WeatherCode,
+				Description:   raw.Description,
+				WindSpeed:     raw.WindSpeed,
+				WindDirection: raw.WindDirection,
+				Visibility:    raw.Visibility,
+			})
+		}
+	}
+	return steps, nil
+}
+
+// File CAP yang diambil bersamaan per refresh feed
+const nowcastConcurrency = 8
+
+// --- API Call ke BMKG Nowcast: peringatan dini cuaca aktif seluruh Indonesia ---
+// RSS hanya berisi daftar; waktu berlaku, tingkat dan poligon wilayah ada di
+// file CAP per peringatan. Link di RSS memakai host BMKG, jadi path-nya
+// dipasang ke base URL dari config (supaya mock/replay ikut).
+func (c *Client) NowcastWarnings(ctx context.Context) ([]model.AreaAlert, error) {
+	endpoint := c.opts.Config().BMKGNowcast
+	feedURL := withAPIKey(endpoint.BaseURL+"/alerts/nowcast/id/rss.xml", endpoint)
+
+	var feed struct {
+		Items []struct {
+			Link string `xml:"link"`
+		} `xml:"channel>item"`
+	}
+	if err := c.getXML(ctx, BMKGNowcast, feedURL, &feed); err != nil {
+		return nil, err
+	}
+
+	warnings := make([]model.AreaAlert, len(feed.Items))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(nowcastConcurrency)
+	for i, item := range feed.Items {
+		link, err := url.Parse(strings.TrimSpace(item.Link))
+		if err != nil {
+			return nil, newDecodeError(BMKGNowcast, fmt.Errorf("invalid alert link %q", item.Link))
+		}
+		g.Go(func() (err error) {
+			warnings[i], err = c.nowcastAlert(ctx, withAPIKey(endpoint.BaseURL+link.Path, endpoint))
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func (c *Client) nowcastAlert(ctx context.Context, capURL string) (model.AreaAlert, error) {
+	var alert struct {
+		Info struct {
+			Event       string `xml:"event"`
+			Severity    string `xml:"severity"`
+			Effective   string `xml:"effective"`
+			Expires     string `xml:"expires"`
+			Headline    string `xml:"headline"`
+			Description string `xml:"description"`
+			Areas       []struct {
+				Polygons []string `xml:"polygon"`
+			} `xml:"area"`
+		} `xml:"info"`
+	}
+	if err := c.getXML(ctx, BMKGNowcast, capURL, &alert); err != nil {
+		return model.AreaAlert{}, err
+	}
+
+	info := alert.Info
+	start, err := time.Parse(time.RFC3339, strings.TrimSpace(info.Effective))
+	if err != nil {
+		return model.AreaAlert{}, newDecodeError(BMKGNowcast, fmt.Errorf("invalid effective time %q", info.Effective))
+	}
+	end, err := time.Parse(time.RFC3339, strings.TrimSpace(info.Expires))
+	if err != nil {
+		return model.AreaAlert{}, newDecodeError(BMKGNowcast, fmt.Errorf("invalid expires time %q", info.Expires))
+	}
+	result := model.AreaAlert{Alert: model.Alert{
+		Type:        nowcastType(info.Event),
+		Severity:    strings.ToLower(strings.TrimSpace(info.Severity)),
+		Title:       strings.TrimSpace(info.Headline),
+		Description: strings.TrimSpace(info.Description),
+		Source:      "bmkg",
+		Start:       start,
+		End:         end,
+	}}
+	if result.Title == "" {
+		result.Title = strings.TrimSpace(info.Event)
+	}
+	for _, area := range info.Areas {
+		for _, raw := range area.Polygons {
+			polygon, err := parseCAPPolygon(raw)
+			if err != nil {
+				return model.AreaAlert{}, newDecodeError(BMKGNowcast, err)
+			}
+			result.Polygons = append(result.Polygons, polygon)
+		}
+	}
+	return result, nil
+}
+
+// Event BMKG berupa teks bebas, misalnya "Hujan Lebat disertai Petir dan
+// Angin Kencang"; yang paling berbahaya menentukan jenisnya.
+func nowcastType(event string) string {
+	event = strings.ToLower(event)
+	switch {
+	case strings.Contains(event, "petir") || strings.Contains(event, "kilat"):
+		return model.AlertThunderstorm
+	case strings.Contains(event, "hujan"):
+		return model.AlertHeavyRain
+	case strings.Contains(event, "angin"):
+		return model.AlertStrongWind
+	default:
+		return model.AlertOther
+	}
+}
+
+// Poligon CAP: pasangan "lat,lon" dipisah spasi
+func parseCAPPolygon(raw string) ([][2]float64, error) {
+	fields := strings.Fields(raw)
+	polygon := make([][2]float64, 0, len(fields))
+	for _, pair := range fields {
+		rawLat, rawLon, ok := strings.Cut(pair, ",")
+		lat, errLat := strconv.ParseFloat(rawLat, 64)
+		lon, errLon := strconv.ParseFloat(rawLon, 64)
+		if !ok || errLat != nil || errLon != nil {
+			return nil, fmt.Errorf("invalid polygon point %q", pair)
+		}
+		polygon = append(polygon, [2]float64{lat, lon})
+	}
+	return polygon, nil
+}
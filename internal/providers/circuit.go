@@ -0,0 +1,148 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// --- Status circuit breaker, juga dipakai di /status/providers & metrics ---
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// --- Kebijakan circuit breaker; bagian "upstream.circuit" di config ---
+// Setelah Failures kegagalan sementara berturut-turut circuit terbuka: semua
+// panggilan ke provider itu langsung gagal dengan KindCircuitOpen selama
+// Cooldown, lalu satu panggilan dibiarkan lewat sebagai percobaan. Berhasil
+// menutup circuit, gagal membukanya lagi. Failures 0 = tanpa circuit breaker.
+type CircuitPolicy struct {
+	Failures int           `yaml:"failures"`
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+func DefaultCircuitPolicy() CircuitPolicy {
+	return CircuitPolicy{Failures: 5, Cooldown: 30 * time.Second}
+}
+
+func (p CircuitPolicy) Validate() error {
+	if p.Failures < 0 {
+		return fmt.Errorf("failures must be >= 0, got %d", p.Failures)
+	}
+	if p.Failures > 0 && p.Cooldown <= 0 {
+		return fmt.Errorf("cooldown must be positive, got %s", p.Cooldown)
+	}
+	return nil
+}
+
+type CircuitState struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Failures int    `json:"consecutive_failures"`
+	// Kapan circuit terbuka & kapan percobaan berikutnya diizinkan
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+	RetryAt  *time.Time `json:"retry_at,omitempty"`
+}
+
+type circuit struct {
+	state    string
+	failures int
+	openedAt time.Time
+	retryAt  time.Time
+}
+
+// --- Circuit breaker semua provider, in-memory per instance ---
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	// Dipanggil (di luar lock) setiap kali status sebuah circuit berubah
+	onChange func(provider, from, to string)
+}
+
+func NewCircuitBreaker(onChange func(provider, from, to string)) *CircuitBreaker {
+	if onChange == nil {
+		onChange = func(string, string, string) {}
+	}
+	return &CircuitBreaker{circuits: map[string]*circuit{}, onChange: onChange}
+}
+
+func (b *CircuitBreaker) get(provider string) *circuit {
+	c, ok := b.circuits[provider]
+	if !ok {
+		c = &circuit{state: CircuitClosed}
+		b.circuits[provider] = c
+	}
+	return c
+}
+
+// Boleh memanggil provider sekarang? Setelah cooldown habis hanya satu
+// panggilan (half-open) yang lolos sampai hasilnya dicatat.
+func (b *CircuitBreaker) allow(provider string, policy CircuitPolicy) bool {
+	if policy.Failures == 0 {
+		return true
+	}
+	b.mu.Lock()
+	c := b.get(provider)
+	switch {
+	case c.state == CircuitClosed:
+		b.mu.Unlock()
+		return true
+	case c.state == CircuitOpen && !time.Now().Before(c.retryAt):
+		c.state = CircuitHalfOpen
+		b.mu.Unlock()
+		b.onChange(provider, CircuitOpen, CircuitHalfOpen)
+		return true
+	}
+	b.mu.Unlock()
+	return false
+}
+
+// Catat hasil panggilan yang diizinkan allow. Hanya kegagalan sementara
+// (Retryable) yang dihitung; 4xx & response rusak berarti upstream masih hidup.
+func (b *CircuitBreaker) record(provider string, policy CircuitPolicy, err error) {
+	if policy.Failures == 0 {
+		return
+	}
+	b.mu.Lock()
+	c := b.get(provider)
+	from := c.state
+	var providerErr *Error
+	errors.As(err, &providerErr)
+	switch {
+	case providerErr != nil && providerErr.Kind == KindCanceled:
+		// Pemanggil berhenti menunggu; percobaan half-open diulang panggilan berikutnya
+		if c.state == CircuitHalfOpen {
+			c.state = CircuitOpen
+		}
+	case providerErr != nil && providerErr.Retryable():
+		c.failures++
+		if c.state == CircuitHalfOpen || c.failures >= policy.Failures {
+			now := time.Now()
+			c.state, c.openedAt, c.retryAt = CircuitOpen, now, now.Add(policy.Cooldown)
+		}
+	default:
+		c.state, c.failures = CircuitClosed, 0
+	}
+	to := c.state
+	b.mu.Unlock()
+	if from != to {
+		b.onChange(provider, from, to)
+	}
+}
+
+func (b *CircuitBreaker) State(provider string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := CircuitState{Provider: provider, State: CircuitClosed}
+	if c, ok := b.circuits[provider]; ok {
+		state.State, state.Failures = c.state, c.failures
+		if c.state != CircuitClosed {
+			openedAt, retryAt := c.openedAt.UTC(), c.retryAt.UTC()
+			state.OpenedAt, state.RetryAt = &openedAt, &retryAt
+		}
+	}
+	return state
+}
@@ -0,0 +1,337 @@
+// Package providers membungkus semua panggilan ke API eksternal (Open-Meteo,
+// sunrise-sunset.org): URL, decode, error per provider, kill switch, serta
+// mode mock/record/replay.
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// --- Mode provider ---
+// live (default), mock untuk dev frontend / demo offline, record/replay untuk
+// fixture (lihat fixtures.go).
+const (
+	ModeLive   = "live"
+	ModeMock   = "mock"
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// --- Konfigurasi provider, bagian "providers" di file config ---
+type Config struct {
+	// live (default), mock (data kalengan tanpa network), record atau replay
+	Mode string `yaml:"mode"`
+	// Folder fixture untuk mode record/replay
+	FixturesDir string `yaml:"fixtures_dir"`
+
+	OpenMeteo     Endpoint `yaml:"open_meteo"`
+	AirQuality    Endpoint `yaml:"air_quality"`
+	SunriseSunset Endpoint `yaml:"sunrise_sunset"`
+	Geocoding     Endpoint `yaml:"geocoding"`
+	// Reverse geocoding (koordinat -> nama tempat) lewat Nominatim/OpenStreetMap
+	ReverseGeocoding Endpoint `yaml:"reverse_geocoding"`
+	// Suhu permukaan laut untuk indeks pantai
+	Marine Endpoint `yaml:"marine"`
+	// Cuaca harian masa lalu (reanalisis ERA5) untuk /history
+	Archive Endpoint `yaml:"archive"`
+	// Indeks Kp dari NOAA Space Weather Prediction Center untuk indeks drone
+	SpaceWeather Endpoint `yaml:"space_weather"`
+	// Peringatan dini cuaca BMKG (nowcast, format CAP) untuk wilayah Indonesia
+	BMKGNowcast Endpoint `yaml:"bmkg_nowcast"`
+	// Prakiraan cuaca publik BMKG per desa/kelurahan
+	BMKG Endpoint `yaml:"bmkg"`
+	// API BMKG dicari per kode wilayah (adm4), bukan koordinat; titik dilayani
+	// wilayah terdekat dari daftar ini
+	BMKGAreas []BMKGArea `yaml:"bmkg_areas"`
+	// Gempa terkini & dirasakan dari BMKG (data.bmkg.go.id)
+	BMKGQuakes Endpoint `yaml:"bmkg_quakes"`
+	// Katalog gempa USGS (FDSN event service), seluruh dunia
+	USGS Endpoint `yaml:"usgs"`
+	// Tingkat aktivitas gunung api dari MAGMA Indonesia (PVMBG)
+	MAGMA Endpoint `yaml:"magma"`
+	// Kecerahan langit buatan (World Atlas 2015) dari lightpollutionmap.info;
+	// wajib API key, tanpa key indeks stargazing dihitung tanpa polusi cahaya
+	LightPollution Endpoint `yaml:"light_pollution"`
+}
+
+// --- Satu wilayah adm4 (kode Kemendagri, misal 31.71.03.1001) dan titik pusatnya ---
+type BMKGArea struct {
+	Code      string  `yaml:"code"`
+	Name      string  `yaml:"name"`
+	Latitude  float64 `yaml:"latitude"`
+	Longitude float64 `yaml:"longitude"`
+}
+
+type Endpoint struct {
+	BaseURL string `yaml:"base_url"`
+	APIKey  string `yaml:"api_key"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Mode:             ModeLive,
+		FixturesDir:      "testdata/fixtures",
+		OpenMeteo:        Endpoint{BaseURL: "https://api.open-meteo.com"},
+		AirQuality:       Endpoint{BaseURL: "https://air-quality-api.open-meteo.com"},
+		SunriseSunset:    Endpoint{BaseURL: "https://api.sunrise-sunset.org"},
+		Geocoding:        Endpoint{BaseURL: "https://geocoding-api.open-meteo.com"},
+		ReverseGeocoding: Endpoint{BaseURL: "https://nominatim.openstreetmap.org"},
+		Marine:           Endpoint{BaseURL: "https://marine-api.open-meteo.com"},
+		Archive:          Endpoint{BaseURL: "https://archive-api.open-meteo.com"},
+		SpaceWeather:     Endpoint{BaseURL: "https://services.swpc.noaa.gov"},
+		BMKGNowcast:      Endpoint{BaseURL: "https://www.bmkg.go.id"},
+		BMKG:             Endpoint{BaseURL: "https://api.bmkg.go.id"},
+		BMKGQuakes:       Endpoint{BaseURL: "https://data.bmkg.go.id"},
+		USGS:             Endpoint{BaseURL: "https://earthquake.usgs.gov"},
+		MAGMA:            Endpoint{BaseURL: "https://magma.esdm.go.id"},
+		LightPollution:   Endpoint{BaseURL: "https://www.lightpollutionmap.info"},
+	}
+}
+
+func (c Config) endpoint(provider string) Endpoint {
+	switch provider {
+	case OpenMeteo:
+		return c.OpenMeteo
+	case AirQuality:
+		return c.AirQuality
+	case Geocoding:
+		return c.Geocoding
+	case Nominatim:
+		return c.ReverseGeocoding
+	case Marine:
+		return c.Marine
+	case Archive:
+		return c.Archive
+	case SpaceWeather:
+		return c.SpaceWeather
+	case BMKGNowcast:
+		return c.BMKGNowcast
+	case BMKG:
+		return c.BMKG
+	case BMKGQuakes:
+		return c.BMKGQuakes
+	case USGS:
+		return c.USGS
+	case MAGMA:
+		return c.MAGMA
+	case LightPollution:
+		return c.LightPollution
+	default:
+		return c.SunriseSunset
+	}
+}
+
+// Nominatim mewajibkan User-Agent yang mengidentifikasi aplikasi
+const userAgent = "TitikKondisi-Backend (+https://github.com/AntonTian/TitikKondisi-Backend)"
+
+// --- Dependency Client; Config & Timeout berupa getter supaya ikut hot reload ---
+type Options struct {
+	// Client untuk mode live; mock/record/replay memakai transport sendiri
+	HTTPClient *http.Client
+	Config     func() Config
+	Timeout    func() time.Duration
+	KillSwitch *KillSwitch
+
+	// Retry untuk error sementara; nil = tanpa retry
+	Retry func() RetryPolicy
+	// Circuit breaker per provider; Circuit nil = tanpa circuit breaker
+	Circuits *CircuitBreaker
+	Circuit  func() CircuitPolicy
+
+	// Dipanggil setelah setiap percobaan ke upstream (metrics, trace, status)
+	Observe func(ctx context.Context, provider string, start time.Time, err error)
+	// Dipanggil sebelum menunggu untuk retry; attempt = percobaan yang baru gagal
+	Retried func(ctx context.Context, provider string, attempt int, err error)
+}
+
+type Client struct {
+	opts Options
+}
+
+// Batas tahap koneksi; total per panggilan tetap diatur Options.Timeout
+const (
+	dialTimeout           = 5 * time.Second
+	tlsHandshakeTimeout   = 5 * time.Second
+	responseHeaderTimeout = 10 * time.Second
+)
+
+// Pool koneksi. Satu request /weather membuka belasan panggilan paralel ke
+// segelintir host (terutama api.open-meteo.com), jadi batas bawaan 2 koneksi
+// idle per host membuat sebagian besar koneksi ditutup lalu di-handshake ulang.
+const (
+	maxIdleConns        = 200
+	maxIdleConnsPerHost = 32
+	idleConnTimeout     = 90 * time.Second
+	keepAliveInterval   = 30 * time.Second
+)
+
+// --- Client HTTP bersama untuk semua provider, pengganti http.DefaultClient ---
+// DefaultClient tidak punya batas waktu sama sekali; di sini tiap tahap
+// koneksi dibatasi sehingga upstream yang tidak menjawab cepat ketahuan.
+// Koneksi dipakai ulang antar request, dan HTTP/2 dipakai kalau upstream
+// mendukungnya sehingga panggilan paralel ke host yang sama berbagi satu koneksi.
+func NewHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: keepAliveInterval}).DialContext
+	transport.TLSHandshakeTimeout = tlsHandshakeTimeout
+	transport.ResponseHeaderTimeout = responseHeaderTimeout
+	transport.MaxIdleConns = maxIdleConns
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.IdleConnTimeout = idleConnTimeout
+	// DialContext kustom mematikan HTTP/2 otomatis kalau tidak diminta eksplisit
+	transport.ForceAttemptHTTP2 = true
+	return &http.Client{Transport: transport}
+}
+
+func New(opts Options) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = NewHTTPClient()
+	}
+	if opts.KillSwitch == nil {
+		opts.KillSwitch = NewKillSwitch()
+	}
+	if opts.Circuits == nil {
+		opts.Circuits = NewCircuitBreaker(nil)
+	}
+	if opts.Circuit == nil {
+		opts.Circuit = func() CircuitPolicy { return CircuitPolicy{} }
+	}
+	if opts.Observe == nil {
+		opts.Observe = func(context.Context, string, time.Time, error) {}
+	}
+	if opts.Retried == nil {
+		opts.Retried = func(context.Context, string, int, error) {}
+	}
+	return &Client{opts: opts}
+}
+
+func (c *Client) KillSwitch() *KillSwitch { return c.opts.KillSwitch }
+
+func (c *Client) Circuits() *CircuitBreaker { return c.opts.Circuits }
+
+// --- Client HTTP sesuai mode provider; mock dan replay tidak menyentuh network ---
+func (c *Client) httpClient(cfg Config) *http.Client {
+	switch cfg.Mode {
+	case ModeMock:
+		return mockClient
+	case ModeRecord:
+		base := c.opts.HTTPClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		return &http.Client{Transport: recordingTransport{dir: cfg.FixturesDir, base: base}}
+	case ModeReplay:
+		return &http.Client{Transport: replayTransport{dir: cfg.FixturesDir}}
+	default:
+		return c.opts.HTTPClient
+	}
+}
+
+// --- GET JSON dari upstream: cek status, decode, catat metrics & trace per provider ---
+func (c *Client) getJSON(ctx context.Context, provider, url string, out any) error {
+	return c.get(ctx, provider, url, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(out)
+	})
+}
+
+// --- GET XML (RSS, CAP) dari upstream, sama seperti getJSON ---
+func (c *Client) getXML(ctx context.Context, provider, url string, out any) error {
+	return c.get(ctx, provider, url, func(body io.Reader) error {
+		return xml.NewDecoder(body).Decode(out)
+	})
+}
+
+// Satu percobaan GET; retry ada di get (retry.go)
+func (c *Client) attempt(ctx context.Context, provider, url string, decode func(io.Reader) error) (err error) {
+	start := time.Now()
+	defer func() { c.opts.Observe(ctx, provider, start, err) }()
+
+	if c.opts.KillSwitch.Disabled(provider) {
+		return newDisabledError(provider)
+	}
+	// Provider yang sedang down tidak dibanjiri request; pemanggil langsung
+	// jatuh ke cache atau data parsial
+	policy := c.opts.Circuit()
+	if !c.opts.Circuits.allow(provider, policy) {
+		return newCircuitOpenError(provider)
+	}
+	defer func() { c.opts.Circuits.record(provider, policy, err) }()
+
+	// Timeout per panggilan di atas context request: upstream yang lambat
+	// tidak bisa menggantung handler, dan request yang dibatalkan client ikut
+	// membatalkan fetch ini.
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return newError(provider, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := c.httpClient(c.opts.Config()).Do(req)
+	if err != nil {
+		if parent.Err() != nil {
+			return newCanceledError(provider, parent.Err())
+		}
+		return newError(provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError(provider, resp.StatusCode)
+	}
+	if err := decode(resp.Body); err != nil {
+		if parent.Err() != nil {
+			return newCanceledError(provider, parent.Err())
+		}
+		if ctx.Err() != nil {
+			// Timeout saat body masih dibaca
+			return newError(provider, ctx.Err())
+		}
+		return newDecodeError(provider, err)
+	}
+	return nil
+}
+
+// --- Cek provider cukup sampai dapat response HTTP apa pun di bawah 500 ---
+// Base URL dibaca dari config aktif supaya ikut berubah setelah reload.
+func (c *Client) Probe(ctx context.Context, provider string) error {
+	if c.opts.KillSwitch.Disabled(provider) {
+		return newDisabledError(provider)
+	}
+	cfg := c.opts.Config()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.endpoint(provider).BaseURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient(cfg).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return newStatusError(provider, resp.StatusCode)
+	}
+	return nil
+}
+
+// --- Tambahkan apikey ke URL kalau provider memakai plan berbayar ---
+func withAPIKey(url string, e Endpoint) string {
+	if e.APIKey == "" {
+		return url
+	}
+	return url + "&apikey=" + e.APIKey
+}
+
+// --- Format koordinat untuk query string upstream ---
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,139 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// --- Nama provider upstream, dipakai di error, metrics & status ---
+const (
+	OpenMeteo     = "open-meteo"
+	AirQuality    = "open-meteo-air-quality"
+	SunriseSunset = "sunrise-sunset"
+	Geocoding     = "open-meteo-geocoding"
+	Nominatim     = "nominatim"
+	Marine        = "open-meteo-marine"
+	Archive       = "open-meteo-archive"
+	SpaceWeather  = "noaa-swpc"
+	BMKGNowcast   = "bmkg-nowcast"
+	BMKG          = "bmkg"
+	BMKGQuakes    = "bmkg-quakes"
+	USGS          = "usgs"
+	MAGMA         = "magma"
+	// lightpollutionmap.info, kecerahan langit World Atlas 2015
+	LightPollution = "light-pollution"
+)
+
+// Semua provider yang dikenal, urut untuk output admin/status
+var Names = []string{OpenMeteo, AirQuality, SunriseSunset, Geocoding, Nominatim, Marine, Archive, SpaceWeather, BMKGNowcast, BMKG, BMKGQuakes, USGS, MAGMA, LightPollution}
+
+// --- Jenis kegagalan upstream, juga dipakai sebagai label metrics ---
+const (
+	KindNetwork  = "network"
+	KindTimeout  = "timeout"
+	KindStatus   = "status"
+	KindDecode   = "decode"
+	KindDisabled = "disabled"
+	// Pemanggil sudah berhenti menunggu (client putus, deadline request habis,
+	// atau fetch lain dalam grup gagal); bukan kesalahan upstream
+	KindCanceled = "canceled"
+	// Circuit breaker terbuka setelah provider gagal berturut-turut
+	KindCircuitOpen = "circuit_open"
+)
+
+// --- Error dari upstream provider, membawa info retryable ---
+type Error struct {
+	Provider   string
+	Kind       string
+	StatusCode int
+	Err        error
+}
+
+func (e *Error) Error() string {
+	switch e.Kind {
+	case KindStatus:
+		return fmt.Sprintf("%s bad response: %d %s", e.Provider, e.StatusCode, http.StatusText(e.StatusCode))
+	case KindDisabled:
+		return fmt.Sprintf("%s is temporarily disabled", e.Provider)
+	case KindCanceled:
+		return fmt.Sprintf("%s call canceled: %v", e.Provider, e.Err)
+	case KindCircuitOpen:
+		return fmt.Sprintf("%s is failing, calls are paused until it recovers", e.Provider)
+	}
+	return fmt.Sprintf("%s %s error: %v", e.Provider, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// --- Network error, timeout, 429 dan 5xx layak di-retry; 4xx & response rusak tidak ---
+func (e *Error) Retryable() bool {
+	switch e.Kind {
+	case KindNetwork, KindTimeout:
+		return true
+	case KindStatus:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+// --- Kind dari error apa pun; error non-provider dianggap network ---
+func KindOf(err error) string {
+	var providerErr *Error
+	if errors.As(err, &providerErr) {
+		return providerErr.Kind
+	}
+	return KindNetwork
+}
+
+func newError(provider string, err error) *Error {
+	// URL request bisa memuat API key; jangan sampai ikut ke response/log
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+	kind := KindNetwork
+	var netErr interface{ Timeout() bool }
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		kind = KindTimeout
+	}
+	return &Error{Provider: provider, Kind: kind, Err: err}
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	q := u.Query()
+	for _, name := range fixtureRedactedParams {
+		if q.Has(name) {
+			q.Set(name, "REDACTED")
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func newStatusError(provider string, status int) *Error {
+	return &Error{Provider: provider, Kind: KindStatus, StatusCode: status}
+}
+
+func newDecodeError(provider string, err error) *Error {
+	return &Error{Provider: provider, Kind: KindDecode, Err: err}
+}
+
+func newDisabledError(provider string) *Error {
+	return &Error{Provider: provider, Kind: KindDisabled}
+}
+
+func newCircuitOpenError(provider string) *Error {
+	return &Error{Provider: provider, Kind: KindCircuitOpen}
+}
+
+// cause adalah ctx.Err() milik pemanggil: context.Canceled atau DeadlineExceeded
+func newCanceledError(provider string, cause error) *Error {
+	return &Error{Provider: provider, Kind: KindCanceled, Err: cause}
+}
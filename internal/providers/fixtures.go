@@ -0,0 +1,122 @@
+package providers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- Mode record/replay: rekam response upstream asli ke fixture, lalu putar ulang ---
+// record: panggil upstream seperti biasa dan simpan setiap response ke
+// FixturesDir. replay: jawab dari fixture saja, tanpa network; request yang
+// belum pernah direkam langsung gagal supaya tes tidak diam-diam lolos.
+// Parameter rahasia: tidak ikut kunci fixture, tidak ditulis ke disk, disamarkan di error
+var fixtureRedactedParams = []string{"apikey", "key"}
+
+type fixture struct {
+	Method   string          `json:"method"`
+	URL      string          `json:"url"`
+	Status   int             `json:"status"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"body_text,omitempty"`
+}
+
+// --- Lokasi file fixture: <dir>/<host>/<path>-<hash query>.json ---
+func fixturePath(dir string, req *http.Request) (string, string) {
+	u := *req.URL
+	q := u.Query()
+	for _, name := range fixtureRedactedParams {
+		q.Del(name)
+	}
+	u.RawQuery = q.Encode() // Encode mengurutkan key, jadi kunci stabil
+
+	name := strings.Trim(strings.ReplaceAll(u.Path, "/", "_"), "_")
+	if name == "" {
+		name = "root"
+	}
+	sum := sha256.Sum256([]byte(req.Method + " " + u.Path + "?" + u.RawQuery))
+	file := fmt.Sprintf("%s-%s.json", name, hex.EncodeToString(sum[:])[:12])
+	return filepath.Join(dir, u.Host, file), u.String()
+}
+
+// --- Transport perekam: teruskan ke base lalu tulis response ke fixture ---
+type recordingTransport struct {
+	dir  string
+	base http.RoundTripper
+}
+
+func (t recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	path, redactedURL := fixturePath(t.dir, req)
+	fx := fixture{Method: req.Method, URL: redactedURL, Status: resp.StatusCode}
+	if json.Valid(body) {
+		fx.Body = body
+	} else {
+		fx.BodyText = string(body)
+	}
+	if err := writeFixture(path, fx); err != nil {
+		return nil, fmt.Errorf("record fixture: %w", err)
+	}
+	return resp, nil
+}
+
+func writeFixture(path string, fx fixture) error {
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// --- Transport replay: hanya baca fixture ---
+type replayTransport struct {
+	dir string
+}
+
+func (t replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, redactedURL := fixturePath(t.dir, req)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture for %s %s (record it with PROVIDER_MODE=record): %w", req.Method, redactedURL, err)
+	}
+	var fx fixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return nil, fmt.Errorf("fixture %s is corrupt: %w", path, err)
+	}
+	body := []byte(fx.Body)
+	if fx.BodyText != "" {
+		body = []byte(fx.BodyText)
+	}
+	return &http.Response{
+		StatusCode:    fx.Status,
+		Status:        fmt.Sprintf("%d %s", fx.Status, http.StatusText(fx.Status)),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
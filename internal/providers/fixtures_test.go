@@ -0,0 +1,92 @@
+package providers_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// Response Jakarta (-6.2, 106.8) tanggal 21 Juni 2024 di testdata/fixtures;
+// rekam ulang dengan PROVIDER_MODE=record lalu sesuaikan angka di bawah.
+const fixtureLat, fixtureLon = -6.2, 106.8
+
+func newReplayClient(t *testing.T) *providers.Client {
+	t.Helper()
+	cfg := providers.DefaultConfig()
+	cfg.Mode = providers.ModeReplay
+	cfg.FixturesDir = "testdata/fixtures"
+	return providers.New(providers.Options{
+		Config:  func() providers.Config { return cfg },
+		Timeout: func() time.Duration { return time.Second },
+	})
+}
+
+func TestReplayCurrentWeather(t *testing.T) {
+	weather, err := newReplayClient(t).CurrentWeather(context.Background(), fixtureLat, fixtureLon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weather.Temperature != 31.4 || weather.Apparent != 36.2 || weather.Humidity != 66 {
+		t.Errorf("temperature, apparent, humidity = %v, %v, %v; want 31.4, 36.2, 66", weather.Temperature, weather.Apparent, weather.Humidity)
+	}
+	if weather.Pressure != 1009.8 || weather.Visibility != 24140 || weather.Elevation != 8 {
+		t.Errorf("pressure, visibility, elevation = %v, %v, %v; want 1009.8, 24140, 8", weather.Pressure, weather.Visibility, weather.Elevation)
+	}
+}
+
+func TestReplayAirQualityForecast(t *testing.T) {
+	hours, err := newReplayClient(t).AirQualityForecast(context.Background(), fixtureLat, fixtureLon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 48 jam, empat jam terakhir belum punya AQI
+	if len(hours) != 44 {
+		t.Fatalf("got %d hours, want 44", len(hours))
+	}
+	wib := time.FixedZone("", 7*3600)
+	noon := hours[12]
+	if want := time.Date(2024, 6, 21, 12, 0, 0, 0, wib); !noon.Time.Equal(want) {
+		t.Errorf("hour 12 time = %s, want %s", noon.Time, want)
+	}
+	if noon.AQI != 64 || noon.Pollutants.PM25 != 30.7 || noon.Pollutants.O3 != 75 {
+		t.Errorf("hour 12 = AQI %d, PM2.5 %v, O3 %v; want 64, 30.7, 75", noon.AQI, noon.Pollutants.PM25, noon.Pollutants.O3)
+	}
+	if want := time.Date(2024, 6, 22, 19, 0, 0, 0, wib); !hours[43].Time.Equal(want) {
+		t.Errorf("last hour = %s, want %s", hours[43].Time, want)
+	}
+	// Jakarta di luar domain serbuk sari CAMS Eropa
+	for _, hour := range hours {
+		if hour.Pollen != nil {
+			t.Fatalf("pollen at %s = %+v, want nil", hour.Time, hour.Pollen)
+		}
+	}
+}
+
+func TestReplaySunTimes(t *testing.T) {
+	sunrise, sunset, err := newReplayClient(t).SunTimes(context.Background(), fixtureLat, fixtureLon, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 6, 20, 23, 1, 42, 0, time.UTC); !sunrise.Equal(want) {
+		t.Errorf("sunrise = %s, want %s", sunrise, want)
+	}
+	if want := time.Date(2024, 6, 21, 10, 47, 19, 0, time.UTC); !sunset.Equal(want) {
+		t.Errorf("sunset = %s, want %s", sunset, want)
+	}
+}
+
+// Request yang belum direkam gagal, bukan diam-diam ke network
+func TestReplayMissingFixture(t *testing.T) {
+	_, err := newReplayClient(t).CurrentWeather(context.Background(), 51.5, -0.12)
+	var providerErr *providers.Error
+	if !errors.As(err, &providerErr) || providerErr.Provider != providers.OpenMeteo {
+		t.Fatalf("err = %v, want an open-meteo provider error", err)
+	}
+	if !strings.Contains(err.Error(), "no fixture") {
+		t.Errorf("err = %v, want it to mention the missing fixture", err)
+	}
+}
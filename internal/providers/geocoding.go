@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- API Call ke Open-Meteo Geocoding: cari tempat berdasarkan nama ---
+// Hasil diurutkan upstream berdasarkan relevansi; kosong kalau tidak ketemu.
+func (c *Client) SearchPlace(ctx context.Context, name string) ([]model.Place, error) {
+	endpoint := c.opts.Config().Geocoding
+	searchURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/search?name=%s&count=5&language=id&format=json",
+		endpoint.BaseURL, url.QueryEscape(name),
+	), endpoint)
+
+	var result struct {
+		Results []struct {
+			Name      string  `json:"name"`
+			Admin1    string  `json:"admin1"`
+			Country   string  `json:"country"`
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"results"`
+	}
+	if err := c.getJSON(ctx, Geocoding, searchURL, &result); err != nil {
+		return nil, err
+	}
+
+	places := make([]model.Place, 0, len(result.Results))
+	for _, r := range result.Results {
+		places = append(places, model.Place{
+			Name: r.Name, Region: r.Admin1, Country: r.Country,
+			Latitude: r.Latitude, Longitude: r.Longitude,
+		})
+	}
+	return places, nil
+}
+
+// --- API Call ke Nominatim: tempat terdekat untuk satu koordinat ---
+// ok=false kalau tidak ada tempat yang bisa dilabeli (misal di tengah laut).
+func (c *Client) ReversePlace(ctx context.Context, lat, lon float64) (place model.Place, ok bool, err error) {
+	endpoint := c.opts.Config().ReverseGeocoding
+	reverseURL := withAPIKey(fmt.Sprintf(
+		"%s/reverse?lat=%s&lon=%s&format=jsonv2&zoom=14&accept-language=id",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var result struct {
+		Error   string `json:"error"`
+		Name    string `json:"name"`
+		Lat     string `json:"lat"`
+		Lon     string `json:"lon"`
+		Address struct {
+			Village      string `json:"village"`
+			Town         string `json:"town"`
+			City         string `json:"city"`
+			Municipality string `json:"municipality"`
+			County       string `json:"county"`
+			State        string `json:"state"`
+			Country      string `json:"country"`
+		} `json:"address"`
+	}
+	if err := c.getJSON(ctx, Nominatim, reverseURL, &result); err != nil {
+		return model.Place{}, false, err
+	}
+	if result.Error != "" {
+		return model.Place{}, false, nil
+	}
+
+	a := result.Address
+	place = model.Place{
+		Name:    cmp.Or(result.Name, a.Village, a.Town, a.City, a.Municipality, a.County, a.State),
+		Region:  a.State,
+		Country: a.Country,
+	}
+	// Koordinat objek yang ditemukan; kalau tidak terbaca pakai koordinat query
+	if place.Latitude, err = strconv.ParseFloat(result.Lat, 64); err != nil {
+		place.Latitude = lat
+	}
+	if place.Longitude, err = strconv.ParseFloat(result.Lon, 64); err != nil {
+		place.Longitude = lon
+	}
+	return place, place.Name != "", nil
+}
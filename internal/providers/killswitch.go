@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"sync"
+	"time"
+)
+
+// --- Kill switch per provider: matikan upstream yang bermasalah tanpa deploy ---
+// Selama dimatikan, Client langsung gagal dengan KindDisabled tanpa
+// menyentuh jaringan, sehingga pemanggil jatuh ke fallback masing-masing
+// (misalnya AQI dianggap tidak tersedia).
+type State struct {
+	Provider   string     `json:"provider"`
+	Disabled   bool       `json:"disabled"`
+	Reason     string     `json:"reason,omitempty"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+}
+
+type KillSwitch struct {
+	mu       sync.RWMutex
+	disabled map[string]State
+}
+
+func NewKillSwitch() *KillSwitch {
+	return &KillSwitch{disabled: map[string]State{}}
+}
+
+func (s *KillSwitch) Disabled(provider string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.disabled[provider]
+	return ok
+}
+
+func (s *KillSwitch) Disable(provider, reason string) State {
+	now := time.Now().UTC()
+	state := State{Provider: provider, Disabled: true, Reason: reason, DisabledAt: &now}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disabled[provider] = state
+	return state
+}
+
+func (s *KillSwitch) Enable(provider string) State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.disabled, provider)
+	return State{Provider: provider}
+}
+
+func (s *KillSwitch) State(provider string) State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if state, ok := s.disabled[provider]; ok {
+		return state
+	}
+	return State{Provider: provider}
+}
+
+func (s *KillSwitch) List() []State {
+	list := make([]State, 0, len(Names))
+	for _, provider := range Names {
+		list = append(list, s.State(provider))
+	}
+	return list
+}
+
+func IsKnown(name string) bool {
+	for _, provider := range Names {
+		if provider == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// lightpollutionmap.info tidak melayani request tanpa key; service
+// memperlakukannya sebagai data yang memang tidak tersedia, bukan gangguan.
+var ErrNoAPIKey = errors.New("api key not configured")
+
+// --- API Call ke lightpollutionmap.info: kecerahan langit buatan di satu titik ---
+// Nilai dari World Atlas 2015 (Falchi dkk.) dalam mcd/m², di luar langit
+// alami. Response-nya satu angka polos, jadi bisa di-decode sebagai JSON.
+func (c *Client) SkyBrightness(ctx context.Context, lat, lon float64) (float64, error) {
+	cfg := c.opts.Config()
+	endpoint := cfg.LightPollution
+	if endpoint.APIKey == "" && cfg.Mode == ModeLive {
+		return 0, ErrNoAPIKey
+	}
+	queryURL := fmt.Sprintf("%s/QueryRaster/?ql=wa_2015&qt=point&qd=%s,%s&key=%s",
+		endpoint.BaseURL, formatCoordinate(lon), formatCoordinate(lat), url.QueryEscape(endpoint.APIKey))
+
+	var brightness float64
+	if err := c.getJSON(ctx, LightPollution, queryURL, &brightness); err != nil {
+		return 0, err
+	}
+	if brightness < 0 {
+		return 0, newDecodeError(LightPollution, fmt.Errorf("invalid sky brightness %v", brightness))
+	}
+	return brightness, nil
+}
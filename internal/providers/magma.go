@@ -0,0 +1,45 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- API Call ke MAGMA Indonesia: tingkat aktivitas semua gunung api ---
+// Satu daftar nasional; service memilih gunung terdekat dari titik.
+// Distance dan Status diisi service.
+func (c *Client) VolcanoStatuses(ctx context.Context) ([]model.Volcano, error) {
+	endpoint := c.opts.Config().MAGMA
+	statusURL := withAPIKey(endpoint.BaseURL+"/api/v1/gunung-api/status", endpoint)
+
+	var result struct {
+		Data []struct {
+			Code      string  `json:"ga_code"`
+			Name      string  `json:"ga_nama_gapi"`
+			Latitude  float64 `json:"ga_lat_gapi"`
+			Longitude float64 `json:"ga_lon_gapi"`
+			// 1 Normal .. 4 Awas
+			Status int `json:"ga_status"`
+		} `json:"data"`
+	}
+	if err := c.getJSON(ctx, MAGMA, statusURL, &result); err != nil {
+		return nil, err
+	}
+
+	volcanoes := make([]model.Volcano, 0, len(result.Data))
+	for _, raw := range result.Data {
+		if raw.Status < 1 || raw.Status > 4 {
+			return nil, newDecodeError(MAGMA, fmt.Errorf("invalid status %d for %s", raw.Status, raw.Code))
+		}
+		volcanoes = append(volcanoes, model.Volcano{
+			Name:      raw.Name,
+			Code:      raw.Code,
+			Level:     raw.Status,
+			Latitude:  raw.Latitude,
+			Longitude: raw.Longitude,
+		})
+	}
+	return volcanoes, nil
+}
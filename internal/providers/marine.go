@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- API Call ke Open-Meteo Marine: suhu permukaan laut saat ini ---
+// Titik di darat tidak punya data laut; hasilnya nil, bukan error.
+func (c *Client) SeaSurfaceTemperature(ctx context.Context, lat, lon float64) (*float64, error) {
+	endpoint := c.opts.Config().Marine
+	marineURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/marine?latitude=%s&longitude=%s&current=sea_surface_temperature&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var result struct {
+		Current struct {
+			SeaSurfaceTemperature *float64 `json:"sea_surface_temperature"`
+		} `json:"current"`
+	}
+	if err := c.getJSON(ctx, Marine, marineURL, &result); err != nil {
+		return nil, err
+	}
+	return result.Current.SeaSurfaceTemperature, nil
+}
+
+// --- API Call ke Open-Meteo Marine: muka air & gelombang per jam, 3 hari ---
+// Muka air (sea_level_height_msl) sudah termasuk pasang surut dari model
+// global; resolusinya kasar di teluk & selat sempit. Jam tanpa muka air
+// (titik di darat) dilewati, jadi hasilnya bisa kosong.
+func (c *Client) MarineForecast(ctx context.Context, lat, lon float64) ([]model.MarineHour, error) {
+	endpoint := c.opts.Config().Marine
+	marineURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/marine?latitude=%s&longitude=%s&hourly=sea_level_height_msl,wave_height,wave_period,wave_direction&forecast_days=3&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var result struct {
+		UTCOffsetSeconds int `json:"utc_offset_seconds"`
+		Hourly           struct {
+			Time          []string   `json:"time"`
+			SeaLevel      []*float64 `json:"sea_level_height_msl"`
+			WaveHeight    []*float64 `json:"wave_height"`
+			WavePeriod    []*float64 `json:"wave_period"`
+			WaveDirection []*float64 `json:"wave_direction"`
+		} `json:"hourly"`
+	}
+	if err := c.getJSON(ctx, Marine, marineURL, &result); err != nil {
+		return nil, err
+	}
+
+	series := result.Hourly
+	loc := time.FixedZone("", result.UTCOffsetSeconds)
+	hours := make([]model.MarineHour, 0, len(series.Time))
+	for i, raw := range series.Time {
+		t, err := time.ParseInLocation("2006-01-02T15:04", raw, loc)
+		if err != nil {
+			return nil, newDecodeError(Marine, fmt.Errorf("invalid hourly time %q", raw))
+		}
+		level := valueAt(series.SeaLevel, i)
+		if level == nil {
+			continue
+		}
+		hours = append(hours, model.MarineHour{
+			Time:          t,
+			SeaLevel:      *level,
+			WaveHeight:    valueOrZero(valueAt(series.WaveHeight, i)),
+			WavePeriod:    valueOrZero(valueAt(series.WavePeriod, i)),
+			WaveDirection: int(valueOrZero(valueAt(series.WaveDirection, i))),
+		})
+	}
+	return hours, nil
+}
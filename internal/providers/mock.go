@@ -0,0 +1,583 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+var mockClient = &http.Client{Transport: mockTransport{}}
+
+// --- Transport yang menjawab dengan data kalengan per endpoint provider ---
+// Nilainya deterministik dari koordinat (dan tanggal untuk jam matahari),
+// jadi lokasi yang sama selalu memberi hasil yang sama.
+type mockTransport struct{}
+
+func (mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	q := req.URL.Query()
+	lat, _ := strconv.ParseFloat(q.Get("latitude"), 64)
+	lon, _ := strconv.ParseFloat(q.Get("longitude"), 64)
+
+	var body any
+	switch req.URL.Path {
+	case "/v1/forecast":
+		body = mockForecast(lat, lon, q.Has("hourly"), q.Has("daily"))
+	case "/v1/air-quality":
+		body = mockAirQuality(lat, lon)
+	case "/v1/marine":
+		body = mockMarine(lat, lon, q.Has("hourly"))
+	case "/v1/archive":
+		start, _ := time.Parse(time.DateOnly, q.Get("start_date"))
+		end, _ := time.Parse(time.DateOnly, q.Get("end_date"))
+		body = mockArchive(lat, lon, start, end)
+	case "/json/planetary_k_index_1m.json":
+		body = mockKIndex(time.Now().UTC())
+	case "/v1/search":
+		body = mockSearch(q.Get("name"))
+	case "/reverse":
+		lat, _ = strconv.ParseFloat(q.Get("lat"), 64)
+		lon, _ = strconv.ParseFloat(q.Get("lon"), 64)
+		body = mockReverse(lat, lon)
+	case "/json":
+		lat, _ = strconv.ParseFloat(q.Get("lat"), 64)
+		lon, _ = strconv.ParseFloat(q.Get("lng"), 64)
+		date, err := time.Parse(time.DateOnly, q.Get("date"))
+		if err != nil {
+			date = time.Now().UTC()
+		}
+		body = mockSunriseSunset(lon, date)
+	case "/publik/prakiraan-cuaca":
+		body = mockBMKGForecast(q.Get("adm4"), time.Now().UTC())
+	case "/fdsnws/event/1/query":
+		lat, _ = strconv.ParseFloat(q.Get("latitude"), 64)
+		lon, _ = strconv.ParseFloat(q.Get("longitude"), 64)
+		body = mockUSGSQuakes(lat, lon, time.Now().UTC())
+	case "/DataMKG/TEWS/gempaterkini.json", "/DataMKG/TEWS/gempadirasakan.json":
+		body = mockBMKGQuakes(strings.HasSuffix(req.URL.Path, "dirasakan.json"), time.Now().UTC())
+	case "/api/v1/gunung-api/status":
+		body = map[string]any{"data": mockVolcanoes}
+	case "/QueryRaster/":
+		lonText, latText, _ := strings.Cut(q.Get("qd"), ",")
+		lat, _ = strconv.ParseFloat(latText, 64)
+		lon, _ = strconv.ParseFloat(lonText, 64)
+		body = mockSkyBrightness(lat, lon)
+	case "/alerts/nowcast/id/rss.xml":
+		body = mockNowcastFeed()
+	case "", "/":
+		// Probe readiness ke base URL
+		body = map[string]string{"status": "ok"}
+	default:
+		id, ok := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/alerts/nowcast/id/"), "_alert.xml")
+		if alert, found := mockNowcastAlerts[id]; ok && found {
+			body = mockNowcastCAP(id, alert, time.Now())
+			break
+		}
+		return mockResponse(req, http.StatusNotFound, map[string]string{"error": "no mock for " + req.URL.Path}), nil
+	}
+	return mockResponse(req, http.StatusOK, body), nil
+}
+
+// Body XML (RSS/CAP) dikirim apa adanya, selain itu di-encode sebagai JSON
+type mockXML string
+
+func mockResponse(req *http.Request, status int, body any) *http.Response {
+	contentType := "application/json"
+	data, _ := json.Marshal(body)
+	if raw, ok := body.(mockXML); ok {
+		contentType, data = "application/xml", []byte(raw)
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		Header:        http.Header{"Content-Type": []string{contentType}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
+
+// --- Angka semu stabil dalam [0,1) untuk koordinat + nama field ---
+func mockUnit(lat, lon float64, field string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(strconv.FormatFloat(lat, 'f', 2, 64) + "," + strconv.FormatFloat(lon, 'f', 2, 64) + ":" + field))
+	return float64(h.Sum32()%1000) / 1000
+}
+
+// --- Beberapa tempat populer; nama lain tidak ditemukan ---
+var mockPlaces = []map[string]any{
+	{"name": "Gunung Rinjani", "admin1": "Nusa Tenggara Barat", "country": "Indonesia", "latitude": -8.4117, "longitude": 116.4575},
+	{"name": "Gunung Merbabu", "admin1": "Jawa Tengah", "country": "Indonesia", "latitude": -7.455, "longitude": 110.44},
+	{"name": "Gunung Bromo", "admin1": "Jawa Timur", "country": "Indonesia", "latitude": -7.9425, "longitude": 112.953},
+	{"name": "Jakarta", "admin1": "DKI Jakarta", "country": "Indonesia", "latitude": -6.2146, "longitude": 106.8451},
+	{"name": "Yogyakarta", "admin1": "DI Yogyakarta", "country": "Indonesia", "latitude": -7.8014, "longitude": 110.3647},
+}
+
+func mockSearch(name string) map[string]any {
+	var results []map[string]any
+	for _, place := range mockPlaces {
+		if name != "" && strings.Contains(strings.ToLower(place["name"].(string)), strings.ToLower(name)) {
+			results = append(results, place)
+		}
+	}
+	// Seperti upstream: tanpa hasil, field results tidak ada
+	if len(results) == 0 {
+		return map[string]any{"generationtime_ms": 0.1}
+	}
+	return map[string]any{"results": results}
+}
+
+// --- Tempat terdekat dari daftar di atas, dalam format Nominatim ---
+func mockReverse(lat, lon float64) map[string]any {
+	nearest, best := mockPlaces[0], math.Inf(1)
+	for _, place := range mockPlaces {
+		d := math.Hypot(place["latitude"].(float64)-lat, place["longitude"].(float64)-lon)
+		if d < best {
+			nearest, best = place, d
+		}
+	}
+	return map[string]any{
+		"name": nearest["name"],
+		"lat":  strconv.FormatFloat(nearest["latitude"].(float64), 'f', -1, 64),
+		"lon":  strconv.FormatFloat(nearest["longitude"].(float64), 'f', -1, 64),
+		"address": map[string]any{
+			"state":   nearest["admin1"],
+			"country": nearest["country"],
+		},
+	}
+}
+
+func mockForecast(lat, lon float64, hourly, daily bool) any {
+	// Lebih dingin menjauhi khatulistiwa, dengan variasi kecil per lokasi
+	temp := 30 - math.Abs(lat)*0.4 + mockUnit(lat, lon, "temp")*4 - 2
+	rain := 0.0
+	if r := mockUnit(lat, lon, "rain"); r > 0.7 {
+		rain = model.Round1((r - 0.7) * 20)
+	}
+	humidity := 55 + int(mockUnit(lat, lon, "humidity")*40)
+	wind := model.Round1(mockUnit(lat, lon, "wind") * 35)
+	body := map[string]any{
+		"current": map[string]any{
+			"temperature_2m":       model.Round1(temp),
+			"apparent_temperature": mockApparent(temp, humidity, wind),
+			"relative_humidity_2m": humidity,
+			"dew_point_2m":         mockDewPoint(temp, humidity),
+			"precipitation":        rain,
+			"cloud_cover":          int(mockUnit(lat, lon, "cloud") * 100),
+			"uv_index":             model.Round1(mockUnit(lat, lon, "uv") * 11),
+			"wind_speed_10m":       wind,
+			"wind_gusts_10m":       model.Round1(wind * (1.3 + mockUnit(lat, lon, "gust")*0.5)),
+			"wind_direction_10m":   int(mockUnit(lat, lon, "wind_dir") * 360),
+			"visibility":           math.Round(24000 - rain*3000 - mockUnit(lat, lon, "haze")*10000),
+			"pressure_msl":         mockPressure(lat, lon, time.Now()),
+			// Turun ~12 hPa per 100 m; ketinggian semu 0-1500 m
+			"surface_pressure": model.Round1(mockPressure(lat, lon, time.Now()) - mockUnit(lat, lon, "elevation")*180),
+		},
+	}
+	body["elevation"] = math.Round(mockUnit(lat, lon, "elevation") * 1500)
+	zone, offset := mockTimezone(lon)
+	body["timezone"], body["utc_offset_seconds"] = zone, offset
+	loc := time.FixedZone(zone, offset)
+	if hourly {
+		body["hourly"] = mockHourly(lat, lon, temp, loc)
+	}
+	if daily {
+		body["daily"] = mockDaily(lat, lon, temp, loc)
+	}
+	return body
+}
+
+// --- Titik embun dari suhu & RH (Magnus), supaya konsisten dengan kelembapan ---
+func mockDewPoint(temp float64, humidity int) float64 {
+	gamma := math.Log(float64(humidity)/100) + 17.62*temp/(243.12+temp)
+	return model.Round1(243.12 * gamma / (17.62 - gamma))
+}
+
+// --- Suhu terasa ala Steadman (seperti Open-Meteo): lembap menambah, angin mengurangi ---
+func mockApparent(temp float64, humidity int, windKmh float64) float64 {
+	vapor := float64(humidity) / 100 * 6.105 * math.Exp(17.27*temp/(237.7+temp))
+	return model.Round1(temp + 0.33*vapor - 0.70*windKmh/3.6 - 4)
+}
+
+// --- Zona waktu kasar dari bujur: WIB/WITA/WIT di Indonesia, selain itu UTC ---
+func mockTimezone(lon float64) (string, int) {
+	switch {
+	case lon < 95 || lon > 141:
+		return "GMT", 0
+	case lon < 114.5:
+		return "Asia/Jakarta", 7 * 3600
+	case lon < 127:
+		return "Asia/Makassar", 8 * 3600
+	default:
+		return "Asia/Jayapura", 9 * 3600
+	}
+}
+
+// --- 7 hari mulai hari ini (waktu lokal), tiap hari sedikit berbeda ---
+func mockDaily(lat, lon, temp float64, loc *time.Location) map[string]any {
+	start := time.Now().In(loc)
+	const days = 7
+	dates := make([]string, days)
+	tempMin, tempMax := make([]float64, days), make([]float64, days)
+	rain, uv := make([]float64, days), make([]float64, days)
+	for d := range dates {
+		field := "day" + strconv.Itoa(d)
+		dates[d] = start.AddDate(0, 0, d).Format(time.DateOnly)
+		tempMax[d] = model.Round1(temp + 2 + mockUnit(lat, lon, field+"_max")*3)
+		tempMin[d] = model.Round1(temp - 5 - mockUnit(lat, lon, field+"_min")*3)
+		if r := mockUnit(lat, lon, field+"_rain"); r > 0.4 {
+			rain[d] = model.Round1((r - 0.4) * 30)
+		}
+		uv[d] = model.Round1(6 + mockUnit(lat, lon, field+"_uv")*6)
+	}
+	return map[string]any{
+		"time": dates, "temperature_2m_min": tempMin, "temperature_2m_max": tempMax,
+		"precipitation_sum": rain, "uv_index_max": uv,
+	}
+}
+
+// --- 3 hari mulai 00:00 hari ini (waktu lokal); hujan & UV naik-turun mengikuti siang/malam ---
+func mockHourly(lat, lon, temp float64, loc *time.Location) map[string]any {
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	base := mockUnit(lat, lon, "rain_chance") * 40
+	peakUV := 6 + mockUnit(lat, lon, "uv_peak")*6
+	const hours = 72
+	times := make([]string, hours)
+	temperature, wind := make([]float64, hours), make([]float64, hours)
+	dewPoint, wind850, cape := make([]float64, hours), make([]float64, hours), make([]float64, hours)
+	windDir, windDir850, cloudLow := make([]int, hours), make([]int, hours), make([]int, hours)
+	rainChance := make([]int, hours)
+	uv := make([]float64, hours)
+	pressure, gusts := make([]float64, hours), make([]float64, hours)
+	weatherCode := make([]int, hours)
+	for h := range times {
+		t := start.Add(time.Duration(h) * time.Hour)
+		times[h] = t.Format("2006-01-02T15:04")
+		// Terdingin menjelang subuh, terpanas sekitar jam 14
+		temperature[h] = model.Round1(temp + 4*math.Sin(float64(t.Hour()-8)/24*2*math.Pi))
+		wind[h] = model.Round1(mockUnit(lat, lon, "wind") * 35 * (0.6 + 0.4*math.Max(0, math.Sin(float64(t.Hour()-8)/24*2*math.Pi))))
+		// Udara paling mendekati jenuh menjelang subuh (peluang kabut)
+		dewPoint[h] = model.Round1(temperature[h] - (1+mockUnit(lat, lon, "dew")*10)*(0.4+0.6*math.Max(0, math.Sin(float64(t.Hour()-8)/24*2*math.Pi))))
+		windDir[h] = (int(mockUnit(lat, lon, "wind_dir")*360) + h*3) % 360
+		wind850[h] = model.Round1(wind[h]*0.8 + mockUnit(lat, lon, "wind_850")*25)
+		windDir850[h] = (windDir[h] + 20) % 360
+		cloudLow[h] = int(mockUnit(lat, lon, "cloud_low") * 100)
+		// Termal (CAPE) tumbuh siang hari
+		cape[h] = math.Round(math.Max(0, math.Sin(float64(t.Hour()-8)/16*math.Pi)) * mockUnit(lat, lon, "cape") * 1800)
+		// Hujan tropis cenderung sore hari
+		afternoon := math.Max(0, math.Sin(float64(t.Hour()-6)/24*2*math.Pi))
+		rainChance[h] = int(math.Min(100, base+afternoon*30))
+		// UV puncak tengah hari, nol di malam hari
+		daylight := math.Max(0, math.Sin(float64(t.Hour()-6)/12*math.Pi))
+		uv[h] = model.Round1(peakUV * daylight)
+		pressure[h] = mockPressure(lat, lon, t)
+		gusts[h] = model.Round1(wind[h] * (1.3 + mockUnit(lat, lon, "gust")*0.8))
+		// Kode cuaca WMO: badai petir saat peluang hujan & CAPE tinggi
+		switch {
+		case rainChance[h] >= 55 && cape[h] > 600:
+			weatherCode[h] = 95
+		case rainChance[h] >= 50:
+			weatherCode[h] = 63
+		case rainChance[h] >= 30:
+			weatherCode[h] = 61
+		case cloudLow[h] > 60:
+			weatherCode[h] = 3
+		default:
+			weatherCode[h] = 1
+		}
+	}
+	return map[string]any{
+		"time": times, "temperature_2m": temperature, "precipitation_probability": rainChance,
+		"uv_index": uv, "pressure_msl": pressure, "wind_speed_10m": wind,
+		"dew_point_2m": dewPoint, "wind_direction_10m": windDir, "wind_speed_850hPa": wind850,
+		"wind_direction_850hPa": windDir850, "cloud_cover_low": cloudLow, "cape": cape,
+		"weather_code": weatherCode, "wind_gusts_10m": gusts,
+	}
+}
+
+// --- Tekanan permukaan laut: pasang atmosfer 2x sehari plus tren lambat beberapa hari ---
+func mockPressure(lat, lon float64, t time.Time) float64 {
+	base := 1006 + mockUnit(lat, lon, "pressure")*10
+	tide := 1.5 * math.Cos(float64(t.UTC().Hour()-3)/12*2*math.Pi)
+	trend := 4 * math.Sin(float64(t.Unix())/(3*86400)*2*math.Pi+mockUnit(lat, lon, "pressure_phase")*6)
+	return model.Round1(base + tide + trend)
+}
+
+// --- 2 hari per jam mulai 00:00 lokal; polusi memuncak saat jam sibuk pagi & sore ---
+// Serbuk sari hanya di "Eropa" (lintang 35-72, bujur -25-45) seperti CAMS.
+func mockAirQuality(lat, lon float64) any {
+	zone, offset := mockTimezone(lon)
+	loc := time.FixedZone(zone, offset)
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	europe := lat >= 35 && lat <= 72 && lon >= -25 && lon <= 45
+	pollenKinds := []string{"alder", "birch", "grass", "mugwort", "olive", "ragweed"}
+
+	const hours = 48
+	times := make([]string, hours)
+	aqi := make([]int, hours)
+	pollutants := map[string][]float64{}
+	pollen := map[string][]any{}
+	for h := range times {
+		t := start.Add(time.Duration(h) * time.Hour)
+		times[h] = t.Format("2006-01-02T15:04")
+		rush := math.Max(0, math.Cos(float64(t.Hour()-8)/12*2*math.Pi))
+		// Konsentrasi ikut naik-turun bersama AQI supaya tetap masuk akal
+		level := mockUnit(lat, lon, "aqi") * (0.7 + 0.3*rush)
+		aqi[h] = 10 + int(level*60)
+		pollutants["pm2_5"] = append(pollutants["pm2_5"], model.Round1(3+level*45*(0.6+0.4*mockUnit(lat, lon, "pm2_5"))))
+		pollutants["pm10"] = append(pollutants["pm10"], model.Round1(6+level*70*(0.6+0.4*mockUnit(lat, lon, "pm10"))))
+		pollutants["ozone"] = append(pollutants["ozone"], model.Round1(20+level*100*mockUnit(lat, lon, "ozone")))
+		pollutants["nitrogen_dioxide"] = append(pollutants["nitrogen_dioxide"], model.Round1(2+level*60*mockUnit(lat, lon, "no2")))
+		pollutants["sulphur_dioxide"] = append(pollutants["sulphur_dioxide"], model.Round1(1+level*40*mockUnit(lat, lon, "so2")))
+		pollutants["carbon_monoxide"] = append(pollutants["carbon_monoxide"], math.Round(150+level*900*mockUnit(lat, lon, "co")))
+		for _, kind := range pollenKinds {
+			var value any
+			if europe {
+				value = model.Round1(math.Pow(mockUnit(lat, lon, kind+"_pollen"), 3) * 200)
+			}
+			pollen[kind] = append(pollen[kind], value)
+		}
+	}
+
+	hourly := map[string]any{"time": times, "european_aqi": aqi}
+	for name, values := range pollutants {
+		hourly[name] = values
+	}
+	for _, kind := range pollenKinds {
+		hourly[kind+"_pollen"] = pollen[kind]
+	}
+	return map[string]any{"timezone": zone, "utc_offset_seconds": offset, "hourly": hourly}
+}
+
+// --- Laut tropis 27-30 °C; mock tidak membedakan darat dan laut ---
+func mockMarine(lat, lon float64, hourly bool) any {
+	if !hourly {
+		return map[string]any{
+			"current": map[string]any{
+				"sea_surface_temperature": model.Round1(27 + mockUnit(lat, lon, "sea")*3),
+			},
+		}
+	}
+
+	// Pasang surut campuran: komponen M2 (12,42 jam) plus K1 (23,93 jam)
+	zone, offset := mockTimezone(lon)
+	loc := time.FixedZone(zone, offset)
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	phase := mockUnit(lat, lon, "tide") * 2 * math.Pi
+	amplitude := 0.4 + mockUnit(lat, lon, "tide_range")*0.8
+	waves := 0.3 + mockUnit(lat, lon, "waves")*2.5
+
+	const hours = 72
+	series := map[string][]any{}
+	for h := range hours {
+		t := start.Add(time.Duration(h) * time.Hour)
+		elapsed := t.Sub(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).Hours()
+		level := amplitude*math.Cos(2*math.Pi*elapsed/12.42+phase) + amplitude*0.4*math.Cos(2*math.Pi*elapsed/23.93+phase)
+		series["time"] = append(series["time"], t.Format("2006-01-02T15:04"))
+		series["sea_level_height_msl"] = append(series["sea_level_height_msl"], model.Round2(level))
+		series["wave_height"] = append(series["wave_height"], model.Round2(waves*(0.85+0.3*math.Sin(float64(h)/9))))
+		series["wave_period"] = append(series["wave_period"], model.Round1(5+mockUnit(lat, lon, "period")*10))
+		series["wave_direction"] = append(series["wave_direction"], int(mockUnit(lat, lon, "wave_dir")*360))
+	}
+	return map[string]any{"timezone": zone, "utc_offset_seconds": offset, "hourly": series}
+}
+
+// --- Riwayat harian: musim hujan November-Maret, kemarau Juni-September ---
+// Nilai per tanggal stabil, jadi rentang yang sama selalu memberi hasil sama.
+func mockArchive(lat, lon float64, start, end time.Time) any {
+	zone, _ := mockTimezone(lon)
+	base := 30 - math.Abs(lat)*0.4 - mockUnit(lat, lon, "elevation")*9
+	series := map[string][]any{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		field := day.Format(time.DateOnly)
+		// Puncak basah di Januari, paling kering di Agustus
+		wet := (1 + math.Cos(2*math.Pi*float64(day.YearDay()-15)/365)) / 2
+		rain := 0.0
+		if r := mockUnit(lat, lon, field+"_rain"); r < 0.2+wet*0.6 {
+			rain = model.Round1(r * (4 + wet*30))
+		}
+		tempMax := model.Round1(base + 3 - wet*2 + mockUnit(lat, lon, field+"_max")*3)
+		code := 1
+		switch {
+		case rain >= 10:
+			code = 63
+		case rain > 0:
+			code = 61
+		}
+		series["time"] = append(series["time"], field)
+		series["weather_code"] = append(series["weather_code"], code)
+		series["temperature_2m_min"] = append(series["temperature_2m_min"], model.Round1(tempMax-7-mockUnit(lat, lon, field+"_min")*3))
+		series["temperature_2m_max"] = append(series["temperature_2m_max"], tempMax)
+		series["precipitation_sum"] = append(series["precipitation_sum"], rain)
+		series["precipitation_hours"] = append(series["precipitation_hours"], math.Ceil(rain/2))
+		series["wind_speed_10m_max"] = append(series["wind_speed_10m_max"], model.Round1(8+mockUnit(lat, lon, field+"_wind")*25))
+	}
+	return map[string]any{"timezone": zone, "daily": series}
+}
+
+// --- Peringatan dini BMKG kalengan: selalu aktif 30 menit lalu sampai 2 jam lagi ---
+type mockNowcast struct {
+	Event, Severity, Area string
+	Polygon               string
+}
+
+var mockNowcastAlerts = map[string]mockNowcast{
+	"CJK00000000001": {"Hujan Lebat disertai Petir dan Angin Kencang", "Moderate", "Jakarta Selatan, Depok",
+		"-6.15,106.70 -6.15,106.95 -6.45,106.95 -6.45,106.70 -6.15,106.70"},
+	"CJT00000000002": {"Hujan Sedang-Lebat", "Minor", "Kab. Magelang, Kab. Boyolali",
+		"-7.35,110.30 -7.35,110.60 -7.65,110.60 -7.65,110.30 -7.35,110.30"},
+}
+
+func mockNowcastFeed() mockXML {
+	var items strings.Builder
+	for _, id := range []string{"CJK00000000001", "CJT00000000002"} {
+		alert := mockNowcastAlerts[id]
+		items.WriteString("<item><title>" + alert.Event + " di " + alert.Area + "</title>" +
+			"<link>https://www.bmkg.go.id/alerts/nowcast/id/" + id + "_alert.xml</link></item>")
+	}
+	return mockXML(`<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>Peringatan Dini Cuaca</title>` +
+		items.String() + `</channel></rss>`)
+}
+
+func mockNowcastCAP(id string, alert mockNowcast, now time.Time) mockXML {
+	wib := time.FixedZone("WIB", 7*3600)
+	issued := now.In(wib).Truncate(30 * time.Minute)
+	return mockXML(`<?xml version="1.0" encoding="UTF-8"?><alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">` +
+		`<identifier>` + id + `</identifier><info><event>` + alert.Event + `</event><urgency>Immediate</urgency>` +
+		`<severity>` + alert.Severity + `</severity><certainty>Likely</certainty>` +
+		`<effective>` + issued.Format(time.RFC3339) + `</effective><expires>` + issued.Add(150*time.Minute).Format(time.RFC3339) + `</expires>` +
+		`<headline>` + alert.Event + ` di ` + alert.Area + `</headline>` +
+		`<description>Waspada potensi ` + strings.ToLower(alert.Event) + ` yang dapat meluas di ` + alert.Area + `.</description>` +
+		`<area><areaDesc>` + alert.Area + `</areaDesc><polygon>` + alert.Polygon + `</polygon></area></info></alert>`)
+}
+
+// --- Kp semu per jam, kebanyakan tenang (0-4) dengan sesekali badai kecil ---
+func mockKIndex(now time.Time) any {
+	hour := now.Truncate(time.Hour)
+	kp := mockUnit(0, 0, hour.Format(time.RFC3339)) * 5.5
+	return []map[string]any{
+		{"time_tag": hour.Format("2006-01-02T15:04:05"), "kp_index": int(kp), "estimated_kp": model.Round2(kp)},
+	}
+}
+
+func mockSunriseSunset(lon float64, date time.Time) any {
+	// Matahari terbit ~06:00 dan terbenam ~18:00 waktu surya lokal pada tanggal itu
+	today := date.Truncate(24 * time.Hour)
+	offset := time.Duration(-lon / 15 * float64(time.Hour))
+	return map[string]any{
+		"results": map[string]any{
+			"sunrise": today.Add(6*time.Hour + offset).Format(time.RFC3339),
+			"sunset":  today.Add(18*time.Hour + offset).Format(time.RFC3339),
+		},
+		"status": "OK",
+	}
+}
+
+// --- Kecerahan langit buatan, mcd/m²: dari langit alami (~0,005) sampai pusat kota (~20) ---
+func mockSkyBrightness(lat, lon float64) float64 {
+	return math.Round(0.005*math.Pow(4000, mockUnit(lat, lon, "sky_brightness"))*1000) / 1000
+}
+
+// --- BMKG: prakiraan 3-jaman selama tiga hari untuk satu kode adm4 ---
+// Langkah pertama dimulai sebelum sekarang supaya selalu ada yang sedang berjalan.
+var mockBMKGWeather = []struct {
+	code int
+	desc string
+}{{0, "Cerah"}, {1, "Cerah Berawan"}, {3, "Berawan"}, {61, "Hujan Ringan"}, {95, "Hujan Petir"}}
+
+func mockBMKGForecast(code string, now time.Time) map[string]any {
+	start := now.Truncate(3 * time.Hour)
+	days := make([][]map[string]any, 3)
+	for i := range 24 {
+		at := start.Add(time.Duration(i) * 3 * time.Hour)
+		unit := func(field string) float64 { return mockUnit(0, 0, code+":"+field+":"+strconv.Itoa(i)) }
+		weather := mockBMKGWeather[int(unit("weather")*float64(len(mockBMKGWeather)))]
+		precipitation := 0.0
+		if weather.code >= 61 {
+			precipitation = math.Round(unit("tp")*150) / 10
+		}
+		days[i/8] = append(days[i/8], map[string]any{
+			"utc_datetime": at.Format(time.DateTime),
+			"t":            math.Round(23 + unit("t")*9),
+			"hu":           int(60 + unit("hu")*35),
+			"tcc":          int(unit("tcc") * 100),
+			"tp":           precipitation,
+			"weather":      weather.code,
+			"weather_desc": weather.desc,
+			"ws":           math.Round(unit("ws")*250) / 10,
+			"wd_deg":       int(unit("wd") * 360),
+			"vs":           math.Round(5000 + unit("vs")*5000),
+		})
+	}
+	return map[string]any{
+		"lokasi": map[string]string{"adm4": code},
+		"data":   []map[string]any{{"cuaca": days}},
+	}
+}
+
+// --- USGS: beberapa gempa dalam ~200 km dari titik selama seminggu terakhir ---
+// Waktunya dibulatkan ke jam supaya hasil sama selama satu jam.
+func mockUSGSQuakes(lat, lon float64, now time.Time) map[string]any {
+	base := now.Truncate(time.Hour)
+	count := int(mockUnit(lat, lon, "quakes") * 5)
+	features := make([]map[string]any, 0, count)
+	for i := range count {
+		unit := func(field string) float64 { return mockUnit(lat, lon, "quake"+strconv.Itoa(i)+":"+field) }
+		at := base.Add(-time.Duration(unit("age") * float64(7*24*time.Hour))).Truncate(time.Minute)
+		features = append(features, map[string]any{
+			"properties": map[string]any{
+				"mag":   model.Round1(2.5 + unit("mag")*3),
+				"place": "Mock region",
+				"time":  at.UnixMilli(),
+			},
+			"geometry": map[string]any{
+				"coordinates": []float64{lon + unit("lon")*3 - 1.5, lat + unit("lat")*3 - 1.5, model.Round1(5 + unit("depth")*145)},
+			},
+		})
+	}
+	return map[string]any{"type": "FeatureCollection", "features": features}
+}
+
+// --- BMKG: gempa terkini (M5+) dan yang dirasakan, di lokasi tetap ---
+// Gempa Magelang ada di kedua feed, seperti gempa kuat yang dirasakan.
+func mockBMKGQuakes(felt bool, now time.Time) map[string]any {
+	base := now.Truncate(time.Hour)
+	quake := func(age time.Duration, lat, lon float64, magnitude, depth, region, feltBy string) map[string]any {
+		return map[string]any{
+			"DateTime":    base.Add(-age).Format(time.RFC3339),
+			"Coordinates": strconv.FormatFloat(lat, 'f', 2, 64) + "," + strconv.FormatFloat(lon, 'f', 2, 64),
+			"Magnitude":   magnitude,
+			"Kedalaman":   depth,
+			"Wilayah":     region,
+			"Dirasakan":   feltBy,
+		}
+	}
+	magelang := quake(26*time.Hour, -7.62, 110.31, "5.1", "12 km", "Pusat gempa berada di darat 9 km BaratDaya Magelang", "IV Magelang, III Yogyakarta")
+	quakes := []map[string]any{magelang}
+	if felt {
+		quakes = append(quakes, quake(50*time.Hour, -8.52, 116.36, "3.6", "10 km", "Pusat gempa berada di darat 14 km Timur Laut Lombok Tengah", "III Lombok Tengah"))
+	} else {
+		quakes = append(quakes, quake(4*24*time.Hour, -10.21, 118.92, "5.4", "25 km", "134 km BaratDaya SUMBATIMUR-NTT", ""))
+	}
+	return map[string]any{"Infogempa": map[string]any{"gempa": quakes}}
+}
+
+// --- MAGMA: beberapa gunung api dengan status tetap ---
+var mockVolcanoes = []map[string]any{
+	{"ga_code": "MER", "ga_nama_gapi": "Merapi", "ga_lat_gapi": -7.5407, "ga_lon_gapi": 110.4457, "ga_status": 3},
+	{"ga_code": "MBB", "ga_nama_gapi": "Merbabu", "ga_lat_gapi": -7.455, "ga_lon_gapi": 110.44, "ga_status": 1},
+	{"ga_code": "SMR", "ga_nama_gapi": "Semeru", "ga_lat_gapi": -8.1077, "ga_lon_gapi": 112.9224, "ga_status": 2},
+	{"ga_code": "BRO", "ga_nama_gapi": "Bromo", "ga_lat_gapi": -7.9425, "ga_lon_gapi": 112.953, "ga_status": 1},
+	{"ga_code": "RIN", "ga_nama_gapi": "Rinjani", "ga_lat_gapi": -8.4117, "ga_lon_gapi": 116.4575, "ga_status": 1},
+	{"ga_code": "LWK", "ga_nama_gapi": "Lewotobi Laki-laki", "ga_lat_gapi": -8.542, "ga_lon_gapi": 122.775, "ga_status": 4},
+}
@@ -0,0 +1,327 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- API Call ke Open-Meteo: cuaca saat ini (tanpa AQI) ---
+func (c *Client) CurrentWeather(ctx context.Context, lat, lon float64) (model.WeatherData, error) {
+	endpoint := c.opts.Config().OpenMeteo
+	weatherURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/forecast?latitude=%s&longitude=%s&current=temperature_2m,apparent_temperature,relative_humidity_2m,dew_point_2m,precipitation,cloud_cover,uv_index,wind_speed_10m,wind_gusts_10m,wind_direction_10m,pressure_msl,surface_pressure,visibility&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var weatherResult struct {
+		Elevation float64 `json:"elevation"`
+		Current   struct {
+			Temperature   float64 `json:"temperature_2m"`
+			Apparent      float64 `json:"apparent_temperature"`
+			Humidity      int     `json:"relative_humidity_2m"`
+			DewPoint      float64 `json:"dew_point_2m"`
+			Precipitation float64 `json:"precipitation"`
+			CloudCover    int     `json:"cloud_cover"`
+			UVIndex       float64 `json:"uv_index"`
+			WindSpeed     float64 `json:"wind_speed_10m"`
+			WindGusts     float64 `json:"wind_gusts_10m"`
+			WindDirection int     `json:"wind_direction_10m"`
+			Pressure      float64 `json:"pressure_msl"`
+			SurfacePress  float64 `json:"surface_pressure"`
+			Visibility    float64 `json:"visibility"`
+		} `json:"current"`
+	}
+	if err := c.getJSON(ctx, OpenMeteo, weatherURL, &weatherResult); err != nil {
+		return model.WeatherData{}, err
+	}
+
+	return model.WeatherData{
+		Temperature:     weatherResult.Current.Temperature,
+		Apparent:        weatherResult.Current.Apparent,
+		Humidity:        weatherResult.Current.Humidity,
+		DewPoint:        weatherResult.Current.DewPoint,
+		Precipitation:   weatherResult.Current.Precipitation,
+		CloudCover:      weatherResult.Current.CloudCover,
+		UVIndex:         weatherResult.Current.UVIndex,
+		WindSpeed:       weatherResult.Current.WindSpeed,
+		WindGusts:       weatherResult.Current.WindGusts,
+		WindDirection:   weatherResult.Current.WindDirection,
+		Pressure:        weatherResult.Current.Pressure,
+		SurfacePressure: weatherResult.Current.SurfacePress,
+		Visibility:      weatherResult.Current.Visibility,
+		Elevation:       weatherResult.Elevation,
+	}, nil
+}
+
+// --- API Call ke Open-Meteo Air Quality: European AQI, polutan dan serbuk sari ---
+// Per jam mulai 00:00 hari ini waktu lokal sampai besok; service memilih jam
+// sekarang dan prakiraan 24 jam ke depan. Jam tanpa AQI (null) dilewati.
+// Serbuk sari hanya ada di domain CAMS Eropa; di luar itu nilainya null
+// dan Pollen dibiarkan nil.
+func (c *Client) AirQualityForecast(ctx context.Context, lat, lon float64) ([]model.AirQualityHour, error) {
+	endpoint := c.opts.Config().AirQuality
+	aqiURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/air-quality?latitude=%s&longitude=%s&hourly=european_aqi,pm2_5,pm10,ozone,nitrogen_dioxide,sulphur_dioxide,carbon_monoxide,alder_pollen,birch_pollen,grass_pollen,mugwort_pollen,olive_pollen,ragweed_pollen&forecast_days=2&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var aqiResult struct {
+		UTCOffsetSeconds int `json:"utc_offset_seconds"`
+		Hourly           struct {
+			Time    []string   `json:"time"`
+			AQI     []*int     `json:"european_aqi"`
+			PM25    []*float64 `json:"pm2_5"`
+			PM10    []*float64 `json:"pm10"`
+			O3      []*float64 `json:"ozone"`
+			NO2     []*float64 `json:"nitrogen_dioxide"`
+			SO2     []*float64 `json:"sulphur_dioxide"`
+			CO      []*float64 `json:"carbon_monoxide"`
+			Alder   []*float64 `json:"alder_pollen"`
+			Birch   []*float64 `json:"birch_pollen"`
+			Grass   []*float64 `json:"grass_pollen"`
+			Mugwort []*float64 `json:"mugwort_pollen"`
+			Olive   []*float64 `json:"olive_pollen"`
+			Ragweed []*float64 `json:"ragweed_pollen"`
+		} `json:"hourly"`
+	}
+	if err := c.getJSON(ctx, AirQuality, aqiURL, &aqiResult); err != nil {
+		return nil, err
+	}
+
+	series := aqiResult.Hourly
+	loc := time.FixedZone("", aqiResult.UTCOffsetSeconds)
+	hours := make([]model.AirQualityHour, 0, len(series.Time))
+	for i, raw := range series.Time {
+		t, err := time.ParseInLocation("2006-01-02T15:04", raw, loc)
+		if err != nil {
+			return nil, newDecodeError(AirQuality, fmt.Errorf("invalid hourly time %q", raw))
+		}
+		if i >= len(series.AQI) || series.AQI[i] == nil {
+			continue
+		}
+		hour := model.AirQualityHour{
+			Time: t,
+			AQI:  *series.AQI[i],
+			Pollutants: model.Pollutants{
+				PM25: valueOrZero(valueAt(series.PM25, i)),
+				PM10: valueOrZero(valueAt(series.PM10, i)),
+				O3:   valueOrZero(valueAt(series.O3, i)),
+				NO2:  valueOrZero(valueAt(series.NO2, i)),
+				SO2:  valueOrZero(valueAt(series.SO2, i)),
+				CO:   valueOrZero(valueAt(series.CO, i)),
+			},
+		}
+		pollen := model.Pollen{
+			Alder:   valueAt(series.Alder, i),
+			Birch:   valueAt(series.Birch, i),
+			Grass:   valueAt(series.Grass, i),
+			Mugwort: valueAt(series.Mugwort, i),
+			Olive:   valueAt(series.Olive, i),
+			Ragweed: valueAt(series.Ragweed, i),
+		}
+		if pollen != (model.Pollen{}) {
+			hour.Pollen = &pollen
+		}
+		hours = append(hours, hour)
+	}
+	return hours, nil
+}
+
+// Nilai jam ke-i dari deret per jam; nil kalau tidak ada atau null
+func valueAt(values []*float64, i int) *float64 {
+	if i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
+
+func valueOrZero(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// --- API Call ke Open-Meteo: prakiraan per jam, hari ini sampai lusa ---
+// Jam dari Open-Meteo berupa waktu lokal tanpa offset; offset-nya dikirim
+// terpisah di utc_offset_seconds.
+func (c *Client) HourlyForecast(ctx context.Context, lat, lon float64) (model.HourlyForecast, error) {
+	endpoint := c.opts.Config().OpenMeteo
+	forecastURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/forecast?latitude=%s&longitude=%s&hourly=temperature_2m,precipitation_probability,uv_index,pressure_msl,wind_speed_10m,dew_point_2m,weather_code,wind_gusts_10m&forecast_days=3&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var result struct {
+		UTCOffsetSeconds int `json:"utc_offset_seconds"`
+		Hourly           struct {
+			Time                     []string  `json:"time"`
+			Temperature              []float64 `json:"temperature_2m"`
+			PrecipitationProbability []int     `json:"precipitation_probability"`
+			UVIndex                  []float64 `json:"uv_index"`
+			PressureMSL              []float64 `json:"pressure_msl"`
+			WindSpeed                []float64 `json:"wind_speed_10m"`
+			DewPoint                 []float64 `json:"dew_point_2m"`
+			WeatherCode              []int     `json:"weather_code"`
+			WindGusts                []float64 `json:"wind_gusts_10m"`
+		} `json:"hourly"`
+	}
+	if err := c.getJSON(ctx, OpenMeteo, forecastURL, &result); err != nil {
+		return nil, err
+	}
+
+	loc := time.FixedZone("", result.UTCOffsetSeconds)
+	forecast := make(model.HourlyForecast, 0, len(result.Hourly.Time))
+	for i, raw := range result.Hourly.Time {
+		t, err := time.ParseInLocation("2006-01-02T15:04", raw, loc)
+		if err != nil {
+			return nil, newDecodeError(OpenMeteo, fmt.Errorf("invalid hourly time %q", raw))
+		}
+		hour := model.ForecastHour{Time: t}
+		if i < len(result.Hourly.Temperature) {
+			hour.Temperature = result.Hourly.Temperature[i]
+		}
+		if i < len(result.Hourly.PrecipitationProbability) {
+			hour.PrecipitationProbability = result.Hourly.PrecipitationProbability[i]
+		}
+		if i < len(result.Hourly.UVIndex) {
+			hour.UVIndex = result.Hourly.UVIndex[i]
+		}
+		if i < len(result.Hourly.PressureMSL) {
+			hour.PressureMSL = result.Hourly.PressureMSL[i]
+		}
+		if i < len(result.Hourly.WindSpeed) {
+			hour.WindSpeed = result.Hourly.WindSpeed[i]
+		}
+		if i < len(result.Hourly.DewPoint) {
+			hour.DewPoint = result.Hourly.DewPoint[i]
+		}
+		if i < len(result.Hourly.WeatherCode) {
+			hour.WeatherCode = result.Hourly.WeatherCode[i]
+		}
+		if i < len(result.Hourly.WindGusts) {
+			hour.WindGusts = result.Hourly.WindGusts[i]
+		}
+		forecast = append(forecast, hour)
+	}
+	return forecast, nil
+}
+
+// --- API Call ke Open-Meteo: agregat harian 7 hari (tanggal lokal lokasi) ---
+func (c *Client) DailyForecast(ctx context.Context, lat, lon float64) ([]model.DailyForecast, error) {
+	endpoint := c.opts.Config().OpenMeteo
+	forecastURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/forecast?latitude=%s&longitude=%s&daily=temperature_2m_min,temperature_2m_max,precipitation_sum,uv_index_max&forecast_days=7&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var result struct {
+		Daily struct {
+			Time             []string  `json:"time"`
+			TemperatureMin   []float64 `json:"temperature_2m_min"`
+			TemperatureMax   []float64 `json:"temperature_2m_max"`
+			PrecipitationSum []float64 `json:"precipitation_sum"`
+			UVIndexMax       []float64 `json:"uv_index_max"`
+		} `json:"daily"`
+	}
+	if err := c.getJSON(ctx, OpenMeteo, forecastURL, &result); err != nil {
+		return nil, err
+	}
+
+	at := func(values []float64, i int) float64 {
+		if i < len(values) {
+			return values[i]
+		}
+		return 0
+	}
+	days := make([]model.DailyForecast, 0, len(result.Daily.Time))
+	for i, date := range result.Daily.Time {
+		days = append(days, model.DailyForecast{
+			Date:             date,
+			TemperatureMin:   at(result.Daily.TemperatureMin, i),
+			TemperatureMax:   at(result.Daily.TemperatureMax, i),
+			PrecipitationSum: at(result.Daily.PrecipitationSum, i),
+			UVIndexMax:       at(result.Daily.UVIndexMax, i),
+		})
+	}
+	return days, nil
+}
+
+// --- API Call ke Open-Meteo: nama zona waktu IANA untuk koordinat ---
+// Tanpa variabel cuaca, timezone=auto tetap mengembalikan metadata zona.
+func (c *Client) Timezone(ctx context.Context, lat, lon float64) (string, error) {
+	endpoint := c.opts.Config().OpenMeteo
+	tzURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/forecast?latitude=%s&longitude=%s&forecast_days=1&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var result struct {
+		Timezone string `json:"timezone"`
+	}
+	if err := c.getJSON(ctx, OpenMeteo, tzURL, &result); err != nil {
+		return "", err
+	}
+	return result.Timezone, nil
+}
+
+// --- API Call ke Open-Meteo: angin permukaan & 850 hPa plus data termal, hari ini ---
+func (c *Client) AloftForecast(ctx context.Context, lat, lon float64) ([]model.AloftHour, error) {
+	endpoint := c.opts.Config().OpenMeteo
+	aloftURL := withAPIKey(fmt.Sprintf(
+		"%s/v1/forecast?latitude=%s&longitude=%s&hourly=temperature_2m,dew_point_2m,wind_speed_10m,wind_direction_10m,wind_speed_850hPa,wind_direction_850hPa,cloud_cover_low,cape&forecast_days=1&timezone=auto",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon),
+	), endpoint)
+
+	var result struct {
+		UTCOffsetSeconds int `json:"utc_offset_seconds"`
+		Hourly           struct {
+			Time                []string  `json:"time"`
+			Temperature         []float64 `json:"temperature_2m"`
+			DewPoint            []float64 `json:"dew_point_2m"`
+			WindSpeed10m        []float64 `json:"wind_speed_10m"`
+			WindDirection10m    []int     `json:"wind_direction_10m"`
+			WindSpeed850hPa     []float64 `json:"wind_speed_850hPa"`
+			WindDirection850hPa []int     `json:"wind_direction_850hPa"`
+			CloudCoverLow       []int     `json:"cloud_cover_low"`
+			CAPE                []float64 `json:"cape"`
+		} `json:"hourly"`
+	}
+	if err := c.getJSON(ctx, OpenMeteo, aloftURL, &result); err != nil {
+		return nil, err
+	}
+
+	at := func(values []float64, i int) float64 {
+		if i < len(values) {
+			return values[i]
+		}
+		return 0
+	}
+	atInt := func(values []int, i int) int {
+		if i < len(values) {
+			return values[i]
+		}
+		return 0
+	}
+	loc := time.FixedZone("", result.UTCOffsetSeconds)
+	hours := make([]model.AloftHour, 0, len(result.Hourly.Time))
+	for i, raw := range result.Hourly.Time {
+		t, err := time.ParseInLocation("2006-01-02T15:04", raw, loc)
+		if err != nil {
+			return nil, newDecodeError(OpenMeteo, fmt.Errorf("invalid hourly time %q", raw))
+		}
+		hours = append(hours, model.AloftHour{
+			Time:          t,
+			Temperature:   at(result.Hourly.Temperature, i),
+			DewPoint:      at(result.Hourly.DewPoint, i),
+			WindSurface:   model.Wind{Speed: at(result.Hourly.WindSpeed10m, i), Direction: atInt(result.Hourly.WindDirection10m, i)},
+			Wind850hPa:    model.Wind{Speed: at(result.Hourly.WindSpeed850hPa, i), Direction: atInt(result.Hourly.WindDirection850hPa, i)},
+			CloudCoverLow: atInt(result.Hourly.CloudCoverLow, i),
+			CAPE:          at(result.Hourly.CAPE, i),
+		})
+	}
+	return hours, nil
+}
@@ -0,0 +1,186 @@
+package providers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// Client live yang semua endpoint-nya menunjuk ke server tes
+func newTestClient(t *testing.T, handler http.Handler) *providers.Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	cfg := providers.DefaultConfig()
+	cfg.OpenMeteo.BaseURL = srv.URL
+	cfg.AirQuality.BaseURL = srv.URL
+	cfg.SunriseSunset.BaseURL = srv.URL
+	return providers.New(providers.Options{
+		HTTPClient: srv.Client(),
+		Config:     func() providers.Config { return cfg },
+		Timeout:    func() time.Duration { return 200 * time.Millisecond },
+		Retry: func() providers.RetryPolicy {
+			return providers.RetryPolicy{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}
+		},
+	})
+}
+
+func serveJSON(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+}
+
+func TestCurrentWeather(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/forecast", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("latitude") != "-6.2" || q.Get("longitude") != "106.8" {
+			t.Errorf("coordinates = %s, %s; want -6.2, 106.8", q.Get("latitude"), q.Get("longitude"))
+		}
+		serveJSON(`{"elevation": 8, "current": {"temperature_2m": 31.4, "apparent_temperature": 36.2,
+			"relative_humidity_2m": 66, "precipitation": 0.2, "cloud_cover": 40, "uv_index": 7.5,
+			"wind_speed_10m": 12.1, "wind_direction_10m": 310, "pressure_msl": 1009.8, "visibility": 24140}}`)(w, r)
+	})
+	client := newTestClient(t, mux)
+
+	weather, err := client.CurrentWeather(context.Background(), -6.2, 106.8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weather.Temperature != 31.4 || weather.Humidity != 66 || weather.WindDirection != 310 || weather.Elevation != 8 {
+		t.Errorf("weather = %+v", weather)
+	}
+}
+
+func TestAirQualityForecast(t *testing.T) {
+	// Jam dengan AQI null dilewati; serbuk sari null di luar Eropa
+	client := newTestClient(t, serveJSON(`{"utc_offset_seconds": 25200, "hourly": {
+		"time": ["2024-06-21T00:00", "2024-06-21T01:00", "2024-06-21T02:00"],
+		"european_aqi": [42, null, 57],
+		"pm2_5": [20.5, null, 31],
+		"pm10": [30, null, 44.2],
+		"birch_pollen": [null, null, null]}}`))
+
+	hours, err := client.AirQualityForecast(context.Background(), -6.2, 106.8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hours) != 2 {
+		t.Fatalf("got %d hours, want 2", len(hours))
+	}
+	want := time.Date(2024, 6, 21, 2, 0, 0, 0, time.FixedZone("", 7*3600))
+	if !hours[1].Time.Equal(want) || hours[1].AQI != 57 || hours[1].Pollutants.PM10 != 44.2 {
+		t.Errorf("second hour = %+v, want AQI 57 at %s", hours[1], want)
+	}
+	if hours[0].Pollen != nil {
+		t.Errorf("pollen = %+v, want nil when every value is null", hours[0].Pollen)
+	}
+}
+
+func TestSunTimes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /json", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("date"); got != "2024-06-21" {
+			t.Errorf("date = %q, want 2024-06-21", got)
+		}
+		serveJSON(`{"results": {"sunrise": "2024-06-20T23:01:42+00:00", "sunset": "2024-06-21T10:47:19+00:00"}, "status": "OK"}`)(w, r)
+	})
+	client := newTestClient(t, mux)
+
+	sunrise, sunset, err := client.SunTimes(context.Background(), -6.2, 106.8, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 6, 20, 23, 1, 42, 0, time.UTC); !sunrise.Equal(want) {
+		t.Errorf("sunrise = %s, want %s", sunrise, want)
+	}
+	if want := time.Date(2024, 6, 21, 10, 47, 19, 0, time.UTC); !sunset.Equal(want) {
+		t.Errorf("sunset = %s, want %s", sunset, want)
+	}
+}
+
+// --- Jalur gagal: 5xx di-retry, timeout, dan JSON rusak tidak di-retry ---
+func TestProviderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		kind     string
+		attempts int32
+	}{
+		{
+			name:     "server error",
+			handler:  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+			kind:     providers.KindStatus,
+			attempts: 3,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(2 * time.Second):
+				}
+			},
+			kind:     providers.KindTimeout,
+			attempts: 3,
+		},
+		{
+			name:     "bad json",
+			handler:  serveJSON(`{"current": {"temperature_2m": "hot"`),
+			kind:     providers.KindDecode,
+			attempts: 1,
+		},
+		{
+			name:     "not found",
+			handler:  func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) },
+			kind:     providers.KindStatus,
+			attempts: 1,
+		},
+	}
+	calls := map[string]func(*providers.Client) error{
+		providers.OpenMeteo: func(c *providers.Client) error {
+			_, err := c.CurrentWeather(context.Background(), -6.2, 106.8)
+			return err
+		},
+		providers.AirQuality: func(c *providers.Client) error {
+			_, err := c.AirQualityForecast(context.Background(), -6.2, 106.8)
+			return err
+		},
+		providers.SunriseSunset: func(c *providers.Client) error {
+			_, _, err := c.SunTimes(context.Background(), -6.2, 106.8, time.Now())
+			return err
+		},
+	}
+	for _, tt := range tests {
+		for provider, call := range calls {
+			t.Run(tt.name+"/"+provider, func(t *testing.T) {
+				var hits atomic.Int32
+				client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					hits.Add(1)
+					tt.handler(w, r)
+				}))
+
+				err := call(client)
+				var providerErr *providers.Error
+				if !errors.As(err, &providerErr) {
+					t.Fatalf("err = %v, want *providers.Error", err)
+				}
+				if providerErr.Provider != provider || providerErr.Kind != tt.kind {
+					t.Errorf("provider, kind = %s, %s; want %s, %s", providerErr.Provider, providerErr.Kind, provider, tt.kind)
+				}
+				if got := hits.Load(); got != tt.attempts {
+					t.Errorf("upstream called %d times, want %d", got, tt.attempts)
+				}
+			})
+		}
+	}
+}
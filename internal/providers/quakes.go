@@ -0,0 +1,149 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Katalog USGS di luar Amerika Serikat hanya lengkap mulai M2.5
+const usgsMinMagnitude = 2.5
+
+// --- API Call ke USGS: gempa dalam radius dari titik sejak waktu tertentu ---
+// Distance dibiarkan 0; service yang menghitung jarak ke titik yang ditanyakan.
+func (c *Client) Earthquakes(ctx context.Context, lat, lon, radiusKm float64, since time.Time) ([]model.Earthquake, error) {
+	endpoint := c.opts.Config().USGS
+	quakesURL := withAPIKey(fmt.Sprintf(
+		"%s/fdsnws/event/1/query?format=geojson&latitude=%s&longitude=%s&maxradiuskm=%g&starttime=%s&minmagnitude=%g&orderby=time",
+		endpoint.BaseURL, formatCoordinate(lat), formatCoordinate(lon), radiusKm, since.UTC().Format("2006-01-02T15:04:05"), usgsMinMagnitude,
+	), endpoint)
+
+	var result struct {
+		Features []struct {
+			Properties struct {
+				Magnitude float64 `json:"mag"`
+				Place     string  `json:"place"`
+				// Milidetik sejak epoch
+				Time int64 `json:"time"`
+			} `json:"properties"`
+			Geometry struct {
+				// [lon, lat, kedalaman km]
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := c.getJSON(ctx, USGS, quakesURL, &result); err != nil {
+		return nil, err
+	}
+
+	quakes := make([]model.Earthquake, 0, len(result.Features))
+	for _, feature := range result.Features {
+		coords := feature.Geometry.Coordinates
+		if len(coords) < 3 {
+			return nil, newDecodeError(USGS, fmt.Errorf("invalid coordinates %v", coords))
+		}
+		quakes = append(quakes, model.Earthquake{
+			Time:      time.UnixMilli(feature.Properties.Time).UTC(),
+			Latitude:  coords[1],
+			Longitude: coords[0],
+			Depth:     model.Round1(coords[2]),
+			Magnitude: model.Round1(feature.Properties.Magnitude),
+			Place:     feature.Properties.Place,
+			Source:    USGS,
+		})
+	}
+	return quakes, nil
+}
+
+// --- API Call ke BMKG: gempa M5+ terkini dan gempa yang dirasakan ---
+// Dua feed nasional (masing-masing ~15 kejadian terakhir); gempa yang ada di
+// keduanya hanya diambil sekali, dengan keterangan "dirasakan" kalau ada.
+func (c *Client) BMKGEarthquakes(ctx context.Context) ([]model.Earthquake, error) {
+	var latest, felt []model.Earthquake
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		latest, err = c.bmkgQuakeFeed(gctx, "gempaterkini.json")
+		return err
+	})
+	g.Go(func() (err error) {
+		felt, err = c.bmkgQuakeFeed(gctx, "gempadirasakan.json")
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	quakes := felt
+	for _, quake := range latest {
+		duplicate := false
+		for _, f := range felt {
+			if f.Time.Equal(quake.Time) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			quakes = append(quakes, quake)
+		}
+	}
+	return quakes, nil
+}
+
+func (c *Client) bmkgQuakeFeed(ctx context.Context, file string) ([]model.Earthquake, error) {
+	endpoint := c.opts.Config().BMKGQuakes
+	feedURL := withAPIKey(endpoint.BaseURL+"/DataMKG/TEWS/"+file, endpoint)
+
+	var result struct {
+		Infogempa struct {
+			Gempa []struct {
+				DateTime string `json:"DateTime"`
+				// "lat,lon"
+				Coordinates string `json:"Coordinates"`
+				Magnitude   string `json:"Magnitude"`
+				// Misal "10 km"
+				Kedalaman string `json:"Kedalaman"`
+				Wilayah   string `json:"Wilayah"`
+				Dirasakan string `json:"Dirasakan"`
+			} `json:"gempa"`
+		} `json:"Infogempa"`
+	}
+	if err := c.getJSON(ctx, BMKGQuakes, feedURL, &result); err != nil {
+		return nil, err
+	}
+
+	quakes := make([]model.Earthquake, 0, len(result.Infogempa.Gempa))
+	for _, raw := range result.Infogempa.Gempa {
+		at, err := time.Parse(time.RFC3339, raw.DateTime)
+		if err != nil {
+			return nil, newDecodeError(BMKGQuakes, fmt.Errorf("invalid DateTime %q", raw.DateTime))
+		}
+		rawLat, rawLon, ok := strings.Cut(raw.Coordinates, ",")
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(rawLat), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(rawLon), 64)
+		if !ok || latErr != nil || lonErr != nil {
+			return nil, newDecodeError(BMKGQuakes, fmt.Errorf("invalid coordinates %q", raw.Coordinates))
+		}
+		magnitude, err := strconv.ParseFloat(raw.Magnitude, 64)
+		if err != nil {
+			return nil, newDecodeError(BMKGQuakes, fmt.Errorf("invalid magnitude %q", raw.Magnitude))
+		}
+		depth, _ := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(raw.Kedalaman, "km")), 64)
+		quakes = append(quakes, model.Earthquake{
+			Time:      at.UTC(),
+			Latitude:  lat,
+			Longitude: lon,
+			Depth:     depth,
+			Magnitude: magnitude,
+			Place:     raw.Wilayah,
+			Felt:      raw.Dirasakan,
+			Source:    BMKG,
+		})
+	}
+	return quakes, nil
+}
@@ -0,0 +1,84 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"time"
+)
+
+// --- Kebijakan retry untuk kegagalan sementara (network, timeout, 429, 5xx) ---
+// Bagian "upstream.retry" di config; dibaca per panggilan supaya ikut reload.
+type RetryPolicy struct {
+	// Total percobaan termasuk yang pertama; 1 = tanpa retry
+	Attempts int `yaml:"attempts"`
+	// Jeda sebelum retry pertama, lalu berlipat dua sampai MaxBackoff
+	Backoff    time.Duration `yaml:"backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// Bagian acak tiap jeda (0-1), supaya banyak request yang gagal bersamaan
+	// tidak retry serempak
+	Jitter float64 `yaml:"jitter"`
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{Attempts: 3, Backoff: 200 * time.Millisecond, MaxBackoff: 2 * time.Second, Jitter: 0.5}
+}
+
+func (p RetryPolicy) Validate() error {
+	if p.Attempts < 1 {
+		return fmt.Errorf("attempts must be at least 1, got %d", p.Attempts)
+	}
+	if p.Backoff < 0 || p.MaxBackoff < p.Backoff {
+		return fmt.Errorf("backoff must be >= 0 and max_backoff >= backoff, got %s and %s", p.Backoff, p.MaxBackoff)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1, got %g", p.Jitter)
+	}
+	return nil
+}
+
+// Jeda sebelum retry ke-n (mulai 1)
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.MaxBackoff
+	if retry < 32 {
+		d = min(p.Backoff<<(retry-1), p.MaxBackoff)
+	}
+	return d - time.Duration(rand.Float64()*p.Jitter*float64(d))
+}
+
+// Hanya API yang murah & toleran terhadap request ulang. Nominatim membatasi
+// 1 request/detik, feed pemerintah & NOAA cukup di-cache saja.
+var retriedProviders = map[string]bool{
+	OpenMeteo:     true,
+	AirQuality:    true,
+	Geocoding:     true,
+	Marine:        true,
+	Archive:       true,
+	SunriseSunset: true,
+}
+
+// --- GET dengan retry; tiap percobaan punya timeout & observasi sendiri ---
+func (c *Client) get(ctx context.Context, provider, url string, decode func(io.Reader) error) error {
+	policy := RetryPolicy{Attempts: 1}
+	if retriedProviders[provider] && c.opts.Retry != nil {
+		policy = c.opts.Retry()
+	}
+	for attempt := 1; ; attempt++ {
+		err := c.attempt(ctx, provider, url, decode)
+		var providerErr *Error
+		if err == nil || attempt >= policy.Attempts || !errors.As(err, &providerErr) || !providerErr.Retryable() {
+			return err
+		}
+		c.opts.Retried(ctx, provider, attempt, err)
+
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return newCanceledError(provider, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+)
+
+// --- API Call ke NOAA SWPC: indeks Kp planet terkini (per menit, estimasi) ---
+// Kp berlaku global, jadi tidak butuh koordinat. Badai geomagnetik (Kp >= 5)
+// menurunkan akurasi GPS.
+func (c *Client) PlanetaryKIndex(ctx context.Context) (float64, error) {
+	endpoint := c.opts.Config().SpaceWeather
+	kpURL := withAPIKey(fmt.Sprintf("%s/json/planetary_k_index_1m.json", endpoint.BaseURL), endpoint)
+
+	var result []struct {
+		TimeTag     string  `json:"time_tag"`
+		EstimatedKp float64 `json:"estimated_kp"`
+	}
+	if err := c.getJSON(ctx, SpaceWeather, kpURL, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, newDecodeError(SpaceWeather, fmt.Errorf("empty k-index series"))
+	}
+	// Urut waktu; yang terakhir paling baru
+	return result[len(result)-1].EstimatedKp, nil
+}
@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// --- API Call ke Sunrise-Sunset: jam terbit & terbenam (UTC) untuk satu tanggal ---
+func (c *Client) SunTimes(ctx context.Context, lat, lon float64, date time.Time) (sunrise, sunset time.Time, err error) {
+	url := fmt.Sprintf("%s/json?lat=%s&lng=%s&date=%s&formatted=0",
+		c.opts.Config().SunriseSunset.BaseURL, formatCoordinate(lat), formatCoordinate(lon), date.Format(time.DateOnly))
+
+	var result struct {
+		Results struct {
+			Sunrise string `json:"sunrise"`
+			Sunset  string `json:"sunset"`
+		} `json:"results"`
+	}
+	if err := c.getJSON(ctx, SunriseSunset, url, &result); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	sunrise, err1 := time.Parse(time.RFC3339, result.Results.Sunrise)
+	sunset, err2 := time.Parse(time.RFC3339, result.Results.Sunset)
+	if err1 != nil || err2 != nil {
+		return time.Time{}, time.Time{}, newDecodeError(SunriseSunset, fmt.Errorf("invalid time format"))
+	}
+	return sunrise, sunset, nil
+}
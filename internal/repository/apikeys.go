@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- API key di tabel api_keys, memenuhi services.APIKeyStore ---
+type APIKeyStore struct {
+	db *DB
+}
+
+func (db *DB) APIKeys() *APIKeyStore {
+	return &APIKeyStore{db: db}
+}
+
+const apiKeyColumns = `id, name, prefix, daily_quota, created_at, revoked_at`
+
+func (s *APIKeyStore) Create(ctx context.Context, key model.APIKey, hash string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	_, err := s.db.pool.Exec(ctx, `
+		INSERT INTO api_keys (id, key_hash, name, prefix, daily_quota, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		key.ID, hash, key.Name, key.Prefix, key.DailyQuota, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create api key: %w", err)
+	}
+	return nil
+}
+
+func (s *APIKeyStore) List(ctx context.Context) ([]model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := s.db.pool.Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowToStructByPos[model.APIKey])
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *APIKeyStore) Lookup(ctx context.Context, hash string) (model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := s.db.pool.Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, hash)
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("look up api key: %w", err)
+	}
+	key, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByPos[model.APIKey])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.APIKey{}, services.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("look up api key: %w", err)
+	}
+	return key, nil
+}
+
+func (s *APIKeyStore) Revoke(ctx context.Context, id string, at time.Time) (model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := s.db.pool.Query(ctx, `
+		UPDATE api_keys SET revoked_at = coalesce(revoked_at, $2)
+		WHERE id = $1
+		RETURNING `+apiKeyColumns,
+		id, at)
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("revoke api key: %w", err)
+	}
+	key, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByPos[model.APIKey])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.APIKey{}, fmt.Errorf("%w: %s", services.ErrAPIKeyNotFound, id)
+	}
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("revoke api key: %w", err)
+	}
+	return key, nil
+}
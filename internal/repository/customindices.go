@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Indeks kustom di tabel custom_indices, memenuhi services.CustomIndexStore ---
+// Aturan disimpan sebagai JSONB dalam bentuk yang sama dengan body request.
+type CustomIndexStore struct {
+	db *DB
+	// Jumlah indeks maksimum per pemilik
+	limit int
+}
+
+func (db *DB) CustomIndices(limit int) *CustomIndexStore {
+	return &CustomIndexStore{db: db, limit: limit}
+}
+
+func (s *CustomIndexStore) List(ctx context.Context, owner string) ([]indices.CustomIndex, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := s.db.pool.Query(ctx, `
+		SELECT name, rules
+		FROM custom_indices
+		WHERE owner = $1
+		ORDER BY name`,
+		owner)
+	if err != nil {
+		return nil, fmt.Errorf("list custom indices: %w", err)
+	}
+	list, err := pgx.CollectRows(rows, scanCustomIndex)
+	if err != nil {
+		return nil, fmt.Errorf("list custom indices: %w", err)
+	}
+	return list, nil
+}
+
+func (s *CustomIndexStore) Get(ctx context.Context, owner, name string) (indices.CustomIndex, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := s.db.pool.Query(ctx, `SELECT name, rules FROM custom_indices WHERE owner = $1 AND name = $2`, owner, name)
+	if err != nil {
+		return indices.CustomIndex{}, fmt.Errorf("get custom index: %w", err)
+	}
+	index, err := pgx.CollectExactlyOneRow(rows, scanCustomIndex)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return indices.CustomIndex{}, fmt.Errorf("%w: %s", services.ErrCustomIndexNotFound, name)
+	}
+	if err != nil {
+		return indices.CustomIndex{}, fmt.Errorf("get custom index: %w", err)
+	}
+	return index, nil
+}
+
+// Batas dicek dalam transaksi yang sama dengan insert, dikunci per pemilik
+// seperti lokasi favorit
+func (s *CustomIndexStore) Save(ctx context.Context, owner string, index indices.CustomIndex) error {
+	rules, err := json.Marshal(index.Rules)
+	if err != nil {
+		return fmt.Errorf("encode custom index rules: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	err = pgx.BeginFunc(ctx, s.db.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('custom_indices/' || $1))`, owner); err != nil {
+			return err
+		}
+		var count int
+		var exists bool
+		if err := tx.QueryRow(ctx, `
+			SELECT count(*), coalesce(bool_or(name = $2), false)
+			FROM custom_indices
+			WHERE owner = $1`,
+			owner, index.Name).Scan(&count, &exists); err != nil {
+			return err
+		}
+		if !exists && count >= s.limit {
+			return fmt.Errorf("%w: at most %d per API key", services.ErrCustomIndexLimit, s.limit)
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO custom_indices (owner, name, rules)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (owner, name)
+			DO UPDATE SET rules = excluded.rules, saved_at = now()`,
+			owner, index.Name, rules)
+		return err
+	})
+	if errors.Is(err, services.ErrCustomIndexLimit) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("save custom index: %w", err)
+	}
+	return nil
+}
+
+func (s *CustomIndexStore) Delete(ctx context.Context, owner, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	tag, err := s.db.pool.Exec(ctx, `DELETE FROM custom_indices WHERE owner = $1 AND name = $2`, owner, name)
+	if err != nil {
+		return fmt.Errorf("delete custom index: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", services.ErrCustomIndexNotFound, name)
+	}
+	return nil
+}
+
+func scanCustomIndex(row pgx.CollectableRow) (indices.CustomIndex, error) {
+	var index indices.CustomIndex
+	var rules []byte
+	if err := row.Scan(&index.Name, &rules); err != nil {
+		return indices.CustomIndex{}, err
+	}
+	if err := json.Unmarshal(rules, &index.Rules); err != nil {
+		return indices.CustomIndex{}, fmt.Errorf("decode custom index %s: %w", index.Name, err)
+	}
+	return index, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Lokasi favorit di tabel favorites, memenuhi services.FavoriteStore ---
+type FavoriteStore struct {
+	db *DB
+	// Jumlah lokasi maksimum per pengguna
+	limit int
+}
+
+func (db *DB) Favorites(limit int) *FavoriteStore {
+	return &FavoriteStore{db: db, limit: limit}
+}
+
+func (s *FavoriteStore) List(ctx context.Context, owner, user string) ([]model.Favorite, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := s.db.pool.Query(ctx, `
+		SELECT name, latitude, longitude, saved_at
+		FROM favorites
+		WHERE owner = $1 AND user_id = $2
+		ORDER BY name`,
+		owner, user)
+	if err != nil {
+		return nil, fmt.Errorf("list favorites: %w", err)
+	}
+	favorites, err := pgx.CollectRows(rows, pgx.RowToStructByPos[model.Favorite])
+	if err != nil {
+		return nil, fmt.Errorf("list favorites: %w", err)
+	}
+	return favorites, nil
+}
+
+// Batas dicek dalam transaksi yang sama dengan insert; advisory lock per
+// pengguna mencegah dua request bersamaan sama-sama lolos batas
+func (s *FavoriteStore) Save(ctx context.Context, owner, user string, favorite model.Favorite) (model.Favorite, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	err := pgx.BeginFunc(ctx, s.db.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1 || '/' || $2))`, owner, user); err != nil {
+			return err
+		}
+		var count int
+		var exists bool
+		if err := tx.QueryRow(ctx, `
+			SELECT count(*), coalesce(bool_or(name = $3), false)
+			FROM favorites
+			WHERE owner = $1 AND user_id = $2`,
+			owner, user, favorite.Name).Scan(&count, &exists); err != nil {
+			return err
+		}
+		if !exists && count >= s.limit {
+			return fmt.Errorf("%w: at most %d per user", services.ErrFavoriteLimit, s.limit)
+		}
+		return tx.QueryRow(ctx, `
+			INSERT INTO favorites (owner, user_id, name, latitude, longitude)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (owner, user_id, name)
+			DO UPDATE SET latitude = excluded.latitude, longitude = excluded.longitude, saved_at = now()
+			RETURNING saved_at`,
+			owner, user, favorite.Name, favorite.Latitude, favorite.Longitude).Scan(&favorite.SavedAt)
+	})
+	if errors.Is(err, services.ErrFavoriteLimit) {
+		return model.Favorite{}, err
+	}
+	if err != nil {
+		return model.Favorite{}, fmt.Errorf("save favorite: %w", err)
+	}
+	return favorite, nil
+}
+
+func (s *FavoriteStore) Delete(ctx context.Context, owner, user, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	tag, err := s.db.pool.Exec(ctx, `DELETE FROM favorites WHERE owner = $1 AND user_id = $2 AND name = $3`, owner, user, name)
+	if err != nil {
+		return fmt.Errorf("delete favorite: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", services.ErrFavoriteNotFound, name)
+	}
+	return nil
+}
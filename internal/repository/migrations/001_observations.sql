@@ -0,0 +1,15 @@
+-- Observasi cuaca & indeks setiap kali response gabungan dihitung dari upstream
+CREATE TABLE observations (
+    id          BIGSERIAL PRIMARY KEY,
+    -- Sel lokasi "lat,lon" 2 desimal, sama dengan kunci cache
+    location    TEXT             NOT NULL,
+    latitude    DOUBLE PRECISION NOT NULL,
+    longitude   DOUBLE PRECISION NOT NULL,
+    observed_at TIMESTAMPTZ      NOT NULL,
+    -- open-meteo atau bmkg (weather.source)
+    source      TEXT             NOT NULL,
+    weather     JSONB            NOT NULL,
+    indices     JSONB            NOT NULL
+);
+
+CREATE INDEX observations_location_observed_at ON observations (location, observed_at DESC);
@@ -0,0 +1,10 @@
+-- Lokasi favorit per pengguna; owner = hash SHA-256 dari X-API-Key
+CREATE TABLE favorites (
+    owner     TEXT             NOT NULL,
+    user_id   TEXT             NOT NULL,
+    name      TEXT             NOT NULL,
+    latitude  DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    saved_at  TIMESTAMPTZ      NOT NULL DEFAULT now(),
+    PRIMARY KEY (owner, user_id, name)
+);
@@ -0,0 +1,10 @@
+-- API key yang diterbitkan lewat /admin/api-keys; hanya hash SHA-256 key yang disimpan
+CREATE TABLE api_keys (
+    id          TEXT        PRIMARY KEY,
+    key_hash    TEXT        NOT NULL UNIQUE,
+    name        TEXT        NOT NULL,
+    prefix      TEXT        NOT NULL,
+    daily_quota BIGINT      NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL,
+    revoked_at  TIMESTAMPTZ
+);
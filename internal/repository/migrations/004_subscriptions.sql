@@ -0,0 +1,15 @@
+-- Langganan notifikasi kondisi; owner = hash SHA-256 dari X-API-Key
+CREATE TABLE subscriptions (
+    id                TEXT             PRIMARY KEY,
+    owner             TEXT             NOT NULL,
+    latitude          DOUBLE PRECISION NOT NULL,
+    longitude         DOUBLE PRECISION NOT NULL,
+    condition         TEXT             NOT NULL,
+    webhook_url       TEXT             NOT NULL,
+    secret            TEXT             NOT NULL,
+    created_at        TIMESTAMPTZ      NOT NULL,
+    triggered         BOOLEAN          NOT NULL DEFAULT false,
+    last_triggered_at TIMESTAMPTZ
+);
+
+CREATE INDEX subscriptions_owner ON subscriptions (owner, created_at);
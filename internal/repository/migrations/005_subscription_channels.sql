@@ -0,0 +1,10 @@
+-- Kanal email & ringkasan harian untuk langganan; langganan lama tetap webhook
+ALTER TABLE subscriptions
+    ADD COLUMN channel            TEXT    NOT NULL DEFAULT 'webhook',
+    ADD COLUMN email              TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN lang               TEXT    NOT NULL DEFAULT 'id',
+    ADD COLUMN daily_summary_hour INTEGER,
+    ADD COLUMN last_summary_date  TEXT    NOT NULL DEFAULT '';
+
+-- Langganan yang hanya ringkasan harian tidak punya kondisi
+ALTER TABLE subscriptions ALTER COLUMN condition SET DEFAULT '';
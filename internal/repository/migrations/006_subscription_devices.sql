@@ -0,0 +1,10 @@
+-- Token FCM perangkat per langganan; ikut terhapus bersama langganannya
+CREATE TABLE subscription_devices (
+    subscription_id TEXT        NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    token           TEXT        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subscription_id, token)
+);
+
+-- Token yang tidak berlaku lagi dihapus dari semua langganan sekaligus
+CREATE INDEX subscription_devices_token ON subscription_devices (token);
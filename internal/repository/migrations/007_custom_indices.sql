@@ -0,0 +1,8 @@
+-- Indeks kustom tersimpan per API key; owner = hash SHA-256 dari X-API-Key
+CREATE TABLE custom_indices (
+    owner    TEXT        NOT NULL,
+    name     TEXT        NOT NULL,
+    rules    JSONB       NOT NULL,
+    saved_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (owner, name)
+);
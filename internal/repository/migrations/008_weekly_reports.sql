@@ -0,0 +1,4 @@
+-- Laporan mingguan lokasi tersimpan; jadwalnya (user_id, weekday, hour) disimpan utuh
+ALTER TABLE subscriptions
+    ADD COLUMN weekly_report    JSONB,
+    ADD COLUMN last_report_date TEXT  NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Pengguna aplikasi pemilik langganan, untuk menilai event astronomi di lokasi tersimpannya
+ALTER TABLE subscriptions
+    ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
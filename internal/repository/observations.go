@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Catat satu observasi; cuaca & indeks disimpan sebagai JSONB ---
+func (db *DB) RecordObservation(ctx context.Context, obs model.Observation) error {
+	weather, err := json.Marshal(obs.Weather)
+	if err != nil {
+		return fmt.Errorf("encode observation weather: %w", err)
+	}
+	calculated, err := json.Marshal(obs.Indices)
+	if err != nil {
+		return fmt.Errorf("encode observation indices: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	_, err = db.pool.Exec(ctx, `
+		INSERT INTO observations (location, latitude, longitude, observed_at, source, weather, indices)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		obs.Location, obs.Latitude, obs.Longitude, obs.ObservedAt, obs.Weather.Source, weather, calculated)
+	if err != nil {
+		return fmt.Errorf("record observation: %w", err)
+	}
+	return nil
+}
+
+// --- Observasi terbaru di sel lokasi dari sumber tertentu sejak since; ok false kalau tidak ada ---
+func (db *DB) LatestObservation(ctx context.Context, location, source string, since time.Time) (model.Observation, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := db.pool.Query(ctx, `
+		SELECT location, latitude, longitude, observed_at, weather, indices
+		FROM observations
+		WHERE location = $1 AND source = $2 AND observed_at >= $3
+		ORDER BY observed_at DESC
+		LIMIT 1`,
+		location, source, since)
+	if err != nil {
+		return model.Observation{}, false, fmt.Errorf("latest observation: %w", err)
+	}
+	obs, err := pgx.CollectExactlyOneRow(rows, scanObservation)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Observation{}, false, nil
+	}
+	if err != nil {
+		return model.Observation{}, false, fmt.Errorf("latest observation: %w", err)
+	}
+	return obs, true, nil
+}
+
+// --- Observasi di sel lokasi dalam [since, until), diringkas per hari lokal ---
+// Tanggal dihitung di zona loc; hari tanpa observasi tidak muncul.
+func (db *DB) ObservedDays(ctx context.Context, location string, loc *time.Location, since, until time.Time) ([]model.ObservedDay, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := db.pool.Query(ctx, `
+		SELECT to_char(observed_at AT TIME ZONE $2, 'YYYY-MM-DD'),
+			min((weather->>'temperature')::float8),
+			max((weather->>'temperature')::float8),
+			max((weather->>'wind_gusts')::float8),
+			bool_or((weather->>'precipitation')::float8 > 0),
+			count(*)::int,
+			round(avg((indices->>'hiking_index')::float8)::numeric, 1)::float8
+		FROM observations
+		WHERE location = $1 AND observed_at >= $3 AND observed_at < $4
+		GROUP BY 1
+		ORDER BY 1`,
+		location, loc.String(), since, until)
+	if err != nil {
+		return nil, fmt.Errorf("observed days: %w", err)
+	}
+	days, err := pgx.CollectRows(rows, pgx.RowToStructByPos[model.ObservedDay])
+	if err != nil {
+		return nil, fmt.Errorf("observed days: %w", err)
+	}
+	return days, nil
+}
+
+func scanObservation(row pgx.CollectableRow) (model.Observation, error) {
+	var obs model.Observation
+	var weather, calculated []byte
+	if err := row.Scan(&obs.Location, &obs.Latitude, &obs.Longitude, &obs.ObservedAt, &weather, &calculated); err != nil {
+		return obs, err
+	}
+	if err := json.Unmarshal(weather, &obs.Weather); err != nil {
+		return obs, fmt.Errorf("decode observation weather: %w", err)
+	}
+	if err := json.Unmarshal(calculated, &obs.Indices); err != nil {
+		return obs, fmt.Errorf("decode observation indices: %w", err)
+	}
+	return obs, nil
+}
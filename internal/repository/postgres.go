@@ -0,0 +1,52 @@
+// Package repository menyimpan data yang perlu bertahan lebih lama dari
+// cache (riwayat observasi, indeks kustom, lokasi favorit, API key, langganan
+// notifikasi) di PostgreSQL. Skema dibuat dan diperbarui lewat migrasi SQL
+// yang ikut di binary, dijalankan saat Open.
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Batas waktu satu query; database yang lambat tidak boleh menahan response
+const queryTimeout = 2 * time.Second
+
+type DB struct {
+	pool *pgxpool.Pool
+}
+
+// --- Buka pool koneksi, pastikan database bisa diakses, lalu jalankan migrasi ---
+func Open(ctx context.Context, url string) (*DB, error) {
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, fmt.Errorf("database.url is invalid: %v", err)
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	db := &DB{pool: pool}
+	if err := db.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	if err := db.migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *DB) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	return db.pool.Ping(ctx)
+}
+
+func (db *DB) Close() {
+	db.pool.Close()
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Langganan notifikasi di tabel subscriptions, memenuhi services.SubscriptionStore ---
+type SubscriptionStore struct {
+	db *DB
+	// Jumlah langganan maksimum per API key
+	limit int
+}
+
+func (db *DB) Subscriptions(limit int) *SubscriptionStore {
+	return &SubscriptionStore{db: db, limit: limit}
+}
+
+// Urutannya sama dengan field model.Subscription (RowToStructByPos); token
+// perangkat digabung jadi satu array per langganan, jadwal laporan dibaca
+// dari JSONB
+const subscriptionColumns = `id, latitude, longitude, condition, channel, webhook_url, email, secret, lang,
+	daily_summary_hour, created_at, triggered, last_triggered_at, last_summary_date,
+	ARRAY(SELECT token FROM subscription_devices d WHERE d.subscription_id = subscriptions.id ORDER BY d.created_at),
+	weekly_report, last_report_date, user_id, owner`
+
+// Batas dicek dalam transaksi yang sama dengan insert, dikunci per pemilik
+func (s *SubscriptionStore) Create(ctx context.Context, owner string, sub model.Subscription) (model.Subscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	err := pgx.BeginFunc(ctx, s.db.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('subscriptions/' || $1))`, owner); err != nil {
+			return err
+		}
+		var count int
+		if err := tx.QueryRow(ctx, `SELECT count(*) FROM subscriptions WHERE owner = $1`, owner).Scan(&count); err != nil {
+			return err
+		}
+		if count >= s.limit {
+			return fmt.Errorf("%w: at most %d per API key", services.ErrSubscriptionLimit, s.limit)
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO subscriptions (id, owner, latitude, longitude, condition, channel, webhook_url, email, secret, lang, daily_summary_hour, created_at, weekly_report, user_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+			sub.ID, owner, sub.Latitude, sub.Longitude, sub.Condition, sub.Channel, sub.WebhookURL, sub.Email, sub.Secret, sub.Lang, sub.DailySummaryHour, sub.CreatedAt, sub.WeeklyReport, sub.UserID)
+		return err
+	})
+	if errors.Is(err, services.ErrSubscriptionLimit) {
+		return model.Subscription{}, err
+	}
+	if err != nil {
+		return model.Subscription{}, fmt.Errorf("create subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *SubscriptionStore) List(ctx context.Context, owner string) ([]model.Subscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := s.db.pool.Query(ctx, `SELECT `+subscriptionColumns+` FROM subscriptions WHERE owner = $1 ORDER BY created_at`, owner)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %w", err)
+	}
+	subs, err := pgx.CollectRows(rows, pgx.RowToStructByPos[model.Subscription])
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+func (s *SubscriptionStore) Delete(ctx context.Context, owner, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	tag, err := s.db.pool.Exec(ctx, `DELETE FROM subscriptions WHERE owner = $1 AND id = $2`, owner, id)
+	if err != nil {
+		return fmt.Errorf("delete subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", services.ErrSubscriptionNotFound, id)
+	}
+	return nil
+}
+
+func (s *SubscriptionStore) All(ctx context.Context) ([]model.Subscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := s.db.pool.Query(ctx, `SELECT `+subscriptionColumns+` FROM subscriptions`)
+	if err != nil {
+		return nil, fmt.Errorf("list all subscriptions: %w", err)
+	}
+	subs, err := pgx.CollectRows(rows, pgx.RowToStructByPos[model.Subscription])
+	if err != nil {
+		return nil, fmt.Errorf("list all subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// Update bersyarat: kalau dua instance menilai langganan yang sama, hanya
+// satu yang mengubah baris dan mendapat true
+func (s *SubscriptionStore) SetTriggered(ctx context.Context, id string, triggered bool, at time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	tag, err := s.db.pool.Exec(ctx, `
+		UPDATE subscriptions
+		SET triggered = $2, last_triggered_at = CASE WHEN $2 THEN $3 ELSE last_triggered_at END
+		WHERE id = $1 AND triggered <> $2`,
+		id, triggered, at)
+	if err != nil {
+		return false, fmt.Errorf("update subscription: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Seperti SetTriggered, hanya satu instance yang mendapat true per tanggal
+func (s *SubscriptionStore) MarkSummarySent(ctx context.Context, id, date string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	tag, err := s.db.pool.Exec(ctx, `UPDATE subscriptions SET last_summary_date = $2 WHERE id = $1 AND last_summary_date <> $2`, id, date)
+	if err != nil {
+		return false, fmt.Errorf("update subscription: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (s *SubscriptionStore) MarkReportSent(ctx context.Context, id, date string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	tag, err := s.db.pool.Exec(ctx, `UPDATE subscriptions SET last_report_date = $2 WHERE id = $1 AND last_report_date <> $2`, id, date)
+	if err != nil {
+		return false, fmt.Errorf("update subscription: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Kepemilikan & batas dicek dalam transaksi yang sama; baris langganan
+// dikunci supaya dua request tidak sama-sama lolos batas
+func (s *SubscriptionStore) AddDevice(ctx context.Context, owner, id, token string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	err := pgx.BeginFunc(ctx, s.db.pool, func(tx pgx.Tx) error {
+		var exists bool
+		err := tx.QueryRow(ctx, `SELECT true FROM subscriptions WHERE id = $1 AND owner = $2 FOR UPDATE`, id, owner).Scan(&exists)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", services.ErrSubscriptionNotFound, id)
+		}
+		if err != nil {
+			return err
+		}
+		var count int
+		var registered bool
+		if err := tx.QueryRow(ctx, `SELECT count(*), coalesce(bool_or(token = $2), false) FROM subscription_devices WHERE subscription_id = $1`, id, token).Scan(&count, &registered); err != nil {
+			return err
+		}
+		if registered {
+			return nil
+		}
+		if count >= services.MaxDevicesPerSubscription {
+			return fmt.Errorf("%w: at most %d per subscription", services.ErrDeviceLimit, services.MaxDevicesPerSubscription)
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO subscription_devices (subscription_id, token) VALUES ($1, $2)`, id, token)
+		return err
+	})
+	if errors.Is(err, services.ErrSubscriptionNotFound) || errors.Is(err, services.ErrDeviceLimit) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("add device: %w", err)
+	}
+	return nil
+}
+
+func (s *SubscriptionStore) RemoveDevice(ctx context.Context, owner, id, token string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	var owned bool
+	err := s.db.pool.QueryRow(ctx, `SELECT true FROM subscriptions WHERE id = $1 AND owner = $2`, id, owner).Scan(&owned)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %s", services.ErrSubscriptionNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("remove device: %w", err)
+	}
+	tag, err := s.db.pool.Exec(ctx, `DELETE FROM subscription_devices WHERE subscription_id = $1 AND token = $2`, id, token)
+	if err != nil {
+		return fmt.Errorf("remove device: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return services.ErrDeviceNotFound
+	}
+	return nil
+}
+
+func (s *SubscriptionStore) ForgetDevice(ctx context.Context, token string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	if _, err := s.db.pool.Exec(ctx, `DELETE FROM subscription_devices WHERE token = $1`, token); err != nil {
+		return fmt.Errorf("forget device: %w", err)
+	}
+	return nil
+}
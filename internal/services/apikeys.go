@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// --- Penyimpanan API key yang diterbitkan admin; dicari lewat hash SHA-256 key ---
+type APIKeyStore interface {
+	Create(ctx context.Context, key model.APIKey, hash string) error
+	// Semua key termasuk yang sudah dicabut, paling lama dulu
+	List(ctx context.Context) ([]model.APIKey, error)
+	Lookup(ctx context.Context, hash string) (model.APIKey, error)
+	// Mencabut key yang sudah dicabut tidak mengubah revoked_at
+	Revoke(ctx context.Context, id string, at time.Time) (model.APIKey, error)
+}
+
+// --- Simpan di memori proses; key hilang saat restart ---
+type memoryAPIKeyStore struct {
+	mu     sync.RWMutex
+	byHash map[string]model.APIKey
+}
+
+func NewMemoryAPIKeyStore() APIKeyStore {
+	return &memoryAPIKeyStore{byHash: map[string]model.APIKey{}}
+}
+
+func (m *memoryAPIKeyStore) Create(_ context.Context, key model.APIKey, hash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byHash[hash] = key
+	return nil
+}
+
+func (m *memoryAPIKeyStore) List(context.Context) ([]model.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]model.APIKey, 0, len(m.byHash))
+	for _, key := range m.byHash {
+		list = append(list, key)
+	}
+	slices.SortFunc(list, func(a, b model.APIKey) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return list, nil
+}
+
+func (m *memoryAPIKeyStore) Lookup(_ context.Context, hash string) (model.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	key, ok := m.byHash[hash]
+	if !ok {
+		return model.APIKey{}, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (m *memoryAPIKeyStore) Revoke(_ context.Context, id string, at time.Time) (model.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for hash, key := range m.byHash {
+		if key.ID != id {
+			continue
+		}
+		if key.RevokedAt == nil {
+			key.RevokedAt = &at
+			m.byHash[hash] = key
+		}
+		return key, nil
+	}
+	return model.APIKey{}, fmt.Errorf("%w: %s", ErrAPIKeyNotFound, id)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// --- Sumber cuaca saat ini, dipilih lewat ?provider= ---
+// auto memakai BMKG untuk titik di dekat wilayah BMKG yang dikonfigurasi,
+// selain itu Open-Meteo.
+const (
+	SourceAuto      = "auto"
+	SourceOpenMeteo = providers.OpenMeteo
+	SourceBMKG      = providers.BMKG
+)
+
+var ErrNoBMKGArea = errors.New("no BMKG forecast area near this location")
+
+// --- Parse nilai ?provider=, default ke auto kalau kosong ---
+func ParseSource(raw string) (string, error) {
+	switch source := strings.ToLower(strings.TrimSpace(raw)); source {
+	case "":
+		return SourceAuto, nil
+	case SourceAuto, SourceOpenMeteo, SourceBMKG:
+		return source, nil
+	default:
+		return "", fmt.Errorf("unsupported provider %q (use %q, %q or %q)", raw, SourceAuto, SourceOpenMeteo, SourceBMKG)
+	}
+}
+
+// --- Langkah prakiraan BMKG yang sedang berjalan; nil kalau BMKG tidak dipakai ---
+// Dengan provider=bmkg kegagalan jadi error; dengan auto cukup kembali ke Open-Meteo.
+func (s *Weather) bmkgStep(ctx context.Context, lat, lon float64, source string) (*model.BMKGForecastStep, error) {
+	if source == SourceOpenMeteo {
+		return nil, nil
+	}
+	area, ok := s.client.BMKGArea(lat, lon)
+	if !ok {
+		if source == SourceBMKG {
+			return nil, fmt.Errorf("%w (%g, %g)", ErrNoBMKGArea, lat, lon)
+		}
+		return nil, nil
+	}
+
+	steps, ok := cacheGet[[]model.BMKGForecastStep](ctx, s.cache, CacheBMKG, area.Code)
+	if !ok {
+		var err error
+		steps, err = coalesce(ctx, flightKey(CacheBMKG, area.Code), func() ([]model.BMKGForecastStep, error) {
+			return s.client.BMKGForecast(ctx, area.Code)
+		})
+		if err != nil {
+			if source == SourceBMKG {
+				return nil, err
+			}
+			degraded(ctx, "weather.source", "BMKG forecast unavailable, using Open-Meteo", err, "area", area.Code)
+			return nil, nil
+		}
+		cacheSet(ctx, s.cache, CacheBMKG, area.Code, steps, CacheTTLs[CacheBMKG])
+	}
+
+	step, ok := currentBMKGStep(steps, s.clock.Now())
+	if !ok {
+		if source == SourceBMKG {
+			return nil, fmt.Errorf("%w: forecast for area %s does not cover the current time", ErrNoBMKGArea, area.Code)
+		}
+		return nil, nil
+	}
+	return &step, nil
+}
+
+// Langkah BMKG berlaku 3 jam sejak waktunya; ambil yang sedang berjalan
+const bmkgStepLength = 3 * time.Hour
+
+func currentBMKGStep(steps []model.BMKGForecastStep, now time.Time) (model.BMKGForecastStep, bool) {
+	for _, step := range steps {
+		if !now.Before(step.Time) && now.Before(step.Time.Add(bmkgStepLength)) {
+			return step, true
+		}
+	}
+	return model.BMKGForecastStep{}, false
+}
+
+// --- Timpa field yang dimiliki BMKG ---
+// Suhu terasa, UV, tekanan dan hembusan tetap dari Open-Meteo karena BMKG
+// tidak menyediakannya; titik embun dihitung ulang dari suhu & RH BMKG.
+func applyBMKG(weather *model.WeatherData, step model.BMKGForecastStep) {
+	weather.Source = SourceBMKG
+	weather.Temperature = step.Temperature
+	weather.Humidity = step.Humidity
+	weather.DewPoint = indices.DewPoint(step.Temperature, step.Humidity)
+	weather.CloudCover = step.CloudCover
+	weather.Precipitation = step.Precipitation
+	weather.WindSpeed = step.WindSpeed
+	weather.WindDirection = step.WindDirection
+	weather.Visibility = step.Visibility
+}
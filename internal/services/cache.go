@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// --- Jenis data yang di-cache, juga dipakai untuk invalidasi per jenis ---
+const (
+	CacheWeather    = "weather"
+	CacheAirQuality = "air_quality"
+	CacheSun        = "sun"
+	CacheForecast   = "forecast"
+	CacheDaily      = "daily_forecast"
+	CacheMarine     = "marine"
+	CacheTides      = "tides"
+	CacheAloft      = "aloft"
+	// Riwayat pembacaan tekanan per lokasi untuk tendensi 3 jam
+	CachePressure = "pressure_history"
+	// Indeks Kp global, disimpan dengan lokasi "global"
+	CacheSpaceWeather = "space_weather"
+	// Peringatan dini BMKG seluruh Indonesia, juga dengan lokasi "global"
+	CacheAlerts = "alerts"
+	// Prakiraan BMKG per kode wilayah adm4 (bukan per koordinat)
+	CacheBMKG = "bmkg_forecast"
+	// Gempa USGS per lokasi; feed nasional BMKG dengan lokasi "global"
+	CacheQuakes = "quakes"
+	// Status semua gunung api Indonesia, dengan lokasi "global"
+	CacheVolcano = "volcano"
+	// Polusi cahaya per lokasi; dataset-nya statis (World Atlas 2015)
+	CacheLightPollution = "light_pollution"
+	// Hasil geocoding per nama tempat (bukan per koordinat)
+	CacheGeocode = "geocode"
+	CachePlace   = "place"
+	// Riwayat harian ERA5 per lokasi & rentang tanggal (lokasi:start:end)
+	CacheHistory = "history"
+	// Rata-rata bulanan per lokasi & periode tahun (lokasi:awal-akhir)
+	CacheClimatology = "climatology"
+	// Nama zona waktu IANA per koordinat; praktis tidak pernah berubah
+	CacheTimezone = "timezone"
+	// Response gabungan; TTL-nya dari Settings.ConditionsTTL
+	CacheConditions = "conditions"
+)
+
+// Umur data per jenis; cuaca & AQI berubah per jam, jam matahari per hari
+var CacheTTLs = map[string]time.Duration{
+	CacheWeather:        10 * time.Minute,
+	CacheAirQuality:     30 * time.Minute,
+	CacheSun:            time.Hour,
+	CacheForecast:       30 * time.Minute,
+	CacheDaily:          time.Hour,
+	CacheMarine:         time.Hour,
+	CacheTides:          time.Hour,
+	CacheAloft:          30 * time.Minute,
+	CachePressure:       4 * time.Hour,
+	CacheSpaceWeather:   15 * time.Minute,
+	CacheAlerts:         5 * time.Minute,
+	CacheBMKG:           time.Hour,
+	CacheQuakes:         5 * time.Minute,
+	CacheVolcano:        30 * time.Minute,
+	CacheLightPollution: 30 * 24 * time.Hour,
+	CacheGeocode:        24 * time.Hour,
+	CachePlace:          7 * 24 * time.Hour,
+	CacheHistory:        24 * time.Hour,
+	CacheClimatology:    30 * 24 * time.Hour,
+	CacheTimezone:       7 * 24 * time.Hour,
+	CacheConditions:     10 * time.Minute,
+}
+
+// --- Penyimpanan hasil upstream; implementasinya (memory, Redis) ada di main ---
+// Nilai disimpan sebagai JSON supaya bisa dibagi antar instance. Cache yang
+// gagal diakses cukup dianggap miss, bukan error.
+type Cache interface {
+	Get(ctx context.Context, dataType, location string) ([]byte, bool)
+	Set(ctx context.Context, dataType, location string, value []byte, ttl time.Duration)
+}
+
+// Lokasi dibulatkan ke 2 desimal (~1 km) supaya titik yang berdekatan
+// memakai entry yang sama.
+func CacheLocation(lat, lon float64) string {
+	round := func(v float64) float64 { return math.Round(v*100) / 100 }
+	return fmt.Sprintf("%.2f,%.2f", round(lat), round(lon))
+}
+
+// --- Ambil dari cache atau panggil fetch; error tidak pernah di-cache ---
+// Miss untuk lokasi yang sama digabung jadi satu fetch (coalesce).
+func cachedFetch[T any](ctx context.Context, cache Cache, dataType string, lat, lon float64, fetch func() (T, error)) (T, error) {
+	location := CacheLocation(lat, lon)
+	if v, ok := cacheGet[T](ctx, cache, dataType, location); ok {
+		return v, nil
+	}
+	return coalesce(ctx, flightKey(dataType, location), func() (T, error) {
+		v, err := fetch()
+		if err != nil {
+			return v, err
+		}
+		cacheSet(ctx, cache, dataType, location, v, CacheTTLs[dataType])
+		return v, nil
+	})
+}
+
+// Entry yang tidak bisa di-decode (misal format lama) dianggap miss
+func cacheGet[T any](ctx context.Context, cache Cache, dataType, location string) (T, bool) {
+	var v T
+	data, ok := cache.Get(ctx, dataType, location)
+	if !ok {
+		return v, false
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		slog.WarnContext(ctx, "discarding undecodable cache entry", "type", dataType, "error", err)
+		return v, false
+	}
+	return v, true
+}
+
+func cacheSet(ctx context.Context, cache Cache, dataType, location string, value any, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.WarnContext(ctx, "cache entry not stored", "type", dataType, "error", err)
+		return
+	}
+	cache.Set(ctx, dataType, location, data, ttl)
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Jumlah tahun penuh yang dirata-rata (?years=) ---
+const (
+	DefaultClimatologyYears = 10
+	MaxClimatologyYears     = 30
+)
+
+// Klasifikasi Schmidt-Ferguson: bulan kering < 60 mm, bulan basah > 100 mm
+const (
+	dryMonthPrecipitation = 60.0
+	wetMonthPrecipitation = 100.0
+)
+
+// Berapa bulan terbaik yang disebut di best_months
+const bestMonthCount = 3
+
+// --- Rata-rata bulanan dari riwayat ERA5 beberapa tahun terakhir ---
+// Periode berakhir 31 Desember tahun lalu supaya tiap bulan punya jumlah
+// tahun yang sama. Hasilnya di-cache utuh (bukan data hariannya) karena
+// satu periode bisa berisi belasan ribu hari.
+func (s *Weather) Climatology(ctx context.Context, lat, lon float64, years int) (model.ClimatologyResponse, error) {
+	lastYear := s.clock.Now().UTC().Year() - 1
+	start := time.Date(lastYear-years+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(lastYear, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	key := fmt.Sprintf("%s:%d-%d", CacheLocation(lat, lon), start.Year(), end.Year())
+	if resp, ok := cacheGet[model.ClimatologyResponse](ctx, s.cache, CacheClimatology, key); ok {
+		return resp, nil
+	}
+	return coalesce(ctx, flightKey(CacheClimatology, key), func() (model.ClimatologyResponse, error) {
+		days, err := s.client.HistoricalDaily(ctx, lat, lon, start, end)
+		if err != nil {
+			return model.ClimatologyResponse{}, err
+		}
+		resp := climatology(days, s.settings().Scoring.Hiking.Daily)
+		resp.StartYear, resp.EndYear = start.Year(), end.Year()
+		resp.Meta = model.ResponseMeta{Units: model.UnitsMetric}
+		cacheSet(ctx, s.cache, CacheClimatology, key, resp, CacheTTLs[CacheClimatology])
+		return resp, nil
+	})
+}
+
+// Jumlah per bulan dikumpulkan per tahun dulu, lalu dirata-rata antar tahun
+func climatology(days []model.HistoryDay, scoring indices.DailyHikingScoring) model.ClimatologyResponse {
+	type monthTotals struct {
+		days, rainDays                   int
+		tempMin, tempMax, precip, hiking float64
+		years                            map[string]bool
+	}
+	var totals [12]monthTotals
+	for _, day := range days {
+		date, err := time.Parse(time.DateOnly, day.Date)
+		if err != nil {
+			continue
+		}
+		m := &totals[date.Month()-1]
+		if m.years == nil {
+			m.years = map[string]bool{}
+		}
+		m.years[day.Date[:4]] = true
+		m.days++
+		if day.PrecipitationSum >= rainDayThreshold {
+			m.rainDays++
+		}
+		m.tempMin += day.TemperatureMin
+		m.tempMax += day.TemperatureMax
+		m.precip += day.PrecipitationSum
+		m.hiking += indices.HikingPastDay(day, scoring).HikingIndex
+	}
+
+	resp := model.ClimatologyResponse{BestMonths: []int{}, DryMonths: []int{}}
+	for i, m := range totals {
+		month := model.ClimatologyMonth{Month: i + 1}
+		if m.days > 0 {
+			years, n := float64(len(m.years)), float64(m.days)
+			month.TemperatureMin = model.Round1(m.tempMin / n)
+			month.TemperatureMax = model.Round1(m.tempMax / n)
+			month.Precipitation = model.Round1(m.precip / years)
+			month.RainDays = model.Round1(float64(m.rainDays) / years)
+			month.HikingIndexAverage = model.Round1(m.hiking / n)
+			month.Season = season(month.Precipitation)
+		}
+		resp.Months = append(resp.Months, month)
+	}
+
+	ranked := slices.Clone(resp.Months)
+	ranked = slices.DeleteFunc(ranked, func(m model.ClimatologyMonth) bool { return m.Season == "" })
+	slices.SortStableFunc(ranked, func(a, b model.ClimatologyMonth) int {
+		return cmp.Compare(b.HikingIndexAverage, a.HikingIndexAverage)
+	})
+	for _, m := range ranked[:min(bestMonthCount, len(ranked))] {
+		resp.BestMonths = append(resp.BestMonths, m.Month)
+	}
+	for _, m := range resp.Months {
+		if m.Season == model.SeasonDry {
+			resp.DryMonths = append(resp.DryMonths, m.Month)
+		}
+	}
+	return resp
+}
+
+func season(precipitation float64) string {
+	switch {
+	case precipitation < dryMonthPrecipitation:
+		return model.SeasonDry
+	case precipitation > wetMonthPrecipitation:
+		return model.SeasonWet
+	default:
+		return model.SeasonTransition
+	}
+}
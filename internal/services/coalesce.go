@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// --- Fetch upstream yang sedang berjalan, per jenis data & lokasi cache ---
+// Saat banyak client meminta titik populer yang sama bersamaan (dan cache
+// belum terisi), hanya satu set request keluar per lokasi; yang lain menunggu
+// hasilnya. Cukup per instance, seperti cache memory.
+var inflight singleflight.Group
+
+func flightKey(dataType, location string) string {
+	return dataType + "|" + location
+}
+
+// Hasil dibagikan sebagai JSON supaya tiap pemanggil dapat salinan sendiri,
+// sama seperti nilai dari cache.
+func coalesce[T any](ctx context.Context, key string, fetch func() (T, error)) (T, error) {
+	var v T
+	data, err, shared := inflight.Do(key, func() (any, error) {
+		v, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	})
+	if err != nil {
+		// Fetch bersama memakai context request yang memulainya; kalau request
+		// itu dibatalkan, request ini yang masih menunggu mengambil sendiri
+		if shared && ctx.Err() == nil && providers.KindOf(err) == providers.KindCanceled {
+			return coalesce(ctx, key, fetch)
+		}
+		return v, err
+	}
+	err = json.Unmarshal(data.([]byte), &v)
+	return v, err
+}
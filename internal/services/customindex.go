@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+var (
+	ErrCustomIndexNotFound = errors.New("custom index not found")
+	ErrCustomIndexLimit    = errors.New("custom index limit reached")
+)
+
+// --- Skor indeks buatan pengguna dari cuaca saat ini ---
+// Memakai response gabungan (dan cache-nya) supaya tidak ada fetch tambahan.
+func (s *Weather) CustomIndex(ctx context.Context, lat, lon float64, index indices.CustomIndex) (model.CustomIndexResponse, error) {
+	conditions, err := s.Conditions(ctx, lat, lon, SourceAuto)
+	if err != nil {
+		return model.CustomIndexResponse{}, err
+	}
+	score, rules := index.Score(conditions.Weather)
+	return model.CustomIndexResponse{
+		Name:  index.Name,
+		Score: score,
+		Rules: rules,
+		Meta:  model.ResponseMeta{Units: model.UnitsMetric, Errors: conditions.Meta.Errors},
+	}, nil
+}
+
+// --- Penyimpanan indeks kustom per pemilik (hash API key) ---
+type CustomIndexStore interface {
+	List(ctx context.Context, owner string) ([]indices.CustomIndex, error)
+	Get(ctx context.Context, owner, name string) (indices.CustomIndex, error)
+	// Nama yang sama menimpa indeks lama
+	Save(ctx context.Context, owner string, index indices.CustomIndex) error
+	Delete(ctx context.Context, owner, name string) error
+}
+
+// --- Simpan di memori proses; hilang saat restart dan tidak dibagi antar instance ---
+type memoryCustomIndexStore struct {
+	limit int
+
+	mu      sync.RWMutex
+	byOwner map[string]map[string]indices.CustomIndex
+}
+
+// limit = jumlah indeks maksimum per pemilik
+func NewMemoryCustomIndexStore(limit int) CustomIndexStore {
+	return &memoryCustomIndexStore{limit: limit, byOwner: map[string]map[string]indices.CustomIndex{}}
+}
+
+func (m *memoryCustomIndexStore) List(_ context.Context, owner string) ([]indices.CustomIndex, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]indices.CustomIndex, 0, len(m.byOwner[owner]))
+	for _, index := range m.byOwner[owner] {
+		list = append(list, index)
+	}
+	slices.SortFunc(list, func(a, b indices.CustomIndex) int { return strings.Compare(a.Name, b.Name) })
+	return list, nil
+}
+
+func (m *memoryCustomIndexStore) Get(_ context.Context, owner, name string) (indices.CustomIndex, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	index, ok := m.byOwner[owner][name]
+	if !ok {
+		return indices.CustomIndex{}, fmt.Errorf("%w: %s", ErrCustomIndexNotFound, name)
+	}
+	return index, nil
+}
+
+func (m *memoryCustomIndexStore) Save(_ context.Context, owner string, index indices.CustomIndex) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	saved := m.byOwner[owner]
+	if saved == nil {
+		saved = map[string]indices.CustomIndex{}
+		m.byOwner[owner] = saved
+	}
+	if _, exists := saved[index.Name]; !exists && len(saved) >= m.limit {
+		return fmt.Errorf("%w: at most %d per API key", ErrCustomIndexLimit, m.limit)
+	}
+	index.Rules = slices.Clone(index.Rules)
+	saved[index.Name] = index
+	return nil
+}
+
+func (m *memoryCustomIndexStore) Delete(_ context.Context, owner, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.byOwner[owner][name]; !ok {
+		return fmt.Errorf("%w: %s", ErrCustomIndexNotFound, name)
+	}
+	delete(m.byOwner[owner], name)
+	return nil
+}
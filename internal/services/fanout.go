@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// --- Kebijakan saat bagian opsional response gagal diambil ---
+const (
+	// Bagian itu dikosongkan atau diganti cadangan dan dilaporkan di meta.errors
+	FanoutPartial = "partial"
+	// Kegagalan pertama, opsional atau tidak, menggagalkan request dan
+	// membatalkan fetch lain yang masih berjalan
+	FanoutFailFast = "fail_fast"
+)
+
+// --- Fetch paralel untuk satu response di atas errgroup ---
+// Tiap task mendapat timeout sendiri (Settings.TaskTimeout, 0 = hanya
+// dibatasi timeout request) sehingga satu upstream lambat tidak menahan
+// seluruh response lebih lama dari itu. Error task wajib selalu membatalkan
+// task lain.
+type fanout struct {
+	g        *errgroup.Group
+	ctx      context.Context
+	timeout  time.Duration
+	failFast bool
+}
+
+func (s *Weather) fanout(ctx context.Context) *fanout {
+	settings := s.settings()
+	g, gctx := errgroup.WithContext(ctx)
+	return &fanout{g: g, ctx: gctx, timeout: settings.TaskTimeout, failFast: settings.FanoutPolicy == FanoutFailFast}
+}
+
+// task mengembalikan error hanya kalau response tidak bisa dibuat tanpanya;
+// kegagalan opsional cukup dicatat lewat degraded.
+func (f *fanout) Go(task func(ctx context.Context) error) {
+	f.g.Go(func() error {
+		ctx := f.ctx
+		if f.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, f.timeout)
+			defer cancel()
+		}
+		if err := task(ctx); err != nil {
+			return err
+		}
+		if f.failFast {
+			return degradedErr(ctx)
+		}
+		return nil
+	})
+}
+
+func (f *fanout) Wait() error {
+	return f.g.Wait()
+}
+
+// --- Untuk response yang diambil berurutan, tanpa fanout ---
+// Dengan fail_fast kegagalan opsional yang sudah tercatat menggagalkan request.
+func (s *Weather) failFast(ctx context.Context) error {
+	if s.settings().FanoutPolicy != FanoutFailFast {
+		return nil
+	}
+	return degradedErr(ctx)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+var (
+	ErrFavoriteNotFound = errors.New("saved location not found")
+	ErrFavoriteLimit    = errors.New("saved location limit reached")
+)
+
+// --- Lokasi favorit per pengguna; pemilik (hash API key) memisahkan aplikasi ---
+// Pengguna yang sama di dua API key berbeda adalah dua daftar berbeda.
+type FavoriteStore interface {
+	List(ctx context.Context, owner, user string) ([]model.Favorite, error)
+	// Nama yang sama menimpa lokasi lama
+	Save(ctx context.Context, owner, user string, favorite model.Favorite) (model.Favorite, error)
+	Delete(ctx context.Context, owner, user, name string) error
+}
+
+// --- Simpan di memori proses; dipakai kalau database tidak dikonfigurasi ---
+type memoryFavoriteStore struct {
+	limit int
+	now   func() time.Time
+
+	mu     sync.RWMutex
+	byUser map[string]map[string]model.Favorite
+}
+
+// limit = jumlah lokasi maksimum per pengguna
+func NewMemoryFavoriteStore(limit int) FavoriteStore {
+	return &memoryFavoriteStore{limit: limit, now: time.Now, byUser: map[string]map[string]model.Favorite{}}
+}
+
+func favoriteKey(owner, user string) string { return owner + "/" + user }
+
+func (m *memoryFavoriteStore) List(_ context.Context, owner, user string) ([]model.Favorite, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]model.Favorite, 0, len(m.byUser[favoriteKey(owner, user)]))
+	for _, favorite := range m.byUser[favoriteKey(owner, user)] {
+		list = append(list, favorite)
+	}
+	slices.SortFunc(list, func(a, b model.Favorite) int { return strings.Compare(a.Name, b.Name) })
+	return list, nil
+}
+
+func (m *memoryFavoriteStore) Save(_ context.Context, owner, user string, favorite model.Favorite) (model.Favorite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := favoriteKey(owner, user)
+	saved := m.byUser[key]
+	if saved == nil {
+		saved = map[string]model.Favorite{}
+		m.byUser[key] = saved
+	}
+	if _, exists := saved[favorite.Name]; !exists && len(saved) >= m.limit {
+		return model.Favorite{}, fmt.Errorf("%w: at most %d per user", ErrFavoriteLimit, m.limit)
+	}
+	favorite.SavedAt = m.now().UTC()
+	saved[favorite.Name] = favorite
+	return favorite, nil
+}
+
+func (m *memoryFavoriteStore) Delete(_ context.Context, owner, user, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := favoriteKey(owner, user)
+	if _, ok := m.byUser[key][name]; !ok {
+		return fmt.Errorf("%w: %s", ErrFavoriteNotFound, name)
+	}
+	delete(m.byUser[key], name)
+	return nil
+}
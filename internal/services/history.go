@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// Rentang terpanjang satu request /history; ERA5 dimulai 1940
+const MaxHistoryDays = 366
+
+var historyEarliest = time.Date(1940, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Rentang tanggal /history yang tidak bisa dilayani
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// Hujan sehari mulai dari sini dihitung sebagai hari hujan
+const rainDayThreshold = 1.0
+
+// --- Cuaca harian masa lalu dengan indeks mendaki yang dihitung ulang ---
+// Indeks dihitung dengan scoring saat ini, jadi hasilnya bisa dibandingkan
+// dengan /forecast/daily. Status gunung api tidak ikut karena riwayatnya
+// tidak tersedia.
+func (s *Weather) History(ctx context.Context, lat, lon float64, start, end time.Time) (model.HistoryResponse, error) {
+	if err := s.checkHistoryRange(start, end); err != nil {
+		return model.HistoryResponse{}, err
+	}
+	days, err := s.historicalDaily(ctx, lat, lon, start, end)
+	if err != nil {
+		return model.HistoryResponse{}, err
+	}
+	scoring := s.settings().Scoring.Hiking.Daily
+	for i := range days {
+		hiking := indices.HikingPastDay(days[i], scoring)
+		days[i].HikingIndex, days[i].HikingRecommendation = hiking.HikingIndex, hiking.HikingRecommendation
+		days[i].IndexBreakdown = hiking.IndexBreakdown
+	}
+	return model.HistoryResponse{
+		Start:   start.Format(time.DateOnly),
+		End:     end.Format(time.DateOnly),
+		Days:    days,
+		Summary: summarizeHistory(days),
+		Meta:    model.ResponseMeta{Units: model.UnitsMetric},
+	}, nil
+}
+
+// start & end berupa tanggal (00:00 UTC); hari ini belum selesai jadi tidak
+// termasuk riwayat
+func (s *Weather) checkHistoryRange(start, end time.Time) error {
+	now := s.clock.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch {
+	case start.Before(historyEarliest):
+		return fmt.Errorf("%w: start must be %s or later", ErrInvalidDateRange, historyEarliest.Format(time.DateOnly))
+	case end.Before(start):
+		return fmt.Errorf("%w: end must not be before start", ErrInvalidDateRange)
+	case !end.Before(today):
+		return fmt.Errorf("%w: end must be before today (%s)", ErrInvalidDateRange, today.Format(time.DateOnly))
+	case end.Sub(start) >= MaxHistoryDays*24*time.Hour:
+		return fmt.Errorf("%w: at most %d days per request", ErrInvalidDateRange, MaxHistoryDays)
+	}
+	return nil
+}
+
+// Cache per lokasi & rentang tanggal; data lampau jarang berubah, tapi ERA5
+// awal (ERA5T) masih bisa dikoreksi sehingga tidak disimpan selamanya
+func (s *Weather) historicalDaily(ctx context.Context, lat, lon float64, start, end time.Time) ([]model.HistoryDay, error) {
+	key := CacheLocation(lat, lon) + ":" + start.Format(time.DateOnly) + ":" + end.Format(time.DateOnly)
+	if days, ok := cacheGet[[]model.HistoryDay](ctx, s.cache, CacheHistory, key); ok {
+		return days, nil
+	}
+	return coalesce(ctx, flightKey(CacheHistory, key), func() ([]model.HistoryDay, error) {
+		days, err := s.client.HistoricalDaily(ctx, lat, lon, start, end)
+		if err != nil {
+			return nil, err
+		}
+		cacheSet(ctx, s.cache, CacheHistory, key, days, CacheTTLs[CacheHistory])
+		return days, nil
+	})
+}
+
+func summarizeHistory(days []model.HistoryDay) model.HistorySummary {
+	summary := model.HistorySummary{Days: len(days)}
+	if len(days) == 0 {
+		return summary
+	}
+	summary.TemperatureMin, summary.TemperatureMax = math.Inf(1), math.Inf(-1)
+	best, total := -1.0, 0.0
+	for _, day := range days {
+		if day.PrecipitationSum >= rainDayThreshold {
+			summary.RainDays++
+		}
+		summary.PrecipitationTotal += day.PrecipitationSum
+		summary.TemperatureMin = min(summary.TemperatureMin, day.TemperatureMin)
+		summary.TemperatureMax = max(summary.TemperatureMax, day.TemperatureMax)
+		total += day.HikingIndex
+		if day.HikingIndex > best {
+			best, summary.BestDay = day.HikingIndex, day.Date
+		}
+	}
+	summary.PrecipitationTotal = model.Round1(summary.PrecipitationTotal)
+	summary.HikingIndexAverage = model.Round1(total / float64(len(days)))
+	return summary
+}
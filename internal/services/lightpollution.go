@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// --- Polusi cahaya di titik; opsional ---
+// nil kalau API key belum diisi atau provider tidak bisa diakses; indeks
+// stargazing lalu hanya memakai awan dan bulan.
+func (s *Weather) lightPollution(ctx context.Context, lat, lon float64) *model.LightPollution {
+	brightness, err := cachedFetch(ctx, s.cache, CacheLightPollution, lat, lon, func() (float64, error) {
+		return s.client.SkyBrightness(ctx, lat, lon)
+	})
+	if errors.Is(err, providers.ErrNoAPIKey) {
+		return nil
+	}
+	if err != nil {
+		degraded(ctx, "light_pollution", "light pollution unavailable, stargazing without sky brightness", err)
+		return nil
+	}
+	light := indices.Bortle(brightness)
+	return &light
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Riwayat observasi (PostgreSQL di package repository); nil = tidak dicatat ---
+type ObservationStore interface {
+	RecordObservation(ctx context.Context, obs model.Observation) error
+	// Observasi terbaru di sel lokasi dari sumber tertentu sejak since
+	LatestObservation(ctx context.Context, location, source string, since time.Time) (model.Observation, bool, error)
+	// Observasi di sel lokasi dalam [since, until), diringkas per hari lokal zona loc
+	ObservedDays(ctx context.Context, location string, loc *time.Location, since, until time.Time) ([]model.ObservedDay, error)
+}
+
+// --- Catat response gabungan yang baru dihitung dari upstream ---
+// Ditulis di background supaya database yang lambat tidak menahan response.
+func (s *Weather) recordObservation(ctx context.Context, lat, lon float64, data model.ConsolidatedResponse) {
+	if s.observations == nil {
+		return
+	}
+	obs := model.Observation{
+		Location:   CacheLocation(lat, lon),
+		Latitude:   lat,
+		Longitude:  lon,
+		ObservedAt: s.clock.Now(),
+		Weather:    data.Weather,
+		Indices:    data.Indices,
+	}
+	go func() {
+		if err := s.observations.RecordObservation(context.WithoutCancel(ctx), obs); err != nil {
+			slog.WarnContext(ctx, "observation not recorded", "error", err)
+		}
+	}()
+}
+
+// --- Cuaca Open-Meteo dari observasi yang masih segar, supaya tidak fetch ulang ---
+// Berguna setelah restart atau saat cache memory kosong. Field turunan
+// (AQI, humidex, tendensi tekanan, dst.) dikosongkan karena dihitung ulang.
+func (s *Weather) recentWeather(ctx context.Context, lat, lon float64) (model.WeatherData, bool) {
+	if s.observations == nil {
+		return model.WeatherData{}, false
+	}
+	since := s.clock.Now().Add(-CacheTTLs[CacheWeather])
+	obs, ok, err := s.observations.LatestObservation(ctx, CacheLocation(lat, lon), SourceOpenMeteo, since)
+	if err != nil {
+		slog.WarnContext(ctx, "observation lookup failed, fetching upstream", "error", err)
+		return model.WeatherData{}, false
+	}
+	if !ok {
+		return model.WeatherData{}, false
+	}
+	weather := obs.Weather
+	weather.AQI, weather.AirQuality, weather.AQIForecast, weather.Pollen = 0, model.AirQuality{}, nil, nil
+	weather.PressureChange, weather.PressureTrend = nil, ""
+	weather.DailyUV, weather.FogRisk = nil, false
+	return weather, true
+}
+
+// --- Cuaca tercatat seminggu sebelum tanggal laporan, per hari lokal ---
+// Kosong kalau riwayat tidak dicatat atau gagal dibaca; laporan tetap dikirim.
+func (s *Weather) pastWeek(ctx context.Context, lat, lon float64, date string) []model.ObservedDay {
+	if s.observations == nil {
+		return nil
+	}
+	loc := s.timezone(ctx, lat, lon)
+	day, err := time.ParseInLocation(time.DateOnly, date, loc)
+	if err != nil {
+		return nil
+	}
+	days, err := s.observations.ObservedDays(ctx, CacheLocation(lat, lon), loc, day.AddDate(0, 0, -7), day)
+	if err != nil {
+		slog.WarnContext(ctx, "weekly report without past week", "error", err)
+		return nil
+	}
+	return days
+}
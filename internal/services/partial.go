@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// --- Bagian response yang terpaksa dikosongkan atau diganti cadangan ---
+// Dikumpulkan per request lewat context, lalu dilaporkan di meta.errors
+// supaya client tahu data mana yang tidak lengkap.
+type degradedKey struct{}
+
+type degradedSections struct {
+	mu       sync.Mutex
+	sections []model.SectionError
+	// Error kegagalan pertama, untuk kebijakan fail_fast
+	first error
+}
+
+func trackDegraded(ctx context.Context) (context.Context, *degradedSections) {
+	d := &degradedSections{}
+	return context.WithValue(ctx, degradedKey{}, d), d
+}
+
+// Salinan urut per section (fetch berjalan paralel); nil kalau semua bagian lengkap
+func (d *degradedSections) list() []model.SectionError {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.sections) == 0 {
+		return nil
+	}
+	list := append([]model.SectionError(nil), d.sections...)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Section < list[j].Section })
+	return list
+}
+
+// Kegagalan opsional pertama dalam request ini; nil kalau belum ada
+func degradedErr(ctx context.Context) error {
+	d, ok := ctx.Value(degradedKey{}).(*degradedSections)
+	if !ok {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.first
+}
+
+// --- Catat kegagalan opsional: log warning plus entri meta.errors ---
+// section adalah path field response yang terdampak, message menjelaskan
+// apa yang dilakukan sebagai gantinya.
+func degraded(ctx context.Context, section, message string, err error, args ...any) {
+	slog.WarnContext(ctx, message, append(args, "error", err)...)
+
+	d, ok := ctx.Value(degradedKey{}).(*degradedSections)
+	if !ok {
+		return
+	}
+	entry := model.SectionError{Section: section, Message: message}
+	var providerErr *providers.Error
+	if errors.As(err, &providerErr) {
+		entry.Provider, entry.Kind = providerErr.Provider, providerErr.Kind
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sections = append(d.sections, entry)
+	if d.first == nil {
+		d.first = err
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// --- Radius pencarian gempa (?radius_km=) ---
+const (
+	DefaultQuakeRadiusKm = 100.0
+	MaxQuakeRadiusKm     = 500.0
+)
+
+// /quakes melaporkan gempa 7 hari terakhir
+const quakeWindow = 7 * 24 * time.Hour
+
+// Kejadian yang sama di USGS & BMKG: waktu dan episenter hampir sama
+const (
+	sameQuakeWithin   = time.Minute
+	sameQuakeWithinKm = 100.0
+)
+
+// recent_seismic_activity hanya untuk titik pegunungan: ada gempa dalam
+// radius default selama 72 jam terakhir
+const (
+	mountainElevation = 1000.0
+	seismicWindow     = 72 * time.Hour
+)
+
+// --- Gempa terkini dalam radius dari titik, paling baru dulu ---
+func (s *Weather) Quakes(ctx context.Context, lat, lon, radiusKm float64) (model.QuakesResponse, error) {
+	ctx, sections := trackDegraded(ctx)
+	loc := s.timezone(ctx, lat, lon)
+	quakes, err := s.nearbyQuakes(ctx, lat, lon, radiusKm, quakeWindow)
+	if err == nil {
+		err = s.failFast(ctx)
+	}
+	if err != nil {
+		return model.QuakesResponse{}, err
+	}
+	for i := range quakes {
+		quakes[i].Time = quakes[i].Time.In(loc)
+	}
+	return model.QuakesResponse{
+		Quakes:   quakes,
+		RadiusKm: radiusKm,
+		Meta:     model.ResponseMeta{Units: model.UnitsMetric, Timezone: loc.String(), Errors: sections.list()},
+	}, nil
+}
+
+// --- Flag gempa terkini untuk response gabungan; nil kalau bukan pegunungan ---
+func (s *Weather) recentSeismicActivity(ctx context.Context, lat, lon float64, weather model.WeatherData) *bool {
+	if weather.Elevation < mountainElevation {
+		return nil
+	}
+	quakes, err := s.nearbyQuakes(ctx, lat, lon, DefaultQuakeRadiusKm, seismicWindow)
+	if err != nil {
+		degraded(ctx, "recent_seismic_activity", "earthquake feeds unavailable, recent_seismic_activity omitted", err)
+		return nil
+	}
+	active := len(quakes) > 0
+	return &active
+}
+
+// USGS selalu ditanya; di Indonesia feed BMKG ikut dipakai dan menang kalau
+// kejadiannya sama. Kalau hanya salah satu yang gagal, yang lain tetap dipakai.
+func (s *Weather) nearbyQuakes(ctx context.Context, lat, lon, radiusKm float64, window time.Duration) ([]model.Earthquake, error) {
+	since := s.clock.Now().Add(-window)
+	usgs, err := cachedFetch(ctx, s.cache, CacheQuakes, lat, lon, func() ([]model.Earthquake, error) {
+		// Selalu radius maksimum supaya satu entry cache melayani semua radius
+		return s.client.Earthquakes(ctx, lat, lon, MaxQuakeRadiusKm, s.clock.Now().Add(-quakeWindow))
+	})
+	if !providers.InIndonesia(lat, lon) {
+		if err != nil {
+			return nil, err
+		}
+		return mergeQuakes(lat, lon, radiusKm, since, usgs), nil
+	}
+
+	bmkg, bmkgErr := s.bmkgQuakes(ctx)
+	switch {
+	case err != nil && bmkgErr != nil:
+		return nil, err
+	case err != nil:
+		degraded(ctx, "quakes", "USGS earthquakes unavailable, using BMKG only", err)
+	case bmkgErr != nil:
+		degraded(ctx, "quakes", "BMKG earthquakes unavailable, using USGS only", bmkgErr)
+	}
+	return mergeQuakes(lat, lon, radiusKm, since, bmkg, usgs), nil
+}
+
+// Feed BMKG berlaku nasional, disimpan dengan lokasi "global"
+func (s *Weather) bmkgQuakes(ctx context.Context) ([]model.Earthquake, error) {
+	if quakes, ok := cacheGet[[]model.Earthquake](ctx, s.cache, CacheQuakes, globalCacheLocation); ok {
+		return quakes, nil
+	}
+	quakes, err := coalesce(ctx, flightKey(CacheQuakes, globalCacheLocation), func() ([]model.Earthquake, error) {
+		return s.client.BMKGEarthquakes(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	cacheSet(ctx, s.cache, CacheQuakes, globalCacheLocation, quakes, CacheTTLs[CacheQuakes])
+	return quakes, nil
+}
+
+// --- Gabungkan feed: hitung jarak, saring radius & waktu, buang duplikat ---
+// Feed yang disebut lebih dulu menang untuk kejadian yang sama.
+func mergeQuakes(lat, lon, radiusKm float64, since time.Time, feeds ...[]model.Earthquake) []model.Earthquake {
+	result := []model.Earthquake{}
+	for _, feed := range feeds {
+		for _, quake := range feed {
+			if quake.Time.Before(since) {
+				continue
+			}
+			quake.Distance = model.Round1(providers.DistanceKm(lat, lon, quake.Latitude, quake.Longitude))
+			if quake.Distance > radiusKm {
+				continue
+			}
+			if slices.ContainsFunc(result, func(seen model.Earthquake) bool { return sameQuake(seen, quake) }) {
+				continue
+			}
+			result = append(result, quake)
+		}
+	}
+	slices.SortFunc(result, func(a, b model.Earthquake) int { return b.Time.Compare(a.Time) })
+	return result
+}
+
+func sameQuake(a, b model.Earthquake) bool {
+	gap := a.Time.Sub(b.Time)
+	return gap.Abs() <= sameQuakeWithin &&
+		providers.DistanceKm(a.Latitude, a.Longitude, b.Latitude, b.Longitude) <= sameQuakeWithinKm
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/alerts"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionLimit    = errors.New("subscription limit reached")
+	ErrDeviceNotFound       = errors.New("device not registered")
+	ErrDeviceLimit          = errors.New("device limit reached")
+)
+
+// Perangkat per langganan; cukup untuk beberapa ponsel & tablet satu pengguna
+const MaxDevicesPerSubscription = 10
+
+// --- Hasil evaluasi satu kondisi langganan ---
+type ConditionCheck struct {
+	Matched bool
+	Value   float64
+	// Response gabungan yang dinilai, untuk isi webhook
+	Data model.ConsolidatedResponse
+}
+
+// --- Nilai kondisi di satu lokasi dari response gabungan (dan cache-nya) ---
+// Prakiraan per jam hanya diambil untuk "rain starting".
+func (s *Weather) CheckCondition(ctx context.Context, lat, lon float64, condition alerts.Condition) (ConditionCheck, error) {
+	data, err := s.Conditions(ctx, lat, lon, SourceAuto)
+	if err != nil {
+		return ConditionCheck{}, err
+	}
+	var forecast model.HourlyForecast
+	if condition.Event == alerts.EventRainStarting {
+		if forecast, err = s.forecast(ctx, lat, lon); err != nil {
+			return ConditionCheck{}, err
+		}
+	}
+	matched, value := condition.Evaluate(data, forecast, lat, lon, s.clock.Now())
+	return ConditionCheck{Matched: matched, Value: value, Data: data}, nil
+}
+
+// --- Jam sekarang di zona lokasi, untuk jadwal ringkasan harian ---
+func (s *Weather) LocalTime(ctx context.Context, lat, lon float64) time.Time {
+	return s.today(s.timezone(ctx, lat, lon))
+}
+
+// --- Isi ringkasan harian: kondisi sekarang plus prakiraan hari ini ---
+type DailySummary struct {
+	Data model.ConsolidatedResponse
+	// Kosong kalau prakiraan harian gagal diambil; ringkasan tetap dikirim
+	Today *model.DailyForecast
+	// Prakiraan mulai tanggal ringkasan, untuk laporan mingguan
+	Week []model.DailyForecast
+}
+
+func (s *Weather) DailySummary(ctx context.Context, lat, lon float64, date string) (DailySummary, error) {
+	data, err := s.Conditions(ctx, lat, lon, SourceAuto)
+	if err != nil {
+		return DailySummary{}, err
+	}
+	summary := DailySummary{Data: data}
+	forecast, err := s.DailyForecast(ctx, lat, lon)
+	if err != nil {
+		slog.WarnContext(ctx, "daily summary without forecast", "error", err)
+		return summary, nil
+	}
+	for _, day := range forecast.Days {
+		if day.Date < date {
+			continue
+		}
+		if day.Date == date {
+			summary.Today = &day
+		}
+		summary.Week = append(summary.Week, day)
+	}
+	return summary, nil
+}
+
+// Lokasi laporan yang dinilai bersamaan, seperti batch
+const reportConcurrency = 4
+
+// --- Isi laporan mingguan: kondisi di lokasi langganan plus tiap lokasi tersimpan ---
+// Tiap lokasi membawa prakiraan ke depan dan observasi seminggu terakhir.
+type WeeklyReport struct {
+	Data      model.ConsolidatedResponse
+	Locations []model.ReportLocation
+}
+
+// Lokasi yang gagal diambil dilewati; laporan tetap dikirim untuk sisanya
+func (s *Weather) WeeklyReport(ctx context.Context, lat, lon float64, favorites []model.Favorite, date string) (WeeklyReport, error) {
+	data, err := s.Conditions(ctx, lat, lon, SourceAuto)
+	if err != nil {
+		return WeeklyReport{}, err
+	}
+	locations := make([]*model.ReportLocation, len(favorites))
+	g := new(errgroup.Group)
+	g.SetLimit(reportConcurrency)
+	for i, favorite := range favorites {
+		g.Go(func() error {
+			summary, err := s.DailySummary(ctx, favorite.Latitude, favorite.Longitude, date)
+			if err != nil {
+				slog.WarnContext(ctx, "weekly report without location", "location", favorite.Name, "error", err)
+				return nil
+			}
+			locations[i] = &model.ReportLocation{
+				Name:      favorite.Name,
+				Latitude:  favorite.Latitude,
+				Longitude: favorite.Longitude,
+				Weather:   summary.Data.Weather,
+				Days:      summary.Week,
+				BestDay:   bestHikingDay(summary.Week),
+				PastWeek:  s.pastWeek(ctx, favorite.Latitude, favorite.Longitude, date),
+			}
+			return nil
+		})
+	}
+	g.Wait()
+	report := WeeklyReport{Data: data}
+	for _, location := range locations {
+		if location != nil {
+			report.Locations = append(report.Locations, *location)
+		}
+	}
+	if len(report.Locations) == 0 {
+		return WeeklyReport{}, errors.New("no saved location could be fetched")
+	}
+	return report, nil
+}
+
+// Hari paling awal dengan indeks mendaki tertinggi
+func bestHikingDay(days []model.DailyForecast) *model.DailyForecast {
+	var best *model.DailyForecast
+	for i := range days {
+		if best == nil || days[i].HikingIndex > best.HikingIndex {
+			best = &days[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	day := *best
+	return &day
+}
+
+// --- Langganan notifikasi per pemilik (hash API key) ---
+type SubscriptionStore interface {
+	Create(ctx context.Context, owner string, sub model.Subscription) (model.Subscription, error)
+	List(ctx context.Context, owner string) ([]model.Subscription, error)
+	Delete(ctx context.Context, owner, id string) error
+	// Semua langganan dari semua pemilik, untuk scheduler
+	All(ctx context.Context) ([]model.Subscription, error)
+	// Catat hasil evaluasi; true kalau panggilan ini yang mengubah status
+	// triggered. Dengan beberapa instance hanya satu yang mendapat true,
+	// jadi webhook tidak terkirim dobel.
+	SetTriggered(ctx context.Context, id string, triggered bool, at time.Time) (bool, error)
+	// Catat ringkasan harian untuk tanggal lokal date; true kalau belum
+	// tercatat, jadi ringkasan hanya terkirim sekali sehari
+	MarkSummarySent(ctx context.Context, id, date string) (bool, error)
+	// Sama untuk laporan mingguan, dicatat per tanggal lokal pengiriman
+	MarkReportSent(ctx context.Context, id, date string) (bool, error)
+	// Daftarkan token FCM ke langganan milik owner; token yang sudah
+	// terdaftar tidak dihitung dua kali
+	AddDevice(ctx context.Context, owner, id, token string) error
+	RemoveDevice(ctx context.Context, owner, id, token string) error
+	// Hapus token dari semua langganan, setelah FCM bilang token tidak berlaku
+	ForgetDevice(ctx context.Context, token string) error
+}
+
+// --- Simpan di memori proses; dipakai kalau database tidak dikonfigurasi ---
+type memorySubscriptionStore struct {
+	limit int
+
+	mu    sync.RWMutex
+	byID  map[string]model.Subscription
+	owner map[string]string // id -> pemilik
+}
+
+// limit = jumlah langganan maksimum per pemilik
+func NewMemorySubscriptionStore(limit int) SubscriptionStore {
+	return &memorySubscriptionStore{limit: limit, byID: map[string]model.Subscription{}, owner: map[string]string{}}
+}
+
+func (m *memorySubscriptionStore) Create(_ context.Context, owner string, sub model.Subscription) (model.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, o := range m.owner {
+		if o == owner {
+			count++
+		}
+	}
+	if count >= m.limit {
+		return model.Subscription{}, fmt.Errorf("%w: at most %d per API key", ErrSubscriptionLimit, m.limit)
+	}
+	m.byID[sub.ID] = sub
+	m.owner[sub.ID] = owner
+	return sub, nil
+}
+
+func (m *memorySubscriptionStore) List(_ context.Context, owner string) ([]model.Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := []model.Subscription{}
+	for id, sub := range m.byID {
+		if m.owner[id] == owner {
+			list = append(list, sub)
+		}
+	}
+	slices.SortFunc(list, func(a, b model.Subscription) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return list, nil
+}
+
+func (m *memorySubscriptionStore) Delete(_ context.Context, owner, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if o, ok := m.owner[id]; !ok || o != owner {
+		return fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+	delete(m.byID, id)
+	delete(m.owner, id)
+	return nil
+}
+
+func (m *memorySubscriptionStore) All(context.Context) ([]model.Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]model.Subscription, 0, len(m.byID))
+	for id, sub := range m.byID {
+		sub.Owner = m.owner[id]
+		list = append(list, sub)
+	}
+	return list, nil
+}
+
+func (m *memorySubscriptionStore) SetTriggered(_ context.Context, id string, triggered bool, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.byID[id]
+	if !ok || sub.Triggered == triggered {
+		return false, nil
+	}
+	sub.Triggered = triggered
+	if triggered {
+		at = at.UTC()
+		sub.LastTriggeredAt = &at
+	}
+	m.byID[id] = sub
+	return true, nil
+}
+
+func (m *memorySubscriptionStore) MarkSummarySent(_ context.Context, id, date string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.byID[id]
+	if !ok || sub.LastSummaryDate == date {
+		return false, nil
+	}
+	sub.LastSummaryDate = date
+	m.byID[id] = sub
+	return true, nil
+}
+
+func (m *memorySubscriptionStore) MarkReportSent(_ context.Context, id, date string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.byID[id]
+	if !ok || sub.LastReportDate == date {
+		return false, nil
+	}
+	sub.LastReportDate = date
+	m.byID[id] = sub
+	return true, nil
+}
+
+func (m *memorySubscriptionStore) AddDevice(_ context.Context, owner, id, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.byID[id]
+	if !ok || m.owner[id] != owner {
+		return fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+	if slices.Contains(sub.DeviceTokens, token) {
+		return nil
+	}
+	if len(sub.DeviceTokens) >= MaxDevicesPerSubscription {
+		return fmt.Errorf("%w: at most %d per subscription", ErrDeviceLimit, MaxDevicesPerSubscription)
+	}
+	// Slice baru supaya salinan yang sudah dibaca scheduler tidak ikut berubah
+	sub.DeviceTokens = append(slices.Clip(sub.DeviceTokens), token)
+	m.byID[id] = sub
+	return nil
+}
+
+func (m *memorySubscriptionStore) RemoveDevice(_ context.Context, owner, id, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.byID[id]
+	if !ok || m.owner[id] != owner {
+		return fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+	i := slices.Index(sub.DeviceTokens, token)
+	if i < 0 {
+		return ErrDeviceNotFound
+	}
+	sub.DeviceTokens = slices.Delete(slices.Clone(sub.DeviceTokens), i, i+1)
+	m.byID[id] = sub
+	return nil
+}
+
+func (m *memorySubscriptionStore) ForgetDevice(_ context.Context, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, sub := range m.byID {
+		if i := slices.Index(sub.DeviceTokens, token); i >= 0 {
+			sub.DeviceTokens = slices.Delete(slices.Clone(sub.DeviceTokens), i, i+1)
+			m.byID[id] = sub
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+var ErrNoSeaData = errors.New("no tide data at this location")
+
+// --- Pasang surut & gelombang untuk titik pesisir ---
+func (s *Weather) Tides(ctx context.Context, lat, lon float64) (model.TidesResponse, error) {
+	loc := s.timezone(ctx, lat, lon)
+	hours, err := s.marineHours(ctx, lat, lon)
+	if err != nil {
+		return model.TidesResponse{}, err
+	}
+	sea, ok := indices.SeaState(hours, s.clock.Now())
+	if !ok {
+		return model.TidesResponse{}, fmt.Errorf("%w (%g, %g), try a point on the coast or at sea", ErrNoSeaData, lat, lon)
+	}
+	return model.TidesResponse{
+		SeaState: sea,
+		Meta:     model.ResponseMeta{Units: model.UnitsMetric, Timezone: loc.String()},
+	}, nil
+}
+
+// --- Kondisi laut untuk indeks aktivitas; opsional ---
+func (s *Weather) seaState(ctx context.Context, lat, lon float64) *model.SeaState {
+	hours, err := s.marineHours(ctx, lat, lon)
+	if err != nil {
+		degraded(ctx, "sea_state", "marine forecast unavailable, fishing, beach and surfing without tides and waves", err)
+		return nil
+	}
+	sea, ok := indices.SeaState(hours, s.clock.Now())
+	if !ok {
+		return nil
+	}
+	return &sea
+}
+
+func (s *Weather) marineHours(ctx context.Context, lat, lon float64) ([]model.MarineHour, error) {
+	return cachedFetch(ctx, s.cache, CacheTides, lat, lon, func() ([]model.MarineHour, error) {
+		return s.client.MarineForecast(ctx, lat, lon)
+	})
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// Titik sejauh ini dari puncak dianggap berada di gunung api itu (jalur
+// pendakian dan kawasan rawan bencana umumnya dalam radius ini)
+const volcanoRadiusKm = 10.0
+
+// --- Gunung api terdekat dari titik beserta statusnya; opsional ---
+// nil di luar Indonesia, jauh dari gunung api, atau saat MAGMA tidak bisa diakses.
+func (s *Weather) volcano(ctx context.Context, lat, lon float64) *model.Volcano {
+	if !providers.InIndonesia(lat, lon) {
+		return nil
+	}
+	volcanoes, ok := cacheGet[[]model.Volcano](ctx, s.cache, CacheVolcano, globalCacheLocation)
+	if !ok {
+		var err error
+		volcanoes, err = coalesce(ctx, flightKey(CacheVolcano, globalCacheLocation), func() ([]model.Volcano, error) {
+			return s.client.VolcanoStatuses(ctx)
+		})
+		if err != nil {
+			degraded(ctx, "volcano", "volcano status unavailable, hiking index without volcano cap", err)
+			return nil
+		}
+		cacheSet(ctx, s.cache, CacheVolcano, globalCacheLocation, volcanoes, CacheTTLs[CacheVolcano])
+	}
+
+	var nearest *model.Volcano
+	for _, v := range volcanoes {
+		v.Distance = model.Round1(providers.DistanceKm(lat, lon, v.Latitude, v.Longitude))
+		if v.Distance <= volcanoRadiusKm && (nearest == nil || v.Distance < nearest.Distance) {
+			v.Status = indices.VolcanoStatus(v.Level)
+			nearest = &v
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,615 @@
+// Package services menggabungkan data provider, cache, perhitungan astronomi
+// dan indeks menjadi satu response; handler HTTP dan GraphQL memakai ini.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/alerts"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/astro"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/clock"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// --- Pengaturan yang bisa berubah saat reload, dibaca per request ---
+type Settings struct {
+	DefaultTimezone string
+	// Umur cache response gabungan; 0 = selalu dihitung ulang
+	ConditionsTTL time.Duration
+	// FanoutPartial (default) atau FanoutFailFast
+	FanoutPolicy string
+	// Batas waktu tiap fetch paralel dalam satu response; 0 = tanpa batas sendiri
+	TaskTimeout time.Duration
+	// Ambang & penalti indeks; response di cache tetap memakai nilai lama sampai kedaluwarsa
+	Scoring indices.Scoring
+}
+
+type Weather struct {
+	client       *providers.Client
+	cache        Cache
+	clock        clock.Clock
+	settings     func() Settings
+	observations ObservationStore
+}
+
+// observations boleh nil kalau riwayat observasi tidak dipakai
+func NewWeather(client *providers.Client, cache Cache, clk clock.Clock, settings func() Settings, observations ObservationStore) *Weather {
+	return &Weather{client: client, cache: cache, clock: clk, settings: settings, observations: observations}
+}
+
+// --- Fungsi utama untuk ambil semua data, lewat cache response gabungan ---
+// Seperti jam matahari, entry menyimpan tanggalnya supaya lewat tengah malam
+// tidak ada response kemarin yang tersaji.
+type conditionsDay struct {
+	Date string                     `json:"date"`
+	Data model.ConsolidatedResponse `json:"data"`
+}
+
+// source dari ParseSource; tiap sumber punya entry cache sendiri.
+// Response yang tidak lengkap (meta.errors) tidak di-cache, supaya pulih
+// begitu upstream-nya kembali.
+func (s *Weather) Conditions(ctx context.Context, lat, lon float64, source string) (model.ConsolidatedResponse, error) {
+	ctx, sections := trackDegraded(ctx)
+	loc := s.timezone(ctx, lat, lon)
+	ttl := s.settings().ConditionsTTL
+	if ttl <= 0 {
+		data, err := s.conditions(ctx, lat, lon, loc, source)
+		if data.Meta.Errors = sections.list(); err == nil && data.Meta.Errors == nil {
+			s.recordObservation(ctx, lat, lon, data)
+		}
+		return data, err
+	}
+
+	date := s.today(loc).Format(time.DateOnly)
+	location := CacheLocation(lat, lon)
+	if source != SourceAuto {
+		location += "@" + source
+	}
+	if day, ok := cacheGet[conditionsDay](ctx, s.cache, CacheConditions, location); ok && day.Date == date {
+		return day.Data, nil
+	}
+	data, err := s.conditions(ctx, lat, lon, loc, source)
+	if err != nil {
+		return model.ConsolidatedResponse{}, err
+	}
+	if data.Meta.Errors = sections.list(); data.Meta.Errors == nil {
+		cacheSet(ctx, s.cache, CacheConditions, location, conditionsDay{Date: date, Data: data}, ttl)
+		s.recordObservation(ctx, lat, lon, data)
+	}
+	return data, nil
+}
+
+// Cuaca, AQI, jam matahari dan prakiraan diambil bersamaan lewat fanout;
+// tiap task punya timeout sendiri. Error pertama membatalkan sisanya.
+// Semua jam diformat di zona waktu lokasi (loc).
+func (s *Weather) conditions(ctx context.Context, lat, lon float64, loc *time.Location, source string) (model.ConsolidatedResponse, error) {
+	var weather model.WeatherData
+	var sun model.SunData
+	var forecast model.HourlyForecast
+	var place *model.Place
+	var kp *float64
+	var aloft *model.AloftHour
+	var official []model.AreaAlert
+	var seismic *bool
+	var volcano *model.Volcano
+	var light *model.LightPollution
+
+	f := s.fanout(ctx)
+	f.Go(func(ctx context.Context) (err error) {
+		weather, err = s.weather(ctx, lat, lon, source)
+		if err != nil {
+			return err
+		}
+		// Ketinggian baru diketahui dari cuaca; gempa hanya dicek di pegunungan
+		seismic = s.recentSeismicActivity(ctx, lat, lon, weather)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		sun = s.sun(ctx, lat, lon, loc)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		forecast = s.optionalForecast(ctx, lat, lon)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		place = s.place(ctx, lat, lon)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		kp = s.kpIndex(ctx)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		aloft = s.aloft(ctx, lat, lon)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		official = s.officialAlerts(ctx, lat, lon)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		volcano = s.volcano(ctx, lat, lon)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		light = s.lightPollution(ctx, lat, lon)
+		return nil
+	})
+	if err := f.Wait(); err != nil {
+		return model.ConsolidatedResponse{}, err
+	}
+	now := s.clock.Now()
+	weather.DailyUV = dailyUV(forecast, now)
+	weather.FogRisk = indices.FogRisk(weather, forecast, sun, now.In(loc))
+
+	moon := astro.MoonPhase(now)
+	calculated := indices.Calculate(weather, s.settings().Scoring)
+	hiking := calculated.HikingIndex
+	calculated.HikingIndex, calculated.HikingRecommendation = indices.VolcanoHiking(calculated.HikingIndex, calculated.HikingRecommendation, volcano)
+	calculated.IndexBreakdown = indices.VolcanoFactor(calculated.IndexBreakdown, hiking, calculated.HikingIndex, volcano)
+	calculated.StargazingIndex, calculated.StargazingRecommendation = indices.Stargazing(weather, moon, sun, light, now.In(loc))
+	calculated.PhotographyIndex, calculated.PhotographyRecommendation = indices.Photography(weather, sun, now.In(loc))
+	calculated.DroneIndex, calculated.DroneRecommendation = indices.Drone(weather, kp)
+	if aloft != nil {
+		calculated.AirSports = indices.AirSports(weather, *aloft, sun, now.In(loc))
+	}
+	var milkyWay *model.TimeWindow
+	if indices.MilkyWayVisible(light) {
+		milkyWay = astro.MilkyWayWindow(now.In(loc), lat, lon)
+	}
+
+	return model.ConsolidatedResponse{
+		Location:              place,
+		Weather:               weather,
+		Sun:                   sun,
+		Moon:                  moon,
+		MilkyWayWindow:        milkyWay,
+		LightPollution:        light,
+		Indices:               calculated,
+		Alerts:                alerts.ForLocation(lat, lon, official, weather, forecast, now.In(loc)),
+		RecentSeismicActivity: seismic,
+		Volcano:               volcano,
+		Meta:                  model.ResponseMeta{Units: model.UnitsMetric, Timezone: loc.String()},
+	}, nil
+}
+
+// --- Peringatan cuaca untuk satu lokasi: resmi (BMKG) plus dari prakiraan ---
+func (s *Weather) Alerts(ctx context.Context, lat, lon float64) (model.AlertsResponse, error) {
+	ctx, sections := trackDegraded(ctx)
+	loc := s.timezone(ctx, lat, lon)
+	var weather model.WeatherData
+	var forecast model.HourlyForecast
+	var official []model.AreaAlert
+
+	f := s.fanout(ctx)
+	f.Go(func(ctx context.Context) (err error) {
+		weather, err = s.weather(ctx, lat, lon, SourceAuto)
+		return err
+	})
+	f.Go(func(ctx context.Context) (err error) {
+		forecast, err = s.forecast(ctx, lat, lon)
+		return err
+	})
+	f.Go(func(ctx context.Context) error {
+		official = s.officialAlerts(ctx, lat, lon)
+		return nil
+	})
+	if err := f.Wait(); err != nil {
+		return model.AlertsResponse{}, err
+	}
+
+	return model.AlertsResponse{
+		Alerts: alerts.ForLocation(lat, lon, official, weather, forecast, s.clock.Now().In(loc)),
+		Meta:   model.ResponseMeta{Units: model.UnitsMetric, Timezone: loc.String(), Errors: sections.list()},
+	}, nil
+}
+
+// --- Indeks semua aktivitas: cuaca saat ini + prakiraan per jam ---
+func (s *Weather) Activities(ctx context.Context, lat, lon float64) (model.ActivitiesResponse, error) {
+	ctx, sections := trackDegraded(ctx)
+	var weather model.WeatherData
+	var forecast model.HourlyForecast
+	var waterTemp *float64
+	var sea *model.SeaState
+	var volcano *model.Volcano
+
+	f := s.fanout(ctx)
+	f.Go(func(ctx context.Context) (err error) {
+		weather, err = s.weather(ctx, lat, lon, SourceAuto)
+		return err
+	})
+	f.Go(func(ctx context.Context) (err error) {
+		forecast, err = s.forecast(ctx, lat, lon)
+		return err
+	})
+	f.Go(func(ctx context.Context) error {
+		waterTemp = s.seaTemperature(ctx, lat, lon)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		sea = s.seaState(ctx, lat, lon)
+		return nil
+	})
+	f.Go(func(ctx context.Context) error {
+		volcano = s.volcano(ctx, lat, lon)
+		return nil
+	})
+	if err := f.Wait(); err != nil {
+		return model.ActivitiesResponse{}, err
+	}
+
+	now := s.clock.Now()
+	return model.ActivitiesResponse{
+		Activities: indices.Activities(weather, forecast, astro.MoonPhase(now), waterTemp, sea, volcano, s.settings().Scoring, now),
+		Volcano:    volcano,
+		Meta:       model.ResponseMeta{Units: model.UnitsMetric, Errors: sections.list()},
+	}, nil
+}
+
+// --- Cari tempat berdasarkan nama; hasil paling relevan yang dipakai ---
+var ErrPlaceNotFound = errors.New("place not found")
+
+func (s *Weather) Geocode(ctx context.Context, name string) (model.Place, error) {
+	key := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if place, ok := cacheGet[model.Place](ctx, s.cache, CacheGeocode, key); ok {
+		return place, nil
+	}
+	places, err := s.client.SearchPlace(ctx, name)
+	if err != nil {
+		return model.Place{}, err
+	}
+	if len(places) == 0 {
+		return model.Place{}, fmt.Errorf("%w: %q", ErrPlaceNotFound, name)
+	}
+	cacheSet(ctx, s.cache, CacheGeocode, key, places[0], CacheTTLs[CacheGeocode])
+	return places[0], nil
+}
+
+// --- Nama tempat terdekat untuk label lokasi; opsional seperti UV harian ---
+// Lokasi tanpa nama (laut) juga di-cache supaya tidak ditanya ulang.
+type placeLookup struct {
+	Found bool        `json:"found"`
+	Place model.Place `json:"place"`
+}
+
+func (s *Weather) place(ctx context.Context, lat, lon float64) *model.Place {
+	lookup, err := cachedFetch(ctx, s.cache, CachePlace, lat, lon, func() (placeLookup, error) {
+		place, found, err := s.client.ReversePlace(ctx, lat, lon)
+		return placeLookup{Found: found, Place: place}, err
+	})
+	if err != nil {
+		degraded(ctx, "location", "reverse geocoding unavailable, omitting location", err)
+		return nil
+	}
+	if !lookup.Found {
+		return nil
+	}
+	return &lookup.Place
+}
+
+// --- Suhu permukaan laut untuk indeks pantai; opsional seperti nama tempat ---
+// nil untuk titik di darat juga di-cache.
+func (s *Weather) seaTemperature(ctx context.Context, lat, lon float64) *float64 {
+	temp, err := cachedFetch(ctx, s.cache, CacheMarine, lat, lon, func() (*float64, error) {
+		return s.client.SeaSurfaceTemperature(ctx, lat, lon)
+	})
+	if err != nil {
+		degraded(ctx, "water_temperature", "marine data unavailable, beach index without water temperature", err)
+		return nil
+	}
+	return temp
+}
+
+// --- Angin di ketinggian untuk jam ini; opsional, tanpanya air_sports tidak ada ---
+func (s *Weather) aloft(ctx context.Context, lat, lon float64) *model.AloftHour {
+	hours, err := cachedFetch(ctx, s.cache, CacheAloft, lat, lon, func() ([]model.AloftHour, error) {
+		return s.client.AloftForecast(ctx, lat, lon)
+	})
+	if err != nil {
+		degraded(ctx, "indices.air_sports", "upper-air forecast unavailable, omitting air sports", err)
+		return nil
+	}
+	hour, ok := indices.CurrentAloft(hours, s.clock.Now())
+	if !ok {
+		return nil
+	}
+	return &hour
+}
+
+// --- Peringatan dini BMKG; opsional dan hanya untuk titik di Indonesia ---
+// Feed berlaku untuk seluruh Indonesia, jadi di-cache sekali secara global.
+func (s *Weather) officialAlerts(ctx context.Context, lat, lon float64) []model.AreaAlert {
+	if !providers.InIndonesia(lat, lon) {
+		return nil
+	}
+	if warnings, ok := cacheGet[[]model.AreaAlert](ctx, s.cache, CacheAlerts, globalCacheLocation); ok {
+		return warnings
+	}
+	warnings, err := coalesce(ctx, flightKey(CacheAlerts, globalCacheLocation), func() ([]model.AreaAlert, error) {
+		return s.client.NowcastWarnings(ctx)
+	})
+	if err != nil {
+		degraded(ctx, "alerts", "BMKG warnings unavailable, alerts from forecast only", err)
+		return nil
+	}
+	cacheSet(ctx, s.cache, CacheAlerts, globalCacheLocation, warnings, CacheTTLs[CacheAlerts])
+	return warnings
+}
+
+// --- Indeks Kp global untuk indeks drone; opsional ---
+const globalCacheLocation = "global"
+
+func (s *Weather) kpIndex(ctx context.Context) *float64 {
+	if kp, ok := cacheGet[float64](ctx, s.cache, CacheSpaceWeather, globalCacheLocation); ok {
+		return &kp
+	}
+	kp, err := coalesce(ctx, flightKey(CacheSpaceWeather, globalCacheLocation), func() (float64, error) {
+		return s.client.PlanetaryKIndex(ctx)
+	})
+	if err != nil {
+		degraded(ctx, "indices.drone_index", "space weather unavailable, drone index without kp", err)
+		return nil
+	}
+	cacheSet(ctx, s.cache, CacheSpaceWeather, globalCacheLocation, kp, CacheTTLs[CacheSpaceWeather])
+	return &kp
+}
+
+// --- Prakiraan 7 hari dengan indeks mendaki per hari ---
+func (s *Weather) DailyForecast(ctx context.Context, lat, lon float64) (model.DailyForecastResponse, error) {
+	ctx, sections := trackDegraded(ctx)
+	days, err := cachedFetch(ctx, s.cache, CacheDaily, lat, lon, func() ([]model.DailyForecast, error) {
+		return s.client.DailyForecast(ctx, lat, lon)
+	})
+	if err != nil {
+		return model.DailyForecastResponse{}, err
+	}
+	// Status gunung api saat ini juga membatasi hari-hari berikutnya
+	volcano := s.volcano(ctx, lat, lon)
+	if err := s.failFast(ctx); err != nil {
+		return model.DailyForecastResponse{}, err
+	}
+	scoring := s.settings().Scoring.Hiking.Daily
+	for i := range days {
+		hiking := indices.HikingDay(days[i], scoring)
+		days[i].HikingIndex, days[i].HikingRecommendation = indices.VolcanoHiking(hiking.HikingIndex, hiking.HikingRecommendation, volcano)
+		days[i].IndexBreakdown = indices.VolcanoFactor(hiking.IndexBreakdown, hiking.HikingIndex, days[i].HikingIndex, volcano)
+	}
+	return model.DailyForecastResponse{Days: days, Volcano: volcano, Meta: model.ResponseMeta{Units: model.UnitsMetric, Errors: sections.list()}}, nil
+}
+
+// --- Waktu aman di bawah matahari hari ini untuk satu tipe kulit ---
+func (s *Weather) SunExposure(ctx context.Context, lat, lon float64, skinType int) (model.SunExposureResponse, error) {
+	forecast, err := s.forecast(ctx, lat, lon)
+	if err != nil {
+		return model.SunExposureResponse{}, err
+	}
+	return indices.SunExposure(forecast, skinType, s.clock.Now()), nil
+}
+
+// --- Prakiraan per jam (metrik) untuk beberapa hari ke depan ---
+func (s *Weather) HourlyForecast(ctx context.Context, lat, lon float64) (model.HourlyForecast, error) {
+	return s.forecast(ctx, lat, lon)
+}
+
+// --- Prakiraan per jam lewat cache ---
+func (s *Weather) forecast(ctx context.Context, lat, lon float64) (model.HourlyForecast, error) {
+	return cachedFetch(ctx, s.cache, CacheForecast, lat, lon, func() (model.HourlyForecast, error) {
+		return s.client.HourlyForecast(ctx, lat, lon)
+	})
+}
+
+// --- Prakiraan per jam untuk UV harian & risiko kabut; opsional ---
+func (s *Weather) optionalForecast(ctx context.Context, lat, lon float64) model.HourlyForecast {
+	forecast, err := s.forecast(ctx, lat, lon)
+	if err != nil {
+		degraded(ctx, "weather.daily_uv", "hourly forecast unavailable, omitting daily UV and forecast fog risk", err)
+		return nil
+	}
+	return forecast
+}
+
+// --- UV maksimum hari ini & besok; kosong kalau prakiraan tidak ada ---
+const highUVThreshold = 8
+
+func dailyUV(forecast model.HourlyForecast, now time.Time) []model.DailyUV {
+	if len(forecast) == 0 {
+		return nil
+	}
+
+	// Tanggal mengikuti zona lokasi (dari provider), bukan zona server
+	today := now.In(forecast[0].Time.Location())
+	days := []model.DailyUV{
+		{Date: today.Format(time.DateOnly), HighUVHours: []string{}},
+		{Date: today.AddDate(0, 0, 1).Format(time.DateOnly), HighUVHours: []string{}},
+	}
+	for _, hour := range forecast {
+		for i := range days {
+			if hour.Time.Format(time.DateOnly) != days[i].Date {
+				continue
+			}
+			days[i].UVMax = max(days[i].UVMax, hour.UVIndex)
+			if hour.UVIndex > highUVThreshold {
+				days[i].HighUVHours = append(days[i].HighUVHours, hour.Time.Format("15:04"))
+			}
+		}
+	}
+	return days
+}
+
+// --- Cuaca + AQI bersamaan, masing-masing lewat cache, plus kategori kenyamanan ---
+// Dengan sumber BMKG, field yang dimiliki BMKG menimpa data Open-Meteo.
+func (s *Weather) weather(ctx context.Context, lat, lon float64, source string) (model.WeatherData, error) {
+	var weather model.WeatherData
+	var air []model.AirQualityHour
+	var bmkg *model.BMKGForecastStep
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		weather, err = cachedFetch(gctx, s.cache, CacheWeather, lat, lon, func() (model.WeatherData, error) {
+			if recent, ok := s.recentWeather(gctx, lat, lon); ok {
+				return recent, nil
+			}
+			return s.client.CurrentWeather(gctx, lat, lon)
+		})
+		return err
+	})
+	g.Go(func() error {
+		air = s.airQuality(gctx, lat, lon)
+		return nil
+	})
+	g.Go(func() (err error) {
+		bmkg, err = s.bmkgStep(gctx, lat, lon, source)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return model.WeatherData{}, err
+	}
+
+	weather.Source = SourceOpenMeteo
+	if bmkg != nil {
+		applyBMKG(&weather, *bmkg)
+	}
+
+	now := s.clock.Now()
+	current, ok := indices.CurrentAirQuality(air, now)
+	if !ok && len(air) > 0 {
+		slog.WarnContext(ctx, "air quality forecast has no entry for the current hour, defaulting AQI to 0")
+	}
+	weather.AQI = current.AQI
+	weather.AirQuality = indices.AirQuality(current)
+	weather.Pollen = indices.Allergy(current.Pollen)
+	weather.AQIForecast = indices.AQIForecast(air, now)
+	weather.Humidex = indices.Humidex(weather.Temperature, weather.Humidity)
+	weather.Comfort = indices.Comfort(weather.Humidex)
+	if change := s.pressureChange(ctx, lat, lon, weather.Pressure); change != nil {
+		weather.PressureChange, weather.PressureTrend = change, indices.PressureTendency(*change)
+	}
+	return weather, nil
+}
+
+// --- Tendensi tekanan 3 jam dari riwayat pembacaan per lokasi di cache ---
+// Pembacaan baru disimpan paling sering sekali per pressureSampleInterval.
+// Selama riwayat belum cukup tua, tendensi diambil dari prakiraan per jam.
+const pressureSampleInterval = 10 * time.Minute
+
+func (s *Weather) pressureChange(ctx context.Context, lat, lon, current float64) *float64 {
+	if current == 0 {
+		return nil
+	}
+	now := s.clock.Now()
+	history := s.recordPressure(ctx, CacheLocation(lat, lon), current, now)
+	change, ok := indices.PressureHistoryTrend(history, current, now)
+	if !ok {
+		forecast, err := s.forecast(ctx, lat, lon)
+		if err != nil {
+			return nil
+		}
+		if change, ok = indices.PressureTrend(forecast, now); !ok {
+			return nil
+		}
+	}
+	return &change
+}
+
+// Baca-ubah-tulis riwayat tidak boleh tumpang tindih untuk lokasi yang sama,
+// kalau tidak pembacaan dari request yang bersamaan saling menimpa. Request
+// yang datang saat riwayat sedang diperbarui ikut memakai hasilnya.
+var pressureUpdates singleflight.Group
+
+// Riwayat sebelum pembacaan ini ditambahkan
+func (s *Weather) recordPressure(ctx context.Context, location string, current float64, now time.Time) []model.PressureReading {
+	v, _, _ := pressureUpdates.Do(location, func() (any, error) {
+		history, _ := cacheGet[[]model.PressureReading](ctx, s.cache, CachePressure, location)
+		ttl := CacheTTLs[CachePressure]
+		kept := make([]model.PressureReading, 0, len(history)+1)
+		for _, reading := range history {
+			if now.Sub(reading.At) <= ttl {
+				kept = append(kept, reading)
+			}
+		}
+		if len(kept) == 0 || now.Sub(kept[len(kept)-1].At) >= pressureSampleInterval {
+			cacheSet(ctx, s.cache, CachePressure, location, append(kept, model.PressureReading{At: now, Pressure: current}), ttl)
+		}
+		return history, nil
+	})
+	return v.([]model.PressureReading)
+}
+
+// AQI opsional: kalau gagal, AQI 0 dan bagian ini dilaporkan di meta.errors
+func (s *Weather) airQuality(ctx context.Context, lat, lon float64) []model.AirQualityHour {
+	air, err := cachedFetch(ctx, s.cache, CacheAirQuality, lat, lon, func() ([]model.AirQualityHour, error) {
+		return s.client.AirQualityForecast(ctx, lat, lon)
+	})
+	if err != nil {
+		degraded(ctx, "weather.aqi", "AQI unavailable, defaulting to 0", err)
+	}
+	return air
+}
+
+// --- Jam matahari (fix golden hour) hari ini menurut clock, di zona waktu default ---
+// Entry cache menyimpan tanggalnya; lewat tengah malam langsung diambil ulang
+// tanpa menunggu TTL habis. Kalau sunrise-sunset.org gagal, jam matahari
+// dihitung lokal (tidak di-cache), jadi bagian ini tidak pernah menggagalkan request.
+type sunDay struct {
+	Date string        `json:"date"`
+	Data model.SunData `json:"data"`
+}
+
+func (s *Weather) sun(ctx context.Context, lat, lon float64, loc *time.Location) model.SunData {
+	today := s.today(loc)
+	date := today.Format(time.DateOnly)
+
+	location := CacheLocation(lat, lon)
+	if day, ok := cacheGet[sunDay](ctx, s.cache, CacheSun, location); ok && day.Date == date {
+		// Posisi matahari berubah tiap menit, jadi tidak ikut di-cache
+		day.Data.Position = astro.SunPosition(today, lat, lon)
+		return day.Data
+	}
+	var data model.SunData
+	times, err := coalesce(ctx, flightKey(CacheSun, location+"|"+date), func() ([2]time.Time, error) {
+		sunrise, sunset, err := s.client.SunTimes(ctx, lat, lon, today)
+		return [2]time.Time{sunrise, sunset}, err
+	})
+	if err != nil {
+		degraded(ctx, "sun", "sun times unavailable, computing locally", err)
+		data = astro.LocalSunTimes(today, lat, lon)
+	} else {
+		data = astro.SunTimes(times[0], times[1], lat, lon, today.Location())
+		cacheSet(ctx, s.cache, CacheSun, location, sunDay{Date: date, Data: data}, CacheTTLs[CacheSun])
+	}
+	data.Position = astro.SunPosition(today, lat, lon)
+	return data
+}
+
+// --- Waktu sekarang menurut clock, di zona waktu lokasi ---
+func (s *Weather) today(loc *time.Location) time.Time {
+	return s.clock.Now().In(loc)
+}
+
+// --- Zona waktu lokasi dari Open-Meteo; zona default kalau tidak diketahui ---
+// Seperti nama tempat, gagal di sini tidak menggagalkan request.
+func (s *Weather) timezone(ctx context.Context, lat, lon float64) *time.Location {
+	name, err := cachedFetch(ctx, s.cache, CacheTimezone, lat, lon, func() (string, error) {
+		return s.client.Timezone(ctx, lat, lon)
+	})
+	if err != nil {
+		degraded(ctx, "meta.timezone", "timezone lookup unavailable, using default timezone", err)
+	} else if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return loc
+	} else {
+		slog.WarnContext(ctx, "unknown timezone from provider, using default timezone", "timezone", name)
+	}
+	loc, _ := time.LoadLocation(s.settings().DefaultTimezone)
+	return loc
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+var upstreamProviderDisabled = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: metricsNamespace,
+	Name:      "upstream_provider_disabled",
+	Help:      "1 while an upstream provider is disabled through the admin kill switch.",
+}, []string{"provider"})
+
+// --- Admin: GET /admin/providers, POST /admin/providers/:name/{disable,enable} ---
+// killSwitch sama dengan yang dipakai client provider.
+func registerProviderRoutes(g *gin.RouterGroup, killSwitch *providers.KillSwitch) {
+	g.GET("", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"providers": killSwitch.List()})
+	})
+
+	knownProvider := func(c *gin.Context) {
+		if !providers.IsKnown(c.Param("name")) {
+			handlers.AbortWithError(c, http.StatusNotFound, handlers.APIError{
+				Code: handlers.ErrCodeNotFound, Message: "unknown provider " + c.Param("name"),
+			})
+			return
+		}
+		c.Next()
+	}
+
+	g.POST("/:name/disable", knownProvider, func(c *gin.Context) {
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&body); err != nil {
+				handlers.AbortBadRequest(c, handlers.ErrCodeInvalidRequest, errors.New("body must be {\"reason\": string}"))
+				return
+			}
+		}
+		state := killSwitch.Disable(c.Param("name"), body.Reason)
+		upstreamProviderDisabled.WithLabelValues(state.Provider).Set(1)
+		c.JSON(http.StatusOK, state)
+	})
+
+	g.POST("/:name/enable", knownProvider, func(c *gin.Context) {
+		state := killSwitch.Enable(c.Param("name"))
+		upstreamProviderDisabled.WithLabelValues(state.Provider).Set(0)
+		c.JSON(http.StatusOK, state)
+	})
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// --- Hook yang dijalankan saat shutdown (scheduler, queue, koneksi) ---
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// --- Lifecycle proses: status draining + urutan shutdown komponen background ---
+type lifecycle struct {
+	draining atomic.Bool
+
+	mu    sync.Mutex
+	hooks []shutdownHook
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{}
+}
+
+// --- Daftarkan hook; dijalankan terbalik dari urutan pendaftaran ---
+func (l *lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// --- Mulai drain: /readyz langsung 503 supaya load balancer berhenti kirim traffic ---
+func (l *lifecycle) BeginDrain() {
+	l.draining.Store(true)
+}
+
+func (l *lifecycle) Draining() bool {
+	return l.draining.Load()
+}
+
+func (l *lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := append([]shutdownHook(nil), l.hooks...)
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hooks[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+)
+
+const (
+	ErrCodeOverloaded = "overloaded"
+)
+
+// --- Batas request untuk melindungi service saat lonjakan traffic ---
+type LimitsConfig struct {
+	// Ukuran body maksimum (byte); 0 = tanpa batas
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+
+	// Jumlah request yang boleh diproses bersamaan; 0 = tanpa batas.
+	// Probe dan /metrics tidak dihitung supaya tetap bisa menjawab.
+	MaxInFlight int64 `yaml:"max_in_flight"`
+
+	// Jumlah titik maksimum per request batch; 0 = tanpa batas
+	MaxBatchPoints int64 `yaml:"max_batch_points"`
+
+	// Titik batch yang diproses bersamaan per request (worker pool)
+	BatchConcurrency int64 `yaml:"batch_concurrency"`
+
+	// Deadline per kelas endpoint; upstream call ikut dibatalkan lewat context
+	Timeouts RouteTimeouts `yaml:"timeouts"`
+}
+
+type RouteTimeouts struct {
+	Default time.Duration `yaml:"default"`
+	Batch   time.Duration `yaml:"batch"`
+}
+
+// --- Kelas endpoint untuk deadline; batch dapat waktu lebih lama ---
+const (
+	routeClassDefault = ""
+	routeClassBatch   = "batch"
+)
+
+func defaultLimitsConfig() LimitsConfig {
+	return LimitsConfig{
+		MaxBodyBytes:     1 << 20,
+		MaxInFlight:      512,
+		MaxBatchPoints:   25,
+		BatchConcurrency: 4,
+		Timeouts: RouteTimeouts{
+			Default: 15 * time.Second,
+			Batch:   60 * time.Second,
+		},
+	}
+}
+
+func (l LimitsConfig) validate() error {
+	if l.MaxBodyBytes < 0 || l.MaxInFlight < 0 || l.MaxBatchPoints < 0 {
+		return errors.New("limits: max_body_bytes, max_in_flight and max_batch_points must not be negative")
+	}
+	if l.BatchConcurrency < 1 {
+		return errors.New("limits.batch_concurrency must be at least 1")
+	}
+	if l.Timeouts.Default < 0 || l.Timeouts.Batch < 0 {
+		return errors.New("limits.timeouts must not be negative")
+	}
+	return nil
+}
+
+func (t RouteTimeouts) forClass(class string) time.Duration {
+	if class == routeClassBatch {
+		return t.Batch
+	}
+	return t.Default
+}
+
+var httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: metricsNamespace,
+	Name:      "http_in_flight_requests",
+	Help:      "Requests currently being served (excluding probes and metrics).",
+})
+
+// --- Middleware: batasi body dan jumlah request bersamaan ---
+// Dibaca dari config aktif per request supaya ikut hot reload.
+func requestLimits(config func() *Config) gin.HandlerFunc {
+	var inFlight atomic.Int64
+	unlimited := map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+	return func(c *gin.Context) {
+		limits := config().Limits
+		if limits.MaxBodyBytes > 0 && c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxBodyBytes)
+		}
+
+		if unlimited[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		if limits.MaxInFlight > 0 && n > limits.MaxInFlight {
+			c.Header("Retry-After", "1")
+			handlers.AbortWithError(c, http.StatusServiceUnavailable, handlers.APIError{
+				Code:      ErrCodeOverloaded,
+				Message:   "server is busy, try again shortly",
+				Retryable: true,
+			})
+			return
+		}
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+		c.Next()
+	}
+}
+
+// --- Middleware: deadline request sesuai kelas endpoint ---
+func requestTimeout(config func() *Config, class string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := config().Limits.Timeouts.forClass(class)
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+type contextKey string
+
+const (
+	requestIDKey     contextKey = "request_id"
+	upstreamTraceKey contextKey = "upstream_trace"
+)
+
+// --- Logger JSON; request_id dari context otomatis ditempel ke setiap log ---
+func newLogger(level slog.Leveler) *slog.Logger {
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	return slog.New(contextHandler{handler})
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := requestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// --- Catatan panggilan upstream selama satu request, untuk access log ---
+type upstreamCall struct {
+	Provider   string  `json:"provider"`
+	Outcome    string  `json:"outcome"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+type upstreamTrace struct {
+	mu    sync.Mutex
+	calls []upstreamCall
+}
+
+func (t *upstreamTrace) add(call upstreamCall) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls = append(t.calls, call)
+}
+
+func (t *upstreamTrace) snapshot() []upstreamCall {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]upstreamCall(nil), t.calls...)
+}
+
+func recordUpstreamCall(ctx context.Context, call upstreamCall) {
+	if trace, ok := ctx.Value(upstreamTraceKey).(*upstreamTrace); ok {
+		trace.add(call)
+	}
+}
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// --- Middleware: request ID (terima dari client kalau valid) + access log terstruktur ---
+func requestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(requestIDHeader, id)
+
+		trace := &upstreamTrace{}
+		ctx := context.WithValue(c.Request.Context(), requestIDKey, id)
+		ctx = context.WithValue(ctx, upstreamTraceKey, trace)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if calls := trace.snapshot(); len(calls) > 0 {
+			attrs = append(attrs, slog.Any("upstream", calls))
+		}
+		if apiErr, ok := c.Get(handlers.ErrorKey); ok {
+			attrs = append(attrs, slog.Any("error", apiErr))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(ctx, level, "request", attrs...)
+	}
+}
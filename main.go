@@ -1,314 +1,228 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
 	"net/http"
-	"sync"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
-	"math"
-	"github.com/gin-gonic/gin"
-)
-
-// --- Struct untuk data cuaca, matahari, bulan, dan indeks ---
-type WeatherData struct {
-	Temperature   float64 `json:"temperature"`
-	Precipitation float64 `json:"precipitation"`
-	CloudCover    int     `json:"cloud_cover"`
-	UVIndex       float64 `json:"uv_index"`
-	AQI           int     `json:"aqi"`
-}
-
-type SunData struct {
-	Sunrise    string `json:"sunrise"`
-	Sunset     string `json:"sunset"`
-	GoldenHour string `json:"golden_hour_end"`
-}
+	// Database zona waktu ikut di binary; zona lokasi bisa apa saja, image minimal belum tentu punya
+	_ "time/tzdata"
 
-type MoonData struct {
-	PhaseName    string  `json:"phase_name"`
-	Illumination float64 `json:"illumination"`
-}
+	"github.com/gin-gonic/gin"
 
-type CalculatedIndices struct {
-	HikingIndex          float64 `json:"hiking_index"`
-	HikingRecommendation string  `json:"hiking_recommendation"`
-}
+	"github.com/AntonTian/TitikKondisi-Backend/internal/cache"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/clock"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/repository"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
 
-type ConsolidatedResponse struct {
-	Weather WeatherData       `json:"weather"`
-	Sun     SunData           `json:"sun"`
-	Moon    MoonData          `json:"moon"`
-	Indices CalculatedIndices `json:"indices"`
+// Batas indeks kustom tersimpan per API key; disimpan di memori, jadi dibatasi
+const maxCustomIndicesPerKey = 20
+
+// Batas lokasi favorit per pengguna, sama dengan default titik per batch
+// supaya dashboard tetap satu batch yang wajar
+const maxFavoritesPerUser = 25
+
+// Batas langganan notifikasi per API key; tiap langganan dinilai tiap interval
+const maxSubscriptionsPerKey = 50
+
+// --- Komponen yang dirakit di main lalu dipakai saat registrasi route ---
+type app struct {
+	lc       *lifecycle
+	reloader *configReloader
+	// Getter config aktif (reloader.Current), untuk yang ikut hot reload
+	config func() *Config
+	client *providers.Client
+	// Kill switch yang sama dengan di client, diubah lewat /admin/providers
+	killSwitch *providers.KillSwitch
+	// Riwayat panggilan client plus state circuit-nya, untuk /status/providers
+	providerStats *providerStatsStore
+	// Cache hasil upstream, juga dibaca /admin/cache
+	cache cache.Store
+	// Feature flag dari config plus override /admin/flags
+	flags *flagStore
+	// Bucket rate limit per IP, bersama untuk route v1 & alias lamanya
+	rateLimits *ipRateLimiter
+	weather    *services.Weather
+	// nil kalau database.url kosong
+	db *repository.DB
+	// Indeks kustom tersimpan per API key; di database kalau ada, selain itu di memori
+	customIndices services.CustomIndexStore
+	// Lokasi favorit per pengguna; di database kalau ada, selain itu di memori
+	favorites services.FavoriteStore
+	// Langganan notifikasi kondisi, dinilai scheduler di background
+	subscriptions services.SubscriptionStore
+	// API key terbitan admin & penghitung kuota hariannya
+	apiKeys *apiKeyLookup
+	quota   quotaCounter
+	// Idempotency-Key, di Redis kalau cache.backend redis
+	idempotency idempotencyStore
 }
 
 func main() {
-	r := gin.Default()
-
-	// --- Dua endpoint: GET dan POST ---
-	r.GET("/weather/:lat/:lon", getWeatherByParams)
-	r.POST("/weather", getWeatherByJSON)
-
-	fmt.Println("Server berjalan di http://localhost:8080")
-	r.Run(":8080")
-}
-
-// --- Handler untuk GET (pakai URL params) ---
-func getWeatherByParams(c *gin.Context) {
-	lat := c.Param("lat")
-	lon := c.Param("lon")
-	response, err := getConsolidatedData(lat, lon)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, response)
-}
-
-// --- Handler untuk POST (pakai JSON body) ---
-func getWeatherByJSON(c *gin.Context) {
-	var input struct {
-		Lat string `json:"lat"`
-		Lon string `json:"lon"`
-	}
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-		return
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to optional YAML config file")
+	mock := flag.Bool("mock", false, "serve deterministic canned provider data (same as PROVIDER_MODE=mock)")
+	flag.Parse()
+	if *mock {
+		// Lewat env supaya tetap berlaku setelah reload
+		os.Setenv("PROVIDER_MODE", providers.ModeMock)
 	}
 
-	response, err := getConsolidatedData(input.Lat, input.Lon)
+	cfg, err := loadConfig(*configPath)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, response)
-}
-
-// --- Fungsi utama untuk ambil semua data ---
-func getConsolidatedData(lat, lon string) (ConsolidatedResponse, error) {
-	var weather WeatherData
-	var sun SunData
-	var wg sync.WaitGroup
-	var err1, err2 error
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		weather, err1 = fetchWeatherData(lat, lon)
-	}()
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		sun, err2 = fetchSunData(lat, lon)
-	}()
-
-	wg.Wait()
-
-	if err1 != nil {
-		return ConsolidatedResponse{}, err1
-	}
-	if err2 != nil {
-		return ConsolidatedResponse{}, err2
-	}
-
-	moon := calculateMoonPhase()
-	indices := calculateIndices(weather)
-
-	return ConsolidatedResponse{
-		Weather: weather,
-		Sun:     sun,
-		Moon:    moon,
-		Indices: indices,
-	}, nil
-}
-
-// --- API Call ke Open-Meteo ---
-func fetchWeatherData(lat, lon string) (WeatherData, error) {
-	// --- Fetch main weather ---
-	weatherURL := fmt.Sprintf(
-		"https://api.open-meteo.com/v1/forecast?latitude=%s&longitude=%s&current=temperature_2m,precipitation,cloud_cover,uv_index&timezone=auto",
-		lat, lon,
-	)
-
-	resp1, err := http.Get(weatherURL)
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	level, _ := parseLogLevel(cfg.Logging.Level)
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(level)
+	logger := newLogger(logLevel)
+	slog.SetDefault(logger)
+	reloader := newConfigReloader(*configPath, logLevel, cfg)
+	config := reloader.Current
+
+	lc := newLifecycle()
+	reporter := newErrorReporter(cfg.Sentry)
+	lc.OnShutdown("error reporter", flushReporter(reporter))
+	upstreamCache, err := newCacheStore(cfg.Cache, lc, config)
 	if err != nil {
-		return WeatherData{}, fmt.Errorf("weather fetch error: %v", err)
-	}
-	defer resp1.Body.Close()
-
-	if resp1.StatusCode != 200 {
-		return WeatherData{}, fmt.Errorf("weather bad response: %s", resp1.Status)
-	}
-
-	var weatherResult struct {
-		Current struct {
-			Temperature   float64 `json:"temperature_2m"`
-			Precipitation float64 `json:"precipitation"`
-			CloudCover    int     `json:"cloud_cover"`
-			UVIndex       float64 `json:"uv_index"`
-		} `json:"current"`
-	}
-
-	if err := json.NewDecoder(resp1.Body).Decode(&weatherResult); err != nil {
-		return WeatherData{}, fmt.Errorf("weather JSON decode error: %v", err)
-	}
-
-	// --- Fetch AQI separately ---
-	aqiURL := fmt.Sprintf(
-		"https://air-quality-api.open-meteo.com/v1/air-quality?latitude=%s&longitude=%s&hourly=european_aqi&timezone=auto",
-		lat, lon,
-	)
-	resp2, err := http.Get(aqiURL)
+		slog.Error("failed to set up cache", "error", err)
+		os.Exit(1)
+	}
+	rateLimits := newIPRateLimiter(config)
+	rateLimits.StartJanitor(lc, time.Minute)
+	if cfg.Providers.Mode == providers.ModeMock {
+		slog.Warn("provider mode is mock: responses use canned data, no upstream calls are made")
+	}
+
+	r := gin.New()
+	r.Use(requestLogger(logger), recoveryMiddleware(reporter), metricsMiddleware(), corsMiddleware(config), requestLimits(config))
+	r.HandleMethodNotAllowed = true
+	if err := r.SetTrustedProxies(cfg.RateLimit.TrustedProxies); err != nil {
+		slog.Error("invalid trusted proxies", "error", err)
+		os.Exit(1)
+	}
+	r.NoRoute(handlers.NoRoute)
+	r.NoMethod(handlers.NoMethod)
+
+	// --- Client provider & service; config dibaca per panggilan supaya ikut reload ---
+	killSwitch := providers.NewKillSwitch()
+	circuits := providers.NewCircuitBreaker(observeCircuit)
+	providerStats := newProviderStatsStore(circuits, killSwitch)
+	client := providers.New(providers.Options{
+		Config:     func() providers.Config { return config().Providers },
+		Timeout:    func() time.Duration { return config().Upstream.Timeout },
+		Retry:      func() providers.RetryPolicy { return config().Upstream.Retry },
+		KillSwitch: killSwitch,
+		Circuits:   circuits,
+		Circuit:    func() providers.CircuitPolicy { return config().Upstream.Circuit },
+		Observe:    providerStats.observeUpstream,
+		Retried:    observeRetry,
+	})
+	db, err := openDatabase(cfg.Database, lc)
 	if err != nil {
-		return WeatherData{}, fmt.Errorf("aqi fetch error: %v", err)
+		slog.Error("failed to set up database", "error", err)
+		os.Exit(1)
+	}
+	var observations services.ObservationStore
+	if db != nil {
+		observations = db
+	}
+	clk := clock.System{}
+	weather := services.NewWeather(client, upstreamCache, clk, func() services.Settings {
+		cfg := config()
+		return services.Settings{
+			DefaultTimezone: cfg.DefaultTimezone,
+			ConditionsTTL:   cfg.Cache.ConditionsTTL,
+			FanoutPolicy:    cfg.Upstream.FanoutPolicy,
+			TaskTimeout:     cfg.Upstream.TaskTimeout,
+			Scoring:         cfg.Scoring,
+		}
+	}, observations)
+
+	deps := app{
+		lc:            lc,
+		reloader:      reloader,
+		config:        config,
+		client:        client,
+		killSwitch:    killSwitch,
+		providerStats: providerStats,
+		cache:         upstreamCache,
+		flags:         newFlagStore(config),
+		rateLimits:    rateLimits,
+		weather:       weather,
+		db:            db,
+		customIndices: services.NewMemoryCustomIndexStore(maxCustomIndicesPerKey),
+		favorites:     services.NewMemoryFavoriteStore(maxFavoritesPerUser),
+		subscriptions: services.NewMemorySubscriptionStore(maxSubscriptionsPerKey),
+		apiKeys:       newAPIKeyLookup(services.NewMemoryAPIKeyStore()),
+		quota:         newQuotaCounter(cfg.Cache, upstreamCache),
+		idempotency:   newIdempotencyStore(cfg.Cache, upstreamCache, lc),
+	}
+	if db != nil {
+		deps.customIndices = db.CustomIndices(maxCustomIndicesPerKey)
+		deps.favorites = db.Favorites(maxFavoritesPerUser)
+		deps.apiKeys = newAPIKeyLookup(db.APIKeys())
+		deps.subscriptions = db.Subscriptions(maxSubscriptionsPerKey)
+	}
+	deps.apiKeys.StartJanitor(lc, time.Minute)
+	startSubscriptionScheduler(lc, config, clk, weather, deps.subscriptions, deps.favorites)
+	if err := registerRoutes(r, cfg, deps); err != nil {
+		slog.Error("failed to register routes", "error", err)
+		os.Exit(1)
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.Server.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	reloader.WatchSignals(ctx)
+
+	serve, redirect := configureTLS(srv, cfg.Server.TLS)
+	if redirect != nil {
+		startRedirectServer(redirect, lc)
 	}
-	defer resp2.Body.Close()
-
-	var aqiResult struct {
-		Hourly struct {
-			AQI []int `json:"european_aqi"`
-		} `json:"hourly"`
-	}
-
-	if err := json.NewDecoder(resp2.Body).Decode(&aqiResult); err != nil {
-		fmt.Println("AQI decode error:", err)
-	}
-
-	aqi := 0
-	if len(aqiResult.Hourly.AQI) > 0 {
-		// Use latest value (last in slice)
-		aqi = aqiResult.Hourly.AQI[len(aqiResult.Hourly.AQI)-1]
-	}
-
-	return WeatherData{
-		Temperature:   weatherResult.Current.Temperature,
-		Precipitation: weatherResult.Current.Precipitation,
-		CloudCover:    weatherResult.Current.CloudCover,
-		UVIndex:       weatherResult.Current.UVIndex,
-		AQI:           aqi,
-	}, nil
-}
 
-// --- API Call ke Sunrise-Sunset (fix golden hour) ---
-func fetchSunData(lat, lon string) (SunData, error) {
-	url := fmt.Sprintf("https://api.sunrise-sunset.org/json?lat=%s&lng=%s&formatted=0", lat, lon)
-	resp, err := http.Get(url)
-	if err != nil {
-		return SunData{}, err
-	}
-	defer resp.Body.Close()
-
-	var result struct {
-		Results struct {
-			Sunrise string `json:"sunrise"`
-			Sunset  string `json:"sunset"`
-		} `json:"results"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return SunData{}, err
-	}
-
-	sunriseUTC, err1 := time.Parse(time.RFC3339, result.Results.Sunrise)
-	sunsetUTC, err2 := time.Parse(time.RFC3339, result.Results.Sunset)
-	if err1 != nil || err2 != nil {
-		return SunData{}, fmt.Errorf("invalid time format")
-	}
-
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	sunriseLocal := sunriseUTC.In(loc)
-	sunsetLocal := sunsetUTC.In(loc)
-	goldenHourEnd := sunriseLocal.Add(time.Hour)
-
-	return SunData{
-		Sunrise:    sunriseLocal.Format("15:04"),
-		Sunset:     sunsetLocal.Format("15:04"),
-		GoldenHour: goldenHourEnd.Format("15:04"),
-	}, nil
-}
-
-// --- Calculate Moon Phase ---
-func calculateMoonPhase() MoonData {
-	now := time.Now().UTC()
-	newMoon := time.Date(2000, 1, 6, 18, 14, 0, 0, time.UTC)
-	days := now.Sub(newMoon).Hours() / 24
-	phase := math.Mod(days, 29.53058867) / 29.53058867
-
-	var phaseName string
-	switch {
-	case phase < 0.03 || phase > 0.97:
-		phaseName = "Bulan Baru"
-	case phase < 0.25:
-		phaseName = "Sabit Awal"
-	case phase < 0.27:
-		phaseName = "Kuartal Pertama"
-	case phase < 0.50:
-		phaseName = "Cembung Awal"
-	case phase < 0.53:
-		phaseName = "Bulan Purnama"
-	case phase < 0.75:
-		phaseName = "Cembung Akhir"
-	case phase < 0.77:
-		phaseName = "Kuartal Akhir"
-	default:
-		phaseName = "Sabit Akhir"
-	}
-
-	illum := phase
-	if illum > 0.5 {
-		illum = 1 - illum
-	}
-	illum *= 2
-
-	return MoonData{PhaseName: phaseName, Illumination: math.Round(illum*100) / 100}
-}
-
-// --- Calculate Hiking Index ---
-func calculateIndices(weather WeatherData) CalculatedIndices {
-	score := 10
-
-	if weather.Temperature > 33 {
-		score -= 3
-	} else if weather.Temperature < 18 {
-		score -= 2
-	}
-
-	if weather.Precipitation > 1 {
-		score -= 4
-	}
-
-	if weather.UVIndex > 8 {
-		score -= 2
-	}
-
-	if weather.AQI > 100 {
-		score -= 3
-	}
+	go func() {
+		slog.Info("Server berjalan", "addr", srv.Addr, "tls", cfg.Server.TLS.Enabled())
+		if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("server error", "error", err)
+			os.Exit(1)
+		}
+	}()
 
-	if weather.CloudCover > 80 {
-		score -= 1
-	}
+	<-ctx.Done()
+	stop()
 
-	if score < 0 {
-		score = 0
-	} else if score > 10 {
-		score = 10
+	// --- Graceful shutdown: tolak traffic baru, tunggu request yang sedang jalan, lalu matikan komponen background ---
+	lc.BeginDrain()
+	// /readyz sudah 503; tunggu load balancer mencabut instance ini sebelum
+	// listener ditutup. Sinyal kedua menghentikan proses langsung.
+	if delay := cfg.Server.DrainDelay; delay > 0 {
+		slog.Info("shutting down, waiting for load balancers to stop routing", "drain_delay", delay.String())
+		time.Sleep(delay)
 	}
+	slog.Info("shutting down, draining in-flight requests")
 
-	var recommendation string
-	switch {
-	case score >= 8:
-		recommendation = "Sangat baik untuk mendaki!"
-	case score >= 5:
-		recommendation = "Cukup baik, tetapi perhatikan cuaca."
-	case score >= 3:
-		recommendation = "Kurang disarankan, kondisi tidak ideal."
-	default:
-		recommendation = "Tidak disarankan untuk mendaki hari ini."
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server shutdown error", "error", err)
 	}
-
-	return CalculatedIndices{
-		HikingIndex:          math.Round(float64(score) * 10) / 10,
-		HikingRecommendation: recommendation,
+	if err := lc.Shutdown(shutdownCtx); err != nil {
+		slog.Error("background shutdown error", "error", err)
 	}
+	slog.Info("server stopped")
 }
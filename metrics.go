@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+const metricsNamespace = "titikkondisi"
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of calls to upstream providers.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"provider"})
+
+	upstreamRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_requests_total",
+		Help:      "Calls to upstream providers by outcome (ok, network, timeout, status, decode, disabled, circuit_open, canceled).",
+	}, []string{"provider", "outcome"})
+
+	upstreamRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_retries_total",
+		Help:      "Retries of upstream calls after a transient failure, by the outcome that triggered them.",
+	}, []string{"provider", "outcome"})
+)
+
+// --- Middleware: hitung request & latency per route template (bukan path mentah) ---
+func metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		httpRequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// --- Catat satu panggilan upstream beserta hasilnya (metrics, status provider, trace request) ---
+func (s *providerStatsStore) observeUpstream(ctx context.Context, provider string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	upstreamRequestDuration.WithLabelValues(provider).Observe(elapsed.Seconds())
+
+	outcome := "ok"
+	if err != nil {
+		outcome = providers.KindOf(err)
+	}
+	upstreamRequestsTotal.WithLabelValues(provider, outcome).Inc()
+	s.record(provider, elapsed, outcome)
+
+	recordUpstreamCall(ctx, upstreamCall{
+		Provider:   provider,
+		Outcome:    outcome,
+		DurationMS: float64(elapsed.Microseconds()) / 1000,
+	})
+	if err != nil {
+		slog.DebugContext(ctx, "upstream call failed", "provider", provider, "outcome", outcome, "error", err)
+	}
+}
+
+// --- Dipanggil Client sebelum retry; percobaannya sendiri sudah tercatat di observeUpstream ---
+func observeRetry(ctx context.Context, provider string, attempt int, err error) {
+	outcome := providers.KindOf(err)
+	upstreamRetriesTotal.WithLabelValues(provider, outcome).Inc()
+	slog.DebugContext(ctx, "retrying upstream call", "provider", provider, "attempt", attempt, "outcome", outcome)
+}
+
+func metricsHandler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+)
+
+// --- Tipe yang JSON-nya tidak bisa ditebak dari struct-nya sendiri ---
+// (misalnya nilai koordinat di package handlers)
+type openAPISchemaProvider interface {
+	OpenAPISchema() map[string]any
+}
+
+// --- Generate dokumen OpenAPI 3 dari daftar route & tipe Go-nya ---
+// Skema diturunkan lewat reflection dari struct response, jadi selalu sinkron
+// dengan tag json di kode.
+func buildOpenAPISpec(prefix string, routes []routeSpec) map[string]any {
+	schemas := map[string]any{}
+	paths := map[string]any{}
+
+	errorRef := schemaFor(reflect.TypeOf(handlers.ErrorResponse{}), schemas)
+	errorResponse := func(desc string) map[string]any {
+		return map[string]any{
+			"description": desc,
+			"content":     map[string]any{"application/json": map[string]any{"schema": errorRef}},
+		}
+	}
+
+	for _, route := range routes {
+		path := prefix + openAPIPath(route.Path)
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[path] = item
+		}
+
+		responses := map[string]any{
+			"400": errorResponse("Invalid input"),
+			"502": errorResponse("Upstream provider failed"),
+		}
+		if route.Response != nil {
+			content := map[string]any{"application/json": map[string]any{
+				"schema": schemaFor(reflect.TypeOf(route.Response), schemas),
+			}}
+			responses["200"] = map[string]any{"description": "OK", "content": content}
+			if hasResponseMeta(reflect.TypeOf(route.Response)) {
+				responses["206"] = map[string]any{"description": "Some sections unavailable, see meta.errors", "content": content}
+			}
+		} else {
+			responses["204"] = map[string]any{"description": "No Content"}
+		}
+
+		op := map[string]any{
+			"summary":     route.Summary,
+			"operationId": operationID(route),
+			"responses":   responses,
+		}
+		if route.Tag != "" {
+			op["tags"] = []string{route.Tag}
+		}
+		if len(route.Params) > 0 {
+			params := make([]any, 0, len(route.Params))
+			for _, p := range route.Params {
+				schema := map[string]any{"type": "string"}
+				if len(p.Enum) > 0 {
+					schema["enum"] = p.Enum
+				}
+				params = append(params, map[string]any{
+					"name":        p.Name,
+					"in":          p.In,
+					"required":    p.Required,
+					"description": p.Description,
+					"schema":      schema,
+				})
+			}
+			op["parameters"] = params
+		}
+		if route.Body != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{"application/json": map[string]any{
+					"schema": schemaFor(reflect.TypeOf(route.Body), schemas),
+				}},
+			}
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "TitikKondisi API",
+			"version":     "1.0.0",
+			"description": "Weather, sun, moon and outdoor-activity indices for any coordinate.",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+}
+
+// --- /weather/:lat/:lon -> /weather/{lat}/{lon} ---
+// Response dengan model.ResponseMeta bisa dikirim sebagian (meta.errors, status 206)
+func hasResponseMeta(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	field, ok := t.FieldByName("Meta")
+	return ok && field.Type == reflect.TypeOf(model.ResponseMeta{})
+}
+
+func openAPIPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func operationID(route routeSpec) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(route.Method))
+	for _, seg := range strings.Split(route.Path, "/") {
+		seg = strings.TrimLeft(seg, ":*")
+		if seg == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(seg[:1]) + seg[1:])
+	}
+	return b.String()
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// --- Skema JSON untuk satu tipe; struct bernama masuk components/schemas ---
+func schemaFor(t reflect.Type, schemas map[string]any) map[string]any {
+	if t.Implements(reflect.TypeOf((*openAPISchemaProvider)(nil)).Elem()) {
+		return reflect.Zero(t).Interface().(openAPISchemaProvider).OpenAPISchema()
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return structSchema(t, schemas)
+		}
+		ref := map[string]any{"$ref": "#/components/schemas/" + name}
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = map[string]any{} // placeholder untuk tipe rekursif
+			schemas[name] = structSchema(t, schemas)
+		}
+		return ref
+	default:
+		return map[string]any{}
+	}
+}
+
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	collectStructFields(t, schemas, properties, &required)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func collectStructFields(t reflect.Type, schemas map[string]any, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectStructFields(ft, schemas, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaFor(field.Type, schemas)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// --- Swagger UI dari CDN, membaca spec yang di-serve di sebelahnya ---
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>TitikKondisi API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "{{SPEC_URL}}", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`
+
+// --- Serve spec & Swagger UI untuk satu versi API ---
+func registerDocsRoutes(g *gin.RouterGroup, prefix string, routes []routeSpec) {
+	spec := buildOpenAPISpec(prefix, routes)
+	page := strings.Replace(swaggerUIPage, "{{SPEC_URL}}", prefix+"/openapi.json", 1)
+
+	g.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec)
+	})
+	g.GET("/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	})
+}
+
+// --- /openapi.json & /docs tanpa prefix: redirect ke versi API terbaru ---
+// Sengaja 302 supaya generator client ikut pindah begitu ada versi baru.
+func registerLatestDocsRoutes(g *gin.RouterGroup, latestPrefix string) {
+	for _, path := range []string{"/openapi.json", "/docs"} {
+		g.GET(path, func(c *gin.Context) {
+			c.Redirect(http.StatusFound, latestPrefix+path)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+)
+
+// --- Riwayat singkat panggilan upstream per provider untuk /status/providers ---
+// Cukup ring buffer in-memory per instance; metrics Prometheus tetap sumber
+// data jangka panjang.
+const (
+	providerHistorySize   = 256
+	providerHistoryWindow = 15 * time.Minute
+)
+
+type providerCall struct {
+	at       time.Time
+	duration time.Duration
+	outcome  string
+}
+
+type providerHistory struct {
+	calls       [providerHistorySize]providerCall
+	next, count int
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastOutcome string
+}
+
+type providerStatsStore struct {
+	// Sama dengan yang dipakai client provider
+	circuits   *providers.CircuitBreaker
+	killSwitch *providers.KillSwitch
+
+	mu        sync.Mutex
+	providers map[string]*providerHistory
+}
+
+func newProviderStatsStore(circuits *providers.CircuitBreaker, killSwitch *providers.KillSwitch) *providerStatsStore {
+	return &providerStatsStore{circuits: circuits, killSwitch: killSwitch, providers: map[string]*providerHistory{}}
+}
+
+func (s *providerStatsStore) record(provider string, duration time.Duration, outcome string) {
+	// Panggilan yang ditolak kill switch atau circuit breaker tidak pernah
+	// sampai ke upstream, dan yang dibatalkan pemanggil tidak mengatakan apa
+	// pun tentang kesehatannya
+	if outcome == providers.KindDisabled || outcome == providers.KindCircuitOpen || outcome == providers.KindCanceled {
+		return
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.providers[provider]
+	if !ok {
+		h = &providerHistory{}
+		s.providers[provider] = h
+	}
+	h.calls[h.next] = providerCall{at: now, duration: duration, outcome: outcome}
+	h.next = (h.next + 1) % providerHistorySize
+	h.count = min(h.count+1, providerHistorySize)
+	if outcome == "ok" {
+		h.lastSuccess = now
+	} else {
+		h.lastFailure, h.lastOutcome = now, outcome
+	}
+}
+
+// --- Status satu provider; sengaja tanpa pesan error mentah (URL bisa memuat API key) ---
+const (
+	ProviderStatusOK       = "ok"
+	ProviderStatusDegraded = "degraded"
+	ProviderStatusDown     = "down"
+	ProviderStatusDisabled = "disabled"
+	ProviderStatusUnknown  = "unknown"
+
+	// Selain closed/open/half_open dari circuit breaker
+	CircuitForcedOpen = "forced_open"
+)
+
+// Ambang success rate dalam window untuk status ok / degraded
+const (
+	providerOKRate     = 0.95
+	providerDegradedAt = 0.5
+)
+
+type LatencyPercentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+type ProviderStatus struct {
+	Provider        string              `json:"provider"`
+	Status          string              `json:"status"`
+	Circuit         string              `json:"circuit"`
+	CircuitRetryAt  *time.Time          `json:"circuit_retry_at,omitempty"`
+	WindowSeconds   int                 `json:"window_seconds"`
+	Calls           int                 `json:"calls"`
+	SuccessRate     *float64            `json:"success_rate"`
+	LatencyMS       *LatencyPercentiles `json:"latency_ms,omitempty"`
+	LastSuccess     *time.Time          `json:"last_success,omitempty"`
+	LastFailure     *time.Time          `json:"last_failure,omitempty"`
+	LastFailureKind string              `json:"last_failure_kind,omitempty"`
+	DisabledReason  string              `json:"disabled_reason,omitempty"`
+	DisabledAt      *time.Time          `json:"disabled_at,omitempty"`
+}
+
+type ProviderStatusResponse struct {
+	CheckedAt time.Time        `json:"checked_at"`
+	Providers []ProviderStatus `json:"providers"`
+}
+
+func (s *providerStatsStore) Status(provider string) ProviderStatus {
+	since := time.Now().Add(-providerHistoryWindow)
+	status := ProviderStatus{
+		Provider:      provider,
+		Status:        ProviderStatusUnknown,
+		Circuit:       providers.CircuitClosed,
+		WindowSeconds: int(providerHistoryWindow.Seconds()),
+	}
+
+	s.mu.Lock()
+	if h, ok := s.providers[provider]; ok {
+		var durations []float64
+		succeeded := 0
+		for i := 0; i < h.count; i++ {
+			call := h.calls[i]
+			if call.at.Before(since) {
+				continue
+			}
+			durations = append(durations, float64(call.duration.Microseconds())/1000)
+			if call.outcome == "ok" {
+				succeeded++
+			}
+		}
+		if n := len(durations); n > 0 {
+			status.Calls = n
+			rate := model.Round2(float64(succeeded) / float64(n))
+			status.SuccessRate = &rate
+			slices.Sort(durations)
+			status.LatencyMS = &LatencyPercentiles{
+				P50: percentile(durations, 0.50),
+				P90: percentile(durations, 0.90),
+				P99: percentile(durations, 0.99),
+			}
+			switch {
+			case rate >= providerOKRate:
+				status.Status = ProviderStatusOK
+			case rate >= providerDegradedAt:
+				status.Status = ProviderStatusDegraded
+			default:
+				status.Status = ProviderStatusDown
+			}
+		}
+		if !h.lastSuccess.IsZero() {
+			t := h.lastSuccess.UTC()
+			status.LastSuccess = &t
+		}
+		if !h.lastFailure.IsZero() {
+			t := h.lastFailure.UTC()
+			status.LastFailure, status.LastFailureKind = &t, h.lastOutcome
+		}
+	}
+	s.mu.Unlock()
+
+	// Circuit terbuka: provider dianggap down sampai percobaan berikutnya berhasil
+	if circuit := s.circuits.State(provider); circuit.State != providers.CircuitClosed {
+		status.Circuit, status.CircuitRetryAt = circuit.State, circuit.RetryAt
+		status.Status = ProviderStatusDown
+	}
+
+	// Kill switch admin = circuit yang dibuka paksa
+	if state := s.killSwitch.State(provider); state.Disabled {
+		status.Status, status.Circuit = ProviderStatusDisabled, CircuitForcedOpen
+		status.DisabledReason, status.DisabledAt = state.Reason, state.DisabledAt
+	}
+	return status
+}
+
+// --- Nearest-rank percentile dari slice yang sudah terurut ---
+func percentile(sorted []float64, p float64) float64 {
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	idx = max(0, min(idx, len(sorted)-1))
+	return model.Round1(sorted[idx])
+}
+
+// --- GET /status/providers: publik, supaya user juga tahu kenapa data bisa kurang lengkap ---
+func providerStatusHandler(stats *providerStatsStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := ProviderStatusResponse{CheckedAt: time.Now().UTC()}
+		for _, provider := range providers.Names {
+			response.Providers = append(response.Providers, stats.Status(provider))
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
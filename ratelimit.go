@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+)
+
+const (
+	ErrCodeRateLimited = "rate_limited"
+)
+
+// --- Batas request per IP client (token bucket), bagian "rate_limit" ---
+// Melindungi API upstream gratis (sunrise-sunset.org melarang pemakaian
+// otomatis yang berat) dari satu client yang membanjiri service.
+type RateLimitConfig struct {
+	// Laju isi ulang bucket per IP; 0 = rate limit mati
+	RequestsPerMinute int64 `yaml:"requests_per_minute"`
+	// Kapasitas bucket: request beruntun yang boleh lewat sekaligus
+	Burst int64 `yaml:"burst"`
+	// Proxy (IP/CIDR) yang boleh menentukan IP client lewat X-Forwarded-For.
+	// Hanya dipakai saat start.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+func defaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerMinute: 120,
+		Burst:             30,
+		// Jaringan privat: reverse proxy & load balancer di depan service.
+		// Client dari internet tidak bisa memalsukan IP-nya lewat header.
+		TrustedProxies: []string{"127.0.0.0/8", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "::1/128", "fc00::/7"},
+	}
+}
+
+func (c RateLimitConfig) Enabled() bool { return c.RequestsPerMinute > 0 }
+
+func (c RateLimitConfig) validate() error {
+	if c.RequestsPerMinute < 0 {
+		return errors.New("rate_limit.requests_per_minute must not be negative")
+	}
+	if c.Enabled() && c.Burst < 1 {
+		return errors.New("rate_limit.burst must be at least 1")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			return fmt.Errorf("rate_limit.trusted_proxies: %q is not an IP or CIDR", proxy)
+		}
+	}
+	return nil
+}
+
+var rateLimitedTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: metricsNamespace,
+	Name:      "rate_limited_requests_total",
+	Help:      "Requests rejected by the per-IP rate limit.",
+})
+
+// --- Bucket per IP client, disimpan di memori per instance ---
+type ipRateLimiter struct {
+	config func() *Config
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newIPRateLimiter(config func() *Config) *ipRateLimiter {
+	return &ipRateLimiter{config: config, buckets: map[string]*tokenBucket{}}
+}
+
+// --- Ambil satu token; kalau habis, kembalikan kapan token berikutnya tersedia ---
+func (l *ipRateLimiter) allow(ip string, cfg RateLimitConfig, now time.Time) (bool, time.Duration) {
+	perSecond := float64(cfg.RequestsPerMinute) / 60
+	burst := float64(cfg.Burst)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = &tokenBucket{tokens: burst, updated: now}
+		l.buckets[ip] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := (1 - b.tokens) / perSecond
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// --- Buang bucket yang sudah penuh lagi; IP itu sama saja dengan IP baru ---
+func (l *ipRateLimiter) removeIdle(now time.Time) {
+	cfg := l.config().RateLimit
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !cfg.Enabled() {
+		clear(l.buckets)
+		return
+	}
+	refill := time.Duration(float64(cfg.Burst) / float64(cfg.RequestsPerMinute) * float64(time.Minute))
+	for ip, b := range l.buckets {
+		if now.Sub(b.updated) >= refill {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+func (l *ipRateLimiter) StartJanitor(lc *lifecycle, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.OnShutdown("rate limit janitor", func(context.Context) error {
+		cancel()
+		return nil
+	})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.removeIdle(time.Now())
+			}
+		}
+	}()
+}
+
+// --- Middleware: 429 + Retry-After saat bucket IP client kosong ---
+// Laju & burst dibaca per request supaya ikut hot reload.
+func (l *ipRateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := l.config().RateLimit
+		if !cfg.Enabled() {
+			c.Next()
+			return
+		}
+		ok, wait := l.allow(c.ClientIP(), cfg, time.Now())
+		if ok {
+			c.Next()
+			return
+		}
+		rateLimitedTotal.Inc()
+		seconds := int(math.Ceil(wait.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		handlers.AbortWithError(c, http.StatusTooManyRequests, handlers.APIError{
+			Code:      ErrCodeRateLimited,
+			Message:   fmt.Sprintf("too many requests from this address, retry in %ds", seconds),
+			Retryable: true,
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+)
+
+// --- Konteks request yang ikut dikirim bersama laporan error ---
+type RequestInfo struct {
+	RequestID string
+	Method    string
+	Route     string
+	Path      string
+	Query     string
+	ClientIP  string
+	Request   *http.Request
+}
+
+// --- Tujuan laporan panic & error handler; implementasi bisa diganti ---
+type ErrorReporter interface {
+	ReportPanic(ctx context.Context, recovered any, stack []byte, info RequestInfo)
+	ReportError(ctx context.Context, err error, info RequestInfo)
+	Flush(ctx context.Context) error
+}
+
+// --- Reporter default: tulis ke log terstruktur lengkap dengan stack trace ---
+type logReporter struct{}
+
+func (logReporter) ReportPanic(ctx context.Context, recovered any, stack []byte, info RequestInfo) {
+	slog.ErrorContext(ctx, "panic recovered",
+		"panic", fmt.Sprint(recovered),
+		"stack", string(stack),
+		"method", info.Method, "route", info.Route, "path", info.Path, "query", info.Query,
+	)
+}
+
+func (logReporter) ReportError(ctx context.Context, err error, info RequestInfo) {
+	slog.ErrorContext(ctx, "handler error",
+		"error", err.Error(),
+		"method", info.Method, "route", info.Route, "path", info.Path, "query", info.Query,
+	)
+}
+
+func (logReporter) Flush(context.Context) error { return nil }
+
+// --- Reporter Sentry; log tetap ditulis lewat logReporter ---
+type sentryReporter struct {
+	logReporter
+}
+
+func newSentryReporter(dsn, environment, release string) (*sentryReporter, error) {
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              dsn,
+		Environment:      environment,
+		Release:          release,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sentry init: %w", err)
+	}
+	return &sentryReporter{}, nil
+}
+
+func (r *sentryReporter) hub(info RequestInfo) *sentry.Hub {
+	hub := sentry.CurrentHub().Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		if info.Request != nil {
+			scope.SetRequest(redactRequest(info.Request))
+		}
+		scope.SetTag("request_id", info.RequestID)
+		scope.SetTag("route", info.Route)
+	})
+	return hub
+}
+
+func (r *sentryReporter) ReportPanic(ctx context.Context, recovered any, stack []byte, info RequestInfo) {
+	r.logReporter.ReportPanic(ctx, recovered, stack, info)
+	r.hub(info).RecoverWithContext(ctx, recovered)
+}
+
+func (r *sentryReporter) ReportError(ctx context.Context, err error, info RequestInfo) {
+	r.logReporter.ReportError(ctx, err, info)
+	r.hub(info).CaptureException(err)
+}
+
+func (r *sentryReporter) Flush(ctx context.Context) error {
+	if !sentry.CurrentHub().FlushWithContext(ctx) {
+		return errors.New("sentry flush timed out")
+	}
+	return nil
+}
+
+// --- Pilih reporter: Sentry kalau DSN di-set, selain itu log saja ---
+func newErrorReporter(cfg SentryConfig) ErrorReporter {
+	if cfg.DSN == "" {
+		return logReporter{}
+	}
+	reporter, err := newSentryReporter(cfg.DSN, cfg.Environment, cfg.Release)
+	if err != nil {
+		slog.Error("sentry disabled", "error", err)
+		return logReporter{}
+	}
+	return reporter
+}
+
+func requestInfo(c *gin.Context) RequestInfo {
+	return RequestInfo{
+		RequestID: requestIDFromContext(c.Request.Context()),
+		Method:    c.Request.Method,
+		Route:     c.FullPath(),
+		Path:      c.Request.URL.Path,
+		Query:     c.Request.URL.RawQuery,
+		ClientIP:  c.ClientIP(),
+		Request:   c.Request,
+	}
+}
+
+// --- Pengganti gin.Recovery: laporkan panic dengan konteks request, balas 500 pakai envelope ---
+// Error internal (bukan kegagalan upstream) dari handler juga dilaporkan.
+func recoveryMiddleware(reporter ErrorReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if isBrokenPipe(recovered) {
+				// Client sudah putus, tidak ada yang perlu dibalas atau dilaporkan
+				c.Abort()
+				return
+			}
+			reporter.ReportPanic(c.Request.Context(), recovered, debug.Stack(), requestInfo(c))
+			handlers.AbortWithError(c, http.StatusInternalServerError, handlers.APIError{
+				Code:    handlers.ErrCodeInternal,
+				Message: "internal server error",
+			})
+		}()
+
+		c.Next()
+
+		if apiErr, ok := c.Get(handlers.ErrorKey); ok {
+			if e, ok := apiErr.(handlers.APIError); ok && e.Code == handlers.ErrCodeInternal && c.Writer.Status() >= 500 {
+				reporter.ReportError(c.Request.Context(), errors.New(e.Message), requestInfo(c))
+			}
+		}
+	}
+}
+
+func isBrokenPipe(recovered any) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	msg := strings.ToLower(opErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
+
+// --- Dipakai saat shutdown supaya laporan terakhir tidak hilang ---
+func flushReporter(reporter ErrorReporter) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		return reporter.Flush(ctx)
+	}
+}
+
+// sentry-go hanya membuang Authorization, Cookie & header IP; API key pihak
+// ketiga tidak boleh ikut terkirim ke Sentry
+func redactRequest(r *http.Request) *http.Request {
+	if r.Header.Get(handlers.APIKeyHeader) == "" {
+		return r
+	}
+	redacted := r.Clone(r.Context())
+	redacted.Header.Del(handlers.APIKeyHeader)
+	return redacted
+}
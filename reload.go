@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// --- Reload konfigurasi tanpa restart (SIGHUP atau POST /admin/config/reload) ---
+// Hanya bagian yang dibaca per-request yang ikut berubah; pengaturan server
+// (alamat listen, timeout HTTP) dan Sentry tetap memakai nilai saat start.
+// Komponen yang ikut hot reload menerima Current sebagai getter config.
+type configReloader struct {
+	path     string
+	logLevel *slog.LevelVar
+	current  atomic.Pointer[Config]
+
+	mu sync.Mutex
+}
+
+func newConfigReloader(path string, logLevel *slog.LevelVar, cfg *Config) *configReloader {
+	r := &configReloader{path: path, logLevel: logLevel}
+	r.current.Store(cfg)
+	return r
+}
+
+// --- Konfigurasi aktif; bisa berganti di antara dua panggilan ---
+func (r *configReloader) Current() *Config {
+	return r.current.Load()
+}
+
+type ReloadResult struct {
+	Changed        []string `json:"changed"`
+	RequireRestart []string `json:"require_restart,omitempty"`
+}
+
+func (r *configReloader) Reload() (ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := loadConfig(r.path)
+	if err != nil {
+		return ReloadResult{}, err
+	}
+	prev := r.Current()
+
+	var result ReloadResult
+	static := map[string][2]any{
+		"server":   {prev.Server, next.Server},
+		"sentry":   {prev.Sentry, next.Sentry},
+		"admin":    {prev.Admin, next.Admin},
+		"database": {prev.Database, next.Database},
+		"cache.backend": {
+			[]any{prev.Cache.Backend, prev.Cache.Redis},
+			[]any{next.Cache.Backend, next.Cache.Redis},
+		},
+		"rate_limit.trusted_proxies": {prev.RateLimit.TrustedProxies, next.RateLimit.TrustedProxies},
+	}
+	for name, pair := range static {
+		if !reflect.DeepEqual(pair[0], pair[1]) {
+			result.RequireRestart = append(result.RequireRestart, name)
+		}
+	}
+	next.Server, next.Sentry, next.Admin, next.Database = prev.Server, prev.Sentry, prev.Admin, prev.Database
+	next.Cache.Backend, next.Cache.Redis = prev.Cache.Backend, prev.Cache.Redis
+	next.RateLimit.TrustedProxies = prev.RateLimit.TrustedProxies
+
+	dynamic := map[string][2]any{
+		"upstream":         {prev.Upstream, next.Upstream},
+		"providers":        {prev.Providers, next.Providers},
+		"logging":          {prev.Logging, next.Logging},
+		"default_timezone": {prev.DefaultTimezone, next.DefaultTimezone},
+		"features":         {prev.Features, next.Features},
+		"cors":             {prev.CORS, next.CORS},
+		"limits":           {prev.Limits, next.Limits},
+		"cache":            {prev.Cache, next.Cache},
+		"scoring":          {prev.Scoring, next.Scoring},
+		"api_keys":         {prev.APIKeys, next.APIKeys},
+		"rate_limit":       {prev.RateLimit, next.RateLimit},
+		"subscriptions":    {prev.Subscriptions, next.Subscriptions},
+		"email":            {prev.Email, next.Email},
+		"push":             {prev.Push, next.Push},
+	}
+	for name, pair := range dynamic {
+		if !reflect.DeepEqual(pair[0], pair[1]) {
+			result.Changed = append(result.Changed, name)
+		}
+	}
+
+	level, _ := parseLogLevel(next.Logging.Level)
+	r.logLevel.Set(level)
+	r.current.Store(next)
+
+	slog.Info("configuration reloaded", "changed", result.Changed, "require_restart", result.RequireRestart)
+	return result, nil
+}
+
+// --- Dengarkan SIGHUP sampai ctx selesai ---
+func (r *configReloader) WatchSignals(ctx context.Context) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				if _, err := r.Reload(); err != nil {
+					slog.Error("configuration reload failed, keeping previous config", "error", fmt.Sprint(err))
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/cache"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/handlers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/i18n"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/indices"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/providers"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+const (
+	apiV1Prefix = "/api/v1"
+
+	// --- Akhir masa deprecation route lama tanpa prefix versi ---
+	legacySunset = "Wed, 30 Jun 2027 00:00:00 GMT"
+)
+
+// --- Deskripsi satu route: dipakai untuk registrasi Gin sekaligus OpenAPI ---
+type routeSpec struct {
+	Method  string
+	Path    string
+	Handler gin.HandlerFunc
+	Summary string
+	Tag     string
+	Class   string // kelas deadline: routeClassDefault atau routeClassBatch
+	// POST yang membuat resource; retry dengan Idempotency-Key yang sama dijawab ulang
+	Idempotent bool
+	Params     []paramSpec
+	Body       any // contoh nilai tipe request body, nil kalau tidak ada
+	Response   any // contoh nilai tipe response 200
+}
+
+type paramSpec struct {
+	Name        string
+	In          string // "path" atau "query"
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+var (
+	latParam = paramSpec{Name: "lat", In: "path", Required: true,
+		Description: "Latitude, decimal (-7.54) or DMS (7°32'S)"}
+	lonParam = paramSpec{Name: "lon", In: "path", Required: true,
+		Description: "Longitude, decimal (110.44) or DMS (110°26'E)"}
+	unitsParam = paramSpec{Name: "units", In: "query",
+		Description: "Unit system for the response", Enum: []string{model.UnitsMetric, model.UnitsImperial}}
+	aqiScaleParam = paramSpec{Name: "aqi_scale", In: "query",
+		Description: "Scale for weather.air_quality", Enum: []string{indices.AQIScaleEuropean, indices.AQIScaleUS, indices.AQIScaleISPU}}
+	providerParam = paramSpec{Name: "provider", In: "query",
+		Description: "Source for current weather; auto uses BMKG near a configured BMKG area", Enum: []string{services.SourceAuto, services.SourceOpenMeteo, services.SourceBMKG}}
+	fieldsParam = paramSpec{Name: "fields", In: "query",
+		Description: "Comma-separated JSON paths to return, e.g. weather.temperature,indices,sun.sunrise"}
+	langParam = paramSpec{Name: "lang", In: "query",
+		Description: "Language for recommendations, moon phase names and forecast alert titles", Enum: []string{i18n.LangID, i18n.LangEN}}
+	userIDParam = paramSpec{Name: "id", In: "path", Required: true,
+		Description: "User ID from the client app, 1 to 64 letters, digits, '.', '-' or '_'"}
+	subscriptionIDParam = paramSpec{Name: "id", In: "path", Required: true, Description: "Subscription ID"}
+)
+
+// --- Daftar route API v1 ---
+func v1Routes(weather *handlers.Weather, custom *handlers.CustomIndices, favorites *handlers.Favorites, subscriptions *handlers.Subscriptions) []routeSpec {
+	return []routeSpec{
+		{
+			Method: "GET", Path: "/weather/:lat/:lon", Handler: weather.ByCoordinates, Tag: "weather",
+			Summary:  "Consolidated weather, sun, moon and indices for a coordinate",
+			Params:   []paramSpec{latParam, lonParam, unitsParam, aqiScaleParam, providerParam, fieldsParam, langParam},
+			Response: model.ConsolidatedResponse{},
+		},
+		{
+			Method: "GET", Path: "/weather/geohash/:hash", Handler: weather.ByGeohash, Tag: "weather",
+			Summary: "Consolidated data for the center of a geohash cell",
+			Params: []paramSpec{
+				{Name: "hash", In: "path", Required: true, Description: "Geohash, up to 12 characters"},
+				unitsParam, aqiScaleParam, providerParam, fieldsParam, langParam,
+			},
+			Response: model.ConsolidatedResponse{},
+		},
+		{
+			Method: "GET", Path: "/weather/by-name/:place", Handler: weather.ByName, Tag: "weather",
+			Summary: "Consolidated data for a place name such as a city or mountain",
+			Params: []paramSpec{
+				{Name: "place", In: "path", Required: true, Description: "Place name, e.g. Gunung Rinjani"},
+				unitsParam, aqiScaleParam, providerParam, fieldsParam, langParam,
+			},
+			Response: model.ConsolidatedResponse{},
+		},
+		{
+			Method: "GET", Path: "/weather/pluscode/:code", Handler: weather.ByPlusCode, Tag: "weather",
+			Summary: "Consolidated data for a Plus Code (Open Location Code)",
+			Params: []paramSpec{
+				{Name: "code", In: "path", Required: true, Description: "Full or short Plus Code"},
+				{Name: "ref", In: "query", Description: "Reference \"lat,lon\", required for short codes"},
+				unitsParam, aqiScaleParam, providerParam, fieldsParam, langParam,
+			},
+			Response: model.ConsolidatedResponse{},
+		},
+		{
+			Method: "GET", Path: "/activities/:lat/:lon", Handler: weather.Activities, Tag: "activities",
+			Summary:  "Activity indices (hiking, car wash) for a coordinate",
+			Params:   []paramSpec{latParam, lonParam, langParam},
+			Response: model.ActivitiesResponse{},
+		},
+		{
+			Method: "GET", Path: "/alerts/:lat/:lon", Handler: weather.Alerts, Tag: "alerts",
+			Summary:  "Weather warnings (BMKG and forecast thunderstorms, heavy rain, strong wind) for a coordinate",
+			Params:   []paramSpec{latParam, lonParam, langParam},
+			Response: model.AlertsResponse{},
+		},
+		{
+			Method: "GET", Path: "/quakes/:lat/:lon", Handler: weather.Quakes, Tag: "quakes",
+			Summary: "Earthquakes (USGS, and BMKG in Indonesia) near a coordinate in the last 7 days",
+			Params: []paramSpec{
+				latParam, lonParam,
+				{Name: "radius_km", In: "query", Description: "Search radius in km, default 100, at most 500"},
+			},
+			Response: model.QuakesResponse{},
+		},
+		{
+			Method: "GET", Path: "/tides/:lat/:lon", Handler: weather.Tides, Tag: "marine",
+			Summary:  "Tide state, next high/low tides and waves for a coastal point",
+			Params:   []paramSpec{latParam, lonParam},
+			Response: model.TidesResponse{},
+		},
+		{
+			Method: "GET", Path: "/forecast/daily/:lat/:lon", Handler: weather.DailyForecast, Tag: "forecast",
+			Summary:  "7-day daily forecast with a hiking index per day",
+			Params:   []paramSpec{latParam, lonParam, langParam},
+			Response: model.DailyForecastResponse{},
+		},
+		{
+			Method: "GET", Path: "/history/:lat/:lon", Handler: weather.History, Tag: "forecast",
+			Summary: "Past daily weather (ERA5 reanalysis) with a back-computed hiking index per day",
+			Params: []paramSpec{
+				latParam, lonParam,
+				{Name: "start", In: "query", Required: true, Description: "First day, YYYY-MM-DD, from 1940-01-01"},
+				{Name: "end", In: "query", Required: true, Description: "Last day, YYYY-MM-DD, before today and at most 366 days after start"},
+				langParam,
+			},
+			Response: model.HistoryResponse{},
+		},
+		{
+			Method: "GET", Path: "/climatology/:lat/:lon", Handler: weather.Climatology, Tag: "forecast",
+			Summary: "Monthly averages over past years (rain days, temperature, typical hiking index) and the best months to hike",
+			Params: []paramSpec{
+				latParam, lonParam,
+				{Name: "years", In: "query", Description: "Full calendar years to average, ending last year; default 10, at most 30"},
+			},
+			Response: model.ClimatologyResponse{},
+		},
+		{
+			Method: "GET", Path: "/sun-exposure/:lat/:lon", Handler: weather.SunExposure, Tag: "activities",
+			Summary: "Safe unprotected sun exposure and sunscreen reapplication times for today",
+			Params: []paramSpec{
+				latParam, lonParam,
+				{Name: "skin_type", In: "query", Required: true,
+					Description: "Fitzpatrick skin type, 1 (always burns) to 6 (never burns)",
+					Enum:        []string{"1", "2", "3", "4", "5", "6"}},
+				langParam,
+			},
+			Response: model.SunExposureResponse{},
+		},
+		{
+			Method: "GET", Path: "/weather", Handler: weather.ByPoints, Tag: "weather", Class: routeClassBatch,
+			Summary: "Consolidated data for several points in one request",
+			Params: []paramSpec{
+				{Name: "points", In: "query", Required: true,
+					Description: "Semicolon-separated \"lat,lon\" pairs, e.g. -7.54,110.44;-8.41,116.45"},
+				unitsParam, aqiScaleParam, providerParam, fieldsParam, langParam,
+			},
+			Response: handlers.BatchResponse{},
+		},
+		{
+			Method: "POST", Path: "/weather/batch", Handler: weather.ByBatch, Tag: "weather", Class: routeClassBatch,
+			Summary:  "Consolidated data for a list of named locations in the JSON body",
+			Params:   []paramSpec{unitsParam, aqiScaleParam, providerParam, fieldsParam, langParam},
+			Body:     []handlers.BatchPointRequest{},
+			Response: handlers.BatchResponse{},
+		},
+		{
+			Method: "POST", Path: "/weather", Handler: weather.ByJSON, Tag: "weather",
+			Summary:  "Consolidated data for a location given in the JSON body",
+			Params:   []paramSpec{unitsParam, aqiScaleParam, providerParam, fieldsParam, langParam},
+			Body:     handlers.WeatherRequest{},
+			Response: model.ConsolidatedResponse{},
+		},
+		{
+			Method: "POST", Path: "/indices/custom", Handler: custom.Score, Tag: "activities",
+			Summary:  "Score a user-defined index (rules over current weather) for a location; \"save\" stores it per X-API-Key",
+			Body:     handlers.CustomIndexRequest{},
+			Response: model.CustomIndexResponse{},
+		},
+		{
+			Method: "GET", Path: "/indices/custom", Handler: custom.List, Tag: "activities",
+			Summary:  "Custom indices saved for the X-API-Key",
+			Response: handlers.CustomIndexList{},
+		},
+		{
+			Method: "DELETE", Path: "/indices/custom/:name", Handler: custom.Delete, Tag: "activities",
+			Summary: "Delete a saved custom index",
+			Params:  []paramSpec{{Name: "name", In: "path", Required: true, Description: "Name of the saved index"}},
+		},
+		{
+			Method: "POST", Path: "/users/:id/locations", Handler: favorites.Save, Tag: "favorites",
+			Summary:  "Save a named location for a user of the X-API-Key; the same name replaces it",
+			Params:   []paramSpec{userIDParam},
+			Body:     handlers.FavoriteRequest{},
+			Response: model.Favorite{},
+		},
+		{
+			Method: "GET", Path: "/users/:id/locations", Handler: favorites.List, Tag: "favorites",
+			Summary:  "Locations saved for a user",
+			Params:   []paramSpec{userIDParam},
+			Response: handlers.FavoriteList{},
+		},
+		{
+			Method: "DELETE", Path: "/users/:id/locations/:name", Handler: favorites.Delete, Tag: "favorites",
+			Summary: "Delete a saved location",
+			Params:  []paramSpec{userIDParam, {Name: "name", In: "path", Required: true, Description: "Name of the saved location"}},
+		},
+		{
+			Method: "GET", Path: "/users/:id/dashboard", Handler: favorites.Dashboard, Tag: "favorites", Class: routeClassBatch,
+			Summary:  "Consolidated weather for all of a user's saved locations in one call",
+			Params:   []paramSpec{userIDParam, unitsParam, aqiScaleParam, providerParam, fieldsParam, langParam},
+			Response: handlers.BatchResponse{},
+		},
+		{
+			Method: "POST", Path: "/subscriptions", Handler: subscriptions.Create, Tag: "subscriptions", Idempotent: true,
+			Summary:  "Subscribe a webhook, email or push to a condition at a location (e.g. \"hiking_index >= 8\") and/or a daily summary",
+			Body:     handlers.SubscriptionRequest{},
+			Response: model.Subscription{},
+		},
+		{
+			Method: "GET", Path: "/subscriptions", Handler: subscriptions.List, Tag: "subscriptions",
+			Summary:  "Subscriptions of the X-API-Key",
+			Response: handlers.SubscriptionList{},
+		},
+		{
+			Method: "DELETE", Path: "/subscriptions/:id", Handler: subscriptions.Delete, Tag: "subscriptions",
+			Summary: "Delete a subscription",
+			Params:  []paramSpec{subscriptionIDParam},
+		},
+		{
+			Method: "POST", Path: "/subscriptions/:id/devices", Handler: subscriptions.AddDevice, Tag: "subscriptions",
+			Summary:  "Register an FCM device token to receive push notifications for a subscription",
+			Params:   []paramSpec{subscriptionIDParam},
+			Body:     handlers.DeviceRequest{},
+			Response: handlers.DeviceRegistration{},
+		},
+		{
+			Method: "DELETE", Path: "/subscriptions/:id/devices/:token", Handler: subscriptions.RemoveDevice, Tag: "subscriptions",
+			Summary: "Unregister a device token",
+			Params:  []paramSpec{subscriptionIDParam, {Name: "token", In: "path", Required: true, Description: "FCM registration token"}},
+		},
+	}
+}
+
+// --- Semua route didaftarkan di sini; tiap versi API punya fungsi sendiri ---
+// v2 nanti cukup tambah registerV2Routes dengan handler/response baru tanpa
+// menyentuh v1 yang dipakai aplikasi mobile.
+func registerRoutes(r *gin.Engine, cfg *Config, deps app) error {
+	weather := handlers.NewWeather(deps.weather, func() handlers.WeatherSettings {
+		limits := deps.config().Limits
+		return handlers.WeatherSettings{
+			MaxBatchPoints:   int(limits.MaxBatchPoints),
+			BatchConcurrency: int(limits.BatchConcurrency),
+		}
+	})
+	custom := handlers.NewCustomIndices(deps.weather, deps.customIndices)
+	favorites := handlers.NewFavorites(weather, deps.favorites)
+	subscriptions := handlers.NewSubscriptions(deps.subscriptions, func() handlers.SubscriptionSettings {
+		cfg := deps.config()
+		return handlers.SubscriptionSettings{EmailEnabled: cfg.Email.Enabled(), PushEnabled: cfg.Push.Enabled()}
+	})
+	// Rate limit per IP & kuota API key berlaku untuk API saja, bukan
+	// dokumentasi & probe; rate limit lebih dulu supaya banjir request
+	// tidak ikut menghabiskan kuota
+	keyQuota := requireAPIKeyQuota(deps.config, deps.apiKeys, deps.quota)
+	rateLimit := deps.rateLimits.Middleware()
+	v1 := r.Group(apiV1Prefix)
+	registerV1Routes(v1.Group("", rateLimit, keyQuota), deps, weather, custom, favorites, subscriptions)
+	registerDocsRoutes(v1.Group("", deps.flags.Require(FeatureDocs)), apiV1Prefix, v1Routes(weather, custom, favorites, subscriptions))
+	registerLatestDocsRoutes(r.Group("", deps.flags.Require(FeatureDocs)), apiV1Prefix)
+
+	// --- Alias lama (/weather/...) selama masa deprecation ---
+	registerV1Routes(r.Group("", legacyAlias(apiV1Prefix), rateLimit, keyQuota), deps, weather, custom, favorites, subscriptions)
+
+	// --- GraphQL tidak ikut versi URL; schema berevolusi lewat @deprecated ---
+	schema, err := handlers.BuildGraphQLSchema(deps.weather)
+	if err != nil {
+		return fmt.Errorf("graphql schema: %v", err)
+	}
+	gql := r.Group("/graphql", deps.flags.Require(FeatureGraphQL), requestTimeout(deps.config, routeClassDefault), rateLimit, keyQuota)
+	gql.GET("", handlers.GraphQL(schema))
+	gql.POST("", handlers.GraphQL(schema))
+
+	// --- Probe untuk load balancer / Kubernetes ---
+	readiness := newReadinessChecker(cfg.Upstream.ReadinessTimeout, cfg.Upstream.ReadinessTTL)
+	for _, provider := range providers.Names {
+		readiness.Register(readinessCheck{Name: provider, Check: func(ctx context.Context) error {
+			return deps.client.Probe(ctx, provider)
+		}})
+	}
+	// Redis mati cukup membuat semua lookup miss, jadi tidak critical
+	if store, ok := deps.cache.(*cache.Redis); ok {
+		readiness.Register(readinessCheck{Name: "cache", Check: store.Ping})
+	}
+	// Tanpa database hanya riwayat observasi yang hilang
+	if deps.db != nil {
+		readiness.Register(readinessCheck{Name: "database", Check: deps.db.Ping})
+	}
+	r.GET("/healthz", healthzHandler)
+	r.GET("/readyz", readyzHandler(readiness, deps.lc))
+	r.GET("/metrics", deps.flags.Require(FeatureMetrics), metricsHandler())
+	r.GET("/status/providers", deps.flags.Require(FeatureStatus), providerStatusHandler(deps.providerStats))
+
+	// --- Endpoint operasional, hanya aktif kalau ADMIN_TOKEN di-set ---
+	if cfg.Admin.Token != "" {
+		registerAdminRoutes(r.Group("/admin", requireAdminToken(cfg.Admin.Token)), deps)
+	}
+	return nil
+}
+
+func registerV1Routes(g *gin.RouterGroup, deps app, weather *handlers.Weather, custom *handlers.CustomIndices, favorites *handlers.Favorites, subscriptions *handlers.Subscriptions) {
+	for _, route := range v1Routes(weather, custom, favorites, subscriptions) {
+		chain := []gin.HandlerFunc{requestTimeout(deps.config, route.Class)}
+		if route.Idempotent {
+			chain = append(chain, requireIdempotency(deps.idempotency))
+		}
+		g.Handle(route.Method, route.Path, append(chain, route.Handler)...)
+	}
+}
+
+// --- Tandai response dari route lama sebagai deprecated (RFC 8594) ---
+func legacyAlias(successorPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Sunset", legacySunset)
+		c.Header("Link", "<"+successorPrefix+c.Request.URL.Path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
@@ -0,0 +1,556 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/AntonTian/TitikKondisi-Backend/internal/alerts"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/clock"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/i18n"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/model"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/notify"
+	"github.com/AntonTian/TitikKondisi-Backend/internal/services"
+)
+
+// --- Scheduler langganan notifikasi, bagian "subscriptions" ---
+type SubscriptionsConfig struct {
+	// Jarak antar evaluasi semua langganan; 0 = scheduler mati. Paling lama
+	// 1 jam supaya jam ringkasan harian & laporan mingguan tidak terlewat
+	Interval time.Duration `yaml:"interval"`
+	// Batas waktu satu percobaan kirim webhook
+	WebhookTimeout time.Duration `yaml:"webhook_timeout"`
+	// Izinkan webhook ke alamat privat/loopback; hanya untuk development
+	AllowPrivateWebhooks bool `yaml:"allow_private_webhooks"`
+}
+
+func defaultSubscriptionsConfig() SubscriptionsConfig {
+	return SubscriptionsConfig{Interval: 10 * time.Minute, WebhookTimeout: 10 * time.Second}
+}
+
+func (c SubscriptionsConfig) validate() error {
+	if c.Interval != 0 && (c.Interval < time.Minute || c.Interval > time.Hour) {
+		return fmt.Errorf("subscriptions.interval must be 0 (off) or between 1m and 1h, got %s", c.Interval)
+	}
+	if c.WebhookTimeout <= 0 {
+		return errors.New("subscriptions.webhook_timeout must be positive")
+	}
+	return nil
+}
+
+const (
+	// Percobaan kirim per notifikasi, dengan jeda yang berlipat
+	deliveryAttempts = 3
+	deliveryBackoff  = 5 * time.Second
+	// Langganan yang dinilai bersamaan; sebagian besar kena cache per lokasi
+	subscriptionConcurrency = 4
+
+	webhookEventHeader     = "X-TitikKondisi-Event"
+	webhookSignatureHeader = "X-TitikKondisi-Signature"
+)
+
+var (
+	errPrivateWebhook = errors.New("webhook address is not public")
+	errEmailDisabled  = errors.New("email channel is not configured")
+	errPushDisabled   = errors.New("push channel is not configured")
+)
+
+var notificationDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metricsNamespace,
+	Name:      "notification_deliveries_total",
+	Help:      "Subscription notifications by channel (webhook, email, push) and outcome (ok, failed, blocked, disabled, unregistered).",
+}, []string{"channel", "outcome"})
+
+type subscriptionScheduler struct {
+	// Interval, timeout & kanal dibaca per putaran supaya ikut hot reload
+	config  func() *Config
+	weather *services.Weather
+	// Jam yang sama dengan service cuaca, supaya jendela kondisi & waktu
+	// notifikasi bisa diuji dengan jam tetap
+	clock clock.Clock
+	store services.SubscriptionStore
+	// Lokasi tersimpan pengguna, untuk laporan mingguan & event astronomi
+	favorites services.FavoriteStore
+	client    *http.Client
+	fcm       *notify.FCM
+
+	// Notifikasi yang sedang dikirim, ditunggu saat shutdown
+	deliveries sync.WaitGroup
+	// Selesai saat shutdown; retry berhenti menunggu supaya shutdown tidak
+	// tertahan jeda backoff
+	stopping context.Context
+}
+
+// --- Jalankan scheduler di background sampai shutdown ---
+// Interval dibaca tiap putaran supaya ikut hot reload.
+func startSubscriptionScheduler(lc *lifecycle, config func() *Config, clk clock.Clock, weather *services.Weather, store services.SubscriptionStore, favorites services.FavoriteStore) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &subscriptionScheduler{
+		config:    config,
+		weather:   weather,
+		clock:     clk,
+		store:     store,
+		favorites: favorites,
+		client:    newWebhookClient(config),
+		fcm:       notify.NewFCM(&http.Client{}),
+		stopping:  ctx,
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.run(ctx)
+	}()
+	lc.OnShutdown("subscription scheduler", func(shutdownCtx context.Context) error {
+		cancel()
+		<-stopped
+		done := make(chan struct{})
+		go func() {
+			s.deliveries.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-shutdownCtx.Done():
+			return fmt.Errorf("notifications still being delivered: %w", shutdownCtx.Err())
+		}
+	})
+}
+
+func (s *subscriptionScheduler) run(ctx context.Context) {
+	for {
+		// Scheduler mati: cek lagi sebentar lagi, siapa tahu config di-reload
+		wait := s.config().Subscriptions.Interval
+		if wait <= 0 {
+			wait = time.Minute
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if s.config().Subscriptions.Interval > 0 {
+			s.evaluateAll(ctx)
+		}
+	}
+}
+
+func (s *subscriptionScheduler) evaluateAll(ctx context.Context) {
+	subs, err := s.store.All(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "subscriptions not evaluated", "error", err)
+		return
+	}
+	g := new(errgroup.Group)
+	g.SetLimit(subscriptionConcurrency)
+	for _, sub := range subs {
+		g.Go(func() error {
+			s.evaluate(ctx, sub)
+			return nil
+		})
+	}
+	g.Wait()
+	slog.DebugContext(ctx, "subscriptions evaluated", "count", len(subs))
+}
+
+// --- Nilai satu langganan: kondisi dan/atau jadwal ringkasan & laporannya ---
+func (s *subscriptionScheduler) evaluate(ctx context.Context, sub model.Subscription) {
+	ctx, cancel := context.WithTimeout(ctx, s.config().Limits.Timeouts.Default)
+	defer cancel()
+	if sub.Condition != "" {
+		s.checkCondition(ctx, sub)
+	}
+	if sub.DailySummaryHour != nil {
+		s.dailySummary(ctx, sub)
+	}
+	if sub.WeeklyReport != nil {
+		s.weeklyReport(ctx, sub)
+	}
+}
+
+// Notifikasi hanya dikirim saat kondisi baru terpenuhi
+func (s *subscriptionScheduler) checkCondition(ctx context.Context, sub model.Subscription) {
+	condition, err := alerts.ParseCondition(sub.Condition)
+	if err != nil {
+		slog.WarnContext(ctx, "subscription has an invalid condition", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	check, err := s.weather.CheckCondition(ctx, sub.Latitude, sub.Longitude, condition)
+	if err != nil {
+		slog.WarnContext(ctx, "subscription not evaluated", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	var favorite *model.Favorite
+	if !check.Matched && condition.Astronomical() {
+		check, favorite = s.checkFavorites(ctx, sub, condition, check)
+	}
+	now := s.clock.Now().UTC()
+	changed, err := s.store.SetTriggered(ctx, sub.ID, check.Matched, now)
+	if err != nil {
+		slog.WarnContext(ctx, "subscription state not saved", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	if !changed || !check.Matched {
+		return
+	}
+	notification := newNotification(sub, model.EventConditionTriggered, check.Data, now)
+	notification.Condition = sub.Condition
+	notification.Value = &check.Value
+	if favorite != nil {
+		notification.Latitude, notification.Longitude = favorite.Latitude, favorite.Longitude
+		place := model.Place{Latitude: favorite.Latitude, Longitude: favorite.Longitude}
+		if notification.Location != nil {
+			place = *notification.Location
+		}
+		place.Name = favorite.Name
+		notification.Location = &place
+	}
+	s.send(sub, notification)
+}
+
+// Event astronomi (gerhana, hujan meteor, ...) bisa terlihat dari lokasi
+// tersimpan pengguna walau tidak dari lokasi langganan; lokasi pertama
+// yang cocok yang dilaporkan. Tanpa user_id hanya lokasi langganan yang dicek.
+func (s *subscriptionScheduler) checkFavorites(ctx context.Context, sub model.Subscription, condition alerts.Condition, check services.ConditionCheck) (services.ConditionCheck, *model.Favorite) {
+	if sub.UserID == "" {
+		return check, nil
+	}
+	favorites, err := s.favorites.List(ctx, sub.Owner, sub.UserID)
+	if err != nil {
+		slog.WarnContext(ctx, "saved locations not evaluated", "subscription_id", sub.ID, "error", err)
+		return check, nil
+	}
+	for _, favorite := range favorites {
+		at, err := s.weather.CheckCondition(ctx, favorite.Latitude, favorite.Longitude, condition)
+		if err != nil {
+			slog.WarnContext(ctx, "saved location not evaluated", "subscription_id", sub.ID, "location", favorite.Name, "error", err)
+			continue
+		}
+		if at.Matched {
+			return at, &favorite
+		}
+	}
+	return check, nil
+}
+
+// Ringkasan dikirim sekali sehari, pada evaluasi pertama di jam lokal yang dipilih
+func (s *subscriptionScheduler) dailySummary(ctx context.Context, sub model.Subscription) {
+	local := s.weather.LocalTime(ctx, sub.Latitude, sub.Longitude)
+	date := local.Format(time.DateOnly)
+	if local.Hour() != *sub.DailySummaryHour || sub.LastSummaryDate == date {
+		return
+	}
+	summary, err := s.weather.DailySummary(ctx, sub.Latitude, sub.Longitude, date)
+	if err != nil {
+		slog.WarnContext(ctx, "daily summary not prepared", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	// Dicatat setelah data siap: kalau gagal, dicoba lagi di evaluasi berikutnya
+	sent, err := s.store.MarkSummarySent(ctx, sub.ID, date)
+	if err != nil {
+		slog.WarnContext(ctx, "subscription state not saved", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	if !sent {
+		return
+	}
+	notification := newNotification(sub, model.EventDailySummary, summary.Data, s.clock.Now().UTC())
+	if today := summary.Today; today != nil {
+		localized := localizeForecastDay(*today, sub.Lang)
+		notification.Forecast = &localized
+	}
+	s.send(sub, notification)
+}
+
+// Laporan dikirim sekali seminggu, pada evaluasi pertama di hari & jam
+// lokal yang dipilih; pengguna tanpa lokasi tersimpan dilewati
+func (s *subscriptionScheduler) weeklyReport(ctx context.Context, sub model.Subscription) {
+	schedule := sub.WeeklyReport
+	local := s.weather.LocalTime(ctx, sub.Latitude, sub.Longitude)
+	date := local.Format(time.DateOnly)
+	if int(local.Weekday()) != schedule.Weekday || local.Hour() != schedule.Hour || sub.LastReportDate == date {
+		return
+	}
+	favorites, err := s.favorites.List(ctx, sub.Owner, schedule.UserID)
+	if err != nil {
+		slog.WarnContext(ctx, "weekly report not prepared", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	if len(favorites) == 0 {
+		return
+	}
+	report, err := s.weather.WeeklyReport(ctx, sub.Latitude, sub.Longitude, favorites, date)
+	if err != nil {
+		slog.WarnContext(ctx, "weekly report not prepared", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	sent, err := s.store.MarkReportSent(ctx, sub.ID, date)
+	if err != nil {
+		slog.WarnContext(ctx, "subscription state not saved", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	if !sent {
+		return
+	}
+	notification := newNotification(sub, model.EventWeeklyReport, report.Data, s.clock.Now().UTC())
+	notification.Report = make([]model.ReportLocation, len(report.Locations))
+	for i, location := range report.Locations {
+		location.Days = slices.Clone(location.Days)
+		for d := range location.Days {
+			location.Days[d] = localizeForecastDay(location.Days[d], sub.Lang)
+		}
+		if location.BestDay != nil {
+			best := localizeForecastDay(*location.BestDay, sub.Lang)
+			location.BestDay = &best
+		}
+		notification.Report[i] = location
+	}
+	s.send(sub, notification)
+}
+
+// Rekomendasi sesuai bahasa langganan; rincian faktor tidak ikut notifikasi
+func localizeForecastDay(day model.DailyForecast, lang string) model.DailyForecast {
+	day.HikingRecommendation = i18n.T(lang, day.HikingRecommendation)
+	day.IndexBreakdown = nil
+	return day
+}
+
+func newNotification(sub model.Subscription, event string, data model.ConsolidatedResponse, at time.Time) model.SubscriptionNotification {
+	data = i18n.LocalizeConditions(data, sub.Lang)
+	return model.SubscriptionNotification{
+		Event:          event,
+		SubscriptionID: sub.ID,
+		Latitude:       sub.Latitude,
+		Longitude:      sub.Longitude,
+		Location:       data.Location,
+		TriggeredAt:    at,
+		Timezone:       data.Meta.Timezone,
+		Weather:        data.Weather,
+		Indices:        data.Indices,
+		Alerts:         data.Alerts,
+	}
+}
+
+// Dikirim di goroutine sendiri supaya retry tidak menahan evaluasi
+func (s *subscriptionScheduler) send(sub model.Subscription, notification model.SubscriptionNotification) {
+	s.deliveries.Add(1)
+	go func() {
+		defer s.deliveries.Done()
+		s.deliver(sub, notification)
+	}()
+}
+
+// --- Kirim lewat kanal langganan, lalu push ke perangkatnya ---
+// Webhook: body JSON ditandatangani HMAC-SHA256 dengan secret langganan.
+// Email & push: teks dari template notify sesuai bahasa langganan.
+func (s *subscriptionScheduler) deliver(sub model.Subscription, notification model.SubscriptionNotification) {
+	switch sub.Channel {
+	case model.ChannelPush:
+		// Hanya perangkat, di bawah
+	case model.ChannelEmail:
+		subject, body, err := notify.Render(notification, sub.Lang)
+		if err != nil {
+			slog.Error("email not rendered", "subscription_id", sub.ID, "error", err)
+			break
+		}
+		html, err := notify.RenderHTML(notification, sub.Lang)
+		if err != nil {
+			slog.Error("email not rendered", "subscription_id", sub.ID, "error", err)
+			break
+		}
+		s.retry(sub, model.ChannelEmail, notification.Event, func() error {
+			return s.email(notify.Email{To: sub.Email, Subject: subject, Body: body, HTML: html})
+		})
+	default:
+		body, err := json.Marshal(notification)
+		if err != nil {
+			slog.Error("webhook not encoded", "subscription_id", sub.ID, "error", err)
+			break
+		}
+		mac := hmac.New(sha256.New, []byte(sub.Secret))
+		mac.Write(body)
+		signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		s.retry(sub, model.ChannelWebhook, notification.Event, func() error {
+			return s.post(sub.WebhookURL, notification.Event, body, signature)
+		})
+	}
+	if len(sub.DeviceTokens) > 0 {
+		s.pushAll(sub, notification)
+	}
+}
+
+// Satu push per perangkat; token yang ditolak FCM dilupakan
+func (s *subscriptionScheduler) pushAll(sub model.Subscription, notification model.SubscriptionNotification) {
+	msg, err := notify.RenderPush(notification, sub.Lang)
+	if err != nil {
+		slog.Error("push not rendered", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	msg.Data = map[string]string{
+		"event":           notification.Event,
+		"subscription_id": sub.ID,
+		"latitude":        strconv.FormatFloat(sub.Latitude, 'f', -1, 64),
+		"longitude":       strconv.FormatFloat(sub.Longitude, 'f', -1, 64),
+	}
+	if sub.Condition != "" {
+		msg.Data["condition"] = sub.Condition
+	}
+	for _, token := range sub.DeviceTokens {
+		err := s.retry(sub, model.ChannelPush, notification.Event, func() error { return s.push(token, msg) })
+		if !errors.Is(err, notify.ErrUnregistered) {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.config().Limits.Timeouts.Default)
+		if err := s.store.ForgetDevice(ctx, token); err != nil {
+			slog.Warn("unregistered device not removed", "subscription_id", sub.ID, "error", err)
+		}
+		cancel()
+	}
+}
+
+// --- Coba kirim beberapa kali dengan jeda berlipat; error terakhir dikembalikan ---
+// Kegagalan yang tidak akan berubah kalau dicoba lagi (alamat diblokir,
+// kanal mati, token tidak berlaku) langsung berhenti, begitu juga saat
+// shutdown: percobaan yang sedang jalan diselesaikan, sisanya tidak.
+func (s *subscriptionScheduler) retry(sub model.Subscription, channel, event string, attempt func() error) error {
+	var err error
+	for n := 1; ; n++ {
+		err = attempt()
+		if err == nil {
+			notificationDeliveries.WithLabelValues(channel, "ok").Inc()
+			slog.Info("notification delivered", "subscription_id", sub.ID, "channel", channel, "event", event)
+			return nil
+		}
+		if outcome, final := finalOutcome(err); final {
+			notificationDeliveries.WithLabelValues(channel, outcome).Inc()
+			slog.Warn("notification not sent", "subscription_id", sub.ID, "channel", channel, "error", err)
+			return err
+		}
+		if n == deliveryAttempts {
+			break
+		}
+		select {
+		case <-s.stopping.Done():
+			notificationDeliveries.WithLabelValues(channel, "failed").Inc()
+			slog.Warn("notification not delivered, shutting down", "subscription_id", sub.ID, "channel", channel, "attempts", n, "error", err)
+			return err
+		case <-time.After(deliveryBackoff << (n - 1)):
+		}
+	}
+	notificationDeliveries.WithLabelValues(channel, "failed").Inc()
+	slog.Warn("notification not delivered", "subscription_id", sub.ID, "channel", channel, "attempts", deliveryAttempts, "error", err)
+	return err
+}
+
+func finalOutcome(err error) (string, bool) {
+	switch {
+	case errors.Is(err, errPrivateWebhook):
+		return "blocked", true
+	case errors.Is(err, errEmailDisabled), errors.Is(err, errPushDisabled):
+		return "disabled", true
+	case errors.Is(err, notify.ErrUnregistered):
+		return "unregistered", true
+	}
+	return "", false
+}
+
+func (s *subscriptionScheduler) email(msg notify.Email) error {
+	cfg := s.config().Email
+	if !cfg.Enabled() {
+		return errEmailDisabled
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	return notify.Send(ctx, cfg, msg)
+}
+
+func (s *subscriptionScheduler) post(url, event string, body []byte, signature string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config().Subscriptions.WebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TitikKondisi-Webhook/1.0")
+	req.Header.Set(webhookEventHeader, event)
+	req.Header.Set(webhookSignatureHeader, signature)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// --- Client webhook: URL dari pengguna tidak boleh menjangkau jaringan internal ---
+// Alamat dicek saat dial (setelah DNS), dan redirect tidak diikuti.
+func newWebhookClient(config func() *Config) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			if config().Subscriptions.AllowPrivateWebhooks {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !publicAddr(ip) {
+				return fmt.Errorf("%w: %s", errPrivateWebhook, ip)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// Shared address space (CGNAT) juga bukan alamat internet
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func publicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+func (s *subscriptionScheduler) push(token string, msg notify.Push) error {
+	cfg := s.config().Push
+	if !cfg.Enabled() {
+		return errPushDisabled
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	return s.fcm.Send(ctx, cfg, token, msg)
+}
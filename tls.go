@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// --- HTTPS langsung dari service: sertifikat sendiri atau Let's Encrypt (autocert) ---
+// Untuk deployment kecil tanpa reverse proxy. Kalau keduanya kosong, server
+// tetap HTTP biasa seperti sebelumnya.
+type TLSConfig struct {
+	CertFile string         `yaml:"cert_file"`
+	KeyFile  string         `yaml:"key_file"`
+	Autocert AutocertConfig `yaml:"autocert"`
+
+	// Listener HTTP tambahan (misal ":80") yang redirect ke HTTPS dan
+	// menjawab challenge ACME HTTP-01
+	RedirectAddr string `yaml:"redirect_addr"`
+}
+
+type AutocertConfig struct {
+	Domains  []string `yaml:"domains"`
+	Email    string   `yaml:"email"`
+	CacheDir string   `yaml:"cache_dir"`
+}
+
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != "" || len(t.Autocert.Domains) > 0
+}
+
+func (t TLSConfig) validate() error {
+	if !t.Enabled() {
+		if t.RedirectAddr != "" {
+			return errors.New("server.tls.redirect_addr needs TLS to be configured")
+		}
+		return nil
+	}
+	manual := t.CertFile != "" || t.KeyFile != ""
+	if manual && len(t.Autocert.Domains) > 0 {
+		return errors.New("server.tls: use either cert_file/key_file or autocert, not both")
+	}
+	if manual {
+		if t.CertFile == "" || t.KeyFile == "" {
+			return errors.New("server.tls.cert_file and server.tls.key_file must be set together")
+		}
+		for _, path := range []string{t.CertFile, t.KeyFile} {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("server.tls: %w", err)
+			}
+		}
+		return nil
+	}
+	if t.Autocert.CacheDir == "" {
+		return errors.New("server.tls.autocert.cache_dir is required so certificates survive restarts")
+	}
+	return nil
+}
+
+// --- Siapkan srv untuk TLS; kembalikan fungsi serve dan server redirect (boleh nil) ---
+func configureTLS(srv *http.Server, cfg TLSConfig) (serve func() error, redirect *http.Server) {
+	if !cfg.Enabled() {
+		return srv.ListenAndServe, nil
+	}
+
+	redirectHandler := redirectToHTTPS(srv.Addr)
+	var challenge http.Handler
+	if len(cfg.Autocert.Domains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.Autocert.Domains...),
+			Cache:      autocert.DirCache(cfg.Autocert.CacheDir),
+			Email:      cfg.Autocert.Email,
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		challenge = manager.HTTPHandler(redirectHandler)
+		serve = func() error { return srv.ListenAndServeTLS("", "") }
+	} else {
+		srv.TLSConfig = &tls.Config{}
+		serve = func() error { return srv.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile) }
+	}
+	srv.TLSConfig.MinVersion = tls.VersionTLS12
+
+	if cfg.RedirectAddr != "" {
+		if challenge == nil {
+			challenge = redirectHandler
+		}
+		redirect = &http.Server{
+			Addr:              cfg.RedirectAddr,
+			Handler:           challenge,
+			ReadHeaderTimeout: srv.ReadHeaderTimeout,
+		}
+	}
+	return serve, redirect
+}
+
+// --- Redirect permanen ke https dengan host & path yang sama ---
+// Port HTTPS ikut ditulis kalau bukan 443 (misal listen di :8443).
+func redirectToHTTPS(httpsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
+
+func startRedirectServer(redirect *http.Server, lc *lifecycle) {
+	lc.OnShutdown("https redirect", redirect.Shutdown)
+	go func() {
+		slog.Info("HTTP redirect listener started", "addr", redirect.Addr)
+		if err := redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("redirect listener error", "error", err)
+		}
+	}()
+}